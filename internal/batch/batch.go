@@ -0,0 +1,92 @@
+package batch
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultConcurrency is how many items are worked on at once unless asked otherwise. Each site or image is mostly waiting
+// on Docker so a few at a time is much faster without overloading Docker Desktop.
+const DefaultConcurrency = 4
+
+// Result is the outcome of running a task for a single item, such as stopping one site.
+type Result struct {
+	Item     string
+	Err      error
+	Duration time.Duration
+}
+
+// Summary totals the results of a batch.
+type Summary struct {
+	Succeeded int
+	Failed    []Result
+	Duration  time.Duration
+}
+
+// Run runs the task for every item with no more than concurrency tasks running at once. onDone, if given, is called as
+// each item finishes, never more than one call at a time, with the number of items finished so far and the total. The
+// results are returned in the same order as the items, whatever order they finished in.
+func Run(items []string, concurrency int, task func(item string) error, onDone func(result Result, finished, total int)) []Result {
+	results := make([]Result, len(items))
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		finished int
+	)
+
+	queue := make(chan int)
+
+	for worker := 0; worker < min(concurrency, len(items)); worker++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range queue {
+				start := time.Now()
+				err := task(items[i])
+
+				results[i] = Result{Item: items[i], Err: err, Duration: time.Since(start)}
+
+				mu.Lock()
+				finished++
+
+				if onDone != nil {
+					onDone(results[i], finished, len(items))
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i := range items {
+		queue <- i
+	}
+
+	close(queue)
+	wg.Wait()
+
+	return results
+}
+
+// Summarize totals the results of a batch that took the given time.
+func Summarize(results []Result, duration time.Duration) Summary {
+	summary := Summary{Failed: []Result{}, Duration: duration}
+
+	for _, result := range results {
+		if result.Err != nil {
+			summary.Failed = append(summary.Failed, result)
+
+			continue
+		}
+
+		summary.Succeeded++
+	}
+
+	return summary
+}
@@ -0,0 +1,66 @@
+package batch
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	items := []string{"one", "two", "three", "four", "five", "six", "seven"}
+
+	var running, maxRunning atomic.Int32
+
+	progress := []int{}
+
+	results := Run(items, 3, func(item string) error {
+		current := running.Add(1)
+		defer running.Add(-1)
+
+		for {
+			seen := maxRunning.Load()
+			if current <= seen || maxRunning.CompareAndSwap(seen, current) {
+				break
+			}
+		}
+
+		time.Sleep(10 * time.Millisecond)
+
+		if item == "four" {
+			return fmt.Errorf("unable to stop %s", item)
+		}
+
+		return nil
+	}, func(result Result, finished, total int) {
+		assert.Equal(t, len(items), total)
+
+		progress = append(progress, finished)
+	})
+
+	assert.LessOrEqual(t, maxRunning.Load(), int32(3))
+	assert.Greater(t, maxRunning.Load(), int32(1))
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7}, progress)
+
+	for i, result := range results {
+		assert.Equal(t, items[i], result.Item)
+	}
+
+	summary := Summarize(results, time.Second)
+
+	assert.Equal(t, 6, summary.Succeeded)
+	assert.Len(t, summary.Failed, 1)
+	assert.Equal(t, "four", summary.Failed[0].Item)
+	assert.EqualError(t, summary.Failed[0].Err, "unable to stop four")
+}
+
+func TestRunWithoutItems(t *testing.T) {
+	results := Run([]string{}, DefaultConcurrency, func(item string) error {
+		return fmt.Errorf("no items should be run")
+	}, nil)
+
+	assert.Empty(t, results)
+	assert.Equal(t, 0, Summarize(results, 0).Succeeded)
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+var flagAutostartRestart bool
+
+func autostart(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "autostart",
+		Short: "Stop running sites cleanly when you log out or shut down and, optionally, start them again when you log in.",
+		Args:  cobra.NoArgs,
+	}
+
+	installCmd := &cobra.Command{
+		Use:   "install",
+		Short: "Install the agent that stops your running sites cleanly at logout or shutdown.",
+		Run: func(cmd *cobra.Command, args []string) {
+			autostartFile, err := kanaSite.InstallAutostart(flagAutostartRestart)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			message := "Your running sites will be stopped cleanly when you log out or shut down."
+
+			if flagAutostartRestart {
+				message = "Your running sites will be stopped cleanly when you log out or shut down and started again when you log in."
+			}
+
+			consoleOutput.Success(fmt.Sprintf("%s The agent has been installed at %s.", message, autostartFile))
+		},
+		Args: cobra.NoArgs,
+	}
+
+	installCmd.Flags().BoolVar(
+		&flagAutostartRestart,
+		"restart",
+		false,
+		"Start the sites that were running at logout or shutdown again when you log in")
+
+	uninstallCmd := &cobra.Command{
+		Use:   "uninstall",
+		Short: "Remove the agent that stops your running sites at logout or shutdown. Running sites are left running.",
+		Run: func(cmd *cobra.Command, args []string) {
+			err := kanaSite.UninstallAutostart()
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			consoleOutput.Success("The autostart agent has been removed.")
+		},
+		Args: cobra.NoArgs,
+	}
+
+	agentCmd := &cobra.Command{
+		Use:    "agent",
+		Short:  "Run the autostart agent. This is started by launchd or systemd after `kana autostart install`.",
+		Hidden: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := kanaSite.RunAutostartAgent(flagAutostartRestart, consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+		},
+		Args: cobra.NoArgs,
+	}
+
+	agentCmd.Flags().BoolVar(
+		&flagAutostartRestart,
+		"restart",
+		false,
+		"Start the sites that were running at the last logout or shutdown")
+
+	cmd.AddCommand(installCmd, uninstallCmd, agentCmd)
+
+	return cmd
+}
@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/helpers"
+	"github.com/ChrisWiegman/kana/internal/settings"
+	"github.com/ChrisWiegman/kana/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+var flagBackupFull bool
+var flagBackupKeep int64
+var flagBackupOutput string
+var flagBackupPush bool
+
+func backup(consoleOutput *console.Console, kanaSite *site.Site, kanaSettings *settings.Settings) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Create a backup of the site's database, or of the whole site with --full, or manage existing backups.",
+		Run: func(cmd *cobra.Command, args []string) {
+			err := kanaSite.EnsureDocker(consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			if !kanaSite.IsSiteRunning() {
+				consoleOutput.Error(fmt.Errorf("the backup command only works on a running site. Please run 'kana start' to start the site"))
+			}
+
+			if flagBackupOutput != "" && !flagBackupFull {
+				consoleOutput.Error(fmt.Errorf("--output can only be used with --full"))
+			}
+
+			backupType := "database"
+
+			var file string
+
+			if flagBackupFull {
+				backupType = "site"
+				file, err = kanaSite.CreateFullBackup(flagBackupOutput, Version, consoleOutput)
+			} else {
+				file, err = kanaSite.CreateBackup(consoleOutput)
+			}
+
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			if flagBackupPush {
+				err = kanaSite.PushBackup(file)
+				if err != nil {
+					consoleOutput.Error(err)
+				}
+
+				consoleOutput.Success(fmt.Sprintf("Backup complete. Your %s has been saved to %s and pushed to the remote.", backupType, file))
+
+				return
+			}
+
+			consoleOutput.Success(fmt.Sprintf("Backup complete. Your %s has been saved to %s.", backupType, file))
+		},
+		Args: cobra.NoArgs,
+	}
+
+	commandsRequiringSite = append(commandsRequiringSite, cmd.Use)
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List all backups for the site along with their size and date.",
+		Run: func(cmd *cobra.Command, args []string) {
+			backups, err := kanaSite.GetBackups()
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			backupTable := console.NewTable(
+				console.TableColumn{Header: "Name"},
+				console.TableColumn{Header: "Type"},
+				console.TableColumn{Header: "Size", Align: console.AlignRight},
+				console.TableColumn{Header: "Created"})
+
+			for _, backup := range backups {
+				backupType := "database"
+				if backup.Full {
+					backupType = "full"
+				}
+
+				backupTable.AddRow(
+					backup.Name,
+					backupType,
+					console.Cell{Value: backup.Size, Text: helpers.FormatFileSize(backup.Size)},
+					console.Cell{Value: backup.Created, Text: backup.Created.Format(time.DateTime)})
+			}
+
+			consoleOutput.PrintTable(backupTable)
+		},
+		Args: cobra.NoArgs,
+	}
+
+	pruneCmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove old database backups, keeping only the newest as set by the backupRetention setting.",
+		Run: func(cmd *cobra.Command, args []string) {
+			keep := kanaSettings.GetInt("backupRetention")
+
+			if cmd.Flags().Lookup("keep").Changed {
+				keep = flagBackupKeep
+			}
+
+			if keep <= 0 {
+				consoleOutput.Error(fmt.Errorf("the number of backups to keep must be greater than 0"))
+			}
+
+			removed, err := kanaSite.PruneBackups(keep)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			consoleOutput.Success(fmt.Sprintf("Removed %d old backup(s).", len(removed)))
+		},
+		Args: cobra.NoArgs,
+	}
+
+	pushCmd := &cobra.Command{
+		Use:   "push <backup name>",
+		Short: "Push an existing backup to the configured remote backup target.",
+		Run: func(cmd *cobra.Command, args []string) {
+			backup, err := kanaSite.GetBackup(args[0])
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			err = kanaSite.PushBackup(backup.Path)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			consoleOutput.Success(fmt.Sprintf("The backup %s has been pushed to %s.", backup.Name, kanaSettings.Get("backupRemoteBucket")))
+		},
+		Args: cobra.ExactArgs(1),
+	}
+
+	restoreCmd := &cobra.Command{
+		Use:   "restore <backup name or file>",
+		Short: "Restore a backup into the running site, replacing its current database, and its files and config for a full backup.",
+		Run: func(cmd *cobra.Command, args []string) {
+			err := kanaSite.EnsureDocker(consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			if !kanaSite.IsSiteRunning() {
+				consoleOutput.Error(fmt.Errorf("the restore command only works on a running site. Please run 'kana start' to start the site"))
+			}
+
+			warning := "The site's current database will be replaced."
+			if filepath.Ext(args[0]) == ".zip" {
+				warning = "The site's current database, wp-content files and config will be replaced."
+			}
+
+			if !flagForce {
+				confirmRestore := consoleOutput.PromptConfirm(
+					fmt.Sprintf(
+						"Are you sure you want to restore %s to %s? %s",
+						consoleOutput.Bold(args[0]),
+						consoleOutput.Bold(consoleOutput.Blue(kanaSettings.Get("name"))),
+						consoleOutput.Bold(consoleOutput.Yellow(warning))),
+					false)
+
+				if !confirmRestore {
+					consoleOutput.Error(fmt.Errorf("restore canceled. No data has been changed"))
+				}
+			}
+
+			err = kanaSite.RestoreBackup(args[0], consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			if filepath.Ext(args[0]) == ".zip" {
+				consoleOutput.Success(
+					fmt.Sprintf(
+						"The backup %s has been restored. If the site's config changed, stop and start the site to apply it.",
+						args[0]))
+
+				return
+			}
+
+			consoleOutput.Success(fmt.Sprintf("The backup %s has been restored. Reload your site to see the changes.", args[0]))
+		},
+		Args: cobra.ExactArgs(1),
+	}
+
+	commandsRequiringSite = append(commandsRequiringSite, restoreCmd.Use)
+	commandsLockingSite = append(commandsLockingSite, restoreCmd)
+
+	cmd.Flags().BoolVar(
+		&flagBackupFull,
+		"full",
+		false,
+		"Back up the whole site, with its database, wp-content files and config, to an archive that can be restored.")
+	cmd.Flags().StringVar(
+		&flagBackupOutput,
+		"output",
+		"",
+		"The file or folder to save a full backup to instead of the site's backups folder, such as to keep it if the site is destroyed.")
+	cmd.Flags().BoolVar(&flagBackupPush, "push", false, "Push the new backup to the configured remote backup target.")
+	pruneCmd.Flags().Int64Var(&flagBackupKeep, "keep", 0, "The number of backups to keep. Defaults to the backupRetention setting.")
+	restoreCmd.Flags().BoolVar(&flagForce, "force", false, "Restore the backup without prompting for confirmation.")
+
+	cmd.AddCommand(
+		listCmd,
+		pruneCmd,
+		pushCmd,
+		restoreCmd,
+	)
+
+	return cmd
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ChrisWiegman/kana/internal/batch"
+	"github.com/ChrisWiegman/kana/internal/console"
+
+	"github.com/spf13/cobra"
+)
+
+var flagAll bool
+var flagParallel int
+
+// addParallelFlag adds the flag setting how many sites or images a bulk command works on at once.
+func addParallelFlag(cmd *cobra.Command) {
+	cmd.Flags().IntVar(
+		&flagParallel,
+		"parallel",
+		batch.DefaultConcurrency,
+		"How many sites or images to work on at once. Use 1 to work on them one at a time.")
+}
+
+// ensureParallelFlag exits if the parallel flag isn't a usable number of sites or images to work on at once.
+func ensureParallelFlag(consoleOutput *console.Console) {
+	if flagParallel < 1 {
+		consoleOutput.Error(fmt.Errorf("the --parallel flag must be 1 or more"))
+	}
+}
+
+// printBatchProgress returns a function printing each site or image of a bulk command as it finishes, such as
+// "[3/20] Stopped my-site (2.1s)".
+func printBatchProgress(consoleOutput *console.Console, pastTense string) func(result batch.Result, finished, total int) {
+	return func(result batch.Result, finished, total int) {
+		if result.Err != nil {
+			consoleOutput.Warn(fmt.Sprintf("[%d/%d] %s failed: %s", finished, total, result.Item, result.Err))
+
+			return
+		}
+
+		consoleOutput.Println(
+			fmt.Sprintf(
+				"[%d/%d] %s %s (%s)",
+				finished,
+				total,
+				pastTense,
+				consoleOutput.Bold(consoleOutput.Blue(result.Item)),
+				result.Duration.Round(100*time.Millisecond)))
+	}
+}
+
+// printBatchSummary prints how many of a bulk command's sites or images succeeded, exiting with an error listing those
+// that failed if any did.
+func printBatchSummary(consoleOutput *console.Console, results []batch.Result, duration time.Duration, noun, pastTense string) {
+	summary := batch.Summarize(results, duration)
+	took := summary.Duration.Round(100 * time.Millisecond)
+
+	if len(summary.Failed) == 0 {
+		consoleOutput.Success(fmt.Sprintf("%d %s(s) %s in %s.", summary.Succeeded, noun, strings.ToLower(pastTense), took))
+
+		return
+	}
+
+	failed := make([]string, len(summary.Failed))
+
+	for i, result := range summary.Failed {
+		failed[i] = result.Item
+	}
+
+	consoleOutput.Error(
+		fmt.Errorf(
+			"%d of %d %s(s) %s in %s. These failed: %s",
+			summary.Succeeded,
+			len(results),
+			noun,
+			strings.ToLower(pastTense),
+			took,
+			strings.Join(failed, ", ")))
+}
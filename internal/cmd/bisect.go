@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+var flagBisectURL string
+var flagBisectStatus int
+var flagBisectContains string
+
+func bisect(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bisect",
+		Short: "Find the plugin causing a problem by deactivating half of the active plugins at a time.",
+		Run: func(cmd *cobra.Command, args []string) {
+			ensureSiteIsRunning(consoleOutput, kanaSite, cmd.Use)
+
+			probe := func(active []string) (bool, error) {
+				return consoleOutput.PromptConfirm("Check the site now. Is the problem still happening?", true), nil
+			}
+
+			if flagBisectURL != "" {
+				var err error
+
+				probe, err = kanaSite.GetURLProbe(flagBisectURL, flagBisectStatus, flagBisectContains)
+				if err != nil {
+					consoleOutput.Error(err)
+				}
+			} else if consoleOutput.JSON || consoleOutput.CI {
+				consoleOutput.Error(fmt.Errorf("use --url to check for the problem automatically when there isn't anyone to answer"))
+			}
+
+			result, err := kanaSite.BisectPlugins(probe, consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			if consoleOutput.JSON {
+				str, _ := json.Marshal(result)
+				fmt.Println(string(str))
+
+				return
+			}
+
+			if len(result.Plugins) == 1 {
+				consoleOutput.Success(fmt.Sprintf(
+					"Found it in %d steps. The problem is caused by %s. The plugins that were active have been activated again.",
+					result.Steps,
+					consoleOutput.Bold(result.Plugins[0])))
+
+				return
+			}
+
+			consoleOutput.Success(fmt.Sprintf(
+				"Found it in %d steps. The problem is caused by a conflict between %s. The plugins that were active have been activated again.",
+				result.Steps,
+				consoleOutput.Bold(strings.Join(result.Plugins, ", "))))
+		},
+		Args: cobra.NoArgs,
+	}
+
+	commandsRequiringSite = append(commandsRequiringSite, cmd.Use)
+
+	cmd.Flags().StringVar(
+		&flagBisectURL,
+		"url",
+		"",
+		"Check for the problem automatically by requesting this page, given as a path such as /shop/, instead of asking")
+	cmd.Flags().IntVar(&flagBisectStatus, "status", http.StatusOK, "The status the page returns when the problem is fixed")
+	cmd.Flags().StringVar(&flagBisectContains, "contains", "", "Text the page contains when the problem is fixed")
+
+	return cmd
+}
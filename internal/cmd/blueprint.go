@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/settings"
+	"github.com/ChrisWiegman/kana/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+var flagBlueprintDatabase bool
+
+func blueprint(consoleOutput *console.Console, kanaSite *site.Site, kanaSettings *settings.Settings) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "blueprint",
+		Short: "Save a site's config, plugins, theme and, optionally, database to a file that recreates it anywhere.",
+		Args:  cobra.NoArgs,
+	}
+
+	exportCmd := &cobra.Command{
+		Use:   "export [blueprint file]",
+		Short: "Save a blueprint of the current site.",
+		Run: func(cmd *cobra.Command, args []string) {
+			ensureSiteIsRunning(consoleOutput, kanaSite, "blueprint export")
+
+			file, err := kanaSite.ExportBlueprint(args, flagBlueprintDatabase, Version, consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			consoleOutput.Success(fmt.Sprintf("The blueprint has been saved to %s. Use `kana blueprint apply %s` to recreate the site.", file, file))
+		},
+		Args: cobra.MaximumNArgs(1),
+	}
+
+	applyCmd := &cobra.Command{
+		Use:   "apply <blueprint file>",
+		Short: "Recreate the current site from a blueprint, replacing its config and, if the blueprint has one, its database.",
+		Run: func(cmd *cobra.Command, args []string) {
+			blueprint, err := site.ReadBlueprint(args[0])
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			err = kanaSite.EnsureDocker(consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			if !flagForce && !kanaSettings.GetBool("isNew") {
+				confirmApply := consoleOutput.PromptConfirm(
+					fmt.Sprintf(
+						"Are you sure you want to apply %s to %s? %s",
+						consoleOutput.Bold(args[0]),
+						consoleOutput.Bold(consoleOutput.Blue(kanaSettings.Get("name"))),
+						consoleOutput.Bold(
+							consoleOutput.Yellow(
+								"The site's .kana.json file, and its database if the blueprint has one, will be replaced."))),
+					false)
+
+				if !confirmApply {
+					consoleOutput.Error(fmt.Errorf("blueprint canceled. No data has been changed"))
+				}
+			}
+
+			// Ctrl-C cancels the site starting, removing the containers already started, as it does for `kana start`
+			ctx, stopSignals := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stopSignals()
+
+			err = kanaSite.ApplyBlueprint(ctx, &blueprint, consoleOutput)
+			if err != nil {
+				if errors.Is(err, site.ErrStartInterrupted) && kanaSettings.GetBool("isNew") {
+					remError := os.RemoveAll(kanaSettings.Get("siteDirectory"))
+					if remError != nil {
+						consoleOutput.Error(remError)
+					}
+				}
+
+				consoleOutput.Error(err)
+			}
+
+			consoleOutput.Success(
+				fmt.Sprintf(
+					"The blueprint has been applied. Your site, %s, is running at %s.",
+					consoleOutput.Bold(consoleOutput.Blue(kanaSettings.Get("name"))),
+					kanaSettings.GetURL()))
+		},
+		Args: cobra.ExactArgs(1),
+	}
+
+	commandsRequiringSite = append(commandsRequiringSite, exportCmd.Use, applyCmd.Use)
+	commandsLockingSite = append(commandsLockingSite, applyCmd)
+
+	exportCmd.Flags().BoolVar(&flagBlueprintDatabase, "database", false, "Include a dump of the site's database in the blueprint.")
+	applyCmd.Flags().BoolVar(&flagForce, "force", false, "Apply the blueprint without prompting for confirmation.")
+
+	cmd.AddCommand(
+		applyCmd,
+		exportCmd,
+	)
+
+	return cmd
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/settings"
+	"github.com/ChrisWiegman/kana/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+func clone(consoleOutput *console.Console, kanaSite *site.Site, kanaSettings *settings.Settings) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "clone <source> <target>",
+		Short: "Copy an existing site, with its files, database and config, to a new site.",
+		Run: func(cmd *cobra.Command, args []string) {
+			if kanaSettings.GetBool("isNew") {
+				consoleOutput.Error(fmt.Errorf("the site %s could not be found. Use `kana list` to see all sites", args[0]))
+			}
+
+			ensureSiteIsRunning(consoleOutput, kanaSite, "clone")
+
+			// Ctrl-C stops the new site starting, after which it is removed rather than being left half set up
+			ctx, stopSignals := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stopSignals()
+
+			cloneName, cloneURL, err := kanaSite.CloneSite(ctx, args[1], consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			consoleOutput.Success(
+				fmt.Sprintf(
+					"%s has been cloned to %s. Use `kana start --name %s` to start it again after it has been stopped.",
+					consoleOutput.Bold(consoleOutput.Blue(kanaSettings.Get("name"))),
+					cloneURL,
+					cloneName))
+		},
+		Args: cobra.ExactArgs(2),
+	}
+
+	commandsRequiringSite = append(commandsRequiringSite, cmd.Use)
+	commandsLockingSite = append(commandsLockingSite, cmd)
+
+	return cmd
+}
+
+// parseCloneSourceFlag uses the source site of `kana clone` as the site the command runs on, as if it had been given
+// with the name flag.
+func parseCloneSourceFlag(args []string, cmd *cobra.Command) error {
+	if len(args) == 0 || cmd.Flags().Changed("name") {
+		return nil
+	}
+
+	err := cmd.Flag("name").Value.Set(args[0])
+	if err != nil {
+		return err
+	}
+
+	cmd.Flag("name").Changed = true
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+func composer(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "composer",
+		Short: "Run a Composer command in the current project's directory.",
+		Run: func(cmd *cobra.Command, args []string) {
+			err := kanaSite.EnsureDocker(consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			code, err := kanaSite.Composer(removeNameFlag(args, cmd), consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			// Pass Composer's exit code through so it can be used in scripts
+			if code != 0 {
+				os.Exit(int(code))
+			}
+		},
+		Args: cobra.ArbitraryArgs,
+	}
+
+	cmd.DisableFlagParsing = true
+
+	return cmd
+}
@@ -1,12 +1,17 @@
 package cmd
 
 import (
+	"fmt"
+
 	"github.com/ChrisWiegman/kana/internal/console"
 	"github.com/ChrisWiegman/kana/internal/settings"
 
 	"github.com/spf13/cobra"
 )
 
+var flagConfigAll bool
+var flagConfigLocal bool
+
 func config(consoleOutput *console.Console, kanaSettings *settings.Settings) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "config",
@@ -31,5 +36,80 @@ func config(consoleOutput *console.Console, kanaSettings *settings.Settings) *co
 		Args: cobra.RangeArgs(0, 2),
 	}
 
+	editCmd := &cobra.Command{
+		Use:   "edit",
+		Short: "Open the global config, or the site config with --local, in your editor and validate it on save.",
+		Run: func(cmd *cobra.Command, args []string) {
+			settingsType := "global"
+
+			if flagConfigLocal {
+				settingsType = "local"
+			}
+
+			changed, err := kanaSettings.EditConfig(settingsType, consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			if !changed {
+				consoleOutput.Println("No changes were made.")
+				return
+			}
+
+			consoleOutput.Success(fmt.Sprintf("The %s config has been saved.", settingsType))
+		},
+		Args: cobra.NoArgs,
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the global and site values of each setting, or use --all to see where each current value came from.",
+		Run: func(cmd *cobra.Command, args []string) {
+			if flagConfigAll {
+				settings.ListAllSettings(kanaSettings, consoleOutput)
+				return
+			}
+
+			settings.ListSettings(kanaSettings, consoleOutput)
+		},
+		Args: cobra.NoArgs,
+	}
+
+	diffCmd := &cobra.Command{
+		Use:   "diff [site]",
+		Short: "Show the settings the current site overrides from the global config, or how its settings differ from another site.",
+		Run: func(cmd *cobra.Command, args []string) {
+			otherSite := ""
+
+			if len(args) == 1 {
+				otherSite = args[0]
+			}
+
+			err := settings.DiffSettings(kanaSettings, otherSite, consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+		},
+		Args: cobra.RangeArgs(0, 1),
+	}
+
+	editCmd.Flags().BoolVarP(&flagConfigLocal, "local", "l", false, "Edit the config for the current site instead of the global config.")
+	listCmd.Flags().BoolVarP(
+		&flagConfigAll,
+		"all",
+		"a",
+		false,
+		"Show every setting with its current value, default, description and whether it was set by default, globally, by the site, "+
+			"by an environment variable or by a flag.")
+
+	// Only the site's config, edited with --local, is locked
+	commandsLockingSite = append(commandsLockingSite, editCmd)
+
+	cmd.AddCommand(
+		diffCmd,
+		editCmd,
+		listCmd,
+	)
+
 	return cmd
 }
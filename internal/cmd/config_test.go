@@ -14,6 +14,9 @@ func TestConfig(t *testing.T) {
 		{
 			Description: "Test the config command with json output",
 			Command:     []string{"config", "--output-json"}},
+		{
+			Description: "Test the config list command with all details",
+			Command:     []string{"config", "list", "--all"}},
 		{
 			Description: "Retrieve the PHP value from the config command",
 			Command:     []string{"config", "php"}},
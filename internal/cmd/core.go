@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+var flagCoreNoSnapshot bool
+
+func core(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "core",
+		Short: "Manage the site's version of WordPress.",
+		Args:  cobra.NoArgs,
+	}
+
+	commandsRequiringSite = append(commandsRequiringSite, cmd.Use)
+
+	rollbackCmd := &cobra.Command{
+		Use:   "rollback <version>",
+		Short: "Install an older version of WordPress, after taking a snapshot, and verify its files.",
+		Run: func(cmd *cobra.Command, args []string) {
+			ensureSiteIsRunning(consoleOutput, kanaSite, "core")
+
+			rollback, err := kanaSite.RollbackCore(args[0], !flagCoreNoSnapshot, Version, consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			if consoleOutput.JSON {
+				str, _ := json.Marshal(rollback)
+				fmt.Println(string(str))
+
+				return
+			}
+
+			if rollback.Snapshot != "" {
+				consoleOutput.Println(fmt.Sprintf(
+					"A snapshot taken before rolling back was saved to %s. Restore it with 'kana import --force %s'.",
+					rollback.Snapshot,
+					rollback.Snapshot))
+			}
+
+			consoleOutput.Success(fmt.Sprintf(
+				"WordPress has been changed from %s to %s and its files have been verified.",
+				rollback.Before,
+				rollback.After))
+		},
+		Args: cobra.ExactArgs(1),
+	}
+
+	rollbackCmd.Flags().BoolVar(
+		&flagCoreNoSnapshot,
+		"no-snapshot",
+		false,
+		"Don't take a snapshot of the database, plugins and themes before rolling back")
+
+	commandsLockingSite = append(commandsLockingSite, rollbackCmd)
+
+	cmd.AddCommand(rollbackCmd)
+
+	return cmd
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+func credentials(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "credentials",
+		Short: "Show the login details for the admin user and any test users on the current site.",
+		Run: func(cmd *cobra.Command, args []string) {
+			ensureSiteIsRunning(consoleOutput, kanaSite, "credentials")
+
+			users, err := kanaSite.GetCredentials(consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			credentialsTable := console.NewTable(
+				console.TableColumn{Header: "Username"},
+				console.TableColumn{Header: "Password"},
+				console.TableColumn{Header: "Email"},
+				console.TableColumn{Header: "Role"})
+
+			for _, user := range users {
+				credentialsTable.AddRow(user.Username, user.Password, user.Email, user.Role)
+			}
+
+			consoleOutput.PrintTable(credentialsTable)
+		},
+		Args: cobra.NoArgs,
+	}
+
+	commandsRequiringSite = append(commandsRequiringSite, cmd.Use)
+
+	return cmd
+}
@@ -1,9 +1,13 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"time"
 
 	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/helpers"
 	"github.com/ChrisWiegman/kana/internal/site"
 
 	"github.com/spf13/cobra"
@@ -11,6 +15,8 @@ import (
 
 var flagPreserve bool
 var flagReplaceDomain string
+var flagExportPush bool
+var flagSnapshotForce bool
 
 func db(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
 	cmd := &cobra.Command{
@@ -41,6 +47,7 @@ func db(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
 	}
 
 	commandsRequiringSite = append(commandsRequiringSite, importCmd.Use)
+	commandsLockingSite = append(commandsLockingSite, importCmd)
 
 	exportCmd := &cobra.Command{
 		Use:   "export [sql file]",
@@ -56,6 +63,13 @@ func db(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
 				consoleOutput.Error(err)
 			}
 
+			if flagExportPush {
+				err = kanaSite.PushBackup(file)
+				if err != nil {
+					consoleOutput.Error(err)
+				}
+			}
+
 			consoleOutput.Success(fmt.Sprintf("Export complete. Your database has been exported to %s.", file))
 		},
 		Args: cobra.MaximumNArgs(1),
@@ -63,15 +77,167 @@ func db(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
 
 	commandsRequiringSite = append(commandsRequiringSite, exportCmd.Use)
 
+	shellCmd := &cobra.Command{
+		Use:   "shell",
+		Short: "Open a shell for the site's database, the MySQL client or the sqlite3 shell for SQLite sites",
+		Run: func(cmd *cobra.Command, args []string) {
+			ensureSiteIsRunning(consoleOutput, kanaSite, "db shell")
+
+			code, err := kanaSite.DatabaseShell(consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			os.Exit(int(code))
+		},
+		Args: cobra.NoArgs,
+	}
+
+	credentialsCmd := &cobra.Command{
+		Use:   "credentials",
+		Short: "Show the host, port, user and password to connect to the site's database with a database client",
+		Run: func(cmd *cobra.Command, args []string) {
+			ensureSiteIsRunning(consoleOutput, kanaSite, "db credentials")
+
+			databaseCredentials, err := kanaSite.GetDatabaseCredentials()
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			if consoleOutput.JSON {
+				str, _ := json.Marshal(databaseCredentials)
+				fmt.Println(string(str))
+
+				return
+			}
+
+			credentialsTable := console.NewTable(
+				console.TableColumn{Header: "Setting"},
+				console.TableColumn{Header: "Value"})
+
+			credentialsTable.AddRow("Host", databaseCredentials.Host)
+			credentialsTable.AddRow("Port", databaseCredentials.Port)
+			credentialsTable.AddRow("User", databaseCredentials.User)
+			credentialsTable.AddRow("Password", databaseCredentials.Password)
+			credentialsTable.AddRow("Database", databaseCredentials.Database)
+			credentialsTable.AddRow("Root password", databaseCredentials.RootPassword)
+			credentialsTable.AddRow("URL", databaseCredentials.URL())
+
+			consoleOutput.PrintTable(credentialsTable)
+		},
+		Args: cobra.NoArgs,
+	}
+
+	pathCmd := &cobra.Command{
+		Use:   "path",
+		Short: "Print the path of the site's SQLite database file to open it in any SQLite client",
+		Run: func(cmd *cobra.Command, args []string) {
+			databaseFile := kanaSite.GetSQLiteDatabaseFile()
+
+			_, err := os.Stat(databaseFile)
+			if err != nil {
+				consoleOutput.Error(fmt.Errorf("the site doesn't have a SQLite database. Use 'kana db shell' to open its MySQL database"))
+			}
+
+			consoleOutput.Println(databaseFile)
+		},
+		Args: cobra.NoArgs,
+	}
+
 	importCmd.Flags().BoolVarP(&flagPreserve, "preserve", "p", false, "Preserve the existing database (don't drop it before import)")
 	importCmd.Flags().StringVar(&flagReplaceDomain,
 		"replace-domain",
 		"",
 		"The old site domain to replace automatically with the development site domain")
 
+	exportCmd.Flags().BoolVar(&flagExportPush, "push", false, "Push the exported database to the configured remote backup target.")
+
 	cmd.AddCommand(
 		importCmd,
 		exportCmd,
+		credentialsCmd,
+		pathCmd,
+		shellCmd,
+		dbSnapshot(consoleOutput, kanaSite),
+	)
+
+	return cmd
+}
+
+func dbSnapshot(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Save named snapshots of the site's database and switch between them",
+		Args:  cobra.NoArgs,
+	}
+
+	saveCmd := &cobra.Command{
+		Use:   "save <name>",
+		Short: "Save the site's current database as a named snapshot",
+		Run: func(cmd *cobra.Command, args []string) {
+			ensureSiteIsRunning(consoleOutput, kanaSite, "db snapshot save")
+
+			snapshot, err := kanaSite.SaveDatabaseSnapshot(args[0], flagSnapshotForce, consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			consoleOutput.Success(
+				fmt.Sprintf("The database has been saved as %s. Use `kana db snapshot restore %s` to go back to it.", snapshot.Name, snapshot.Name))
+		},
+		Args: cobra.ExactArgs(1),
+	}
+
+	restoreCmd := &cobra.Command{
+		Use:   "restore <name>",
+		Short: "Replace the site's current database with a saved snapshot",
+		Run: func(cmd *cobra.Command, args []string) {
+			ensureSiteIsRunning(consoleOutput, kanaSite, "db snapshot restore")
+
+			err := kanaSite.RestoreDatabaseSnapshot(args[0], consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			consoleOutput.Success(fmt.Sprintf("The snapshot %s has been restored. Reload your site to see the changes.", args[0]))
+		},
+		Args: cobra.ExactArgs(1),
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the site's database snapshots along with their size and date",
+		Run: func(cmd *cobra.Command, args []string) {
+			snapshots, err := kanaSite.GetDatabaseSnapshots()
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			snapshotTable := console.NewTable(
+				console.TableColumn{Header: "Name"},
+				console.TableColumn{Header: "Size", Align: console.AlignRight},
+				console.TableColumn{Header: "Saved"})
+
+			for _, snapshot := range snapshots {
+				snapshotTable.AddRow(
+					snapshot.Name,
+					console.Cell{Value: snapshot.Size, Text: helpers.FormatFileSize(snapshot.Size)},
+					console.Cell{Value: snapshot.Created, Text: snapshot.Created.Format(time.DateTime)})
+			}
+
+			consoleOutput.PrintTable(snapshotTable)
+		},
+		Args: cobra.NoArgs,
+	}
+
+	commandsLockingSite = append(commandsLockingSite, saveCmd, restoreCmd)
+
+	saveCmd.Flags().BoolVar(&flagSnapshotForce, "force", false, "Replace an existing snapshot with the same name.")
+
+	cmd.AddCommand(
+		listCmd,
+		restoreCmd,
+		saveCmd,
 	)
 
 	return cmd
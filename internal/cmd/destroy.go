@@ -2,7 +2,7 @@ package cmd
 
 import (
 	"fmt"
-	"os"
+	"time"
 
 	"github.com/ChrisWiegman/kana/internal/console"
 	"github.com/ChrisWiegman/kana/internal/settings"
@@ -13,12 +13,23 @@ import (
 )
 
 var flagForce bool
+var flagDestroyBackup bool
 
 func destroy(consoleOutput *console.Console, kanaSite *site.Site, kanaSettings *settings.Settings) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "destroy",
 		Short: "Destroys the current WordPress site. This is a permanent change.",
 		Run: func(cmd *cobra.Command, args []string) {
+			if flagAll {
+				if flagDestroyBackup {
+					consoleOutput.Error(fmt.Errorf("--backup can only be used when destroying a single site"))
+				}
+
+				destroyAllSites(consoleOutput, kanaSite)
+
+				return
+			}
+
 			var confirmDestroy bool
 
 			if flagForce {
@@ -40,14 +51,35 @@ func destroy(consoleOutput *console.Console, kanaSite *site.Site, kanaSettings *
 					consoleOutput.Error(err)
 				}
 
+				// The backup is saved in the current folder as the site's own folder is about to be removed
+				if flagDestroyBackup {
+					if !kanaSite.IsSiteRunning() {
+						consoleOutput.Error(fmt.Errorf("the site must be running to back it up. Please run 'kana start' and try again"))
+					}
+
+					var backupFile string
+
+					backupFile, err = kanaSite.CreateFullBackup(".", Version, consoleOutput)
+					if err != nil {
+						consoleOutput.Error(err)
+					}
+
+					consoleOutput.Println(fmt.Sprintf("The site has been backed up to %s.", backupFile))
+				}
+
 				// Stop the WordPress site.
 				err = kanaSite.StopSite()
 				if err != nil {
 					consoleOutput.Error(err)
 				}
 
-				// Remove the site's folder in the config directory.
-				err = os.RemoveAll(kanaSettings.Get("siteDirectory"))
+				// Remove the site's folder in the config directory, or wherever its files were moved to.
+				err = kanaSettings.RemoveSiteDirectory()
+				if err != nil {
+					consoleOutput.Error(err)
+				}
+
+				err = kanaSettings.UnregisterSite(kanaSettings.Get("name"))
 				if err != nil {
 					consoleOutput.Error(err)
 				}
@@ -69,11 +101,62 @@ func destroy(consoleOutput *console.Console, kanaSite *site.Site, kanaSettings *
 	commandsRequiringSite = append(commandsRequiringSite, cmd.Use)
 
 	cmd.Flags().BoolVar(&flagForce, "force", false, "Force destruction of your site (doesn't require a prompt).")
+	cmd.Flags().BoolVar(&flagAll, "all", false, "Destroy every site.")
+	cmd.Flags().BoolVar(&flagDestroyBackup, "backup", false, "Save a full backup of the site to the current folder before destroying it.")
 	cmd.Flags().SetNormalizeFunc(aliasForceFlag)
+	addParallelFlag(cmd)
+
+	commandsLockingSite = append(commandsLockingSite, cmd)
 
 	return cmd
 }
 
+// destroyAllSites destroys every site, a few at a time, each as if `kana destroy --force` were run in its folder.
+func destroyAllSites(consoleOutput *console.Console, kanaSite *site.Site) {
+	ensureParallelFlag(consoleOutput)
+
+	sites, err := kanaSite.GetSiteList(false)
+	if err != nil {
+		consoleOutput.Error(err)
+	}
+
+	if len(sites) == 0 {
+		consoleOutput.Println("There are no sites to destroy.")
+
+		return
+	}
+
+	if !flagForce {
+		confirmDestroy := consoleOutput.PromptConfirm(
+			fmt.Sprintf(
+				"Are you sure you want to destroy all %d sites? %s",
+				len(sites),
+				consoleOutput.Bold(
+					consoleOutput.Yellow(
+						"This operation is destructive and cannot be undone."))),
+			false)
+
+		if !confirmDestroy {
+			consoleOutput.Error(fmt.Errorf("site destruction canceled. No data has been lost"))
+		}
+	}
+
+	err = kanaSite.EnsureDocker(consoleOutput)
+	if err != nil {
+		consoleOutput.Error(err)
+	}
+
+	start := time.Now()
+	results := kanaSite.RunKanaForSites(sites, flagParallel, printBatchProgress(consoleOutput, "Destroyed"), "destroy", "--force")
+
+	err = kanaSite.StopUnusedSharedContainers()
+	if err != nil {
+		consoleOutput.Warn(fmt.Sprintf("Unable to stop the containers shared by every site: %s", err))
+	}
+
+	printBatchSummary(consoleOutput, results, time.Since(start), "site", "Destroyed")
+}
+
 func aliasForceFlag(f *pflag.FlagSet, name string) pflag.NormalizedName {
 	if name == "confirm-destroy" {
 		name = "force"
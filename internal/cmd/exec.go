@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+var flagExecContainer string
+var flagExecRoot bool
+
+func exec(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "exec -- <command>",
+		Short: "Run an arbitrary command in one of the site's containers.",
+		Run: func(cmd *cobra.Command, args []string) {
+			err := kanaSite.EnsureDocker(consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			if !kanaSite.IsSiteRunning() {
+				consoleOutput.Error(fmt.Errorf("the exec command only works on a running site. Please run 'kana start' to start the site"))
+			}
+
+			code, err := kanaSite.Exec(flagExecContainer, args, flagExecRoot)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			// Pass the command's exit code through so exec can be used in scripts
+			if code != 0 {
+				os.Exit(code)
+			}
+		},
+		Args: cobra.MinimumNArgs(1),
+	}
+
+	commandsRequiringSite = append(commandsRequiringSite, cmd.Use)
+
+	cmd.Flags().StringVarP(
+		&flagExecContainer,
+		"container",
+		"c",
+		"wordpress",
+		"The container to run the command in. Can be database, mailpit, redis or wordpress.")
+	cmd.Flags().BoolVar(&flagExecRoot, "root", false, "Run the command as the root user.")
+
+	return cmd
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"path/filepath"
+	"strings"
 
 	"github.com/ChrisWiegman/kana/internal/console"
 	"github.com/ChrisWiegman/kana/internal/settings"
@@ -11,10 +12,13 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var flagExportWhat []string
+var flagExportArchivePush bool
+
 func export(consoleOutput *console.Console, kanaSite *site.Site, kanaSettings *settings.Settings) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "export",
-		Short: "Export the current config to a .kana.json file to save with your repo.",
+		Use:   "export [archive file]",
+		Short: "Export the current config to a .kana.json file to save with your repo, or parts of the site to an archive.",
 		Run: func(cmd *cobra.Command, args []string) {
 			err := kanaSite.EnsureDocker(consoleOutput)
 			if err != nil {
@@ -25,6 +29,32 @@ func export(consoleOutput *console.Console, kanaSite *site.Site, kanaSettings *s
 				consoleOutput.Error(fmt.Errorf("the export command only works on a running site.  Please run 'kana start' to start the site"))
 			}
 
+			if len(flagExportWhat) > 0 {
+				file, err := kanaSite.ExportSite(flagExportWhat, args, Version, consoleOutput)
+				if err != nil {
+					consoleOutput.Error(err)
+				}
+
+				if flagExportArchivePush {
+					err = kanaSite.PushBackup(file)
+					if err != nil {
+						consoleOutput.Error(err)
+					}
+				}
+
+				consoleOutput.Success(fmt.Sprintf("Export complete. The site's %s have been exported to %s.", strings.Join(flagExportWhat, ", "), file))
+
+				return
+			}
+
+			if len(args) > 0 {
+				consoleOutput.Error(fmt.Errorf("an archive file can only be given when exporting parts of the site with --what"))
+			}
+
+			if flagExportArchivePush {
+				consoleOutput.Error(fmt.Errorf("only archives can be pushed. Use --what to export parts of the site to an archive"))
+			}
+
 			err = kanaSite.ExportSiteConfig(consoleOutput)
 			if err != nil {
 				consoleOutput.Error(err)
@@ -35,12 +65,17 @@ func export(consoleOutput *console.Console, kanaSite *site.Site, kanaSettings *s
 					"Your config has been exported to %s",
 					filepath.Join(kanaSettings.Get("workingDirectory"), ".kana.json")))
 		},
-		Args: cobra.ArbitraryArgs,
+		Args: cobra.MaximumNArgs(1),
 	}
 
 	commandsRequiringSite = append(commandsRequiringSite, cmd.Use)
 
-	cmd.DisableFlagParsing = true
+	cmd.Flags().StringSliceVar(
+		&flagExportWhat,
+		"what",
+		[]string{},
+		fmt.Sprintf("Export parts of the site to a zip archive instead of its config. Any of %s", strings.Join(site.ExportParts, ", ")))
+	cmd.Flags().BoolVar(&flagExportArchivePush, "push", false, "Push the exported archive to the configured remote backup target.")
 
 	return cmd
 }
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+var flagExtensionActivate bool
+
+func plugins(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
+	return extensionCommand(consoleOutput, kanaSite, "plugin")
+}
+
+func themes(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
+	return extensionCommand(consoleOutput, kanaSite, "theme")
+}
+
+// extensionCommand builds the list and management commands shared by plugins and themes.
+func extensionCommand(consoleOutput *console.Console, kanaSite *site.Site, extensionType string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   extensionType + "s",
+		Short: fmt.Sprintf("List the %ss installed in the site along with their status, version and available updates.", extensionType),
+		Run: func(cmd *cobra.Command, args []string) {
+			ensureSiteIsRunning(consoleOutput, kanaSite, cmd.Use)
+
+			extensions, err := kanaSite.GetExtensions(extensionType, consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			extensionTable := console.NewTable(
+				console.TableColumn{Header: "Name"},
+				console.TableColumn{Header: "Status"},
+				console.TableColumn{Header: "Version"},
+				console.TableColumn{Header: "Update"})
+
+			for _, extension := range extensions {
+				update := console.Cell{Value: extension.UpdateVersion, Text: extension.Update}
+
+				if extension.Update == "available" {
+					update.Text = extension.UpdateVersion
+					update.Style = consoleOutput.Yellow
+				}
+
+				extensionTable.AddRow(extension.Name, extension.Status, extension.Version, update)
+			}
+
+			consoleOutput.PrintTable(extensionTable)
+		},
+		Args: cobra.NoArgs,
+	}
+
+	commandsRequiringSite = append(commandsRequiringSite, cmd.Use)
+
+	actions := []struct {
+		name  string
+		short string
+	}{
+		{"activate", fmt.Sprintf("Activate one or more installed %ss.", extensionType)},
+		{"deactivate", fmt.Sprintf("Deactivate one or more active %ss.", extensionType)},
+		{"install", fmt.Sprintf("Install one or more %ss from WordPress.org, a URL or a zip file in the site directory.", extensionType)},
+		{"remove", fmt.Sprintf("Remove one or more %ss from the site.", extensionType)},
+	}
+
+	for _, action := range actions {
+		// Themes can only be switched, not deactivated
+		if action.name == "deactivate" && extensionType == "theme" {
+			continue
+		}
+
+		actionCmd := &cobra.Command{
+			Use:   fmt.Sprintf("%s <%s>...", action.name, extensionType),
+			Short: action.short,
+			Run: func(cmd *cobra.Command, args []string) {
+				ensureSiteIsRunning(consoleOutput, kanaSite, cmd.Parent().Use)
+
+				err := kanaSite.ManageExtensions(extensionType, cmd.Name(), args, flagExtensionActivate, consoleOutput)
+				if err != nil {
+					consoleOutput.Error(err)
+				}
+
+				consoleOutput.Success(fmt.Sprintf("The %s command completed for: %s.", cmd.Name(), strings.Join(args, ", ")))
+			},
+			Args: cobra.MinimumNArgs(1),
+		}
+
+		if action.name == "install" {
+			actionCmd.Flags().BoolVar(&flagExtensionActivate, "activate", false, fmt.Sprintf("Activate the %s after installing it.", extensionType))
+		}
+
+		commandsRequiringSite = append(commandsRequiringSite, actionCmd.Use)
+
+		cmd.AddCommand(actionCmd)
+	}
+
+	return cmd
+}
+
+// ensureSiteIsRunning exits with an error if Docker or the current site isn't running.
+func ensureSiteIsRunning(consoleOutput *console.Console, kanaSite *site.Site, commandName string) {
+	err := kanaSite.EnsureDocker(consoleOutput)
+	if err != nil {
+		consoleOutput.Error(err)
+	}
+
+	if !kanaSite.IsSiteRunning() {
+		consoleOutput.Error(
+			fmt.Errorf("the %s command only works on a running site. Please run 'kana start' to start the site", commandName))
+	}
+}
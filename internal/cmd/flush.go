@@ -1,41 +1,42 @@
 package cmd
 
 import (
-	"fmt"
-
 	"github.com/ChrisWiegman/kana/internal/console"
 	"github.com/ChrisWiegman/kana/internal/site"
 
 	"github.com/spf13/cobra"
 )
 
+var flushTargetMessages = map[string]string{
+	"cache":    "The object cache and transients have been flushed.",
+	"rewrites": "The rewrite rules have been flushed.",
+	"opcache":  "The opcache has been flushed.",
+	"all":      "The object cache, transients, rewrite rules and opcache have been flushed.",
+}
+
 func flush(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "flush",
-		Short: "Flushes the cache and deletes all transients.",
+		Use:   "flush [cache|rewrites|opcache|all]",
+		Short: "Flushes the object cache and transients, the rewrite rules, the opcache or all of them.",
 		Run: func(cmd *cobra.Command, args []string) {
-			err := kanaSite.EnsureDocker(consoleOutput)
-			if err != nil {
-				consoleOutput.Error(err)
-			}
+			ensureSiteIsRunning(consoleOutput, kanaSite, "flush")
 
-			commands := [][]string{
-				{"cache", "flush"},
-				{"transient", "delete", "--all"},
-			}
+			// Flushing the cache was the only thing flush did before it had targets
+			target := "cache"
 
-			for _, command := range commands {
-				var code int64
+			if len(args) == 1 {
+				target = args[0]
+			}
 
-				code, _, err = kanaSite.WPCli(command, false, consoleOutput)
-				if code != 0 || err != nil {
-					consoleOutput.Error(fmt.Errorf("unable to complete flush"))
-				}
+			err := kanaSite.Flush(target, consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
 			}
 
-			consoleOutput.Success("Cache and transients have been successfully flushed")
+			consoleOutput.Success(flushTargetMessages[target])
 		},
-		Args: cobra.NoArgs,
+		Args:      cobra.MatchAll(cobra.MaximumNArgs(1), cobra.OnlyValidArgs),
+		ValidArgs: site.FlushTargets,
 	}
 
 	commandsRequiringSite = append(commandsRequiringSite, cmd.Use)
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+var flagHistoryLimit int
+var flagHistoryClear bool
+
+func historyCommand(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Show a timeline of how the site got into its current state, such as when it was started, imported or had plugins installed.",
+		Run: func(cmd *cobra.Command, args []string) {
+			if flagHistoryClear {
+				err := kanaSite.ClearHistory()
+				if err != nil {
+					consoleOutput.Error(err)
+				}
+
+				consoleOutput.Success("The site's history has been cleared.")
+
+				return
+			}
+
+			entries, err := kanaSite.GetHistory(flagHistoryLimit)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			if len(entries) == 0 && !consoleOutput.JSON {
+				consoleOutput.Println("The site has no history yet.")
+
+				return
+			}
+
+			historyTable := console.NewTable(
+				console.TableColumn{Header: "Time"},
+				console.TableColumn{Header: "Event"},
+				console.TableColumn{Header: "Details", MaxWidth: 80})
+
+			for _, entry := range entries {
+				historyTable.AddRow(
+					console.Cell{Value: entry.Time, Text: entry.Time.Local().Format(time.DateTime)},
+					entry.Event,
+					entry.Details)
+			}
+
+			consoleOutput.PrintTable(historyTable)
+		},
+		Args: cobra.NoArgs,
+	}
+
+	commandsRequiringSite = append(commandsRequiringSite, cmd.Use)
+
+	cmd.Flags().IntVar(&flagHistoryLimit, "limit", 0, "Only show this many of the newest events. 0 shows them all")
+	cmd.Flags().BoolVar(&flagHistoryClear, "clear", false, "Delete the site's history")
+
+	return cmd
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+var flagImagesRestart bool
+
+func images(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "images",
+		Short: "Manage the Docker images used by running sites.",
+		Args:  cobra.NoArgs,
+	}
+
+	updateCmd := &cobra.Command{
+		Use:   "update",
+		Short: "Download the newest version of every image used by running sites and report the containers left on older versions.",
+		Run: func(cmd *cobra.Command, args []string) {
+			ensureParallelFlag(consoleOutput)
+
+			err := kanaSite.EnsureDocker(consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			report, err := kanaSite.UpdateImages(flagImagesRestart, flagParallel, printBatchProgress(consoleOutput, "Downloaded"), consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			if consoleOutput.JSON {
+				str, _ := json.Marshal(report)
+				fmt.Println(string(str))
+
+				return
+			}
+
+			if len(report.Images) == 0 {
+				consoleOutput.Println("There are no running sites to update the images of.")
+
+				return
+			}
+
+			imagesTable := console.NewTable(
+				console.TableColumn{Header: "Image"},
+				console.TableColumn{Header: "Status"},
+				console.TableColumn{Header: "Sites", MaxWidth: 60})
+
+			for _, image := range report.Images {
+				status := console.Cell{Value: image.Status}
+
+				switch image.Status {
+				case "updated":
+					status.Style = consoleOutput.Green
+				case "failed":
+					status.Style = consoleOutput.Yellow
+				}
+
+				imagesTable.AddRow(image.Image, status, strings.Join(image.Sites, ", "))
+			}
+
+			consoleOutput.PrintTable(imagesTable)
+
+			for _, image := range report.Images {
+				if image.Error != "" {
+					consoleOutput.Warn(fmt.Sprintf("Unable to update %s: %s", image.Image, image.Error))
+				}
+			}
+
+			if len(report.Stale) == 0 {
+				consoleOutput.Success("Every running container is using the newest version of its image.")
+
+				return
+			}
+
+			staleTable := console.NewTable(
+				console.TableColumn{Header: "Site"},
+				console.TableColumn{Header: "Container"},
+				console.TableColumn{Header: "Image"},
+				console.TableColumn{Header: "Restarted"})
+
+			for _, stale := range report.Stale {
+				staleTable.AddRow(stale.Site, stale.Container, stale.Image, stale.Restarted)
+			}
+
+			consoleOutput.PrintTable(staleTable)
+
+			if flagImagesRestart {
+				consoleOutput.Success(fmt.Sprintf("%d container(s) have been recreated from their new images.", len(report.Stale)))
+
+				return
+			}
+
+			consoleOutput.Warn(fmt.Sprintf(
+				"%d container(s) are running older images. Run 'kana images update --restart' to recreate them.",
+				len(report.Stale)))
+		},
+		Args: cobra.NoArgs,
+	}
+
+	updateCmd.Flags().BoolVar(
+		&flagImagesRestart,
+		"restart",
+		false,
+		"Recreate the containers running older images from their new images")
+	addParallelFlag(updateCmd)
+
+	commandsLockingSite = append(commandsLockingSite, updateCmd)
+
+	cmd.AddCommand(updateCmd)
+
+	return cmd
+}
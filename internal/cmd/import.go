@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+var flagImportForce bool
+
+func importCommand(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import <archive file>",
+		Short: "Import an archive created with 'kana export --what' into the current site.",
+		Run: func(cmd *cobra.Command, args []string) {
+			err := kanaSite.EnsureDocker(consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			if !kanaSite.IsSiteRunning() {
+				consoleOutput.Error(fmt.Errorf("the import command only works on a running site.  Please run 'kana start' to start the site"))
+			}
+
+			manifest, err := kanaSite.ImportSite(args[0], flagImportForce, consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			consoleOutput.Success(
+				fmt.Sprintf("Import complete. The site's %s have been imported from %s.", strings.Join(manifest.Parts, ", "), args[0]))
+
+			for _, part := range manifest.Parts {
+				if part == "settings" {
+					consoleOutput.Println("The site's config has changed. Run 'kana stop' and 'kana start' to apply it.")
+				}
+			}
+		},
+		Args: cobra.ExactArgs(1),
+	}
+
+	commandsRequiringSite = append(commandsRequiringSite, cmd.Use)
+
+	cmd.Flags().BoolVar(
+		&flagImportForce,
+		"force",
+		false,
+		"Import the archive even if it came from a site running a different version of WordPress or PHP or with different plugins")
+
+	commandsLockingSite = append(commandsLockingSite, cmd)
+
+	return cmd
+}
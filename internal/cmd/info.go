@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+func info(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "info",
+		Short: "Shows the site's URLs, credentials, versions, database, mounts, services and directories on one screen.",
+		Run: func(cmd *cobra.Command, args []string) {
+			err := kanaSite.EnsureDocker(consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			siteInfo, err := kanaSite.GetInfo(consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			if consoleOutput.JSON {
+				str, _ := json.Marshal(siteInfo)
+				fmt.Println(string(str))
+
+				return
+			}
+
+			infoTable := console.NewTable(
+				console.TableColumn{Header: "Setting"},
+				console.TableColumn{Header: "Value"})
+
+			running := console.Cell{Value: "stopped"}
+
+			if siteInfo.Running {
+				running = console.Cell{Value: "running", Style: consoleOutput.Green}
+			}
+
+			infoTable.AddRow("Name", siteInfo.Name)
+			infoTable.AddRow("Type", siteInfo.Type)
+			infoTable.AddRow("Status", running)
+			infoTable.AddRow("URL", siteInfo.URLs.Site)
+			infoTable.AddRow("Admin URL", siteInfo.URLs.Admin)
+
+			if siteInfo.URLs.PHPMyAdmin != "" {
+				infoTable.AddRow("phpMyAdmin URL", siteInfo.URLs.PHPMyAdmin)
+			}
+
+			if siteInfo.URLs.Mailpit != "" {
+				infoTable.AddRow("Mailpit URL", siteInfo.URLs.Mailpit)
+			}
+
+			infoTable.AddRow("Admin user", siteInfo.Admin.Username)
+			infoTable.AddRow("Admin password", siteInfo.Admin.Password)
+			infoTable.AddRow("Admin email", siteInfo.Admin.Email)
+			infoTable.AddRow("PHP version", siteInfo.PHP)
+
+			if siteInfo.WordPress != "" {
+				infoTable.AddRow("WordPress version", siteInfo.WordPress)
+			}
+
+			database := siteInfo.Database.Engine
+
+			if siteInfo.Database.Version != "" {
+				database = fmt.Sprintf("%s %s", database, siteInfo.Database.Version)
+			}
+
+			infoTable.AddRow("Database", database)
+
+			if siteInfo.Database.File != "" {
+				infoTable.AddRow("Database file", siteInfo.Database.File)
+			}
+
+			if siteInfo.Database.Credentials != nil {
+				infoTable.AddRow("Database URL", siteInfo.Database.Credentials.URL())
+			}
+
+			for _, project := range siteInfo.Projects {
+				infoTable.AddRow(fmt.Sprintf("Mounted %s", project.Type), fmt.Sprintf("%s (%s)", project.Name, project.Path))
+			}
+
+			services := []string{}
+
+			for _, service := range siteInfo.Services {
+				if service.Status == "running" {
+					services = append(services, service.Service)
+				}
+			}
+
+			if len(services) > 0 {
+				infoTable.AddRow("Running services", strings.Join(services, ", "))
+			}
+
+			infoTable.AddRow("Working directory", siteInfo.Directories.Working)
+			infoTable.AddRow("Site directory", siteInfo.Directories.Site)
+			infoTable.AddRow("WordPress directory", siteInfo.Directories.WordPress)
+			infoTable.AddRow("Logs directory", siteInfo.Directories.Logs)
+			infoTable.AddRow("Backups directory", siteInfo.Directories.Backups)
+
+			consoleOutput.PrintTable(infoTable)
+		},
+		Args: cobra.NoArgs,
+	}
+
+	commandsRequiringSite = append(commandsRequiringSite, cmd.Use)
+
+	return cmd
+}
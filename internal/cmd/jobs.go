@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+var flagJobsRun bool
+var flagJobsRetryFailed bool
+
+func jobs(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "jobs",
+		Short: "List the site's pending and failed Action Scheduler actions and its wp-cron events.",
+		Run: func(cmd *cobra.Command, args []string) {
+			ensureSiteIsRunning(consoleOutput, kanaSite, cmd.Use)
+
+			if flagJobsRetryFailed {
+				retried, err := kanaSite.RetryFailedJobs(consoleOutput)
+				if err != nil {
+					consoleOutput.Error(err)
+				}
+
+				consoleOutput.Println(fmt.Sprintf("Queued %d failed action(s) to run again.", retried))
+			}
+
+			if flagJobsRun {
+				consoleOutput.Println("Running the due wp-cron events and pending Action Scheduler actions.")
+
+				err := kanaSite.RunJobs(consoleOutput)
+				if err != nil {
+					consoleOutput.Error(err)
+				}
+			}
+
+			siteJobs, err := kanaSite.GetJobs(consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			jobsTable := console.NewTable(
+				console.TableColumn{Header: "Source"},
+				console.TableColumn{Header: "ID"},
+				console.TableColumn{Header: "Hook", MaxWidth: 50},
+				console.TableColumn{Header: "Status"},
+				console.TableColumn{Header: "Group"},
+				console.TableColumn{Header: "Scheduled"},
+				console.TableColumn{Header: "Recurrence", MaxWidth: 30})
+
+			for _, job := range siteJobs {
+				status := console.Cell{Value: job.Status, Text: job.Status}
+
+				if job.Status == "failed" {
+					status.Style = consoleOutput.Yellow
+				}
+
+				jobsTable.AddRow(job.Source, job.ID, job.Hook, status, job.Group, job.Scheduled, job.Recurrence)
+			}
+
+			consoleOutput.PrintTable(jobsTable)
+		},
+		Args: cobra.NoArgs,
+	}
+
+	commandsRequiringSite = append(commandsRequiringSite, cmd.Use)
+
+	cmd.Flags().BoolVar(&flagJobsRun, "run", false, "Run the due wp-cron events and pending actions before listing them")
+	cmd.Flags().BoolVar(&flagJobsRetryFailed, "retry-failed", false, "Queue the failed actions to run again before listing them")
+
+	return cmd
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/settings"
+	"github.com/ChrisWiegman/kana/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+func link(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "link [site]",
+		Short: "Link the current directory to an existing site or, without a site, show the site it is linked to.",
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) == 0 {
+				showLink(consoleOutput, kanaSite)
+				return
+			}
+
+			err := kanaSite.EnsureDocker(consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			// The site being linked to is changed too, not just the current directory's site
+			linkedSiteLock := lockOtherSite(args[0], cmd, consoleOutput, kanaSite)
+			defer linkedSiteLock.Release() //nolint:errcheck
+
+			linkInfo, err := kanaSite.LinkSite(args[0])
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			if consoleOutput.JSON {
+				str, _ := json.Marshal(linkInfo)
+
+				fmt.Println(string(str))
+
+				return
+			}
+
+			consoleOutput.Success(
+				fmt.Sprintf(
+					"The current directory is now linked to %s. Use `kana start` to start it.",
+					consoleOutput.Bold(consoleOutput.Blue(linkInfo.Name))))
+		},
+		Args: cobra.MaximumNArgs(1),
+	}
+
+	commandsLockingSite = append(commandsLockingSite, cmd)
+
+	return cmd
+}
+
+func showLink(consoleOutput *console.Console, kanaSite *site.Site) {
+	linkInfo, err := kanaSite.GetLinkInfo()
+	if err != nil {
+		consoleOutput.Error(err)
+	}
+
+	if consoleOutput.JSON {
+		str, _ := json.Marshal(linkInfo)
+
+		fmt.Println(string(str))
+
+		return
+	}
+
+	if !linkInfo.Linked {
+		consoleOutput.Println("The current directory is not linked to a site. Use `kana link <site>` to link it to an existing site.")
+		return
+	}
+
+	consoleOutput.Println(fmt.Sprintf("The current directory is linked to %s.", consoleOutput.Bold(consoleOutput.Blue(linkInfo.Name))))
+}
+
+func unlink(consoleOutput *console.Console, kanaSite *site.Site, kanaSettings *settings.Settings) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "unlink",
+		Short: "Unlink the current directory from its site. The site and the files in the directory are kept.",
+		Run: func(cmd *cobra.Command, args []string) {
+			err := kanaSite.EnsureDocker(consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			err = kanaSite.UnlinkSite()
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			consoleOutput.Success(
+				fmt.Sprintf(
+					"The current directory has been unlinked from %s. Use `kana start --name %s` to use the site on its own.",
+					consoleOutput.Bold(consoleOutput.Blue(kanaSettings.Get("name"))),
+					kanaSettings.Get("name")))
+		},
+		Args: cobra.NoArgs,
+	}
+
+	commandsRequiringSite = append(commandsRequiringSite, cmd.Use)
+	commandsLockingSite = append(commandsLockingSite, cmd)
+
+	return cmd
+}
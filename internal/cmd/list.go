@@ -1,15 +1,11 @@
 package cmd
 
 import (
-	"encoding/json"
-	"fmt"
 	"os"
-	"strconv"
 
 	"github.com/ChrisWiegman/kana/internal/console"
 	"github.com/ChrisWiegman/kana/internal/site"
 
-	"github.com/aquasecurity/table"
 	"github.com/spf13/cobra"
 )
 
@@ -30,33 +26,42 @@ func list(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
 				consoleOutput.Error(err)
 			}
 
-			if consoleOutput.JSON {
-				if len(sites) > 0 {
-					str, _ := json.Marshal(sites)
+			siteTable := console.NewTable(
+				console.TableColumn{Header: "Name"},
+				console.TableColumn{Header: "Path"},
+				console.TableColumn{Header: "Type"},
+				console.TableColumn{Header: "Activate"},
+				console.TableColumn{Header: "Running"})
 
-					fmt.Println(string(str))
-				} else {
-					fmt.Println("[]")
+			for _, site := range sites {
+				path := console.Cell{Value: site.Path}
+				_, err := os.Stat(site.Path)
+				if err != nil && os.IsNotExist(err) {
+					path.Style = consoleOutput.Yellow
 				}
 
-				return
-			}
-
-			t := table.New(os.Stdout)
+				siteType := console.Cell{Value: site.Type}
 
-			t.SetHeaders("Name", "Path", "Running")
+				// Activation only applies to the plugins and themes being developed
+				activate := console.Cell{Value: site.Activate}
+				if site.Type == "site" {
+					activate.Text = "-"
+				}
 
-			for _, site := range sites {
-				path := site.Path
-				_, err := os.Stat(site.Path)
-				if err != nil && os.IsNotExist(err) {
-					path = consoleOutput.Yellow(path)
+				// Sites whose files were moved to a disk that isn't connected can't be read until it is
+				if site.Unavailable {
+					siteType = console.Cell{Value: "unavailable", Style: consoleOutput.Yellow}
+					activate.Text = "-"
 				}
 
-				t.AddRow(site.Name, path, strconv.FormatBool(site.Running))
+				siteTable.AddRow(site.Name, path, siteType, activate, site.Running)
 			}
 
-			t.Render()
+			consoleOutput.PrintTable(siteTable)
+
+			if dockerIsRunning {
+				kanaSite.MaybeResumeSites("", consoleOutput)
+			}
 		},
 		Args: cobra.NoArgs,
 	}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/docker"
+	"github.com/ChrisWiegman/kana/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+var flagLogsFollow bool
+var flagLogsGrep string
+var flagLogsLines int
+var flagLogsTail string
+var flagLogsSince string
+
+func logs(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logs [database|mailpit|phpmyadmin|redis|wordpress]",
+		Short: "Show the output of one of the site's containers or, without a container, of all of them.",
+		Run: func(cmd *cobra.Command, args []string) {
+			ensureSiteIsRunning(consoleOutput, kanaSite, "logs")
+
+			containerName := ""
+
+			if len(args) == 1 {
+				containerName = args[0]
+			}
+
+			err := kanaSite.StreamContainerLogs(containerName, docker.LogOptions{
+				Follow: flagLogsFollow,
+				Tail:   flagLogsTail,
+				Since:  flagLogsSince}, os.Stdout)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+		},
+		Args:      cobra.MatchAll(cobra.MaximumNArgs(1), cobra.OnlyValidArgs),
+		ValidArgs: site.LogContainers,
+	}
+
+	cmd.Flags().BoolVarP(&flagLogsFollow, "follow", "f", false, "Keep showing new output until the containers stop.")
+	cmd.Flags().StringVar(&flagLogsTail, "tail", "all", "The number of lines to show from the end of each log, or all.")
+	cmd.Flags().StringVar(&flagLogsSince, "since", "", "Only show output since a timestamp or a relative time, such as 10m.")
+
+	commandsRequiringSite = append(commandsRequiringSite, cmd.Use)
+
+	phpCmd := &cobra.Command{
+		Use:   "php",
+		Short: "Show the PHP errors, warnings and notices logged by the site, without Apache's access log.",
+		Run: func(cmd *cobra.Command, args []string) {
+			err := kanaSite.ShowPHPLog(flagLogsLines, flagLogsGrep, flagLogsFollow, os.Stdout)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+		},
+		Args: cobra.NoArgs,
+	}
+
+	phpCmd.Flags().BoolVarP(&flagLogsFollow, "follow", "f", false, "Keep showing new entries as they're logged.")
+	phpCmd.Flags().StringVar(&flagLogsGrep, "grep", "", "Only show entries matching this regular expression, such as Fatal.")
+	phpCmd.Flags().IntVarP(&flagLogsLines, "lines", "n", 50, "The number of entries to show before following. Use 0 to show them all.")
+
+	cmd.AddCommand(phpCmd)
+
+	return cmd
+}
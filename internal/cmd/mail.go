@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/settings"
+	"github.com/ChrisWiegman/kana/internal/site"
+	"github.com/ChrisWiegman/kana/pkg/mailpit"
+
+	"github.com/spf13/cobra"
+)
+
+var flagMailLimit int
+var flagMailDeleteAll bool
+var flagMailTo string
+var flagMailWaitFilter mailpit.Filter
+var flagMailWaitTimeout time.Duration
+
+func mail(consoleOutput *console.Console, kanaSite *site.Site, kanaSettings *settings.Settings) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mail",
+		Short: "List, show, wait for, delete and send emails caught by the site's Mailpit instance.",
+		Args:  cobra.NoArgs,
+	}
+
+	commandsRequiringSite = append(commandsRequiringSite, cmd.Use)
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the emails caught by Mailpit, newest first.",
+		Run: func(cmd *cobra.Command, args []string) {
+			ensureSiteIsRunning(consoleOutput, kanaSite, "mail")
+
+			messages, err := kanaSite.ListMail(flagMailLimit)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			mailTable := console.NewTable(
+				console.TableColumn{Header: "ID"},
+				console.TableColumn{Header: "From", MaxWidth: 40},
+				console.TableColumn{Header: "To", MaxWidth: 40},
+				console.TableColumn{Header: "Subject", MaxWidth: 50},
+				console.TableColumn{Header: "Received"})
+
+			for _, message := range messages {
+				recipients := []string{}
+
+				for _, recipient := range message.To {
+					recipients = append(recipients, recipient.String())
+				}
+
+				mailTable.AddRow(
+					message.ID,
+					console.Cell{Value: message.From.Address, Text: message.From.String()},
+					console.Cell{Value: recipients, Text: strings.Join(recipients, ", ")},
+					message.Subject,
+					console.Cell{Value: message.Created, Text: message.Created.Local().Format(time.DateTime)})
+			}
+
+			consoleOutput.PrintTable(mailTable)
+		},
+		Args: cobra.NoArgs,
+	}
+
+	showCmd := &cobra.Command{
+		Use:   "show <id>",
+		Short: "Show an email caught by Mailpit. Use latest as the ID to show the newest email.",
+		Run: func(cmd *cobra.Command, args []string) {
+			ensureSiteIsRunning(consoleOutput, kanaSite, "mail")
+
+			message, err := kanaSite.GetMail(args[0])
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			printMail(consoleOutput, message)
+		},
+		Args: cobra.ExactArgs(1),
+	}
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete [id...]",
+		Short: "Delete emails caught by Mailpit by their IDs or use --all to delete every email.",
+		Run: func(cmd *cobra.Command, args []string) {
+			ensureSiteIsRunning(consoleOutput, kanaSite, "mail")
+
+			if flagMailDeleteAll == (len(args) > 0) {
+				consoleOutput.Error(fmt.Errorf("give the IDs of the emails to delete or use --all to delete every email"))
+			}
+
+			err := kanaSite.DeleteMail(args)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			if flagMailDeleteAll {
+				consoleOutput.Success("All emails have been deleted.")
+
+				return
+			}
+
+			consoleOutput.Success(fmt.Sprintf("Deleted %d email(s).", len(args)))
+		},
+	}
+
+	sendTestCmd := &cobra.Command{
+		Use:   "send-test",
+		Short: "Send a test email through WordPress to check the site's email setup.",
+		Run: func(cmd *cobra.Command, args []string) {
+			ensureSiteIsRunning(consoleOutput, kanaSite, "mail")
+
+			to := kanaSettings.Get("adminEmail")
+
+			if flagMailTo != "" {
+				to = flagMailTo
+			}
+
+			err := kanaSite.SendTestMail(to, consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			consoleOutput.Success(fmt.Sprintf("A test email has been sent to %s. Use 'kana mail show latest' to see it.", to))
+		},
+		Args: cobra.NoArgs,
+	}
+
+	waitCmd := &cobra.Command{
+		Use:   "wait",
+		Short: "Wait until Mailpit catches an email matching the given recipient, sender and subject and show it.",
+		Run: func(cmd *cobra.Command, args []string) {
+			ensureSiteIsRunning(consoleOutput, kanaSite, "mail")
+
+			message, err := kanaSite.WaitForMail(flagMailWaitFilter, flagMailWaitTimeout)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			printMail(consoleOutput, message)
+		},
+		Args: cobra.NoArgs,
+	}
+
+	urlCmd := &cobra.Command{
+		Use:   "url",
+		Short: "Print the URL of the site's Mailpit API for use in integration tests.",
+		Run: func(cmd *cobra.Command, args []string) {
+			ensureSiteIsRunning(consoleOutput, kanaSite, "mail")
+
+			mailpitURL, err := kanaSite.GetMailpitURL()
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			if consoleOutput.JSON {
+				str, _ := json.Marshal(map[string]string{"url": mailpitURL})
+				fmt.Println(string(str))
+
+				return
+			}
+
+			fmt.Println(mailpitURL)
+		},
+		Args: cobra.NoArgs,
+	}
+
+	listCmd.Flags().IntVar(&flagMailLimit, "limit", 50, "The number of emails to list")
+	deleteCmd.Flags().BoolVar(&flagMailDeleteAll, "all", false, "Delete every email caught by Mailpit")
+	sendTestCmd.Flags().StringVar(&flagMailTo, "to", "", "The address to send the test email to. Defaults to the adminEmail setting")
+
+	waitCmd.Flags().StringVar(&flagMailWaitFilter.To, "to", "", "Only match emails sent to this address")
+	waitCmd.Flags().StringVar(&flagMailWaitFilter.From, "from", "", "Only match emails sent from this address")
+	waitCmd.Flags().StringVar(&flagMailWaitFilter.Subject, "subject", "", "Only match emails with a subject containing this text")
+	waitCmd.Flags().DurationVar(&flagMailWaitTimeout, "timeout", 30*time.Second, "How long to wait for a matching email before failing")
+
+	cmd.AddCommand(
+		listCmd,
+		showCmd,
+		waitCmd,
+		deleteCmd,
+		sendTestCmd,
+		urlCmd,
+	)
+
+	return cmd
+}
+
+// printMail prints an email caught by Mailpit, or its JSON if JSON output was requested.
+func printMail(consoleOutput *console.Console, message mailpit.Message) {
+	if consoleOutput.JSON {
+		str, _ := json.Marshal(message)
+		fmt.Println(string(str))
+
+		return
+	}
+
+	recipients := []string{}
+
+	for _, recipient := range message.To {
+		recipients = append(recipients, recipient.String())
+	}
+
+	consoleOutput.Println(fmt.Sprintf("%s %s", consoleOutput.Bold("From:"), message.From.String()))
+	consoleOutput.Println(fmt.Sprintf("%s %s", consoleOutput.Bold("To:"), strings.Join(recipients, ", ")))
+	consoleOutput.Println(fmt.Sprintf("%s %s", consoleOutput.Bold("Date:"), message.Date.Local().Format(time.DateTime)))
+	consoleOutput.Println(fmt.Sprintf("%s %s", consoleOutput.Bold("Subject:"), message.Subject))
+	consoleOutput.Println("")
+	consoleOutput.Println(strings.TrimSpace(message.Text))
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/settings"
+
+	"github.com/spf13/cobra"
+)
+
+var flagMigrateDryRun bool
+
+func migrateConfig(consoleOutput *console.Console, kanaSettings *settings.Settings) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate-config",
+		Short: "Update the global and site config files written by older versions of Kana to the current format.",
+		Run: func(cmd *cobra.Command, args []string) {
+			results, err := kanaSettings.MigrateConfigFiles(flagMigrateDryRun)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			if consoleOutput.JSON {
+				str, _ := json.Marshal(results)
+
+				fmt.Println(string(str))
+
+				return
+			}
+
+			for _, result := range results {
+				if len(result.Changes) == 0 {
+					consoleOutput.Println(fmt.Sprintf("%s is up to date.", consoleOutput.Bold(result.File)))
+					continue
+				}
+
+				consoleOutput.Println(fmt.Sprintf("%s:", consoleOutput.Bold(result.File)))
+
+				for _, change := range result.Changes {
+					consoleOutput.Println(fmt.Sprintf("  - %s", change))
+				}
+			}
+
+			if flagMigrateDryRun {
+				consoleOutput.Println("This was a dry run. No files have been changed.")
+				return
+			}
+
+			consoleOutput.Success("Your config files are up to date.")
+		},
+		Args: cobra.NoArgs,
+	}
+
+	cmd.Flags().BoolVar(&flagMigrateDryRun, "dry-run", false, "Show the changes that would be made without saving them.")
+
+	return cmd
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/settings"
+	"github.com/ChrisWiegman/kana/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+func migrateHome(consoleOutput *console.Console, kanaSite *site.Site, kanaSettings *settings.Settings) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate-home [old directory]",
+		Short: "Move Kana's sites, certificates and config to the directories set by KANA_HOME or the XDG base directories.",
+		Run: func(cmd *cobra.Command, args []string) {
+			from, err := settings.DefaultAppDirectory()
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			if len(args) == 1 {
+				from = args[0]
+			}
+
+			// Running sites have their folders and the certificates mounted into their containers
+			if kanaSite.EnsureDocker(consoleOutput) == nil {
+				sites, err := kanaSite.GetSiteList(true)
+				if err != nil {
+					consoleOutput.Error(err)
+				}
+
+				for i := range sites {
+					if sites[i].Running {
+						consoleOutput.Error(
+							fmt.Errorf("%s is running. Stop every site with `kana stop --all` before moving the app directory", sites[i].Name))
+					}
+				}
+			}
+
+			moved, err := kanaSettings.MigrateAppDirectory(from)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			consoleOutput.Success(
+				fmt.Sprintf(
+					"%s has been moved to %s, with the sites in %s. Keep KANA_HOME or the XDG base directories set so Kana uses them.",
+					strings.Join(moved, ", "),
+					consoleOutput.Bold(kanaSettings.Get("appDirectory")),
+					consoleOutput.Bold(kanaSettings.Get("sitesDirectory"))))
+		},
+		Args: cobra.MaximumNArgs(1),
+	}
+
+	return cmd
+}
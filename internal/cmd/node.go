@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+func npm(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
+	return nodeCommand("npm", "Run an npm command, such as a build, in the current project's directory.", consoleOutput, kanaSite)
+}
+
+func npx(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
+	return nodeCommand("npx", "Run a package's command with npx in the current project's directory.", consoleOutput, kanaSite)
+}
+
+// nodeCommand returns a command passing its arguments on to npm or npx in the site's Node container.
+func nodeCommand(command, short string, consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   command,
+		Short: short,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := kanaSite.EnsureDocker(consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			if len(args) == 0 && command == "npx" {
+				consoleOutput.Error(fmt.Errorf("npx needs the name of the package to run, such as `kana npx @wordpress/create-block my-block`"))
+			}
+
+			code, err := kanaSite.Node(command, removeNameFlag(args, cmd), consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			// Pass the command's exit code through so it can be used in scripts
+			if code != 0 {
+				os.Exit(int(code))
+			}
+		},
+		Args: cobra.ArbitraryArgs,
+	}
+
+	cmd.DisableFlagParsing = true
+
+	return cmd
+}
@@ -11,7 +11,8 @@ import (
 	"github.com/spf13/pflag"
 )
 
-var openDatabaseFlag, openMailpitFlag, openSiteFlag, openAdminFlag bool
+var openDatabaseFlag, openMailpitFlag, openSiteFlag, openAdminFlag, openNetworkFlag bool
+var openPathFlag, openBrowserFlag, openUserFlag string
 
 func open(consoleOutput *console.Console, kanaSite *site.Site, kanaSettings *settings.Settings) *cobra.Command {
 	cmd := &cobra.Command{
@@ -23,23 +24,50 @@ func open(consoleOutput *console.Console, kanaSite *site.Site, kanaSettings *set
 				consoleOutput.Error(err)
 			}
 
-			// Default to opening the site if no flags are specified
+			// Default to opening the site, or the dashboard when logging in as a user, if no flags are specified
 			if !cmd.Flags().Lookup("database").Changed &&
 				!cmd.Flags().Lookup("mailpit").Changed &&
 				!cmd.Flags().Lookup("site").Changed &&
-				!cmd.Flags().Lookup("admin").Changed {
+				!cmd.Flags().Lookup("admin").Changed &&
+				!cmd.Flags().Lookup("network").Changed {
+				if cmd.Flags().Lookup("user").Changed && !cmd.Flags().Lookup("path").Changed {
+					openAdminFlag = true
+				} else {
+					openSiteFlag = true
+				}
+			}
+
+			// A path is always opened on the site
+			if cmd.Flags().Lookup("path").Changed {
 				openSiteFlag = true
 			}
 
-			// Open the site in the user's default browser,
-			err = kanaSite.OpenSite(openDatabaseFlag, openMailpitFlag, openSiteFlag, openAdminFlag, consoleOutput)
+			if cmd.Flags().Lookup("browser").Changed {
+				err = kanaSettings.Set("browser", openBrowserFlag)
+				if err != nil {
+					consoleOutput.Error(err)
+				}
+			}
+
+			// Open the site in the user's browser
+			err = kanaSite.OpenSite(
+				site.OpenTargets{
+					Database: openDatabaseFlag,
+					Mailpit:  openMailpitFlag,
+					Site:     openSiteFlag,
+					Admin:    openAdminFlag,
+					Network:  openNetworkFlag,
+				},
+				openPathFlag,
+				openUserFlag,
+				consoleOutput)
 			if err != nil {
 				consoleOutput.Error(fmt.Errorf("an error occurred and we can't open the requested resource: %s", err))
 			}
 
 			consoleOutput.Success(
 				fmt.Sprintf(
-					"Your site, %s, has been opened in your default browser.",
+					"Your site, %s, has been opened in your browser.",
 					consoleOutput.Bold(
 						consoleOutput.Blue(
 							kanaSettings.Get("name")))))
@@ -67,6 +95,28 @@ func open(consoleOutput *console.Console, kanaSite *site.Site, kanaSettings *set
 		"a",
 		false,
 		"Opens the current or specified Kana site's WordPress dashboard in your default browser")
+	cmd.Flags().StringVar(
+		&openPathFlag,
+		"path",
+		"",
+		"Opens the given path on the current or specified Kana site, such as /wp-admin/plugins.php")
+	cmd.Flags().BoolVarP(
+		&openNetworkFlag,
+		"network",
+		"n",
+		false,
+		"Opens the current or specified Kana site's multisite network admin in your default browser")
+	cmd.Flags().StringVarP(
+		&openUserFlag,
+		"user",
+		"u",
+		"",
+		"Logs in as the given username, or the first user with the given role, such as editor")
+	cmd.Flags().StringVar(
+		&openBrowserFlag,
+		"browser",
+		"",
+		"The browser to open the site in, overriding the browser setting")
 
 	cmd.Flags().SetNormalizeFunc(aliasPhpMyAdminFlag)
 
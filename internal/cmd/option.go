@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+var flagOptionSearch string
+
+func option(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "option",
+		Short: "Get, set and list the site's options, with arrays and objects as JSON.",
+		Args:  cobra.NoArgs,
+	}
+
+	commandsRequiringSite = append(commandsRequiringSite, cmd.Use)
+
+	getCmd := &cobra.Command{
+		Use:   "get <name>",
+		Short: "Print the value of an option. Arrays and objects are printed as JSON.",
+		Run: func(cmd *cobra.Command, args []string) {
+			ensureSiteIsRunning(consoleOutput, kanaSite, "option")
+
+			value, err := kanaSite.GetOption(args[0], consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			if consoleOutput.JSON {
+				fmt.Println(string(value))
+
+				return
+			}
+
+			var stringValue string
+
+			if json.Unmarshal(value, &stringValue) == nil {
+				fmt.Println(stringValue)
+
+				return
+			}
+
+			var indented bytes.Buffer
+
+			err = json.Indent(&indented, value, "", "  ")
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			fmt.Println(indented.String())
+		},
+		Args: cobra.ExactArgs(1),
+	}
+
+	setCmd := &cobra.Command{
+		Use:   "set <name> <value>",
+		Short: "Set an option, adding it if needed. Values that are JSON arrays or objects are stored as arrays.",
+		Run: func(cmd *cobra.Command, args []string) {
+			ensureSiteIsRunning(consoleOutput, kanaSite, "option")
+
+			err := kanaSite.SetOption(args[0], args[1], consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			consoleOutput.Success(fmt.Sprintf("The %s option has been set.", args[0]))
+		},
+		Args: cobra.ExactArgs(2),
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the site's options, other than transients.",
+		Run: func(cmd *cobra.Command, args []string) {
+			ensureSiteIsRunning(consoleOutput, kanaSite, "option")
+
+			options, err := kanaSite.ListOptions(flagOptionSearch, consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			optionsTable := console.NewTable(
+				console.TableColumn{Header: "Name", MaxWidth: 50},
+				console.TableColumn{Header: "Value", MaxWidth: 60},
+				console.TableColumn{Header: "Autoload"})
+
+			for _, option := range options {
+				optionsTable.AddRow(option.Name, option.Value, option.Autoload)
+			}
+
+			consoleOutput.PrintTable(optionsTable)
+		},
+		Args: cobra.NoArgs,
+	}
+
+	listCmd.Flags().StringVar(&flagOptionSearch, "search", "", "Only list options matching this name, which can include * wildcards")
+
+	cmd.AddCommand(
+		getCmd,
+		setCmd,
+		listCmd,
+	)
+
+	return cmd
+}
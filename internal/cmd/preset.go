@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/settings"
+	"github.com/ChrisWiegman/kana/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+func preset(consoleOutput *console.Console, kanaSite *site.Site, kanaSettings *settings.Settings) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "preset",
+		Short: "Commands to apply recipes of plugins, options and content for common stacks to the current site.",
+		Args:  cobra.NoArgs,
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the built-in presets and your custom presets.",
+		Run: func(cmd *cobra.Command, args []string) {
+			presets, err := kanaSettings.GetPresets()
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			presetTable := console.NewTable(
+				console.TableColumn{Header: "Name"},
+				console.TableColumn{Header: "Source"},
+				console.TableColumn{Header: "Description"})
+
+			for i := range presets {
+				presetTable.AddRow(presets[i].Name, presets[i].Source, presets[i].Description)
+			}
+
+			consoleOutput.PrintTable(presetTable)
+		},
+		Args: cobra.NoArgs,
+	}
+
+	applyCmd := &cobra.Command{
+		Use:   "apply <preset name or file>",
+		Short: "Apply a preset to the current site.",
+		Run: func(cmd *cobra.Command, args []string) {
+			sitePreset, err := kanaSettings.GetPreset(args[0])
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			ensureSiteIsRunning(consoleOutput, kanaSite, "preset apply")
+
+			err = kanaSite.ApplyPreset(&sitePreset, consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			consoleOutput.Success(
+				fmt.Sprintf(
+					"The %s preset has been applied to %s.",
+					consoleOutput.Bold(sitePreset.Name),
+					consoleOutput.Bold(consoleOutput.Blue(kanaSettings.Get("name")))))
+		},
+		Args: cobra.ExactArgs(1),
+	}
+
+	commandsRequiringSite = append(commandsRequiringSite, applyCmd.Use)
+	commandsLockingSite = append(commandsLockingSite, applyCmd)
+
+	cmd.AddCommand(applyCmd, listCmd)
+
+	return cmd
+}
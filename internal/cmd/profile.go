@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+var flagProfileLimit int
+
+func profile(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "profile",
+		Short: "Profile the site's requests to find slow hooks and queries.",
+		Args:  cobra.NoArgs,
+	}
+
+	commandsRequiringSite = append(commandsRequiringSite, cmd.Use)
+
+	requestCmd := &cobra.Command{
+		Use:   "request <url>",
+		Short: "Request a page of the site, given as a path such as /shop/, and show its slowest hooks and queries.",
+		Run: func(cmd *cobra.Command, args []string) {
+			ensureSiteIsRunning(consoleOutput, kanaSite, "profile")
+
+			report, err := kanaSite.ProfileRequest(args[0], consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			if flagProfileLimit > 0 {
+				report.Hooks = report.Hooks[:min(flagProfileLimit, len(report.Hooks))]
+				report.Queries = report.Queries[:min(flagProfileLimit, len(report.Queries))]
+			}
+
+			if consoleOutput.JSON {
+				str, _ := json.Marshal(report)
+				fmt.Println(string(str))
+
+				return
+			}
+
+			consoleOutput.Println(fmt.Sprintf(
+				"%s returned %d in %s using %.1f MB of memory and %d queries taking %s.",
+				consoleOutput.Bold(report.URL),
+				report.Status,
+				formatProfileTime(report.Time),
+				float64(report.Memory)/1024/1024,
+				report.QueryCount,
+				formatProfileTime(report.QueryTime)))
+
+			hooksTable := console.NewTable(
+				console.TableColumn{Header: "Hook", MaxWidth: 60},
+				console.TableColumn{Header: "Calls", Align: console.AlignRight},
+				console.TableColumn{Header: "Time", Align: console.AlignRight})
+
+			for _, hook := range report.Hooks {
+				hooksTable.AddRow(hook.Hook, hook.Calls, console.Cell{Value: hook.Time, Text: formatProfileTime(hook.Time)})
+			}
+
+			consoleOutput.PrintTable(hooksTable)
+
+			queriesTable := console.NewTable(
+				console.TableColumn{Header: "Query", MaxWidth: 80},
+				console.TableColumn{Header: "Caller", MaxWidth: 60},
+				console.TableColumn{Header: "Time", Align: console.AlignRight})
+
+			for _, query := range report.Queries {
+				queriesTable.AddRow(query.Query, query.Caller, console.Cell{Value: query.Time, Text: formatProfileTime(query.Time)})
+			}
+
+			consoleOutput.PrintTable(queriesTable)
+		},
+		Args: cobra.ExactArgs(1),
+	}
+
+	requestCmd.Flags().IntVar(&flagProfileLimit, "limit", 10, "The number of hooks and queries to show. Use 0 to show every one recorded")
+
+	cmd.AddCommand(requestCmd)
+
+	return cmd
+}
+
+// formatProfileTime formats a time in seconds as milliseconds.
+func formatProfileTime(seconds float64) string {
+	return fmt.Sprintf("%.1fms", seconds*1000)
+}
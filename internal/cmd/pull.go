@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/settings"
+	"github.com/ChrisWiegman/kana/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+var flagPullWhat []string
+
+func pull(consoleOutput *console.Console, kanaSite *site.Site, kanaSettings *settings.Settings) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pull",
+		Short: "Copy the database and files of the site on the server set by pullHost and pullPath, over SSH.",
+		Run: func(cmd *cobra.Command, args []string) {
+			ensureSiteIsRunning(consoleOutput, kanaSite, "pull")
+
+			if !flagForce {
+				confirmPull := consoleOutput.PromptConfirm(
+					fmt.Sprintf(
+						"Are you sure you want to pull the %s from %s into %s? %s",
+						strings.Join(flagPullWhat, ", "),
+						consoleOutput.Bold(kanaSettings.Get("pullHost")),
+						consoleOutput.Bold(consoleOutput.Blue(kanaSettings.Get("name"))),
+						consoleOutput.Bold(
+							consoleOutput.Yellow(
+								"The site's database and any files that are also on the server will be replaced."))),
+					false)
+
+				if !confirmPull {
+					consoleOutput.Error(fmt.Errorf("pull canceled. No data has been changed"))
+				}
+			}
+
+			remoteURL, err := kanaSite.Pull(flagPullWhat, consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			consoleOutput.Success(
+				fmt.Sprintf(
+					"Pull complete. The site's %s have been copied from %s.",
+					strings.Join(flagPullWhat, ", "),
+					consoleOutput.Bold(remoteURL)))
+		},
+		Args: cobra.NoArgs,
+	}
+
+	commandsRequiringSite = append(commandsRequiringSite, cmd.Use)
+	commandsLockingSite = append(commandsLockingSite, cmd)
+
+	cmd.Flags().StringSliceVar(
+		&flagPullWhat,
+		"what",
+		site.PullParts,
+		fmt.Sprintf("The parts of the site to pull. Any of %s", strings.Join(site.PullParts, ", ")))
+	cmd.Flags().BoolVar(&flagForce, "force", false, "Pull the site without prompting for confirmation.")
+
+	return cmd
+}
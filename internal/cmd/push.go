@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/settings"
+	"github.com/ChrisWiegman/kana/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagPushDryRun  bool
+	flagPushExclude []string
+	flagPushWhat    []string
+)
+
+func push(consoleOutput *console.Console, kanaSite *site.Site, kanaSettings *settings.Settings) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "push",
+		Short: "Deploy the plugin or theme being developed, and optionally the database and other files, to a server over SSH.",
+		Run: func(cmd *cobra.Command, args []string) {
+			ensureSiteIsRunning(consoleOutput, kanaSite, "push")
+
+			if !flagForce && !flagPushDryRun {
+				pushHost := kanaSettings.Get("pushHost")
+				if pushHost == "" {
+					pushHost = kanaSettings.Get("pullHost")
+				}
+
+				warning := "Any files on the server that are also in the site will be replaced."
+				if slices.Contains(flagPushWhat, "db") {
+					warning = "The server's database, and any files on it that are also in the site, will be replaced."
+
+					safeImportChanges, err := kanaSite.GetSafeImportChanges()
+					if err != nil {
+						consoleOutput.Error(err)
+					}
+
+					if len(safeImportChanges) > 0 {
+						warning += fmt.Sprintf(
+							" The safe import changes to %s will be undone in the pushed database.",
+							strings.Join(safeImportChanges, ", "))
+					}
+				}
+
+				confirmPush := consoleOutput.PromptConfirm(
+					fmt.Sprintf(
+						"Are you sure you want to push the %s of %s to %s? %s",
+						strings.Join(flagPushWhat, ", "),
+						consoleOutput.Bold(consoleOutput.Blue(kanaSettings.Get("name"))),
+						consoleOutput.Bold(pushHost),
+						consoleOutput.Bold(consoleOutput.Yellow(warning))),
+					false)
+
+				if !confirmPush {
+					consoleOutput.Error(fmt.Errorf("push canceled. Nothing has been changed on the server"))
+				}
+			}
+
+			remoteURL, err := kanaSite.Push(flagPushWhat, flagPushExclude, flagPushDryRun, consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			if flagPushDryRun {
+				consoleOutput.Success(
+					fmt.Sprintf("Dry run complete. Nothing has been changed on %s.", consoleOutput.Bold(remoteURL)))
+
+				return
+			}
+
+			consoleOutput.Success(
+				fmt.Sprintf(
+					"Push complete. The site's %s have been copied to %s.",
+					strings.Join(flagPushWhat, ", "),
+					consoleOutput.Bold(remoteURL)))
+		},
+		Args: cobra.NoArgs,
+	}
+
+	commandsRequiringSite = append(commandsRequiringSite, cmd.Use)
+	commandsLockingSite = append(commandsLockingSite, cmd)
+
+	cmd.Flags().StringSliceVar(
+		&flagPushWhat,
+		"what",
+		[]string{"project"},
+		fmt.Sprintf("The parts of the site to push. Any of %s", strings.Join(site.PushParts, ", ")))
+	cmd.Flags().StringSliceVar(
+		&flagPushExclude,
+		"exclude",
+		[]string{},
+		"Files and folders, as rsync patterns, to skip in addition to those in the pushExclude setting.")
+	cmd.Flags().BoolVar(&flagPushDryRun, "dry-run", false, "Show the changes that would be made on the server without making them.")
+	cmd.Flags().BoolVar(&flagForce, "force", false, "Push the site without prompting for confirmation.")
+
+	return cmd
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/docker"
+	"github.com/ChrisWiegman/kana/internal/settings"
+	"github.com/ChrisWiegman/kana/internal/site"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/spf13/cobra"
+)
+
+var flagRelocateReset bool
+
+func relocate(consoleOutput *console.Console, kanaSite *site.Site, kanaSettings *settings.Settings) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "relocate [directory]",
+		Short: "Move the site's files, such as its database, to another folder or disk or, without a directory, show where they are.",
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) == 0 && !flagRelocateReset {
+				consoleOutput.Println(
+					fmt.Sprintf(
+						"The files for %s are in %s.",
+						consoleOutput.Bold(consoleOutput.Blue(kanaSettings.Get("name"))),
+						consoleOutput.Bold(kanaSettings.Get("siteDirectory"))))
+
+				return
+			}
+
+			if len(args) == 1 && flagRelocateReset {
+				consoleOutput.Error(
+					fmt.Errorf("the --reset flag moves the site's files back to Kana's app directory so it can't be used with a directory"))
+			}
+
+			directory := ""
+
+			if len(args) == 1 {
+				expanded, err := homedir.Expand(args[0])
+				if err != nil {
+					consoleOutput.Error(err)
+				}
+
+				directory, err = filepath.Abs(expanded)
+				if err != nil {
+					consoleOutput.Error(err)
+				}
+
+				err = docker.EnsureFileSharing(directory)
+				if err != nil {
+					consoleOutput.Error(err)
+				}
+			}
+
+			err := kanaSite.EnsureDocker(consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			// The site's files are mounted into its containers so they can't be moved while it runs
+			if kanaSite.IsSiteRunning() {
+				consoleOutput.Error(fmt.Errorf("the site is running. Stop it with `kana stop` before moving its files"))
+			}
+
+			location, err := kanaSettings.RelocateSite(directory)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			consoleOutput.Success(
+				fmt.Sprintf(
+					"The files for %s have been moved to %s.",
+					consoleOutput.Bold(consoleOutput.Blue(kanaSettings.Get("name"))),
+					consoleOutput.Bold(location)))
+		},
+		Args: cobra.MaximumNArgs(1),
+	}
+
+	cmd.Flags().BoolVar(&flagRelocateReset, "reset", false, "Move the site's files back to Kana's app directory.")
+
+	commandsRequiringSite = append(commandsRequiringSite, cmd.Use)
+	commandsLockingSite = append(commandsLockingSite, cmd)
+
+	return cmd
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+var flagResumeLast bool
+
+func resume(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "resume",
+		Short: "List, or with --last start again, the sites that were running when Docker restarted.",
+		Run: func(cmd *cobra.Command, args []string) {
+			err := kanaSite.EnsureDocker(consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			sites, err := kanaSite.GetResumableSites()
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			if len(sites) == 0 {
+				consoleOutput.Println("There are no sites to resume. Every site that was running is still running.")
+
+				return
+			}
+
+			if !flagResumeLast {
+				siteTable := console.NewTable(
+					console.TableColumn{Header: "Name"},
+					console.TableColumn{Header: "Path"},
+					console.TableColumn{Header: "Type"})
+
+				for _, site := range sites {
+					siteTable.AddRow(site.Name, site.Path, site.Type)
+				}
+
+				consoleOutput.PrintTable(siteTable)
+				consoleOutput.Println("Run 'kana resume --last' to start them again.")
+
+				return
+			}
+
+			resumed := kanaSite.ResumeSites(sites, consoleOutput)
+
+			if len(resumed) < len(sites) {
+				consoleOutput.Error(fmt.Errorf("%d of %d site(s) could not be started again", len(sites)-len(resumed), len(sites)))
+			}
+
+			consoleOutput.Success(fmt.Sprintf("%d site(s) have been started again.", len(resumed)))
+		},
+		Args: cobra.NoArgs,
+	}
+
+	cmd.Flags().BoolVar(&flagResumeLast, "last", false, "Start every site that was running when Docker restarted")
+
+	return cmd
+}
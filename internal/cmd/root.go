@@ -1,9 +1,18 @@
 package cmd
 
 import (
+	"errors"
+	"fmt"
+	"os"
 	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/docker"
+	"github.com/ChrisWiegman/kana/internal/lock"
 	"github.com/ChrisWiegman/kana/internal/settings"
 	"github.com/ChrisWiegman/kana/internal/site"
 
@@ -12,7 +21,13 @@ import (
 
 var (
 	flagVerbose, flagJSONOutput bool
+	flagTiming, flagCI          bool
+	flagNoWait                  bool
+	flagLogFormat               string
 	commandsRequiringSite       []string
+	commandsLockingSite         []*cobra.Command
+	commandStart                time.Time
+	siteLock                    *lock.Lock
 )
 
 func Execute() {
@@ -26,23 +41,99 @@ func Execute() {
 		Short: "Kana is a simple WordPress development tool designed for plugin and theme developers.",
 		Args:  cobra.NoArgs,
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			commandStart = time.Now()
+
+			// CI mode can be set once for a whole job with the KANA_CI environment variable
+			if isCI, err := strconv.ParseBool(os.Getenv("KANA_CI")); err == nil && !cmd.Flags().Changed("ci") {
+				flagCI = isCI
+			}
+
+			// CI mode logs JSON unless another format is asked for
+			if flagCI && !cmd.Flags().Changed("log-format") {
+				flagLogFormat = "json"
+			}
+
 			consoleOutput.Debug = flagVerbose
-			consoleOutput.JSON = flagJSONOutput
+			consoleOutput.CI = flagCI
+			consoleOutput.JSON = flagJSONOutput || flagLogFormat == "json"
+			consoleOutput.Timing = flagTiming
+			consoleOutput.LogFormat = flagLogFormat
+
+			if flagLogFormat != "text" && flagLogFormat != "json" {
+				consoleOutput.Error(fmt.Errorf("invalid log format %s. Valid formats are text and json", flagLogFormat))
+			}
+
 			var err error
 
-			if cmd.Use == "wp" {
+			if slices.Contains([]string{"wp", "composer", "npm", "npx"}, cmd.Use) {
 				err = parseWPNameFlag(args, cmd)
 				if err != nil {
 					consoleOutput.Error(err)
 				}
 			}
 
+			if cmd.Name() == "clone" {
+				err = parseCloneSourceFlag(args, cmd)
+				if err != nil {
+					consoleOutput.Error(err)
+				}
+			}
+
 			err = settings.Load(kanaSettings, Version, cmd)
 			if err != nil {
 				consoleOutput.Error(err)
 			}
 
+			err = consoleOutput.SetTheme(kanaSettings.Get("colorTheme"), kanaSettings.GetSlice("colorOverrides"))
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			if cmd.Use != "migrate-config" {
+				for _, configFile := range kanaSettings.GetOutdatedConfigFiles() {
+					consoleOutput.Warn(
+						fmt.Sprintf("%s was written by an older version of Kana. Run `kana migrate-config` to update it.", configFile))
+				}
+			}
+
+			if unmigratedDirectory := kanaSettings.GetUnmigratedAppDirectory(); unmigratedDirectory != "" && cmd.Name() != "migrate-home" {
+				consoleOutput.Warn(
+					fmt.Sprintf(
+						"Your sites are in %s but KANA_HOME or an XDG base directory has Kana using %s for sites. "+
+							"Run `kana migrate-home` to move them there or unset the variable to keep using them where they are.",
+						unmigratedDirectory,
+						kanaSettings.Get("sitesDirectory")))
+			}
+
+			for _, setting := range kanaSettings.GetIgnoredOverrides() {
+				consoleOutput.Warn(
+					fmt.Sprintf("The %s setting is locked by the site's .kana.json file so your value in .kana.local.json is being ignored.", setting))
+			}
+
+			docker.SetRetries(kanaSettings.GetInt("networkRetries"))
+
+			err = kanaSettings.Set("isCI", flagCI)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
 			site.Load(kanaSite, kanaSettings)
+
+			// Commands run for every site lock each site as it is run for instead and the global config isn't part of a site
+			isEditingGlobalConfig := cmd.CommandPath() == "kana config edit" && !flagConfigLocal
+
+			if slices.Contains(commandsLockingSite, cmd) && !flagAll && !isEditingGlobalConfig {
+				lockSite(cmd, consoleOutput, kanaSite)
+			}
+		},
+		PersistentPostRun: func(cmd *cobra.Command, args []string) {
+			err := siteLock.Release()
+			if err != nil {
+				consoleOutput.Warn(fmt.Sprintf("The site's lock couldn't be released: %s", err))
+			}
+
+			consoleOutput.PrintTimings(time.Since(commandStart))
+			recordUsage(cmd, kanaSettings)
 		},
 	}
 
@@ -52,6 +143,18 @@ func Execute() {
 	// Add the "name" flag to allow for sites not connected to the local directory
 	cmd.PersistentFlags().String("name", "", "Specify a name for the site, used to override using the current folder.")
 	cmd.PersistentFlags().BoolVarP(&flagVerbose, "verbose", "v", false, "Display debugging information along with detailed command output")
+	cmd.PersistentFlags().StringVar(&flagLogFormat, "log-format", "text", "The format of console messages, text or json")
+	cmd.PersistentFlags().BoolVar(
+		&flagCI,
+		"ci",
+		false,
+		"Run without prompts, colors or a browser, serving the site on a local port for CI runners such as GitHub Actions")
+	cmd.PersistentFlags().BoolVar(
+		&flagNoWait,
+		"no-wait",
+		false,
+		"Fail straight away, instead of waiting, if another kana command is already changing the site")
+	cmd.PersistentFlags().BoolVar(&flagTiming, "timing", false, "Display how long each phase of the command took")
 	cmd.PersistentFlags().BoolVar(&flagJSONOutput, "output-json", false, "Display all output in JSON format for further processing")
 
 	err := cmd.PersistentFlags().MarkHidden("output-json")
@@ -61,16 +164,58 @@ func Execute() {
 
 	// Register the subcommands
 	cmd.AddCommand(
+		autostart(consoleOutput, kanaSite),
+		backup(consoleOutput, kanaSite, kanaSettings),
+		bisect(consoleOutput, kanaSite),
+		blueprint(consoleOutput, kanaSite, kanaSettings),
 		changelog(consoleOutput),
+		clone(consoleOutput, kanaSite, kanaSettings),
+		composer(consoleOutput, kanaSite),
 		config(consoleOutput, kanaSettings),
+		core(consoleOutput, kanaSite),
+		credentials(consoleOutput, kanaSite),
 		db(consoleOutput, kanaSite),
 		destroy(consoleOutput, kanaSite, kanaSettings),
+		exec(consoleOutput, kanaSite),
 		export(consoleOutput, kanaSite, kanaSettings),
 		flush(consoleOutput, kanaSite),
+		historyCommand(consoleOutput, kanaSite),
+		images(consoleOutput, kanaSite),
+		importCommand(consoleOutput, kanaSite),
+		info(consoleOutput, kanaSite),
+		jobs(consoleOutput, kanaSite),
+		link(consoleOutput, kanaSite),
 		list(consoleOutput, kanaSite),
+		logs(consoleOutput, kanaSite),
+		mail(consoleOutput, kanaSite, kanaSettings),
+		migrateConfig(consoleOutput, kanaSettings),
+		migrateHome(consoleOutput, kanaSite, kanaSettings),
+		npm(consoleOutput, kanaSite),
+		npx(consoleOutput, kanaSite),
 		open(consoleOutput, kanaSite, kanaSettings),
+		option(consoleOutput, kanaSite),
+		plugins(consoleOutput, kanaSite),
+		preset(consoleOutput, kanaSite, kanaSettings),
+		profile(consoleOutput, kanaSite),
+		pull(consoleOutput, kanaSite, kanaSettings),
+		push(consoleOutput, kanaSite, kanaSettings),
+		ready(consoleOutput, kanaSite, kanaSettings),
+		relocate(consoleOutput, kanaSite, kanaSettings),
+		resume(consoleOutput, kanaSite),
+		seed(consoleOutput, kanaSite),
+		share(consoleOutput, kanaSite),
+		shell(consoleOutput, kanaSite),
 		start(consoleOutput, kanaSite, kanaSettings),
+		static(consoleOutput, kanaSite, kanaSettings),
+		status(consoleOutput, kanaSite),
 		stop(consoleOutput, kanaSite, kanaSettings),
+		supportBundle(consoleOutput, kanaSite),
+		telemetryCommand(consoleOutput, kanaSettings),
+		test(consoleOutput, kanaSite),
+		themes(consoleOutput, kanaSite),
+		unlink(consoleOutput, kanaSite, kanaSettings),
+		update(consoleOutput, kanaSite),
+		updates(consoleOutput, kanaSite),
 		version(consoleOutput),
 		wp(consoleOutput, kanaSite),
 		xdebug(consoleOutput, kanaSite),
@@ -85,3 +230,37 @@ func Execute() {
 		consoleOutput.Error(err)
 	}
 }
+
+// lockSite stops other kana commands from changing the site while this one does, such as an editor task starting the
+// site while it is being stopped in a terminal. The lock is released when the command finishes or exits.
+func lockSite(cmd *cobra.Command, consoleOutput *console.Console, kanaSite *site.Site) {
+	var err error
+
+	siteLock, err = kanaSite.Lock(strings.TrimPrefix(cmd.CommandPath(), "kana "), !flagNoWait, func(holder lock.Holder) {
+		consoleOutput.Println(fmt.Sprintf("Waiting for %s to finish with this site...", holder))
+	})
+
+	exitOnLockError(consoleOutput, err)
+}
+
+// lockOtherSite locks another site the command changes, as lockSite does for the current site. The caller releases the
+// returned lock.
+func lockOtherSite(name string, cmd *cobra.Command, consoleOutput *console.Console, kanaSite *site.Site) *lock.Lock {
+	otherLock, err := kanaSite.LockSite(name, strings.TrimPrefix(cmd.CommandPath(), "kana "), !flagNoWait, func(holder lock.Holder) {
+		consoleOutput.Println(fmt.Sprintf("Waiting for %s to finish with %s...", holder, name))
+	})
+
+	exitOnLockError(consoleOutput, err)
+
+	return otherLock
+}
+
+func exitOnLockError(consoleOutput *console.Console, err error) {
+	if errors.Is(err, lock.ErrLocked) {
+		consoleOutput.Error(fmt.Errorf("%w. Wait for it to finish or run this command again without --no-wait to wait for it", err))
+	}
+
+	if err != nil {
+		consoleOutput.Error(err)
+	}
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+func seed(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "seed",
+		Short: "Commands to add test data to the current site.",
+		Args:  cobra.NoArgs,
+	}
+
+	commandsRequiringSite = append(commandsRequiringSite, cmd.Use)
+
+	usersCmd := &cobra.Command{
+		Use:   "users",
+		Short: "Create a user for each core WordPress role, plus any users in the extraUsers setting, with known credentials.",
+		Run: func(cmd *cobra.Command, args []string) {
+			ensureSiteIsRunning(consoleOutput, kanaSite, "seed users")
+
+			created, err := kanaSite.SeedUsers(consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			if consoleOutput.JSON {
+				str, _ := json.Marshal(created)
+
+				fmt.Println(string(str))
+
+				return
+			}
+
+			for _, user := range created {
+				consoleOutput.Println(fmt.Sprintf("Created %s user: %s", user.Role, consoleOutput.Bold(consoleOutput.Blue(user.Username))))
+			}
+
+			consoleOutput.Success("Your test users are ready. Use `kana credentials` to see how to log in as them.")
+		},
+		Args: cobra.NoArgs,
+	}
+
+	cmd.AddCommand(usersCmd)
+
+	return cmd
+}
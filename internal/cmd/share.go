@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+func share(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "share",
+		Short: "Share the running site at a temporary public URL, such as for testing webhooks, until you press Ctrl-C.",
+		Run: func(cmd *cobra.Command, args []string) {
+			ensureSiteIsRunning(consoleOutput, kanaSite, "share")
+
+			// Closing the terminal also stops sharing so the site is changed back to its local URL
+			ctx, stopSignals := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+			defer stopSignals()
+
+			publicURL, err := kanaSite.StartSharing(ctx, consoleOutput)
+			if err != nil {
+				// Anything already changed is undone so the site isn't left pointing at a tunnel that doesn't exist
+				_ = kanaSite.StopSharing(consoleOutput)
+
+				consoleOutput.Error(err)
+			}
+
+			consoleOutput.Success(
+				fmt.Sprintf(
+					"Your site is shared at %s. Press Ctrl-C to stop sharing it.",
+					consoleOutput.Bold(consoleOutput.Green(publicURL))))
+
+			kanaSite.WaitWhileSharing(ctx)
+
+			consoleOutput.Println("Changing the site back to its local URL.")
+
+			err = kanaSite.StopSharing(consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			consoleOutput.Success("Your site is no longer shared.")
+		},
+		Args: cobra.NoArgs,
+	}
+
+	commandsRequiringSite = append(commandsRequiringSite, cmd.Use)
+
+	return cmd
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+var flagShellContainer string
+var flagShellRoot bool
+
+func shell(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "shell",
+		Short: "Open an interactive shell in one of the site's containers.",
+		Run: func(cmd *cobra.Command, args []string) {
+			ensureSiteIsRunning(consoleOutput, kanaSite, "shell")
+
+			code, err := kanaSite.Shell(flagShellContainer, flagShellRoot)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			// Pass the exit code of the shell's last command through as `docker exec` would
+			if code != 0 {
+				os.Exit(code)
+			}
+		},
+		Args: cobra.NoArgs,
+	}
+
+	commandsRequiringSite = append(commandsRequiringSite, cmd.Use)
+
+	cmd.Flags().StringVarP(
+		&flagShellContainer,
+		"container",
+		"c",
+		"wordpress",
+		"The container to open the shell in. Can be database, mailpit, redis or wordpress.")
+	cmd.Flags().BoolVar(&flagShellRoot, "root", false, "Open the shell as the root user.")
+
+	return cmd
+}
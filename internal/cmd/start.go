@@ -1,8 +1,11 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/ChrisWiegman/kana/internal/console"
 	"github.com/ChrisWiegman/kana/internal/helpers"
@@ -13,6 +16,8 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var flagStartOpen bool
+
 func start(consoleOutput *console.Console, kanaSite *site.Site, kanaSettings *settings.Settings) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "start",
@@ -38,9 +43,9 @@ func start(consoleOutput *console.Console, kanaSite *site.Site, kanaSettings *se
 				consoleOutput.Error(fmt.Errorf("a default theme cannot be set on a site of type 'theme"))
 			}
 
-			// Check that the site is already running and show an error if it is.
+			// Starting a running site recreates any of its containers whose settings have changed
 			if kanaSite.IsSiteRunning() {
-				consoleOutput.Error(fmt.Errorf("the site is already running. Please stop your site before running the start command"))
+				consoleOutput.Println("The site is already running. Any of its containers whose settings have changed will be recreated.")
 			}
 
 			// Check that we're not using our home directory as the working directory as that could cause security or other issues.
@@ -59,11 +64,37 @@ func start(consoleOutput *console.Console, kanaSite *site.Site, kanaSettings *se
 				consoleOutput.Error(fmt.Errorf("you are attempting to start a new site from your home directory. This could create security issues. Please create a folder and start a site from there")) //nolint:lll
 			}
 
-			err = kanaSite.StartSite(consoleOutput)
+			// Ctrl-C cancels the start so the containers already started can be removed rather than leaving the site half
+			// started. Once it has been pressed, pressing it again quits straight away.
+			ctx, stopSignals := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stopSignals()
+
+			go func() {
+				<-ctx.Done()
+				stopSignals()
+			}()
+
+			err = kanaSite.StartSite(ctx, flagStartOpen, consoleOutput)
 			if err != nil {
+				// A new site that was interrupted is removed entirely so the next start begins again from scratch
+				if errors.Is(err, site.ErrStartInterrupted) && kanaSettings.GetBool("IsNew") {
+					remError := os.RemoveAll(kanaSettings.Get("siteDirectory"))
+					if remError != nil {
+						consoleOutput.Error(remError)
+					}
+				}
+
 				consoleOutput.Error(err)
 			}
 
+			kanaSite.MaybeResumeSites(kanaSettings.Get("name"), consoleOutput)
+
+			if kanaSettings.GetBool("isCI") || !flagStartOpen {
+				consoleOutput.Success(fmt.Sprintf("Your site, %s, has started at %s.", kanaSettings.Get("name"), kanaSettings.GetURL()))
+
+				return
+			}
+
 			consoleOutput.Success(
 				fmt.Sprintf(
 					"Your site, %s, has has started and should be open in your default browser.",
@@ -74,10 +105,21 @@ func start(consoleOutput *console.Console, kanaSite *site.Site, kanaSettings *se
 
 	settings.AddStartFlags(cmd, kanaSettings)
 
+	cmd.Flags().BoolVar(&flagStartOpen, "open", true, "Open the site in your browser once it has started")
+
+	commandsLockingSite = append(commandsLockingSite, cmd)
+
 	return cmd
 }
 
 func handleTypeDetection(cmd *cobra.Command, consoleOutput *console.Console, kanaSettings *settings.Settings) error {
+	cwd, err := os.Getwd()
+	if err == nil && cwd != kanaSettings.Get("workingDirectory") {
+		consoleOutput.Printf(
+			"A project was found in %s. Starting the site from there instead of the current folder.\n",
+			consoleOutput.Bold(kanaSettings.Get("workingDirectory")))
+	}
+
 	if !cmd.Flags().Lookup("type").Changed && !kanaSettings.GetBool("HasLocalSettings") {
 		if !cmd.Flags().Lookup("name").Changed {
 			err := verifyEmpty(kanaSettings, consoleOutput)
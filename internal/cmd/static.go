@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/settings"
+	"github.com/ChrisWiegman/kana/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+var flagStaticBaseURL string
+
+func static(consoleOutput *console.Console, kanaSite *site.Site, kanaSettings *settings.Settings) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "static",
+		Short: "Export the site to static HTML and preview the export.",
+		Args:  cobra.NoArgs,
+	}
+
+	commandsRequiringSite = append(commandsRequiringSite, cmd.Use)
+
+	// getStaticDirectory returns the directory to export to or serve, relative to the site's working directory.
+	getStaticDirectory := func(args []string) string {
+		directory := "static"
+
+		if len(args) == 1 {
+			directory = args[0]
+		}
+
+		if !filepath.IsAbs(directory) {
+			directory = filepath.Join(kanaSettings.Get("workingDirectory"), directory)
+		}
+
+		return directory
+	}
+
+	exportCmd := &cobra.Command{
+		Use:   "export [directory]",
+		Short: "Crawl the site and save its pages and files as static files. Exports to the static directory by default.",
+		Run: func(cmd *cobra.Command, args []string) {
+			ensureSiteIsRunning(consoleOutput, kanaSite, "static")
+
+			export, err := kanaSite.ExportStaticSite(getStaticDirectory(args), flagStaticBaseURL, consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			if consoleOutput.JSON {
+				str, _ := json.Marshal(export)
+				fmt.Println(string(str))
+
+				return
+			}
+
+			for _, failed := range export.Failed {
+				consoleOutput.Warn(fmt.Sprintf("%s is linked to but could not be exported.", failed))
+			}
+
+			consoleOutput.Success(fmt.Sprintf("Exported %d files to %s.", export.Files, export.Directory))
+		},
+		Args: cobra.MaximumNArgs(1),
+	}
+
+	serveCmd := &cobra.Command{
+		Use:   "serve [directory]",
+		Short: "Serve a static export on its own domain to preview it. Serves the static directory by default.",
+		Run: func(cmd *cobra.Command, args []string) {
+			ensureSiteIsRunning(consoleOutput, kanaSite, "static")
+
+			staticURL, err := kanaSite.StartStaticServer(getStaticDirectory(args), consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			consoleOutput.Success(fmt.Sprintf(
+				"The static export is being served at %s. Run 'kana static stop' to stop serving it.",
+				consoleOutput.Bold(staticURL)))
+		},
+		Args: cobra.MaximumNArgs(1),
+	}
+
+	stopCmd := &cobra.Command{
+		Use:   "stop",
+		Short: "Stop serving the static export.",
+		Run: func(cmd *cobra.Command, args []string) {
+			err := kanaSite.EnsureDocker(consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			err = kanaSite.StopStaticServer()
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			consoleOutput.Success("The static export is no longer being served.")
+		},
+		Args: cobra.NoArgs,
+	}
+
+	exportCmd.Flags().StringVar(
+		&flagStaticBaseURL,
+		"base-url",
+		"",
+		"The URL the export will be published at, such as https://example.com. Links are relative to the domain's root by default")
+
+	cmd.AddCommand(
+		exportCmd,
+		serveCmd,
+		stopCmd,
+	)
+
+	return cmd
+}
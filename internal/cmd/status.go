@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+func status(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Shows which of the site's services are running, stopped or not used by the site.",
+		Run: func(cmd *cobra.Command, args []string) {
+			err := kanaSite.EnsureDocker(consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			statuses, err := kanaSite.GetStatus()
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			statusTable := console.NewTable(
+				console.TableColumn{Header: "Service"},
+				console.TableColumn{Header: "Container"},
+				console.TableColumn{Header: "Status"})
+
+			for _, service := range statuses {
+				serviceStatus := console.Cell{Value: service.Status}
+
+				if service.Status == "running" {
+					serviceStatus.Style = consoleOutput.Green
+				}
+
+				statusTable.AddRow(service.Service, service.Container, serviceStatus)
+			}
+
+			consoleOutput.PrintTable(statusTable)
+		},
+		Args: cobra.NoArgs,
+	}
+
+	commandsRequiringSite = append(commandsRequiringSite, cmd.Use)
+
+	return cmd
+}
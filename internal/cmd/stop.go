@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/ChrisWiegman/kana/internal/console"
 	"github.com/ChrisWiegman/kana/internal/settings"
@@ -20,6 +21,12 @@ func stop(consoleOutput *console.Console, kanaSite *site.Site, kanaSettings *set
 				consoleOutput.Error(err)
 			}
 
+			if flagAll {
+				stopAllSites(consoleOutput, kanaSite)
+
+				return
+			}
+
 			// Stop the WordPress site
 			err = kanaSite.StopSite()
 			if err != nil {
@@ -35,6 +42,44 @@ func stop(consoleOutput *console.Console, kanaSite *site.Site, kanaSettings *set
 	}
 
 	commandsRequiringSite = append(commandsRequiringSite, cmd.Use)
+	commandsLockingSite = append(commandsLockingSite, cmd)
+
+	cmd.Flags().BoolVar(&flagAll, "all", false, "Stop every running site.")
+	addParallelFlag(cmd)
 
 	return cmd
 }
+
+// stopAllSites stops every running site, a few at a time, each as if `kana stop` were run in its folder.
+func stopAllSites(consoleOutput *console.Console, kanaSite *site.Site) {
+	ensureParallelFlag(consoleOutput)
+
+	sites, err := kanaSite.GetSiteList(true)
+	if err != nil {
+		consoleOutput.Error(err)
+	}
+
+	runningSites := []site.SiteInfo{}
+
+	for i := range sites {
+		if sites[i].Running {
+			runningSites = append(runningSites, sites[i])
+		}
+	}
+
+	if len(runningSites) == 0 {
+		consoleOutput.Println("There are no running sites to stop.")
+
+		return
+	}
+
+	start := time.Now()
+	results := kanaSite.RunKanaForSites(runningSites, flagParallel, printBatchProgress(consoleOutput, "Stopped"), "stop")
+
+	err = kanaSite.StopUnusedSharedContainers()
+	if err != nil {
+		consoleOutput.Warn(fmt.Sprintf("Unable to stop the containers shared by every site: %s", err))
+	}
+
+	printBatchSummary(consoleOutput, results, time.Since(start), "site", "Stopped")
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/settings"
+	"github.com/ChrisWiegman/kana/internal/telemetry"
+
+	"github.com/spf13/cobra"
+)
+
+type TelemetryStatus struct {
+	Enabled  bool
+	Endpoint string
+	Pending  []telemetry.Event
+}
+
+func telemetryCommand(consoleOutput *console.Console, kanaSettings *settings.Settings) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:       "telemetry <on|off|status>",
+		Short:     "Turn anonymous usage metrics on or off and preview what would be sent.",
+		ValidArgs: []string{"on", "off", "status"},
+		Run: func(cmd *cobra.Command, args []string) {
+			switch args[0] {
+			case "on", "off":
+				err := kanaSettings.Set("telemetry", args[0] == "on", true)
+				if err != nil {
+					consoleOutput.Error(err)
+				}
+
+				if args[0] == "off" {
+					err = telemetry.Clear(kanaSettings.Get("appDirectory"))
+					if err != nil {
+						consoleOutput.Error(err)
+					}
+				}
+
+				consoleOutput.Success(fmt.Sprintf("Anonymous usage metrics have been turned %s.", args[0]))
+			case "status":
+				printTelemetryStatus(consoleOutput, kanaSettings)
+			}
+		},
+		Args: cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	}
+
+	return cmd
+}
+
+func printTelemetryStatus(consoleOutput *console.Console, kanaSettings *settings.Settings) {
+	pending, err := telemetry.GetPending(kanaSettings.Get("appDirectory"))
+	if err != nil {
+		consoleOutput.Error(err)
+	}
+
+	status := TelemetryStatus{
+		Enabled:  kanaSettings.GetBool("telemetry"),
+		Endpoint: kanaSettings.Get("telemetryEndpoint"),
+		Pending:  pending,
+	}
+
+	if consoleOutput.JSON {
+		str, _ := json.Marshal(status)
+
+		fmt.Println(string(str))
+
+		return
+	}
+
+	enabled := "off"
+
+	if status.Enabled {
+		enabled = "on"
+	}
+
+	consoleOutput.Printf("Anonymous usage metrics are %s.\n", consoleOutput.Bold(enabled))
+
+	if status.Endpoint == "" {
+		consoleOutput.Println("No telemetryEndpoint has been set so metrics are only stored on this computer.")
+	}
+
+	if len(pending) == 0 {
+		consoleOutput.Println("There are no metrics waiting to be sent.")
+
+		return
+	}
+
+	preview, _ := json.MarshalIndent(pending, "", "  ")
+
+	consoleOutput.Println("The following metrics will be sent:")
+	consoleOutput.Println(string(preview))
+}
+
+// recordUsage saves an anonymous record of the command that was run if the user has turned on usage metrics.
+func recordUsage(cmd *cobra.Command, kanaSettings *settings.Settings) {
+	if !kanaSettings.GetBool("telemetry") || cmd.Name() == "telemetry" {
+		return
+	}
+
+	appDirectory := kanaSettings.Get("appDirectory")
+
+	// Metrics should never get in the way of the user so errors are ignored
+	_ = telemetry.Record(appDirectory, telemetry.NewEvent(cmd.CommandPath(), Version, time.Since(commandStart)))
+	_ = telemetry.MaybeSend(appDirectory, kanaSettings.Get("telemetryEndpoint"))
+}
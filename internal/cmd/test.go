@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+var flagTestWordPressVersions []string
+var flagTestCoverage string
+
+func test(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "test",
+		Short: "Run the project's tests against one or more versions of WordPress, each in its own throwaway site.",
+		Run: func(cmd *cobra.Command, args []string) {
+			err := kanaSite.EnsureDocker(consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			results, err := kanaSite.TestWordPressVersions(flagTestWordPressVersions, flagTestCoverage, consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			resultsTable := console.NewTable(
+				console.TableColumn{Header: "WordPress"},
+				console.TableColumn{Header: "Result"},
+				console.TableColumn{Header: "Time"})
+
+			failed := 0
+
+			for _, result := range results {
+				status := console.Cell{Value: "passed", Text: "Passed", Style: consoleOutput.Green}
+
+				switch {
+				case result.Error != nil:
+					status = console.Cell{Value: result.Error.Error(), Text: fmt.Sprintf("Error: %s", result.Error), Style: consoleOutput.Yellow}
+					failed++
+				case !result.Passed:
+					status = console.Cell{Value: "failed", Text: "Failed", Style: consoleOutput.Yellow}
+					failed++
+				}
+
+				resultsTable.AddRow(
+					result.WordPressVersion,
+					status,
+					console.Cell{Value: result.Duration.Seconds(), Text: result.Duration.Round(time.Second).String()})
+			}
+
+			consoleOutput.PrintTable(resultsTable)
+
+			for _, result := range results {
+				if result.CoverageReport != "" {
+					consoleOutput.Println(fmt.Sprintf(
+						"The coverage report for WordPress %s was written to %s", result.WordPressVersion, result.CoverageReport))
+				}
+			}
+
+			if failed > 0 {
+				consoleOutput.Error(fmt.Errorf("the tests failed on %d of %d versions of WordPress", failed, len(results)))
+			}
+
+			consoleOutput.Success(fmt.Sprintf("The tests passed on all %d versions of WordPress.", len(results)))
+		},
+		Args: cobra.NoArgs,
+	}
+
+	cmd.Flags().StringSliceVar(
+		&flagTestWordPressVersions,
+		"wp",
+		[]string{"latest"},
+		"The versions of WordPress to test against, such as 6.2,6.4,nightly")
+
+	cmd.Flags().StringVar(
+		&flagTestCoverage,
+		"coverage",
+		"",
+		fmt.Sprintf("Collect code coverage with PHPUnit using %s, writing the reports to the project's coverage folder",
+			strings.Join(site.CoverageDrivers, " or ")))
+	cmd.Flags().Lookup("coverage").NoOptDefVal = site.CoverageDrivers[0]
+
+	return cmd
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+var flagUpdateNoSnapshot bool
+
+func update(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update WordPress, plugins, themes and translations, after taking a snapshot, and report the versions before and after.",
+		Run: func(cmd *cobra.Command, args []string) {
+			ensureSiteIsRunning(consoleOutput, kanaSite, cmd.Use)
+
+			report, err := kanaSite.UpdateSite(!flagUpdateNoSnapshot, Version, consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			if consoleOutput.JSON {
+				str, _ := json.Marshal(report)
+				fmt.Println(string(str))
+
+				return
+			}
+
+			updateTable := console.NewTable(
+				console.TableColumn{Header: "Type"},
+				console.TableColumn{Header: "Name"},
+				console.TableColumn{Header: "Before"},
+				console.TableColumn{Header: "After"})
+
+			updated := 0
+
+			for _, item := range report.Items {
+				after := console.Cell{Value: item.After, Text: item.After}
+
+				if item.Updated() {
+					updated++
+					after.Style = consoleOutput.Green
+				}
+
+				updateTable.AddRow(item.Type, item.Name, item.Before, after)
+			}
+
+			consoleOutput.PrintTable(updateTable)
+
+			for _, failed := range report.Failed {
+				consoleOutput.Warn(failed)
+			}
+
+			if report.Snapshot != "" {
+				consoleOutput.Println(fmt.Sprintf(
+					"A snapshot taken before updating was saved to %s. Restore it with 'kana import --force %s'.",
+					report.Snapshot,
+					report.Snapshot))
+			}
+
+			if len(report.Failed) > 0 {
+				consoleOutput.Error(fmt.Errorf("%d of the update steps failed", len(report.Failed)))
+			}
+
+			consoleOutput.Success(fmt.Sprintf("The update is complete. %d item(s) were updated.", updated))
+		},
+		Args: cobra.NoArgs,
+	}
+
+	commandsRequiringSite = append(commandsRequiringSite, cmd.Use)
+
+	cmd.Flags().BoolVar(
+		&flagUpdateNoSnapshot,
+		"no-snapshot",
+		false,
+		"Don't take a snapshot of the database, plugins and themes before updating")
+
+	commandsLockingSite = append(commandsLockingSite, cmd)
+
+	return cmd
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+var flagUpdatesInstall bool
+
+func updates(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "updates",
+		Short: "Publish releases of the plugin in the current directory to the site's mock update server.",
+		Args:  cobra.NoArgs,
+	}
+
+	commandsRequiringSite = append(commandsRequiringSite, cmd.Use)
+
+	publishCmd := &cobra.Command{
+		Use:   "publish [version]",
+		Short: "Package the plugin in the current directory as a new version, the next patch version by default, and offer it as an update.",
+		Run: func(cmd *cobra.Command, args []string) {
+			ensureSiteIsRunning(consoleOutput, kanaSite, "updates")
+
+			directory, err := os.Getwd()
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			version := ""
+
+			if len(args) == 1 {
+				version = args[0]
+			}
+
+			release, err := kanaSite.PublishUpdate(directory, version, flagUpdatesInstall, consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			if consoleOutput.JSON {
+				str, _ := json.Marshal(release)
+				fmt.Println(string(str))
+
+				return
+			}
+
+			if flagUpdatesInstall {
+				consoleOutput.Success(fmt.Sprintf("%s %s has been published and installed.", release.Slug, release.Version))
+
+				return
+			}
+
+			consoleOutput.Success(
+				fmt.Sprintf("%s %s has been published. Update the plugin from the dashboard or with 'kana wp plugin update %s'.",
+					release.Slug,
+					release.Version,
+					release.Slug))
+		},
+		Args: cobra.MaximumNArgs(1),
+	}
+
+	publishCmd.Flags().BoolVar(&flagUpdatesInstall, "install", false, "Install the release on the site rather than offering it as an update")
+
+	cmd.AddCommand(publishCmd)
+
+	return cmd
+}
@@ -25,32 +25,27 @@ func wp(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
 				consoleOutput.Error(fmt.Errorf("the `wp` command only works on a running site. Please run 'kana start' to start the site"))
 			}
 
-			if cmd.Flags().Lookup("name").Changed {
-				for i := range args {
-					if !strings.Contains(args[i], "--name=") && !strings.Contains(args[i], "-n=") {
-						continue
-					}
-
-					nameFlag := strings.Split(args[i], "=")
-
-					if nameFlag[1] == cmd.Flags().Lookup("name").Value.String() {
-						args = append(args[:i], args[i+1:]...)
-						break
-					}
-				}
-			}
+			args = removeNameFlag(args, cmd)
 
-			// Run the output from wp-cli
+			// Run wp-cli with the user's terminal attached. Output is streamed directly so there is nothing left to print
 			code, output, err := kanaSite.WPCli(args, true, consoleOutput)
 			if err != nil {
 				consoleOutput.Error(err)
 			}
 
 			if code != 0 {
+				if output == "" {
+					output = fmt.Sprintf("wp-cli exited with status %d", code)
+				}
+
 				consoleOutput.Error(errors.New(output))
 			}
 
-			consoleOutput.Println(output)
+			kanaSite.RecordWPCliCommand(args)
+
+			if output != "" {
+				consoleOutput.Println(output)
+			}
 		},
 		Args: cobra.ArbitraryArgs,
 	}
@@ -62,6 +57,28 @@ func wp(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
 	return cmd
 }
 
+// removeNameFlag removes Kana's name flag from the arguments of commands, such as wp, that pass their flags on to another
+// command.
+func removeNameFlag(args []string, cmd *cobra.Command) []string {
+	if !cmd.Flags().Lookup("name").Changed {
+		return args
+	}
+
+	for i := range args {
+		if !strings.Contains(args[i], "--name=") && !strings.Contains(args[i], "-n=") {
+			continue
+		}
+
+		nameFlag := strings.Split(args[i], "=")
+
+		if nameFlag[1] == cmd.Flags().Lookup("name").Value.String() {
+			return append(args[:i], args[i+1:]...)
+		}
+	}
+
+	return args
+}
+
 func parseWPNameFlag(args []string, cmd *cobra.Command) error {
 	for i := range args {
 		if !strings.Contains(args[i], "--name=") && !strings.Contains(args[i], "-n=") {
@@ -1,6 +1,9 @@
 package cmd
 
 import (
+	"encoding/json"
+	"fmt"
+
 	"github.com/ChrisWiegman/kana/internal/console"
 	"github.com/ChrisWiegman/kana/internal/site"
 
@@ -48,6 +51,7 @@ func xdebug(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command
 	}
 
 	commandsRequiringSite = append(commandsRequiringSite, onCommand.Use)
+	commandsLockingSite = append(commandsLockingSite, onCommand)
 
 	offCommand := &cobra.Command{
 		Use:   "off",
@@ -75,10 +79,95 @@ func xdebug(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command
 	}
 
 	commandsRequiringSite = append(commandsRequiringSite, offCommand.Use)
+	commandsLockingSite = append(commandsLockingSite, offCommand)
+
+	statusCommand := &cobra.Command{
+		Use:   "status",
+		Short: "Shows Xdebug's mode and client port and whether a debugging client is listening for it",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			ensureSiteIsRunning(consoleOutput, kanaSite, "xdebug status")
+
+			status, err := kanaSite.GetXdebugStatus(consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			if consoleOutput.JSON {
+				str, _ := json.Marshal(status)
+				fmt.Println(string(str))
+
+				return
+			}
+
+			if !status.Enabled {
+				consoleOutput.Println("Xdebug is off. Run 'kana xdebug on' to start it.")
+
+				return
+			}
+
+			listening := console.Cell{Value: "no", Style: consoleOutput.Yellow}
+
+			if status.ClientListening {
+				listening = console.Cell{Value: "yes", Style: consoleOutput.Green}
+			}
+
+			statusTable := console.NewTable(
+				console.TableColumn{Header: "Setting"},
+				console.TableColumn{Header: "Value"})
+
+			statusTable.AddRow("Mode", status.Mode)
+			statusTable.AddRow("Start with request", status.StartWithRequest)
+			statusTable.AddRow("Client host", status.ClientHost)
+			statusTable.AddRow("Client port", status.ClientPort)
+			statusTable.AddRow("Client listening", listening)
+
+			consoleOutput.PrintTable(statusTable)
+
+			if !status.ClientListening {
+				consoleOutput.Warn(xdebugClientWarning(status))
+			}
+		},
+	}
+
+	commandsRequiringSite = append(commandsRequiringSite, statusCommand.Use)
+
+	triggerCommand := &cobra.Command{
+		Use:   "trigger <url>",
+		Short: "Requests a page of the site, given as a path such as /shop/, with the XDEBUG_TRIGGER cookie set to start debugging it",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			ensureSiteIsRunning(consoleOutput, kanaSite, "xdebug trigger")
+
+			status, err := kanaSite.GetXdebugStatus(consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			if !status.Enabled {
+				consoleOutput.Error(fmt.Errorf("xdebug is off. Run 'kana xdebug on' to start it first"))
+			}
+
+			if !status.ClientListening {
+				consoleOutput.Warn(xdebugClientWarning(status))
+			}
+
+			requestURL, code, err := kanaSite.TriggerXdebug(args[0])
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			consoleOutput.Success(fmt.Sprintf("%s returned %d with the Xdebug trigger set.", requestURL, code))
+		},
+	}
+
+	commandsRequiringSite = append(commandsRequiringSite, triggerCommand.Use)
 
 	cmd.AddCommand(
 		onCommand,
 		offCommand,
+		statusCommand,
+		triggerCommand,
 	)
 
 	return cmd
@@ -93,3 +182,10 @@ func outputXdebugStatus(status bool) string {
 
 	return displayStatus
 }
+
+func xdebugClientWarning(status site.XdebugStatus) string {
+	return fmt.Sprintf(
+		"No debugging client is listening on %s:%s so breakpoints won't be hit. Start listening for Xdebug in your editor.",
+		status.ClientHost,
+		status.ClientPort)
+}
@@ -6,30 +6,52 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/logrusorgru/aurora/v4"
 )
 
 type Console struct {
-	Debug, JSON bool
+	Debug, JSON, Timing bool
+	CI                  bool // CI disables colors and prompts for non-interactive environments such as CI runners
+	LogFormat           string
+	colors              map[string]aurora.Color
+	timings             []phaseTiming
 }
 
 type Message struct {
 	Status, Message string
 }
 
+// LogEntry is a single console message when using the json log format.
+type LogEntry struct {
+	Level   string                 `json:"level"`
+	Time    string                 `json:"time"`
+	Status  string                 `json:"status"`
+	Message string                 `json:"message"`
+	Fields  map[string]interface{} `json:"fields,omitempty"`
+}
+
+var logLevels = map[string]string{
+	"Error":   "error",
+	"Info":    "info",
+	"Success": "info",
+	"Timing":  "debug",
+	"Warning": "warn",
+}
+
 // Blue outputs the requested text as blue.
 func (c *Console) Blue(output string) string {
-	if c.JSON {
+	if c.isPlain() {
 		return output
 	}
 
-	return aurora.Blue(output).String()
+	return aurora.Colorize(output, c.color("name")).String()
 }
 
 // Bold outputs the requested text as bold.
 func (c *Console) Bold(output string) string {
-	if c.JSON {
+	if c.isPlain() {
 		return output
 	}
 
@@ -39,16 +61,9 @@ func (c *Console) Bold(output string) string {
 // Error displays the error message and a panic if needed.
 func (c *Console) Error(err error) {
 	if c.JSON {
-		message := Message{
-			Status:  "Error",
-			Message: err.Error(),
-		}
-
-		str, _ := json.Marshal(message)
-
-		fmt.Println(string(str))
+		c.printMessage("Error", err.Error(), nil)
 	} else {
-		fmt.Fprintf(os.Stderr, "%s %s\n", aurora.Bold(aurora.Red("[Error]")), err)
+		fmt.Fprintf(os.Stderr, "%s %s\n", c.label("Error", "error"), err)
 
 		if c.Debug {
 			c.Println("")
@@ -61,24 +76,17 @@ func (c *Console) Error(err error) {
 
 // Green outputs the requested text as green.
 func (c *Console) Green(output string) string {
-	if c.JSON {
+	if c.isPlain() {
 		return output
 	}
 
-	return aurora.Green(output).String()
+	return aurora.Colorize(output, c.color("url")).String()
 }
 
 // Printf is a temporary wrapper on fmt.Printf.
 func (c *Console) Printf(format string, a ...any) {
 	if c.JSON {
-		message := Message{
-			Status:  "Info",
-			Message: fmt.Sprintf(format, a...),
-		}
-
-		str, _ := json.Marshal(message)
-
-		fmt.Println(string(str))
+		c.printMessage("Info", fmt.Sprintf(format, a...), nil)
 	} else {
 		fmt.Printf(format, a...)
 	}
@@ -87,22 +95,16 @@ func (c *Console) Printf(format string, a ...any) {
 // Println is a temporary wrapper on fmt.Println.
 func (c *Console) Println(output string) {
 	if c.JSON {
-		message := Message{
-			Status:  "Info",
-			Message: output,
-		}
-
-		str, _ := json.Marshal(message)
-
-		fmt.Println(string(str))
+		c.printMessage("Info", output, nil)
 	} else {
 		fmt.Println(output)
 	}
 }
 
 // PromptConfirm asks the user to confirm output.
+// Without a user to answer, such as in JSON or CI mode, the default is used.
 func (c *Console) PromptConfirm(promptText string, def bool) bool {
-	if c.JSON {
+	if c.JSON || c.CI {
 		return def
 	}
 
@@ -134,40 +136,64 @@ func (c *Console) PromptConfirm(promptText string, def bool) bool {
 // Success displays a formatted success message on successful completion of the command.
 func (c *Console) Success(output string) {
 	if c.JSON {
-		message := Message{
-			Status:  "Success",
-			Message: output,
-		}
-
-		str, _ := json.Marshal(message)
-
-		fmt.Println(string(str))
+		c.printMessage("Success", output, nil)
 	} else {
-		fmt.Printf("%s %s\n", aurora.Bold(aurora.Green("[Success]")), output)
+		fmt.Printf("%s %s\n", c.label("Success", "success"), output)
 	}
 }
 
 // Warn displays a formatted warning message.
 func (c *Console) Warn(output string) {
 	if c.JSON {
-		message := Message{
-			Status:  "Warning",
-			Message: output,
-		}
-
-		str, _ := json.Marshal(message)
-
-		fmt.Println(string(str))
+		c.printMessage("Warning", output, nil)
 	} else {
-		fmt.Printf("%s %s\n", aurora.Bold(aurora.Yellow("[Warning]")), output)
+		fmt.Printf("%s %s\n", c.label("Warning", "warning"), output)
 	}
 }
 
 // Yellow outputs the requested text as yellow.
 func (c *Console) Yellow(output string) string {
-	if c.JSON {
+	if c.isPlain() {
 		return output
 	}
 
-	return aurora.Yellow(output).String()
+	return aurora.Colorize(output, c.color("highlight")).String()
+}
+
+// isPlain returns true when output shouldn't be colored or styled.
+func (c *Console) isPlain() bool {
+	return c.JSON || c.CI
+}
+
+// label returns the label shown before a message, such as "[Success]", in the color of its type.
+func (c *Console) label(label, colorType string) string {
+	label = fmt.Sprintf("[%s]", label)
+
+	if c.isPlain() {
+		return label
+	}
+
+	return aurora.Bold(aurora.Colorize(label, c.color(colorType))).String()
+}
+
+// printMessage writes a message as a single line of JSON using the requested log format.
+func (c *Console) printMessage(status, output string, fields map[string]interface{}) {
+	var str []byte
+
+	if c.LogFormat == "json" {
+		str, _ = json.Marshal(LogEntry{
+			Level:   logLevels[status],
+			Time:    time.Now().Format(time.RFC3339),
+			Status:  strings.ToLower(status),
+			Message: output,
+			Fields:  fields,
+		})
+	} else {
+		str, _ = json.Marshal(Message{
+			Status:  status,
+			Message: output,
+		})
+	}
+
+	fmt.Println(string(str))
 }
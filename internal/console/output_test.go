@@ -1,6 +1,9 @@
 package console
 
 import (
+	"encoding/json"
+	"io"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -32,3 +35,40 @@ func TestConsole_Yellow(t *testing.T) {
 	expected := "\x1b[33mHello, World!\x1b[0m"
 	assert.Equal(t, expected, output)
 }
+
+func TestConsole_CI(t *testing.T) {
+	console := &Console{CI: true}
+
+	assert.Equal(t, "Hello, World!", console.Blue("Hello, World!"))
+	assert.Equal(t, "Hello, World!", console.Bold("Hello, World!"))
+	assert.Equal(t, "[Warning]", console.label("Warning", "warning"))
+	assert.True(t, console.PromptConfirm("Are you sure?", true))
+	assert.False(t, console.PromptConfirm("Are you sure?", false))
+}
+
+func TestConsole_printMessage(t *testing.T) {
+	console := &Console{JSON: true, LogFormat: "json"}
+
+	reader, writer, err := os.Pipe()
+	assert.NoError(t, err)
+
+	stdout := os.Stdout
+	os.Stdout = writer
+
+	console.Warn("Hello, World!")
+
+	os.Stdout = stdout
+	writer.Close()
+
+	output, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+
+	var entry LogEntry
+
+	err = json.Unmarshal(output, &entry)
+	assert.NoError(t, err)
+	assert.Equal(t, "warn", entry.Level)
+	assert.Equal(t, "warning", entry.Status)
+	assert.Equal(t, "Hello, World!", entry.Message)
+	assert.NotEmpty(t, entry.Time)
+}
@@ -0,0 +1,195 @@
+package console
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ChrisWiegman/kana/internal/helpers"
+
+	"github.com/moby/term"
+)
+
+// How often progress is redrawn in a terminal and, elsewhere, how far apart in percent progress lines are printed.
+const (
+	progressInterval = 250 * time.Millisecond
+	progressStep     = 10
+)
+
+// Progress reports how far through a long-running transfer, such as a database import, a command is.
+// It is updated by wrapping the reader or writer of the transfer.
+type Progress struct {
+	console     *Console
+	label       string
+	total       int64
+	estimated   bool
+	current     int64
+	lastPrinted time.Time
+	lastPercent int
+	terminal    bool
+	mutex       sync.Mutex
+}
+
+// NewProgress starts reporting the progress of a transfer of total bytes, which is shown as "about" the total when
+// it is an estimate. Progress stops at 99% until Done is called as the transfer can be larger than expected.
+// With the json log format progress is written as log entries so it can be followed by other tools.
+func (c *Console) NewProgress(label string, total int64, estimated bool) *Progress {
+	return &Progress{
+		console:     c,
+		label:       label,
+		total:       total,
+		estimated:   estimated,
+		lastPercent: -1,
+		terminal:    !c.CI && term.IsTerminal(os.Stdout.Fd()),
+	}
+}
+
+// Reader returns a reader that reports the bytes read from the given reader.
+func (p *Progress) Reader(reader io.Reader) io.Reader {
+	return &progressReader{reader: reader, progress: p}
+}
+
+// Writer returns a writer that reports the bytes written to the given writer.
+func (p *Progress) Writer(writer io.Writer) io.Writer {
+	return &progressWriter{writer: writer, progress: p}
+}
+
+// Done reports the transfer as complete.
+func (p *Progress) Done() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.console.JSON {
+		if p.console.LogFormat == "json" {
+			p.printEntry(100)
+		}
+
+		return
+	}
+
+	if p.terminal {
+		fmt.Printf("\r\033[K%s: 100%% (%s)\n", p.label, helpers.FormatFileSize(p.current))
+		return
+	}
+
+	if p.lastPercent < 100 {
+		fmt.Printf("%s: 100%% (%s)\n", p.label, helpers.FormatFileSize(p.current))
+	}
+}
+
+// add records bytes transferred, redrawing the progress in a terminal or printing a line each progressStep percent.
+func (p *Progress) add(bytes int) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.current += int64(bytes)
+
+	percent := p.percent()
+
+	if p.console.JSON {
+		if p.console.LogFormat == "json" && p.isNextStep(percent) {
+			p.printEntry(percent)
+		}
+
+		return
+	}
+
+	if p.terminal {
+		if time.Since(p.lastPrinted) < progressInterval {
+			return
+		}
+
+		p.lastPrinted = time.Now()
+
+		fmt.Printf("\r\033[K%s", p.format(percent))
+
+		return
+	}
+
+	if p.isNextStep(percent) {
+		fmt.Println(p.format(percent))
+	}
+}
+
+// isNextStep records the percent when it has reached the next progressStep since progress was last printed.
+func (p *Progress) isNextStep(percent int) bool {
+	if percent/progressStep <= p.lastPercent/progressStep && p.lastPercent >= 0 {
+		return false
+	}
+
+	p.lastPercent = percent
+
+	return true
+}
+
+// printEntry writes the progress as a log entry with the numbers needed to track it as fields.
+func (p *Progress) printEntry(percent int) {
+	p.console.printMessage("Info", p.format(percent), map[string]interface{}{
+		"progress": p.label,
+		"percent":  percent,
+		"current":  p.current,
+		"total":    p.total,
+	})
+}
+
+// format returns the progress as shown to the user, such as "Importing: 45% (1.2 GB of 2.6 GB)".
+func (p *Progress) format(percent int) string {
+	if p.total <= 0 {
+		return fmt.Sprintf("%s: %s", p.label, helpers.FormatFileSize(p.current))
+	}
+
+	about := ""
+
+	if p.estimated {
+		about = "about "
+	}
+
+	return fmt.Sprintf(
+		"%s: %d%% (%s of %s%s)",
+		p.label,
+		percent,
+		helpers.FormatFileSize(p.current),
+		about,
+		helpers.FormatFileSize(p.total))
+}
+
+// percent returns how far through the transfer is, never reaching 100% before it is done.
+func (p *Progress) percent() int {
+	if p.total <= 0 {
+		return 0
+	}
+
+	percent := int(p.current * 100 / p.total)
+
+	if percent > 99 {
+		percent = 99
+	}
+
+	return percent
+}
+
+type progressReader struct {
+	reader   io.Reader
+	progress *Progress
+}
+
+func (r *progressReader) Read(data []byte) (int, error) {
+	n, err := r.reader.Read(data)
+	r.progress.add(n)
+
+	return n, err
+}
+
+type progressWriter struct {
+	writer   io.Writer
+	progress *Progress
+}
+
+func (w *progressWriter) Write(data []byte) (int, error) {
+	n, err := w.writer.Write(data)
+	w.progress.add(n)
+
+	return n, err
+}
@@ -0,0 +1,119 @@
+package console
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgress_Reader(t *testing.T) {
+	console := &Console{}
+
+	reader, writer, err := os.Pipe()
+	assert.NoError(t, err)
+
+	stdout := os.Stdout
+	os.Stdout = writer
+
+	progress := console.NewProgress("Importing", 2048, false)
+
+	// Read in chunks so each step of progress is reported
+	_, err = io.CopyBuffer(struct{ io.Writer }{io.Discard}, progress.Reader(bytes.NewReader(make([]byte, 2048))), make([]byte, 256))
+	assert.NoError(t, err)
+
+	progress.Done()
+
+	os.Stdout = stdout
+	writer.Close()
+
+	output, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+
+	assert.Equal(t, "Importing: 12% (256 B of 2.0 KB)", lines[0])
+	assert.Equal(t, "Importing: 99% (2.0 KB of 2.0 KB)", lines[len(lines)-2])
+	assert.Equal(t, "Importing: 100% (2.0 KB)", lines[len(lines)-1])
+}
+
+func TestProgress_format(t *testing.T) {
+	tests := []struct {
+		name      string
+		total     int64
+		estimated bool
+		current   int64
+		expected  string
+	}{
+		{"Known total", 4096, false, 1024, "Exporting: 25% (1.0 KB of 4.0 KB)"},
+		{"Estimated total", 4096, true, 1024, "Exporting: 25% (1.0 KB of about 4.0 KB)"},
+		{"Larger than estimated", 1024, true, 4096, "Exporting: 99% (4.0 KB of about 1.0 KB)"},
+		{"Unknown total", 0, true, 1024, "Exporting: 1.0 KB"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			progress := (&Console{}).NewProgress("Exporting", test.total, test.estimated)
+			progress.current = test.current
+
+			assert.Equal(t, test.expected, progress.format(progress.percent()))
+		})
+	}
+}
+
+func TestProgress_JSON(t *testing.T) {
+	console := &Console{JSON: true}
+
+	var buffer bytes.Buffer
+
+	progress := console.NewProgress("Exporting", 10, false)
+
+	_, err := progress.Writer(&buffer).Write([]byte("0123456789"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "0123456789", buffer.String())
+	assert.Equal(t, int64(10), progress.current)
+}
+
+func TestProgress_JSONLogFormat(t *testing.T) {
+	console := &Console{JSON: true, LogFormat: "json"}
+
+	reader, writer, err := os.Pipe()
+	assert.NoError(t, err)
+
+	stdout := os.Stdout
+	os.Stdout = writer
+
+	progress := console.NewProgress("Importing", 2048, false)
+
+	_, err = io.CopyBuffer(struct{ io.Writer }{io.Discard}, progress.Reader(bytes.NewReader(make([]byte, 2048))), make([]byte, 1024))
+	assert.NoError(t, err)
+
+	progress.Done()
+
+	os.Stdout = stdout
+	writer.Close()
+
+	output, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	assert.Len(t, lines, 3)
+
+	var entry LogEntry
+
+	err = json.Unmarshal([]byte(lines[0]), &entry)
+	assert.NoError(t, err)
+	assert.Equal(t, "Importing: 50% (1.0 KB of 2.0 KB)", entry.Message)
+	assert.Equal(t, "Importing", entry.Fields["progress"])
+	assert.Equal(t, float64(50), entry.Fields["percent"])
+
+	err = json.Unmarshal([]byte(lines[2]), &entry)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(100), entry.Fields["percent"])
+	assert.Equal(t, float64(2048), entry.Fields["current"])
+}
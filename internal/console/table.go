@@ -0,0 +1,207 @@
+package console
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/aquasecurity/table"
+)
+
+type Alignment int
+
+const (
+	AlignLeft Alignment = iota
+	AlignCenter
+	AlignRight
+)
+
+// TableColumn describes a single column of a table.
+type TableColumn struct {
+	Header   string
+	Key      string // The key used for the column in JSON output. Defaults to the header.
+	Align    Alignment
+	MaxWidth int // The maximum number of characters to display before truncating the value. 0 for no limit.
+}
+
+// Cell is a table value that is displayed differently than it is output as JSON.
+type Cell struct {
+	Value interface{}         // The value used in JSON output
+	Text  string              // The text displayed in the table. Defaults to the formatted value.
+	Style func(string) string // Applied to the displayed text after it has been truncated, such as to add color
+}
+
+// Table is a set of rows that can be displayed in the terminal or output as JSON with matching columns.
+type Table struct {
+	columns []TableColumn
+	rows    [][]interface{}
+}
+
+// orderedRow keeps the JSON output of a row in the same order as the table's columns.
+type orderedRow struct {
+	keys   []string
+	values []interface{}
+}
+
+const truncationMarker = "…"
+
+// NewTable returns an empty table with the given columns.
+func NewTable(columns ...TableColumn) *Table {
+	for i := range columns {
+		if columns[i].Key == "" {
+			columns[i].Key = columns[i].Header
+		}
+	}
+
+	return &Table{columns: columns}
+}
+
+// AddRow adds a row of values in column order. Values can be any type, or a Cell to control how they are displayed.
+func (t *Table) AddRow(values ...interface{}) {
+	t.rows = append(t.rows, values)
+}
+
+// PrintTable displays the table or, in JSON mode, outputs its rows as a JSON array of objects keyed by column.
+func (c *Console) PrintTable(t *Table) {
+	if c.JSON {
+		str, _ := json.Marshal(t.jsonRows())
+
+		fmt.Println(string(str))
+
+		return
+	}
+
+	t.Render(os.Stdout)
+}
+
+// Render writes the table to the given writer.
+func (t *Table) Render(w io.Writer) {
+	renderedTable := table.New(w)
+
+	headers := make([]string, len(t.columns))
+	alignments := make([]table.Alignment, len(t.columns))
+
+	for i, column := range t.columns {
+		headers[i] = column.Header
+
+		switch column.Align {
+		case AlignCenter:
+			alignments[i] = table.AlignCenter
+		case AlignRight:
+			alignments[i] = table.AlignRight
+		default:
+			alignments[i] = table.AlignLeft
+		}
+	}
+
+	renderedTable.SetHeaders(headers...)
+	renderedTable.SetAlignment(alignments...)
+
+	for _, row := range t.rows {
+		cells := make([]string, len(t.columns))
+
+		for i := range t.columns {
+			if i < len(row) {
+				cells[i] = t.formatCell(i, row[i])
+			}
+		}
+
+		renderedTable.AddRow(cells...)
+	}
+
+	renderedTable.Render()
+}
+
+func (t *Table) formatCell(column int, value interface{}) string {
+	cell, ok := value.(Cell)
+	if !ok {
+		cell = Cell{Value: value}
+	}
+
+	text := cell.Text
+
+	if text == "" && cell.Value != nil {
+		text = fmt.Sprint(cell.Value)
+	}
+
+	text = truncate(text, t.columns[column].MaxWidth)
+
+	if cell.Style != nil {
+		text = cell.Style(text)
+	}
+
+	return text
+}
+
+func (t *Table) jsonRows() []orderedRow {
+	rows := make([]orderedRow, len(t.rows))
+
+	for i, row := range t.rows {
+		rows[i] = orderedRow{}
+
+		for j, column := range t.columns {
+			var value interface{}
+
+			if j < len(row) {
+				value = row[j]
+			}
+
+			if cell, ok := value.(Cell); ok {
+				value = cell.Value
+			}
+
+			rows[i].keys = append(rows[i].keys, column.Key)
+			rows[i].values = append(rows[i].values, value)
+		}
+	}
+
+	return rows
+}
+
+// MarshalJSON outputs the row as a JSON object with its keys in column order.
+func (r orderedRow) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("{")
+
+	for i, key := range r.keys {
+		if i > 0 {
+			buffer.WriteString(",")
+		}
+
+		keyJSON, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+
+		valueJSON, err := json.Marshal(r.values[i])
+		if err != nil {
+			return nil, err
+		}
+
+		buffer.Write(keyJSON)
+		buffer.WriteString(":")
+		buffer.Write(valueJSON)
+	}
+
+	buffer.WriteString("}")
+
+	return buffer.Bytes(), nil
+}
+
+// truncate shortens text to the given number of characters, marking where it was cut.
+func truncate(text string, maxWidth int) string {
+	runes := []rune(text)
+
+	if maxWidth <= 0 || len(runes) <= maxWidth {
+		return text
+	}
+
+	if maxWidth == 1 {
+		return truncationMarker
+	}
+
+	return string(runes[:maxWidth-1]) + truncationMarker
+}
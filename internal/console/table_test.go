@@ -0,0 +1,51 @@
+package console
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTable_Render(t *testing.T) {
+	testTable := NewTable(
+		TableColumn{Header: "Name"},
+		TableColumn{Header: "Path", MaxWidth: 8},
+		TableColumn{Header: "Running", Align: AlignRight})
+
+	testTable.AddRow("site", "/a/very/long/path", true)
+
+	var output bytes.Buffer
+
+	testTable.Render(&output)
+
+	assert.Contains(t, output.String(), "/a/very…")
+	assert.NotContains(t, output.String(), "/a/very/long/path")
+	assert.Contains(t, output.String(), "true")
+}
+
+func TestTable_jsonRows(t *testing.T) {
+	testTable := NewTable(
+		TableColumn{Header: "Name"},
+		TableColumn{Header: "Size", Key: "Bytes"},
+		TableColumn{Header: "Running"})
+
+	testTable.AddRow("site", Cell{Value: 2048, Text: "2.0 KB", Style: strings.ToUpper}, false)
+
+	str, err := json.Marshal(testTable.jsonRows())
+	assert.NoError(t, err)
+	assert.Equal(t, `[{"Name":"site","Bytes":2048,"Running":false}]`, string(str))
+
+	str, err = json.Marshal(NewTable(TableColumn{Header: "Name"}).jsonRows())
+	assert.NoError(t, err)
+	assert.Equal(t, `[]`, string(str))
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "kana", truncate("kana", 0))
+	assert.Equal(t, "kana", truncate("kana", 4))
+	assert.Equal(t, "ka…", truncate("kana", 3))
+	assert.Equal(t, "…", truncate("kana", 1))
+}
@@ -0,0 +1,150 @@
+package console
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/logrusorgru/aurora/v4"
+)
+
+// themes are the built-in color themes that can be selected with the colorTheme setting.
+var themes = map[string]map[string]aurora.Color{
+	"default": {
+		"error":     aurora.RedFg,
+		"highlight": aurora.YellowFg,
+		"name":      aurora.BlueFg,
+		"success":   aurora.GreenFg,
+		"url":       aurora.GreenFg,
+		"warning":   aurora.YellowFg,
+	},
+	"high-contrast": {
+		"error":     aurora.BrightFg | aurora.RedFg,
+		"highlight": aurora.BrightFg | aurora.YellowFg,
+		"name":      aurora.BrightFg | aurora.CyanFg,
+		"success":   aurora.BrightFg | aurora.GreenFg,
+		"url":       aurora.BrightFg | aurora.WhiteFg,
+		"warning":   aurora.BrightFg | aurora.YellowFg,
+	},
+	// Based on the Okabe-Ito palette, which avoids relying on red and green
+	"colorblind": {
+		"error":     aurora.Color(0).Index(202),
+		"highlight": aurora.Color(0).Index(220),
+		"name":      aurora.Color(0).Index(39),
+		"success":   aurora.Color(0).Index(33),
+		"url":       aurora.Color(0).Index(39),
+		"warning":   aurora.Color(0).Index(220),
+	},
+}
+
+var colorNames = map[string]aurora.Color{
+	"black":   aurora.BlackFg,
+	"red":     aurora.RedFg,
+	"green":   aurora.GreenFg,
+	"yellow":  aurora.YellowFg,
+	"blue":    aurora.BlueFg,
+	"magenta": aurora.MagentaFg,
+	"cyan":    aurora.CyanFg,
+	"white":   aurora.WhiteFg,
+}
+
+// GetThemes returns the names of the built-in color themes.
+func GetThemes() []string {
+	return sortedKeys(themes)
+}
+
+// SetTheme sets the colors used for console output from a built-in theme and any per-element overrides.
+// Overrides are in the form element=color where color is a color name, optionally prefixed with bright-, or a number from 0 to 255.
+func (c *Console) SetTheme(theme string, overrides []string) error {
+	themeColors, ok := themes[theme]
+	if !ok {
+		return fmt.Errorf("invalid color theme %s. Valid themes are %s", theme, strings.Join(GetThemes(), ", "))
+	}
+
+	c.colors = make(map[string]aurora.Color, len(themeColors))
+
+	for element, color := range themeColors {
+		c.colors[element] = color
+	}
+
+	for _, override := range overrides {
+		element, color, err := parseColorOverride(override)
+		if err != nil {
+			return err
+		}
+
+		c.colors[element] = color
+	}
+
+	return nil
+}
+
+// ValidateColorOverrides checks that each override refers to a valid element and color.
+func ValidateColorOverrides(overrides []string) error {
+	for _, override := range overrides {
+		if override == "" {
+			continue
+		}
+
+		_, _, err := parseColorOverride(override)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// color returns the color to use for the given element.
+func (c *Console) color(element string) aurora.Color {
+	if color, ok := c.colors[element]; ok {
+		return color
+	}
+
+	return themes["default"][element]
+}
+
+func parseColorOverride(override string) (element string, color aurora.Color, err error) {
+	element, colorName, found := strings.Cut(strings.ToLower(strings.TrimSpace(override)), "=")
+
+	if _, ok := themes["default"][element]; !found || !ok {
+		return "", 0, fmt.Errorf(
+			"invalid color override %s. Overrides must be in the form element=color where element is one of %s",
+			override,
+			strings.Join(sortedKeys(themes["default"]), ", "))
+	}
+
+	index, err := strconv.ParseUint(colorName, 10, 8)
+	if err == nil {
+		return element, aurora.Color(0).Index(aurora.ColorIndex(index)), nil
+	}
+
+	bright := strings.HasPrefix(colorName, "bright-")
+
+	color, ok := colorNames[strings.TrimPrefix(colorName, "bright-")]
+	if !ok {
+		return "", 0, fmt.Errorf(
+			"invalid color %s for %s. Use a color name such as blue or bright-blue, or a number from 0 to 255",
+			colorName,
+			element)
+	}
+
+	if bright {
+		color |= aurora.BrightFg
+	}
+
+	return element, color, nil
+}
+
+func sortedKeys[T any](values map[string]T) []string {
+	keys := make([]string, 0, len(values))
+
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
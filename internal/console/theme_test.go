@@ -0,0 +1,63 @@
+package console
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsole_SetTheme(t *testing.T) {
+	console := &Console{}
+
+	err := console.SetTheme("high-contrast", []string{})
+	assert.NoError(t, err)
+	assert.Equal(t, "\x1b[96mHello, World!\x1b[0m", console.Blue("Hello, World!"))
+
+	err = console.SetTheme("default", []string{"name=magenta", "url=bright-white", "highlight=208"})
+	assert.NoError(t, err)
+	assert.Equal(t, "\x1b[35mHello, World!\x1b[0m", console.Blue("Hello, World!"))
+	assert.Equal(t, "\x1b[97mHello, World!\x1b[0m", console.Green("Hello, World!"))
+	assert.Equal(t, "\x1b[38;5;208mHello, World!\x1b[0m", console.Yellow("Hello, World!"))
+
+	err = console.SetTheme("unknown", []string{})
+	assert.Error(t, err)
+}
+
+func TestValidateColorOverrides(t *testing.T) {
+	var testCases = []struct {
+		name      string
+		overrides []string
+		valid     bool
+	}{
+		{
+			name:      "Empty overrides are valid",
+			overrides: []string{""},
+			valid:     true,
+		},
+		{
+			name:      "Named and numbered colors are valid",
+			overrides: []string{"error=bright-red", "success=33"},
+			valid:     true,
+		},
+		{
+			name:      "Unknown elements are invalid",
+			overrides: []string{"background=red"},
+			valid:     false,
+		},
+		{
+			name:      "Unknown colors are invalid",
+			overrides: []string{"error=purple"},
+			valid:     false,
+		},
+		{
+			name:      "Overrides without a color are invalid",
+			overrides: []string{"error"},
+			valid:     false,
+		},
+	}
+
+	for _, test := range testCases {
+		err := ValidateColorOverrides(test.overrides)
+		assert.Equal(t, test.valid, err == nil, test.name)
+	}
+}
@@ -0,0 +1,86 @@
+package console
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/aquasecurity/table"
+)
+
+type phaseTiming struct {
+	Phase    string
+	Duration time.Duration
+}
+
+type timingMessage struct {
+	Status string
+	Phases []phaseTiming
+	Total  time.Duration
+}
+
+// StartPhase starts timing a phase of the current command. Call the returned function when the phase is complete.
+// Phases with the same name, such as checking several images, are added together.
+func (c *Console) StartPhase(phase string) func() {
+	start := time.Now()
+
+	return func() {
+		duration := time.Since(start)
+
+		for i := range c.timings {
+			if c.timings[i].Phase == phase {
+				c.timings[i].Duration += duration
+				return
+			}
+		}
+
+		c.timings = append(c.timings, phaseTiming{Phase: phase, Duration: duration})
+	}
+}
+
+// PrintTimings displays how long each phase of the command took if timing or verbose output has been requested.
+func (c *Console) PrintTimings(total time.Duration) {
+	if (!c.Timing && !c.Debug) || len(c.timings) == 0 {
+		return
+	}
+
+	if c.LogFormat == "json" {
+		c.printMessage("Timing", "Command timing", map[string]interface{}{
+			"phases": c.timings,
+			"total":  total,
+		})
+
+		return
+	}
+
+	if c.JSON {
+		message := timingMessage{
+			Status: "Timing",
+			Phases: c.timings,
+			Total:  total,
+		}
+
+		str, _ := json.Marshal(message)
+
+		fmt.Println(string(str))
+
+		return
+	}
+
+	timingTable := table.New(os.Stdout)
+
+	timingTable.SetHeaders("Phase", "Duration")
+
+	for _, timing := range c.timings {
+		timingTable.AddRow(timing.Phase, formatDuration(timing.Duration))
+	}
+
+	timingTable.SetFooters("Total", formatDuration(total))
+
+	timingTable.Render()
+}
+
+func formatDuration(duration time.Duration) string {
+	return duration.Round(time.Millisecond).String()
+}
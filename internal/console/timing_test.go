@@ -0,0 +1,27 @@
+package console
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsole_StartPhase(t *testing.T) {
+	console := &Console{}
+
+	endPhase := console.StartPhase("Image checks")
+	time.Sleep(time.Millisecond)
+	endPhase()
+
+	endPhase = console.StartPhase("Image checks")
+	time.Sleep(time.Millisecond)
+	endPhase()
+
+	console.StartPhase("WordPress install")()
+
+	assert.Len(t, console.timings, 2)
+	assert.Equal(t, "Image checks", console.timings[0].Phase)
+	assert.GreaterOrEqual(t, console.timings[0].Duration, 2*time.Millisecond)
+	assert.Equal(t, "WordPress install", console.timings[1].Phase)
+}
@@ -1,14 +1,15 @@
 package docker
 
 import (
-	"bufio"
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io"
-	"os"
+	"maps"
 	"os/user"
-	"runtime"
 	"strings"
 	"time"
 
@@ -19,7 +20,6 @@ import (
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/api/types/strslice"
 	"github.com/docker/docker/pkg/stdcopy"
-	"golang.org/x/sync/errgroup"
 )
 
 type ContainerConfig struct {
@@ -32,6 +32,43 @@ type ContainerConfig struct {
 	Command     []string
 	Env         []string
 	Labels      map[string]string
+	ExtraHosts  []string
+	Init        bool
+}
+
+// configHashLabel stores a hash of the configuration a container was created with so changes to it can be detected.
+const configHashLabel = "kana.config-hash"
+
+// Hash returns a hash of the container's configuration, such as its image, environment variables and mounts.
+// Random host ports aren't part of the configuration so they don't change it.
+func (c *ContainerConfig) Hash() string {
+	config := *c
+	config.Labels = maps.Clone(c.Labels)
+
+	delete(config.Labels, configHashLabel)
+
+	encoded, _ := json.Marshal(config)
+	sum := sha256.Sum256(encoded)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// ContainerIsOutdated returns true if the container is running with a different configuration than the given one.
+// Containers created before their configuration was tracked are never outdated.
+func (d *Client) ContainerIsOutdated(config *ContainerConfig) bool {
+	containerID, isRunning := d.containerIsRunning(config.Name)
+	if !isRunning {
+		return false
+	}
+
+	results, err := d.apiClient.ContainerInspect(context.Background(), containerID)
+	if err != nil || results.Config == nil {
+		return false
+	}
+
+	hash, ok := results.Config.Labels[configHashLabel]
+
+	return ok && hash != config.Hash()
 }
 
 type ExecResult struct {
@@ -40,6 +77,13 @@ type ExecResult struct {
 	ExitCode int
 }
 
+// LogOptions selects the part of a container's log streamed by ContainerLogsStream.
+type LogOptions struct {
+	Follow bool   // Keep streaming new output until the container stops
+	Tail   string // The number of lines to show from the end of the log, or all
+	Since  string // Only show output since this timestamp or relative time, such as 10m
+}
+
 func (d *Client) ContainerExec(containerName string, rootUser bool, command []string) (ExecResult, error) {
 	containerID, isRunning := d.containerIsRunning(containerName)
 	if !isRunning {
@@ -114,6 +158,103 @@ func (d *Client) ContainerExec(containerName string, rootUser bool, command []st
 		nil
 }
 
+// ContainerExecStream Runs a command in a running container, streaming its output as it runs, and returns the command's exit code.
+func (d *Client) ContainerExecStream(
+	containerName string,
+	rootUser bool,
+	command []string,
+	stdin io.Reader,
+	stdout, stderr io.Writer) (int, error) {
+	containerID, isRunning := d.containerIsRunning(containerName)
+	if !isRunning {
+		return 1, fmt.Errorf("the container %s is not running", containerName)
+	}
+
+	execConfig := container.ExecOptions{
+		AttachStdin:  stdin != nil,
+		AttachStdout: true,
+		AttachStderr: true,
+		Cmd:          strslice.StrSlice(command),
+	}
+
+	if rootUser {
+		execConfig.User = "root"
+	}
+
+	containerResponse, err := d.apiClient.ContainerExecCreate(context.Background(), containerID, execConfig)
+	if err != nil {
+		return 1, err
+	}
+
+	execID := containerResponse.ID
+
+	apiResponse, err := d.apiClient.ContainerExecAttach(context.Background(), execID, container.ExecStartOptions{})
+	if err != nil {
+		return 1, err
+	}
+
+	defer apiResponse.Close()
+
+	if stdin != nil {
+		go func() {
+			_, _ = io.Copy(apiResponse.Conn, stdin)
+			_ = apiResponse.CloseWrite()
+		}()
+	}
+
+	// StdCopy demultiplexes the stream as it arrives so the output is shown as the command runs
+	_, err = stdcopy.StdCopy(stdout, stderr, apiResponse.Reader)
+	if err != nil {
+		return 1, err
+	}
+
+	inspectResponse, err := d.apiClient.ContainerExecInspect(context.Background(), execID)
+	if err != nil {
+		return 1, err
+	}
+
+	return inspectResponse.ExitCode, nil
+}
+
+// ContainerInspect Returns the full details of the given container.
+func (d *Client) ContainerInspect(id string) (types.ContainerJSON, error) {
+	return d.apiClient.ContainerInspect(context.Background(), id)
+}
+
+// ContainerLogs Returns the log of the given container.
+func (d *Client) ContainerLogs(id string) (string, error) {
+	return d.containerLog(id)
+}
+
+// ContainerLogsStream writes the log of the given container to output, following it until the container stops if
+// options.Follow is true.
+func (d *Client) ContainerLogsStream(containerName string, options LogOptions, output io.Writer) error {
+	containerID, isRunning := d.containerIsRunning(containerName)
+	if !isRunning {
+		return fmt.Errorf("the %s container isn't running", containerName)
+	}
+
+	reader, err := d.apiClient.ContainerLogs(context.Background(), containerID, container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     options.Follow,
+		Tail:       options.Tail,
+		Since:      options.Since})
+	if err != nil {
+		return err
+	}
+
+	defer reader.Close()
+
+	// The container uses a TTY so the log isn't multiplexed and can be copied as-is
+	_, err = io.Copy(output, reader)
+	if err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
+
 // ContainerGetMounts Returns a slice containing all the mounts to the given container.
 func (d *Client) ContainerGetMounts(containerName string) []types.MountPoint {
 	containerID, isRunning := d.containerIsRunning(containerName)
@@ -126,6 +267,13 @@ func (d *Client) ContainerGetMounts(containerName string) []types.MountPoint {
 	return results.Mounts
 }
 
+// ContainerIsRunning returns true if the named container is running.
+func (d *Client) ContainerIsRunning(containerName string) bool {
+	_, isRunning := d.containerIsRunning(containerName)
+
+	return isRunning
+}
+
 // containerIsRunning Checks if a given container is running by name.
 func (d *Client) containerIsRunning(containerName string) (id string, isRunning bool) {
 	containers, err := d.apiClient.ContainerList(context.Background(), container.ListOptions{})
@@ -164,6 +312,86 @@ func (d *Client) ContainerList(site string) ([]types.Container, error) {
 	return containers, err
 }
 
+// ContainerListGlobal lists the running containers shared by every site, such as Traefik.
+func (d *Client) ContainerListGlobal() ([]types.Container, error) {
+	f := filters.NewArgs()
+	f.Add("label", "kana.global")
+
+	return d.apiClient.ContainerList(context.Background(), container.ListOptions{Filters: f})
+}
+
+// ContainerRemoveExited removes the stopped containers with the given label, such as kana.site=<site> or kana.global.
+// Containers are left stopped, rather than removed, when Docker restarts and would stop new ones with their names from
+// being created.
+func (d *Client) ContainerRemoveExited(label string) (removed int, err error) {
+	f := filters.NewArgs()
+	f.Add("label", label)
+	f.Add("status", "exited")
+	f.Add("status", "created")
+
+	containers, err := d.apiClient.ContainerList(context.Background(), container.ListOptions{All: true, Filters: f})
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range containers {
+		err = d.apiClient.ContainerRemove(context.Background(), containers[i].ID, container.RemoveOptions{})
+		if err != nil {
+			return removed, err
+		}
+
+		removed++
+	}
+
+	return removed, nil
+}
+
+// ContainerRecreate replaces a running container with a new one created from the same configuration, such as to run it
+// from a newer download of its image. Its host ports, mounts and networks are kept.
+func (d *Client) ContainerRecreate(id string) error {
+	results, err := d.apiClient.ContainerInspect(context.Background(), id)
+	if err != nil {
+		return err
+	}
+
+	if results.Config == nil || results.HostConfig == nil {
+		return fmt.Errorf("unable to read the configuration of %s", strings.Trim(results.Name, "/"))
+	}
+
+	networkConfig := network.NetworkingConfig{
+		EndpointsConfig: map[string]*network.EndpointSettings{},
+	}
+
+	if results.NetworkSettings != nil {
+		for networkName, endpoint := range results.NetworkSettings.Networks {
+			networkConfig.EndpointsConfig[networkName] = &network.EndpointSettings{Aliases: endpoint.Aliases}
+		}
+	}
+
+	err = d.apiClient.ContainerStop(context.Background(), id, container.StopOptions{})
+	if err != nil {
+		return err
+	}
+
+	err = d.apiClient.ContainerRemove(context.Background(), id, container.RemoveOptions{})
+	if err != nil {
+		return err
+	}
+
+	resp, err := d.apiClient.ContainerCreate(
+		context.Background(),
+		results.Config,
+		results.HostConfig,
+		&networkConfig,
+		nil,
+		strings.Trim(results.Name, "/"))
+	if err != nil {
+		return err
+	}
+
+	return d.apiClient.ContainerStart(context.Background(), resp.ID, container.StartOptions{})
+}
+
 func (d *Client) containerLog(id string) (result string, err error) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(sleepDuration)*time.Second)
 	defer cancel()
@@ -185,6 +413,29 @@ func (d *Client) containerLog(id string) (result string, err error) {
 	return string(buffer), nil
 }
 
+// containerStreamLog Follows the container's log until it exits, writing it to output and returning the complete log.
+func (d *Client) containerStreamLog(id string, output io.Writer) (result string, err error) {
+	reader, err := d.apiClient.ContainerLogs(context.Background(), id, container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     true})
+	if err != nil {
+		return "", err
+	}
+
+	defer reader.Close()
+
+	var buffer bytes.Buffer
+
+	// The container uses a TTY so the log isn't multiplexed and can be copied as-is
+	_, err = io.Copy(io.MultiWriter(&buffer, output), reader)
+	if err != nil && err != io.EOF {
+		return buffer.String(), err
+	}
+
+	return buffer.String(), nil
+}
+
 func (d *Client) ContainerRestart(containerName string) (bool, error) {
 	containerID, isRunning := d.containerIsRunning(containerName)
 	if !isRunning {
@@ -204,16 +455,40 @@ func (d *Client) ContainerRestart(containerName string) (bool, error) {
 	return true, nil
 }
 
-func (d *Client) ContainerRun(config *ContainerConfig, randomPorts, localUser bool) (id string, err error) {
+// ContainerRun Creates and starts a container unless it is already running. A container that was created but couldn't
+// be started, such as when the context is cancelled, is removed again.
+func (d *Client) ContainerRun(ctx context.Context, config *ContainerConfig, randomPorts, localUser bool) (id string, err error) {
 	containerID, isRunning := d.containerIsRunning(config.Name)
 	if isRunning {
 		return containerID, nil
 	}
 
+	containerID, err = d.containerCreate(ctx, config, randomPorts, localUser, false)
+	if err != nil {
+		return "", err
+	}
+
+	err = d.apiClient.ContainerStart(ctx, containerID, container.StartOptions{})
+	if err != nil {
+		// The context may already be cancelled so the container is removed without it
+		_ = d.apiClient.ContainerRemove(context.Background(), containerID, container.RemoveOptions{Force: true})
+
+		return "", err
+	}
+
+	return containerID, nil
+}
+
+// containerCreate Creates, but does not start, a container from the given configuration.
+// Interactive containers only get a TTY when the user's terminal is attached, sized to match it, so piped input and output work.
+func (d *Client) containerCreate(
+	ctx context.Context,
+	config *ContainerConfig,
+	randomPorts, localUser, interactive bool) (id string, err error) {
 	hostConfig := container.HostConfig{}
 	containerPorts, err := getNetworkConfig(config.Ports, randomPorts)
 	if err != nil {
-		return containerID, err
+		return "", err
 	}
 
 	if len(containerPorts.PortBindings) > 0 {
@@ -229,6 +504,19 @@ func (d *Client) ContainerRun(config *ContainerConfig, randomPorts, localUser bo
 	}
 
 	hostConfig.Mounts = config.Volumes
+	hostConfig.ExtraHosts = config.ExtraHosts
+
+	// An init process forwards signals, such as Ctrl-C, to the command instead of it running as PID 1, which ignores them
+	if config.Init {
+		hostConfig.Init = &config.Init
+	}
+
+	labels := maps.Clone(config.Labels)
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	labels[configHashLabel] = config.Hash()
 
 	containerConfig := &container.Config{
 		Tty:          true,
@@ -237,99 +525,72 @@ func (d *Client) ContainerRun(config *ContainerConfig, randomPorts, localUser bo
 		Cmd:          config.Command,
 		Hostname:     config.HostName,
 		Env:          config.Env,
-		Labels:       config.Labels,
+		Labels:       labels,
 		OpenStdin:    true,
 		AttachStdin:  true,
 		AttachStdout: true,
 		AttachStderr: true,
 	}
 
-	// Linux doesn't abstract the user so we have to do it ourselves
-	if localUser && runtime.GOOS == "linux" {
+	if interactive {
+		containerConfig.Tty, hostConfig.ConsoleSize, containerConfig.Env = getTerminalConfig(config.Env)
+
+		// Close the container's stdin when the input piped to it ends
+		containerConfig.StdinOnce = !containerConfig.Tty
+	}
+
+	// Run as the host user so files created in the container can always be edited on the host
+	if localUser {
 		var currentUser *user.User
 
 		currentUser, err = user.Current()
 		if err != nil {
-			return containerID, err
+			return "", err
 		}
 
 		containerConfig.User = fmt.Sprintf("%s:%s", currentUser.Uid, currentUser.Gid)
 	}
 
-	resp, err := d.apiClient.ContainerCreate(context.Background(), containerConfig, &hostConfig, &networkConfig, nil, config.Name)
-	if err != nil {
-		return "", err
-	}
+	err = withRetry(ctx, func() error {
+		resp, createErr := d.apiClient.ContainerCreate(ctx, containerConfig, &hostConfig, &networkConfig, nil, config.Name)
+		id = resp.ID
 
-	err = d.apiClient.ContainerStart(context.Background(), resp.ID, container.StartOptions{})
-	if err != nil {
-		return "", err
-	}
+		return createErr
+	})
 
-	return resp.ID, nil
+	return id, err
 }
 
-func (d *Client) showInteractiveTerminal(containerID string) error {
-	waiter, err := d.apiClient.ContainerAttach(context.Background(), containerID, container.AttachOptions{
-		Stderr: true,
-		Stdout: true,
-		Stdin:  true,
-		Stream: true,
-	})
-	if err != nil {
-		return err
+// ContainerRunAndClean Runs a container to completion, returning its output, and removes it.
+// If output is not nil the container's output is also written to it as the container runs.
+func (d *Client) ContainerRunAndClean(
+	config *ContainerConfig,
+	interactive bool,
+	output io.Writer) (statusCode int64, body string, err error) {
+	if interactive {
+		return d.containerRunInteractive(config)
 	}
 
-	errs, _ := errgroup.WithContext(context.Background())
-
-	errs.Go(func() error {
-		_, err := io.Copy(os.Stdout, waiter.Reader)
-		return err
-	})
-
-	errs.Go(func() error {
-		_, err := io.Copy(os.Stderr, waiter.Reader)
-		return err
-	})
-
-	inOut := make(chan []byte)
-
-	go func() {
-		scanner := bufio.NewScanner(os.Stdin)
-		for scanner.Scan() {
-			inOut <- []byte(scanner.Text())
-		}
-	}()
-
-	// Write to docker container
-	go func(w io.WriteCloser) {
-		for {
-			data, ok := <-inOut
-			if !ok {
-				fmt.Println("!ok")
-				w.Close()
-				return
-			}
-
-			_, err := w.Write(append(data, '\n'))
-			if err != nil {
-				panic(err)
-			}
-		}
-	}(waiter.Conn)
-
-	return nil
-}
-
-func (d *Client) ContainerRunAndClean(config *ContainerConfig, interactive bool) (statusCode int64, body string, err error) {
 	// Start the container
-	id, err := d.ContainerRun(config, false, true)
+	id, err := d.ContainerRun(context.Background(), config, false, true)
 	if err != nil {
 		return statusCode, body, err
 	}
 
-	if interactive {
-		err = d.showInteractiveTerminal(id)
+	// The container is removed however the run ends, forcing it if streaming its output failed while it was running
+	defer func() {
+		removeErr := d.apiClient.ContainerRemove(context.Background(), id, container.RemoveOptions{Force: true})
+		if err == nil {
+			err = removeErr
+		}
+	}()
+
+	stopForwarding := d.forwardSignals(id)
+	defer stopForwarding()
+
+	// Stream the output while the container runs
+	if output != nil {
+		body, err = d.containerStreamLog(id, output)
 		if err != nil {
 			return statusCode, body, err
 		}
@@ -341,13 +602,12 @@ func (d *Client) ContainerRunAndClean(config *ContainerConfig, interactive bool)
 		return statusCode, body, err
 	}
 
-	// Get the output if we're not running interactively
-	if !interactive {
+	// Get the output
+	if output == nil {
 		body, _ = d.containerLog(id)
 	}
 
-	err = d.apiClient.ContainerRemove(context.Background(), id, container.RemoveOptions{})
-	return statusCode, body, err
+	return statusCode, body, nil
 }
 
 func (d *Client) ContainerStop(containerName string) (bool, error) {
@@ -0,0 +1,123 @@
+package docker
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/ChrisWiegman/kana/internal/docker/mocks"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestContainerConfig_Hash(t *testing.T) {
+	config := ContainerConfig{
+		Name:  "kana-test-wordpress",
+		Image: "wordpress:php8.2",
+		Env:   []string{"IS_KANA_ENVIRONMENT=true"},
+		Volumes: []mount.Mount{
+			{Type: mount.TypeBind, Source: "/tmp/test", Target: "/var/www/html"},
+		},
+		Labels: map[string]string{"kana.site": "test"},
+	}
+
+	hash := config.Hash()
+
+	same := config
+	same.Labels = map[string]string{"kana.site": "test", configHashLabel: "stale"}
+
+	if same.Hash() != hash {
+		t.Errorf("Expected the stored hash label to be ignored")
+	}
+
+	newImage := config
+	newImage.Image = "wordpress:php8.3"
+
+	if newImage.Hash() == hash {
+		t.Errorf("Expected changing the image to change the hash")
+	}
+
+	newEnv := config
+	newEnv.Env = []string{"IS_KANA_ENVIRONMENT=true", "WORDPRESS_DEBUG=1"}
+
+	if newEnv.Hash() == hash {
+		t.Errorf("Expected changing the environment variables to change the hash")
+	}
+
+	newMounts := config
+	newMounts.Volumes = []mount.Mount{
+		{Type: mount.TypeBind, Source: "/tmp/other", Target: "/var/www/html"},
+	}
+
+	if newMounts.Hash() == hash {
+		t.Errorf("Expected changing the mounts to change the hash")
+	}
+
+	if config.Labels[configHashLabel] != "" {
+		t.Errorf("Expected hashing not to change the config's labels")
+	}
+}
+
+func TestContainerRemoveExited(t *testing.T) {
+	var tests = []struct {
+		name            string
+		containers      []types.Container
+		removeErr       error
+		expectedRemoved int
+		expectedError   error
+	}{
+		{
+			"nothing to remove",
+			[]types.Container{},
+			nil,
+			0,
+			nil},
+		{
+			"containers left by a Docker restart",
+			[]types.Container{{ID: "database"}, {ID: "wordpress"}},
+			nil,
+			2,
+			nil},
+		{
+			"remove failed",
+			[]types.Container{{ID: "database"}},
+			fmt.Errorf("container remove function hit error"),
+			0,
+			fmt.Errorf("container remove function hit error")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			apiClient := new(mocks.APIClient)
+			apiClient.On("ContainerList", mock.Anything, mock.Anything).Return(test.containers, nil)
+			apiClient.On("ContainerRemove", mock.Anything, mock.Anything, mock.Anything).Return(test.removeErr)
+
+			d := &Client{apiClient: apiClient}
+
+			removed, err := d.ContainerRemoveExited("kana.site=test")
+
+			assert.Equal(t, test.expectedRemoved, removed)
+			assert.Equal(t, test.expectedError, err)
+		})
+	}
+}
+
+func TestContainerRunAndCleanRemovesContainer(t *testing.T) {
+	apiClient := new(mocks.APIClient)
+	apiClient.On("ContainerList", mock.Anything, mock.Anything).Return(
+		[]types.Container{{ID: "wordpress_cli", Names: []string{"/kana-test-wordpress_cli"}}}, nil)
+	apiClient.On("ContainerLogs", mock.Anything, "wordpress_cli", mock.Anything).Return(
+		nil, fmt.Errorf("container logs function hit error"))
+	apiClient.On("ContainerRemove", mock.Anything, "wordpress_cli", container.RemoveOptions{Force: true}).Return(nil)
+
+	d := &Client{apiClient: apiClient}
+
+	_, _, err := d.ContainerRunAndClean(&ContainerConfig{Name: "kana-test-wordpress_cli"}, false, io.Discard)
+
+	assert.Equal(t, fmt.Errorf("container logs function hit error"), err)
+	apiClient.AssertCalled(t, "ContainerRemove", mock.Anything, "wordpress_cli", container.RemoveOptions{Force: true})
+}
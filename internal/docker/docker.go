@@ -11,10 +11,12 @@ import (
 	"fmt"
 	"os/exec"
 	"strings"
+	"sync"
 
 	"github.com/ChrisWiegman/kana/internal/console"
 
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/system"
 	"github.com/docker/docker/client"
 	"github.com/knadh/koanf/v2"
 )
@@ -28,6 +30,7 @@ type Client struct {
 	apiClient       APIClient
 	imageUpdateData *koanf.Koanf
 	checkedImages   []string
+	imagesMutex     sync.Mutex // Guards checkedImages and imageUpdateData as images can be pulled in parallel
 }
 
 type Context struct {
@@ -114,3 +117,8 @@ func ensureDockerIsAvailable(apiClient APIClient) error {
 
 	return nil
 }
+
+// Info Returns information about the docker server.
+func (d *Client) Info() (system.Info, error) {
+	return d.apiClient.Info(context.Background())
+}
@@ -0,0 +1,74 @@
+package docker
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/mitchellh/go-homedir"
+)
+
+// EnsureFileSharing returns an error if Docker Desktop on macOS can't mount the directory into containers as it isn't in
+// one of the directories shared in Docker Desktop's file sharing settings. Other Docker apps, such as OrbStack or Colima,
+// aren't checked.
+func EnsureFileSharing(directory string) error {
+	if runtime.GOOS != "darwin" {
+		return nil
+	}
+
+	home, err := homedir.Dir()
+	if err != nil {
+		return err
+	}
+
+	sharedDirectories, ok := getSharedDirectories(filepath.Join(home, "Library", "Group Containers", "group.com.docker"))
+	if !ok || isSharedDirectory(directory, sharedDirectories) {
+		return nil
+	}
+
+	return fmt.Errorf(
+		"%s isn't shared with Docker Desktop so it can't be used by the site's containers. "+
+			"Add it, or the disk it is on, in Docker Desktop's Settings under Resources > File sharing and try again",
+		directory)
+}
+
+// getSharedDirectories returns the directories shared in Docker Desktop's settings or false if Docker Desktop's
+// settings can't be found.
+func getSharedDirectories(settingsDirectory string) ([]string, bool) {
+	// Newer versions of Docker Desktop save their settings to settings-store.json
+	for _, settingsFile := range []string{"settings-store.json", "settings.json"} {
+		contents, err := os.ReadFile(filepath.Join(settingsDirectory, settingsFile))
+		if err != nil {
+			continue
+		}
+
+		// Keys are matched without case so this reads both FilesharingDirectories and filesharingDirectories
+		var settings struct {
+			FilesharingDirectories []string `json:"filesharingDirectories"`
+		}
+
+		err = json.Unmarshal(contents, &settings)
+		if err != nil || settings.FilesharingDirectories == nil {
+			continue
+		}
+
+		return settings.FilesharingDirectories, true
+	}
+
+	return nil, false
+}
+
+func isSharedDirectory(directory string, sharedDirectories []string) bool {
+	for _, sharedDirectory := range sharedDirectories {
+		sharedDirectory = filepath.Clean(sharedDirectory)
+
+		if directory == sharedDirectory || strings.HasPrefix(directory, sharedDirectory+string(filepath.Separator)) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,55 @@
+package docker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSharedDirectories(t *testing.T) {
+	settingsDirectory := t.TempDir()
+
+	_, ok := getSharedDirectories(settingsDirectory)
+	assert.False(t, ok)
+
+	assert.NoError(t, os.WriteFile(
+		filepath.Join(settingsDirectory, "settings.json"),
+		[]byte(`{"filesharingDirectories":["/Users","/tmp"]}`),
+		0600))
+
+	sharedDirectories, ok := getSharedDirectories(settingsDirectory)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"/Users", "/tmp"}, sharedDirectories)
+
+	assert.NoError(t, os.WriteFile(
+		filepath.Join(settingsDirectory, "settings-store.json"),
+		[]byte(`{"FilesharingDirectories":["/Users","/Volumes"]}`),
+		0600))
+
+	sharedDirectories, ok = getSharedDirectories(settingsDirectory)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"/Users", "/Volumes"}, sharedDirectories)
+}
+
+func TestIsSharedDirectory(t *testing.T) {
+	sharedDirectories := []string{"/Users", "/Volumes/Sites/"}
+
+	var tests = []struct {
+		directory string
+		expected  bool
+	}{
+		{"/Users", true},
+		{"/Users/kana/sites", true},
+		{"/Volumes/Sites/client", true},
+		{"/Volumes/Other", false},
+		{"/UsersOther", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.directory, func(t *testing.T) {
+			assert.Equal(t, test.expected, isSharedDirectory(test.directory, sharedDirectories))
+		})
+	}
+}
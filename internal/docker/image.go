@@ -8,12 +8,15 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/ChrisWiegman/kana/internal/console"
 
+	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/errdefs"
 	kjson "github.com/knadh/koanf/parsers/json"
 	"github.com/knadh/koanf/providers/file"
 	"github.com/knadh/koanf/v2"
@@ -23,37 +26,43 @@ import (
 
 var displayJSONMessagesStream = jsonmessage.DisplayJSONMessagesStream
 
+const imagesFilePermissions = 0o644
+
+// EnsureImage Pulls the image if it hasn't been downloaded or is due to be checked for updates. Cancelling the context
+// stops the pull.
 // https://gist.github.com/miguelmota/4980b18d750fb3b1eb571c3e207b1b92
 // https://riptutorial.com/docker/example/31980/image-pulling-with-progress-bars--written-in-go
-func (d *Client) EnsureImage(imageName, appDirectory string, updateDays int64, consoleOutput *console.Console) (err error) {
+func (d *Client) EnsureImage(
+	ctx context.Context,
+	imageName, appDirectory string,
+	updateDays int64,
+	consoleOutput *console.Console) (err error) {
+	defer consoleOutput.StartPhase("Image checks")()
+
 	if !strings.Contains(imageName, ":") {
 		imageName = fmt.Sprintf("%s:latest", imageName)
 	}
 
 	// Skip more complicated checks if we can
-	for _, checkedImage := range d.checkedImages {
-		if checkedImage == imageName {
-			return nil
-		}
+	if d.isImageChecked(imageName) {
+		return nil
 	}
 
-	return d.maybeUpdateImage(imageName, updateDays, consoleOutput.JSON, appDirectory)
+	return d.maybeUpdateImage(ctx, imageName, updateDays, consoleOutput.JSON, appDirectory)
 }
 
+// ValidateImage checks Docker Hub for the image tag. Network failures are retried and returned wrapping ErrNetwork so they
+// aren't mistaken for a tag that doesn't exist.
 func ValidateImage(imageName, imageTag string) error {
 	requestURL := fmt.Sprintf("https://hub.docker.com/v2/namespaces/library/repositories/%s/tags/%s", imageName, imageTag)
-	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, requestURL, http.NoBody)
-	if err != nil {
-		return err
-	}
 
-	res, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return err
-	}
-	defer res.Body.Close()
+	var resBody []byte
+
+	err := withRetry(context.Background(), func() (err error) {
+		resBody, err = getRegistryResponse(requestURL)
 
-	resBody, err := io.ReadAll(res.Body)
+		return err
+	})
 	if err != nil {
 		return err
 	}
@@ -72,10 +81,31 @@ func ValidateImage(imageName, imageTag string) error {
 	return err
 }
 
-func (d *Client) maybeUpdateImage(imageName string, updateDays int64, suppressOutput bool, appDirectory string) error {
+func getRegistryResponse(requestURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, requestURL, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= http.StatusInternalServerError {
+		return nil, fmt.Errorf("%w: %s", errRegistryUnavailable, res.Status)
+	}
+
+	return io.ReadAll(res.Body)
+}
+
+func (d *Client) maybeUpdateImage(ctx context.Context, imageName string, updateDays int64, suppressOutput bool, appDirectory string) error {
+	d.imagesMutex.Lock()
 	lastUpdated := d.imageUpdateData.Time(imageName, time.RFC3339)
+	d.imagesMutex.Unlock()
 
-	imageList, err := d.apiClient.ImageList(context.Background(), image.ListOptions{})
+	imageList, err := d.apiClient.ImageList(ctx, image.ListOptions{})
 	if err != nil {
 		return err
 	}
@@ -101,39 +131,94 @@ func (d *Client) maybeUpdateImage(imageName string, updateDays int64, suppressOu
 
 	// Pull the image or a newer image if needed
 	if !hasImage || checkForUpdate {
-		reader, err := d.apiClient.ImagePull(context.Background(), imageName, image.PullOptions{})
-		if err != nil {
-			return err
-		}
+		return d.pullImage(ctx, imageName, suppressOutput, appDirectory)
+	}
 
-		defer func() {
-			if err = reader.Close(); err != nil {
-				panic(err)
-			}
-		}()
+	d.imagesMutex.Lock()
+	defer d.imagesMutex.Unlock()
 
-		out := os.Stdout
+	d.checkedImages = append(d.checkedImages, imageName)
 
-		// Discard the download information if set to suppress
-		if suppressOutput {
-			out, _ = os.Open(os.DevNull)
-		}
+	return nil
+}
 
-		err = d.setImageUpdate(imageName, time.Now(), appDirectory)
-		if err != nil {
-			return err
-		}
+// PullImage downloads the newest image for the image's tag, however recently it was last checked. Images can be pulled in
+// parallel but their download progress should then be suppressed so it isn't interleaved.
+func (d *Client) PullImage(ctx context.Context, imageName, appDirectory string, suppressOutput bool) error {
+	if !strings.Contains(imageName, ":") {
+		imageName = fmt.Sprintf("%s:latest", imageName)
+	}
+
+	return d.pullImage(ctx, imageName, suppressOutput, appDirectory)
+}
 
-		termFd, isTerm := term.GetFdInfo(os.Stdout)
+// ImageID returns the ID of the downloaded image with the given name, or an empty string if it hasn't been downloaded.
+func (d *Client) ImageID(imageName string) (string, error) {
+	f := filters.NewArgs()
+	f.Add("reference", imageName)
 
-		d.checkedImages = append(d.checkedImages, imageName)
+	imageList, err := d.apiClient.ImageList(context.Background(), image.ListOptions{Filters: f})
+	if err != nil || len(imageList) == 0 {
+		return "", err
+	}
+
+	return imageList[0].ID, nil
+}
+
+// pullImage downloads the image, starting the download again if the network drops partway through. Docker keeps the layers
+// that finished so a retried pull only downloads what is left.
+func (d *Client) pullImage(ctx context.Context, imageName string, suppressOutput bool, appDirectory string) error {
+	err := withRetry(ctx, func() error {
+		return d.streamImagePull(ctx, imageName, suppressOutput)
+	})
+	if err != nil {
+		if errdefs.IsNotFound(err) || errdefs.IsUnauthorized(err) {
+			return fmt.Errorf("unable to download the image %s. Check the image and version in your configuration: %w", imageName, err)
+		}
 
-		return displayJSONMessagesStream(reader, out, termFd, isTerm, nil)
+		return err
 	}
 
+	// Images are pulled in parallel so the checked images and images.json are only updated by one pull at a time
+	d.imagesMutex.Lock()
+	defer d.imagesMutex.Unlock()
+
+	// Only a completed pull counts as checked so an interrupted one is tried again next time
 	d.checkedImages = append(d.checkedImages, imageName)
 
-	return nil
+	return d.setImageUpdate(imageName, time.Now(), appDirectory)
+}
+
+// isImageChecked reports whether the image has already been checked for updates by this command.
+func (d *Client) isImageChecked(imageName string) bool {
+	d.imagesMutex.Lock()
+	defer d.imagesMutex.Unlock()
+
+	return slices.Contains(d.checkedImages, imageName)
+}
+
+func (d *Client) streamImagePull(ctx context.Context, imageName string, suppressOutput bool) error {
+	reader, err := d.apiClient.ImagePull(ctx, imageName, image.PullOptions{})
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if closeErr := reader.Close(); closeErr != nil {
+			panic(closeErr)
+		}
+	}()
+
+	out := os.Stdout
+
+	// Discard the download information if set to suppress
+	if suppressOutput {
+		out, _ = os.Open(os.DevNull)
+	}
+
+	termFd, isTerm := term.GetFdInfo(os.Stdout)
+
+	return displayJSONMessagesStream(reader, out, termFd, isTerm, nil)
 }
 
 func (d *Client) removeImage(imageName string) (removed bool, err error) {
@@ -173,23 +258,44 @@ func (d *Client) loadImageUpdateData(appDirectory string) (*koanf.Koanf, error)
 	return imageUpdateData, nil
 }
 
+// setImageUpdate records when the image was last pulled. The caller must hold imagesMutex. images.json is written to a
+// temporary file first and moved into place so an interrupted write can't leave it corrupted.
 func (d *Client) setImageUpdate(imageName string, timeStamp time.Time, appDirectory string) error {
 	err := d.imageUpdateData.Set(imageName, timeStamp.Format(time.RFC3339))
 	if err != nil {
 		return err
 	}
 
+	jsonBytes, err := d.imageUpdateData.Marshal(kjson.Parser())
+	if err != nil {
+		return err
+	}
+
 	configFile := filepath.Join(appDirectory, "config", "images.json")
 
-	f, _ := os.Create(configFile)
-	defer f.Close()
+	f, err := os.CreateTemp(filepath.Dir(configFile), "images-*.json")
+	if err != nil {
+		return err
+	}
 
-	jsonBytes, err := d.imageUpdateData.Marshal(kjson.Parser())
+	defer os.Remove(f.Name())
+
+	err = f.Chmod(imagesFilePermissions)
 	if err != nil {
+		f.Close()
 		return err
 	}
 
 	_, err = f.Write(jsonBytes)
+	if err != nil {
+		f.Close()
+		return err
+	}
 
-	return err
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), configFile)
 }
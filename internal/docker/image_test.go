@@ -1,7 +1,12 @@
 package docker
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
 
 	"github.com/ChrisWiegman/kana/internal/console"
@@ -62,3 +67,103 @@ func TestRemoveImage(t *testing.T) {
 		})
 	}
 }
+
+func TestImageID(t *testing.T) {
+	consoleOutput := new(console.Console)
+
+	d, err := New(consoleOutput, "")
+	assert.NoError(t, err)
+
+	var tests = []struct {
+		name          string
+		imageList     []image.Summary
+		imageListErr  error
+		expectedID    string
+		expectedError error
+	}{
+		{
+			"image hasn't been downloaded",
+			[]image.Summary{},
+			nil,
+			"",
+			nil},
+		{
+			"image has been downloaded",
+			[]image.Summary{
+				{ID: "sha256:1234"},
+			},
+			nil,
+			"sha256:1234",
+			nil},
+		{
+			"image list failed",
+			[]image.Summary{},
+			fmt.Errorf("image list function hit error"),
+			"",
+			fmt.Errorf("image list function hit error")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			apiClient := new(mocks.APIClient)
+			apiClient.On("ImageList", mock.Anything, mock.Anything).Return(test.imageList, test.imageListErr)
+
+			d.apiClient = apiClient
+
+			id, err := d.ImageID("nginx:alpine")
+			assert.Equal(t, test.expectedError, err, test.name)
+			assert.Equal(t, test.expectedID, id, test.name)
+		})
+	}
+}
+
+func TestPullImageInParallel(t *testing.T) {
+	appDirectory := t.TempDir()
+
+	err := os.Mkdir(filepath.Join(appDirectory, "config"), 0o755)
+	assert.NoError(t, err)
+
+	apiClient := new(mocks.APIClient)
+	apiClient.On("ImagePull", mock.Anything, mock.Anything, mock.Anything).Return(&mocks.ReadCloser{ExpectedErr: io.EOF}, nil)
+
+	d := &Client{apiClient: apiClient}
+	d.imageUpdateData, err = d.loadImageUpdateData(appDirectory)
+	assert.NoError(t, err)
+
+	originalDisplayJSONMessagesStream := displayJSONMessagesStream
+	displayJSONMessagesStream = mocks.MockDisplayJSONMessagesStream
+
+	defer func() {
+		displayJSONMessagesStream = originalDisplayJSONMessagesStream
+	}()
+
+	imageNames := []string{"alpine", "nginx", "mariadb", "redis", "mailpit", "phpmyadmin", "wordpress", "traefik"}
+
+	var wg sync.WaitGroup
+
+	errs := make([]error, len(imageNames))
+
+	for i, imageName := range imageNames {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			errs[i] = d.PullImage(context.Background(), imageName, appDirectory, true)
+		}()
+	}
+
+	wg.Wait()
+
+	for i, imageName := range imageNames {
+		assert.NoError(t, errs[i], imageName)
+		assert.True(t, d.isImageChecked(imageName+":latest"), imageName)
+	}
+
+	imageUpdateData, err := d.loadImageUpdateData(appDirectory)
+	assert.NoError(t, err)
+
+	for _, imageName := range imageNames {
+		assert.True(t, imageUpdateData.Exists(imageName+":latest"), imageName)
+	}
+}
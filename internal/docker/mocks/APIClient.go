@@ -15,6 +15,8 @@ import (
 
 	network "github.com/docker/docker/api/types/network"
 
+	system "github.com/docker/docker/api/types/system"
+
 	types "github.com/docker/docker/api/types"
 
 	v1 "github.com/opencontainers/image-spec/specs-go/v1"
@@ -165,6 +167,24 @@ func (_m *APIClient) ContainerExecInspect(ctx context.Context, execID string) (c
 	return r0, r1
 }
 
+// ContainerExecResize provides a mock function with given fields: ctx, execID, options
+func (_m *APIClient) ContainerExecResize(ctx context.Context, execID string, options container.ResizeOptions) error {
+	ret := _m.Called(ctx, execID, options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ContainerExecResize")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, container.ResizeOptions) error); ok {
+		r0 = rf(ctx, execID, options)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ContainerInspect provides a mock function with given fields: ctx, _a1
 func (_m *APIClient) ContainerInspect(ctx context.Context, _a1 string) (types.ContainerJSON, error) {
 	ret := _m.Called(ctx, _a1)
@@ -193,6 +213,24 @@ func (_m *APIClient) ContainerInspect(ctx context.Context, _a1 string) (types.Co
 	return r0, r1
 }
 
+// ContainerKill provides a mock function with given fields: ctx, _a1, signal
+func (_m *APIClient) ContainerKill(ctx context.Context, _a1 string, signal string) error {
+	ret := _m.Called(ctx, _a1, signal)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ContainerKill")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, _a1, signal)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ContainerList provides a mock function with given fields: ctx, options
 func (_m *APIClient) ContainerList(ctx context.Context, options container.ListOptions) ([]types.Container, error) {
 	ret := _m.Called(ctx, options)
@@ -271,6 +309,24 @@ func (_m *APIClient) ContainerRemove(ctx context.Context, _a1 string, options co
 	return r0
 }
 
+// ContainerResize provides a mock function with given fields: ctx, _a1, options
+func (_m *APIClient) ContainerResize(ctx context.Context, _a1 string, options container.ResizeOptions) error {
+	ret := _m.Called(ctx, _a1, options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ContainerResize")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, container.ResizeOptions) error); ok {
+		r0 = rf(ctx, _a1, options)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ContainerStart provides a mock function with given fields: ctx, _a1, options
 func (_m *APIClient) ContainerStart(ctx context.Context, _a1 string, options container.StartOptions) error {
 	ret := _m.Called(ctx, _a1, options)
@@ -429,6 +485,34 @@ func (_m *APIClient) ImageRemove(ctx context.Context, _a1 string, options image.
 	return r0, r1
 }
 
+// Info provides a mock function with given fields: ctx
+func (_m *APIClient) Info(ctx context.Context) (system.Info, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Info")
+	}
+
+	var r0 system.Info
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (system.Info, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) system.Info); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(system.Info)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NetworkCreate provides a mock function with given fields: ctx, name, options
 func (_m *APIClient) NetworkCreate(ctx context.Context, name string, options network.CreateOptions) (network.CreateResponse, error) {
 	ret := _m.Called(ctx, name, options)
@@ -163,6 +163,24 @@ func (_m *ContainerAPIClient) ContainerExecInspect(ctx context.Context, execID s
 	return r0, r1
 }
 
+// ContainerExecResize provides a mock function with given fields: ctx, execID, options
+func (_m *ContainerAPIClient) ContainerExecResize(ctx context.Context, execID string, options container.ResizeOptions) error {
+	ret := _m.Called(ctx, execID, options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ContainerExecResize")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, container.ResizeOptions) error); ok {
+		r0 = rf(ctx, execID, options)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ContainerInspect provides a mock function with given fields: ctx, _a1
 func (_m *ContainerAPIClient) ContainerInspect(ctx context.Context, _a1 string) (types.ContainerJSON, error) {
 	ret := _m.Called(ctx, _a1)
@@ -191,6 +209,24 @@ func (_m *ContainerAPIClient) ContainerInspect(ctx context.Context, _a1 string)
 	return r0, r1
 }
 
+// ContainerKill provides a mock function with given fields: ctx, _a1, signal
+func (_m *ContainerAPIClient) ContainerKill(ctx context.Context, _a1 string, signal string) error {
+	ret := _m.Called(ctx, _a1, signal)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ContainerKill")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, _a1, signal)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ContainerList provides a mock function with given fields: ctx, options
 func (_m *ContainerAPIClient) ContainerList(ctx context.Context, options container.ListOptions) ([]types.Container, error) {
 	ret := _m.Called(ctx, options)
@@ -269,6 +305,24 @@ func (_m *ContainerAPIClient) ContainerRemove(ctx context.Context, _a1 string, o
 	return r0
 }
 
+// ContainerResize provides a mock function with given fields: ctx, _a1, options
+func (_m *ContainerAPIClient) ContainerResize(ctx context.Context, _a1 string, options container.ResizeOptions) error {
+	ret := _m.Called(ctx, _a1, options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ContainerResize")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, container.ResizeOptions) error); ok {
+		r0 = rf(ctx, _a1, options)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ContainerStart provides a mock function with given fields: ctx, _a1, options
 func (_m *ContainerAPIClient) ContainerStart(ctx context.Context, _a1 string, options container.StartOptions) error {
 	ret := _m.Called(ctx, _a1, options)
@@ -0,0 +1,58 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	system "github.com/docker/docker/api/types/system"
+)
+
+// SystemAPIClient is an autogenerated mock type for the SystemAPIClient type
+type SystemAPIClient struct {
+	mock.Mock
+}
+
+// Info provides a mock function with given fields: ctx
+func (_m *SystemAPIClient) Info(ctx context.Context) (system.Info, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Info")
+	}
+
+	var r0 system.Info
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (system.Info, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) system.Info); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(system.Info)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewSystemAPIClient creates a new instance of SystemAPIClient. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSystemAPIClient(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SystemAPIClient {
+	mock := &SystemAPIClient{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -3,10 +3,14 @@ package docker
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strconv"
+	"strings"
 
+	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/go-connections/nat"
 )
@@ -14,6 +18,7 @@ import (
 type ExposedPorts struct {
 	Port     string
 	Protocol string
+	HostPort string // The port on the host, if it differs from Port
 }
 
 type portConfig struct {
@@ -111,7 +116,12 @@ func getNetworkConfig(ports []ExposedPorts, randomPorts bool) (portConfig, error
 
 		hostPort := port.Port
 
-		if randomPorts {
+		if port.HostPort != "" {
+			hostPort = port.HostPort
+		}
+
+		// A port with its own host port, such as the site in CI mode, is always published on that port
+		if randomPorts && port.HostPort == "" {
 			port, err := getRandomPort()
 			if err != nil {
 				return portConfig{}, err
@@ -152,3 +162,34 @@ func getRandomPort() (string, error) {
 
 	return urlParts.Port(), nil
 }
+
+// IsPortAvailable returns true if nothing on the host is listening on the port.
+func IsPortAvailable(port string) bool {
+	listener, err := net.Listen("tcp", net.JoinHostPort("", port))
+	if err != nil {
+		return false
+	}
+
+	listener.Close()
+
+	return true
+}
+
+// GetPortContainer returns the name of the running container, from Kana or anything else, that publishes the port
+// on the host, or an empty string if no container does.
+func (d *Client) GetPortContainer(port string) string {
+	containers, err := d.apiClient.ContainerList(context.Background(), container.ListOptions{})
+	if err != nil {
+		return ""
+	}
+
+	for i := range containers {
+		for _, containerPort := range containers[i].Ports {
+			if strconv.Itoa(int(containerPort.PublicPort)) == port && len(containers[i].Names) > 0 {
+				return strings.Trim(containers[i].Names[0], "/")
+			}
+		}
+	}
+
+	return ""
+}
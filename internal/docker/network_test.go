@@ -1,6 +1,7 @@
 package docker
 
 import (
+	"net"
 	"testing"
 
 	"github.com/ChrisWiegman/kana/internal/console"
@@ -61,3 +62,65 @@ func TestEnsureNetwork(t *testing.T) {
 		t.Errorf("Network should have been removed but wasn't")
 	}
 }
+
+func TestGetNetworkConfig(t *testing.T) {
+	config, err := getNetworkConfig([]ExposedPorts{
+		{Port: "3306", Protocol: "tcp"},
+		{Port: "80", Protocol: "tcp", HostPort: "8080"},
+	}, false)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if hostPort := config.PortBindings["3306/tcp"][0].HostPort; hostPort != "3306" {
+		t.Errorf("Expected port 3306 to be published on 3306; got %s", hostPort)
+	}
+
+	if hostPort := config.PortBindings["80/tcp"][0].HostPort; hostPort != "8080" {
+		t.Errorf("Expected port 80 to be published on 8080; got %s", hostPort)
+	}
+
+	if len(config.PortSet) != 2 {
+		t.Errorf("Expected 2 exposed ports; got %d", len(config.PortSet))
+	}
+}
+
+func TestGetNetworkConfig_RandomPorts(t *testing.T) {
+	config, err := getNetworkConfig([]ExposedPorts{
+		{Port: "3306", Protocol: "tcp"},
+		{Port: "80", Protocol: "tcp", HostPort: "8080"},
+	}, true)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if hostPort := config.PortBindings["3306/tcp"][0].HostPort; hostPort == "3306" || hostPort == "" {
+		t.Errorf("Expected port 3306 to be published on a random port; got %s", hostPort)
+	}
+
+	if hostPort := config.PortBindings["80/tcp"][0].HostPort; hostPort != "8080" {
+		t.Errorf("Expected port 80 to still be published on 8080; got %s", hostPort)
+	}
+}
+
+func TestIsPortAvailable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+
+	if IsPortAvailable(port) {
+		t.Errorf("Expected port %s to be in use", port)
+	}
+
+	listener.Close()
+
+	if !IsPortAvailable(port) {
+		t.Errorf("Expected port %s to be available once it was closed", port)
+	}
+}
@@ -0,0 +1,106 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+)
+
+// ErrNetwork is returned when an operation kept failing because of the network rather than because of the site's configuration.
+var ErrNetwork = errors.New("the network connection kept failing")
+
+var errRegistryUnavailable = errors.New("docker hub is unavailable")
+
+var retryAttempts int64 = 3
+var retryDelay = time.Second
+var maxRetryDelay = 30 * time.Second
+
+// Errors from the Docker daemon arrive as plain text so the network failures it reports are recognized by their message.
+var transientErrorMessages = []string{
+	"connection refused",
+	"connection reset by peer",
+	"i/o timeout",
+	"no such host",
+	"request canceled while waiting for connection",
+	"server misbehaving",
+	"temporary failure in name resolution",
+	"tls handshake timeout",
+	"too many requests",
+	"unexpected eof",
+}
+
+// SetRetries sets how many times image pulls, container creates and registry checks are retried after a network failure.
+func SetRetries(retries int64) {
+	retryAttempts = max(retries, 0)
+}
+
+// withRetry runs the operation, trying it again with an increasing delay while it fails with a network error.
+// Errors that retrying can't fix, such as a missing image, are returned straight away.
+func withRetry(ctx context.Context, operation func() error) error {
+	delay := retryDelay
+
+	for attempt := int64(1); ; attempt++ {
+		err := operation()
+		if err == nil || ctx.Err() != nil || !isTransientError(err) {
+			return err
+		}
+
+		if attempt > retryAttempts {
+			return fmt.Errorf("%w after %d attempts. Check your internet connection and try again: %w", ErrNetwork, attempt, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+
+		delay = min(delay*2, maxRetryDelay)
+	}
+}
+
+// isTransientError reports whether the error was caused by an unreliable network and might not happen again.
+func isTransientError(err error) bool {
+	if errors.Is(err, context.Canceled) || client.IsErrConnectionFailed(err) {
+		return false
+	}
+
+	if errdefs.IsNotFound(err) ||
+		errdefs.IsUnauthorized(err) ||
+		errdefs.IsForbidden(err) ||
+		errdefs.IsInvalidParameter(err) ||
+		errdefs.IsConflict(err) {
+		return false
+	}
+
+	if errors.Is(err, errRegistryUnavailable) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errdefs.IsUnavailable(err) ||
+		errdefs.IsDeadline(err) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	message := strings.ToLower(err.Error())
+
+	for _, transientMessage := range transientErrorMessages {
+		if strings.Contains(message, transientMessage) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,87 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/errdefs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsTransientError(t *testing.T) {
+	var tests = []struct {
+		name      string
+		err       error
+		transient bool
+	}{
+		{"dns failure", &net.DNSError{Err: "no such host", Name: "registry-1.docker.io"}, true},
+		{"unexpected eof", fmt.Errorf("pull failed: %w", io.ErrUnexpectedEOF), true},
+		{"daemon reported timeout", fmt.Errorf("Get \"https://registry-1.docker.io/v2/\": net/http: TLS handshake timeout"), true},
+		{"registry unavailable", fmt.Errorf("%w: 503 Service Unavailable", errRegistryUnavailable), true},
+		{"image not found", errdefs.NotFound(fmt.Errorf("manifest for wordpress:php9 not found")), false},
+		{"cancelled", context.Canceled, false},
+		{"other error", fmt.Errorf("invalid reference format"), false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.transient, isTransientError(test.err))
+		})
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	defer func(delay time.Duration, retries int64) {
+		retryDelay = delay
+		retryAttempts = retries
+	}(retryDelay, retryAttempts)
+
+	retryDelay = 0
+
+	SetRetries(2)
+
+	transientErr := fmt.Errorf("connection reset by peer")
+
+	var tests = []struct {
+		name          string
+		failures      int
+		err           error
+		expectedCalls int
+		networkError  bool
+	}{
+		{"succeeds first time", 0, nil, 1, false},
+		{"succeeds after retries", 2, transientErr, 3, false},
+		{"gives up after retries", 5, transientErr, 3, true},
+		{"fatal error isn't retried", 5, fmt.Errorf("invalid reference format"), 1, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			calls := 0
+
+			err := withRetry(context.Background(), func() error {
+				calls++
+
+				if calls <= test.failures {
+					return test.err
+				}
+
+				return nil
+			})
+
+			assert.Equal(t, test.expectedCalls, calls)
+			assert.Equal(t, test.networkError, errors.Is(err, ErrNetwork))
+
+			if test.failures >= test.expectedCalls {
+				assert.ErrorIs(t, err, test.err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,279 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/strslice"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/moby/term"
+)
+
+// The signals forwarded to a running container rather than stopping Kana and leaving the container behind.
+var forwardedSignals = map[os.Signal]string{
+	syscall.SIGINT:  "SIGINT",
+	syscall.SIGTERM: "SIGTERM",
+}
+
+// resizeFunc resizes the TTY of a container or exec, such as ContainerResize or ContainerExecResize.
+type resizeFunc func(ctx context.Context, id string, options container.ResizeOptions) error
+
+// containerRunInteractive Runs a container with the user's terminal attached, removing the container when it exits.
+func (d *Client) containerRunInteractive(config *ContainerConfig) (statusCode int64, body string, err error) {
+	id, err := d.containerCreate(context.Background(), config, false, true, true)
+	if err != nil {
+		return statusCode, body, err
+	}
+
+	defer func() {
+		removeErr := d.apiClient.ContainerRemove(context.Background(), id, container.RemoveOptions{Force: true})
+		if err == nil {
+			err = removeErr
+		}
+	}()
+
+	// Attach before starting the container so we don't miss any of its output
+	attachResponse, err := d.apiClient.ContainerAttach(context.Background(), id, container.AttachOptions{
+		Stream: true,
+		Stdin:  true,
+		Stdout: true,
+		Stderr: true,
+	})
+	if err != nil {
+		return statusCode, body, err
+	}
+
+	defer attachResponse.Close()
+
+	inFd, isTerminal := term.GetFdInfo(os.Stdin)
+
+	if isTerminal {
+		state, err := term.SetRawTerminal(inFd)
+		if err != nil {
+			return statusCode, body, err
+		}
+
+		defer func() {
+			_ = term.RestoreTerminal(inFd, state)
+		}()
+	}
+
+	err = d.apiClient.ContainerStart(context.Background(), id, container.StartOptions{})
+	if err != nil {
+		return statusCode, body, err
+	}
+
+	stopForwarding := d.forwardSignals(id)
+	defer stopForwarding()
+
+	if isTerminal {
+		resizeTerminal(d.apiClient.ContainerResize, id, inFd)
+
+		stopMonitor := monitorTerminalSize(d.apiClient.ContainerResize, id, inFd)
+		defer stopMonitor()
+	}
+
+	outputDone := make(chan error)
+
+	go func() {
+		var copyErr error
+
+		// With a TTY stdout and stderr arrive on a single stream, otherwise they need to be separated
+		if isTerminal {
+			_, copyErr = io.Copy(os.Stdout, attachResponse.Reader)
+		} else {
+			_, copyErr = stdcopy.StdCopy(os.Stdout, os.Stderr, attachResponse.Reader)
+		}
+
+		outputDone <- copyErr
+	}()
+
+	go func() {
+		_, _ = io.Copy(attachResponse.Conn, os.Stdin)
+		_ = attachResponse.CloseWrite()
+	}()
+
+	err = <-outputDone
+	if err != nil && !errors.Is(err, io.EOF) {
+		return statusCode, body, err
+	}
+
+	statusCode, err = d.containerWait(id)
+
+	return statusCode, body, err
+}
+
+// ContainerExecInteractive Runs a command, such as a shell, in a running container with the user's terminal attached and
+// returns the command's exit code.
+func (d *Client) ContainerExecInteractive(containerName string, rootUser bool, command []string) (int, error) {
+	containerID, isRunning := d.containerIsRunning(containerName)
+	if !isRunning {
+		return 1, fmt.Errorf("the container %s is not running", containerName)
+	}
+
+	tty, size, env := getTerminalConfig(nil)
+
+	execConfig := container.ExecOptions{
+		Tty:          tty,
+		AttachStdin:  true,
+		AttachStdout: true,
+		AttachStderr: true,
+		Env:          env,
+		Cmd:          strslice.StrSlice(command),
+	}
+
+	if size[0] > 0 && size[1] > 0 {
+		execConfig.ConsoleSize = &size
+	}
+
+	if rootUser {
+		execConfig.User = "root"
+	}
+
+	containerResponse, err := d.apiClient.ContainerExecCreate(context.Background(), containerID, execConfig)
+	if err != nil {
+		return 1, err
+	}
+
+	execID := containerResponse.ID
+
+	apiResponse, err := d.apiClient.ContainerExecAttach(context.Background(), execID, container.ExecStartOptions{Tty: tty})
+	if err != nil {
+		return 1, err
+	}
+
+	defer apiResponse.Close()
+
+	if tty {
+		inFd, _ := term.GetFdInfo(os.Stdin)
+
+		state, err := term.SetRawTerminal(inFd)
+		if err != nil {
+			return 1, err
+		}
+
+		defer func() {
+			_ = term.RestoreTerminal(inFd, state)
+		}()
+
+		resizeTerminal(d.apiClient.ContainerExecResize, execID, inFd)
+
+		stopMonitor := monitorTerminalSize(d.apiClient.ContainerExecResize, execID, inFd)
+		defer stopMonitor()
+	}
+
+	outputDone := make(chan error)
+
+	go func() {
+		var copyErr error
+
+		// With a TTY stdout and stderr arrive on a single stream, otherwise they need to be separated
+		if tty {
+			_, copyErr = io.Copy(os.Stdout, apiResponse.Reader)
+		} else {
+			_, copyErr = stdcopy.StdCopy(os.Stdout, os.Stderr, apiResponse.Reader)
+		}
+
+		outputDone <- copyErr
+	}()
+
+	go func() {
+		_, _ = io.Copy(apiResponse.Conn, os.Stdin)
+		_ = apiResponse.CloseWrite()
+	}()
+
+	err = <-outputDone
+	if err != nil && !errors.Is(err, io.EOF) {
+		return 1, err
+	}
+
+	inspectResponse, err := d.apiClient.ContainerExecInspect(context.Background(), execID)
+	if err != nil {
+		return 1, err
+	}
+
+	return inspectResponse.ExitCode, nil
+}
+
+// forwardSignals Sends the signals in forwardedSignals to the container until stopped.
+// Once the container's command exits the caller can clean up the container as normal.
+func (d *Client) forwardSignals(id string) (stop func()) {
+	signals := make(chan os.Signal, 1)
+
+	for forwardedSignal := range forwardedSignals {
+		signal.Notify(signals, forwardedSignal)
+	}
+
+	go func() {
+		for receivedSignal := range signals {
+			_ = d.apiClient.ContainerKill(context.Background(), id, forwardedSignals[receivedSignal])
+		}
+	}()
+
+	return func() {
+		signal.Stop(signals)
+		close(signals)
+	}
+}
+
+// getTerminalConfig Returns whether an interactive container should have a TTY, the size it should start with and its environment.
+// A TTY is only used when the user's terminal is attached to stdin. Without one, the width of the terminal the output is shown in
+// is passed in COLUMNS so tables are still formatted to fit it.
+func getTerminalConfig(env []string) (tty bool, size [2]uint, containerEnv []string) {
+	containerEnv = env
+
+	if inFd, isTerminal := term.GetFdInfo(os.Stdin); isTerminal {
+		winSize, err := term.GetWinsize(inFd)
+		if err == nil {
+			size = [2]uint{uint(winSize.Height), uint(winSize.Width)}
+		}
+
+		return true, size, containerEnv
+	}
+
+	if outFd, isTerminal := term.GetFdInfo(os.Stdout); isTerminal {
+		winSize, err := term.GetWinsize(outFd)
+		if err == nil && winSize.Width > 0 {
+			containerEnv = append(slices.Clone(env), fmt.Sprintf("COLUMNS=%d", winSize.Width))
+		}
+	}
+
+	return false, size, containerEnv
+}
+
+// monitorTerminalSize Resizes the container's, or exec's, TTY with the given resize function whenever the user's terminal is resized.
+func monitorTerminalSize(resize resizeFunc, id string, fd uintptr) (stop func()) {
+	resizeSignal := make(chan os.Signal, 1)
+	signal.Notify(resizeSignal, syscall.SIGWINCH)
+
+	go func() {
+		for range resizeSignal {
+			resizeTerminal(resize, id, fd)
+		}
+	}()
+
+	return func() {
+		signal.Stop(resizeSignal)
+		close(resizeSignal)
+	}
+}
+
+// resizeTerminal Matches the container's, or exec's, TTY size to the size of the user's terminal.
+func resizeTerminal(resize resizeFunc, id string, fd uintptr) {
+	size, err := term.GetWinsize(fd)
+	if err != nil || size.Height == 0 || size.Width == 0 {
+		return
+	}
+
+	_ = resize(context.Background(), id, container.ResizeOptions{
+		Height: uint(size.Height),
+		Width:  uint(size.Width),
+	})
+}
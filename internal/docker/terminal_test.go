@@ -0,0 +1,46 @@
+package docker
+
+import (
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/ChrisWiegman/kana/internal/docker/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestForwardSignals(t *testing.T) {
+	killed := make(chan string, 1)
+
+	apiClient := new(mocks.APIClient)
+	apiClient.On("ContainerKill", mock.Anything, "container-id", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		killed <- args.String(2)
+	})
+
+	d := &Client{apiClient: apiClient}
+
+	stop := d.forwardSignals("container-id")
+	defer stop()
+
+	err := syscall.Kill(syscall.Getpid(), syscall.SIGTERM)
+	assert.NoError(t, err)
+
+	select {
+	case signal := <-killed:
+		assert.Equal(t, "SIGTERM", signal)
+	case <-time.After(5 * time.Second):
+		t.Error("the signal was not forwarded to the container")
+	}
+}
+
+func TestGetTerminalConfig(t *testing.T) {
+	// Tests don't run with a terminal attached so the container shouldn't get a TTY
+	env := []string{"IS_KANA_ENVIRONMENT=true"}
+
+	tty, size, containerEnv := getTerminalConfig(env)
+	assert.False(t, tty)
+	assert.Equal(t, [2]uint{}, size)
+	assert.Equal(t, env, containerEnv)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/system"
 	"github.com/docker/docker/client"
 	specs "github.com/opencontainers/image-spec/specs-go/v1"
 )
@@ -17,6 +18,7 @@ type APIClient interface {
 	ContainerAPIClient
 	ImageAPIClient
 	NetworkAPIClient
+	SystemAPIClient
 }
 
 // Ensure that Client always implements APIClient.
@@ -35,10 +37,13 @@ type ContainerAPIClient interface {
 	ContainerExecAttach(ctx context.Context, execID string, config container.ExecAttachOptions) (types.HijackedResponse, error)
 	ContainerExecCreate(ctx context.Context, container string, config container.ExecOptions) (types.IDResponse, error)
 	ContainerExecInspect(ctx context.Context, execID string) (container.ExecInspect, error)
+	ContainerExecResize(ctx context.Context, execID string, options container.ResizeOptions) error
 	ContainerInspect(ctx context.Context, container string) (types.ContainerJSON, error)
+	ContainerKill(ctx context.Context, container, signal string) error
 	ContainerList(ctx context.Context, options container.ListOptions) ([]types.Container, error)
 	ContainerLogs(ctx context.Context, container string, options container.LogsOptions) (io.ReadCloser, error)
 	ContainerRemove(ctx context.Context, container string, options container.RemoveOptions) error
+	ContainerResize(ctx context.Context, container string, options container.ResizeOptions) error
 	ContainerStart(ctx context.Context, container string, options container.StartOptions) error
 	ContainerStop(ctx context.Context, name string, options container.StopOptions) error
 	ContainerWait(
@@ -60,3 +65,8 @@ type NetworkAPIClient interface {
 	NetworkList(ctx context.Context, options network.ListOptions) ([]network.Inspect, error)
 	NetworkRemove(ctx context.Context, network string) error
 }
+
+// SystemAPIClient defines API client methods for the docker system.
+type SystemAPIClient interface {
+	Info(ctx context.Context) (system.Info, error)
+}
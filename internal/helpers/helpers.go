@@ -3,15 +3,23 @@ package helpers
 import (
 	"archive/zip"
 	"bufio"
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"net/http"
 	"net/url"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 )
 
 // ArrayContains Searches an array of strings for a given string and returns true/false as appropriate.
@@ -118,6 +126,51 @@ func CopyFile(sourceFile, destinationFile string) error {
 	return err
 }
 
+// CopyDirectory copies the regular files in a directory, and its subdirectories, to the destination, keeping their permissions.
+// Paths in skip, relative to the source directory, aren't copied.
+func CopyDirectory(sourceDirectory, destinationDirectory string, skip []string) error {
+	return filepath.WalkDir(sourceDirectory, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relativePath, err := filepath.Rel(sourceDirectory, path)
+		if err != nil {
+			return err
+		}
+
+		if slices.Contains(skip, relativePath) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		destination := filepath.Join(destinationDirectory, relativePath)
+
+		if entry.IsDir() {
+			return os.MkdirAll(destination, info.Mode().Perm())
+		}
+
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		err = CopyFile(path, destination)
+		if err != nil {
+			return err
+		}
+
+		return os.Chmod(destination, info.Mode().Perm())
+	})
+}
+
 // DownloadFile downloads a file from a given URL and saves it to the destination path.
 func DownloadFile(downloadURL, destinationPath string) (string, error) {
 	// Build fileName from fullPath
@@ -214,3 +267,222 @@ func UnZipFile(sourceFile, destinationPath string) error {
 
 	return nil
 }
+
+// ZipFiles writes the given files, keyed by their path in the archive, to a new zip file.
+func ZipFiles(destinationFile string, files map[string][]byte) error {
+	file, err := os.Create(destinationFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	zipWriter := zip.NewWriter(file)
+
+	names := make([]string, 0, len(files))
+
+	for name := range files {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		writer, err := zipWriter.Create(name)
+		if err != nil {
+			return err
+		}
+
+		_, err = writer.Write(files[name])
+		if err != nil {
+			return err
+		}
+	}
+
+	return zipWriter.Close()
+}
+
+// Archive is a zip archive that records the SHA-256 checksum of each file added to it so its contents can be verified.
+// Files are stored with a fixed modification time so archiving the same files always produces the same archive.
+type Archive struct {
+	zipWriter *zip.Writer
+	Checksums map[string]string
+}
+
+const archiveFilePermissions = 0644
+
+// archiveModified is the modification time of every file in an Archive, the earliest time a zip file can store.
+var archiveModified = time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// NewArchive starts a zip archive written to the given writer.
+func NewArchive(writer io.Writer) *Archive {
+	return &Archive{
+		zipWriter: zip.NewWriter(writer),
+		Checksums: map[string]string{},
+	}
+}
+
+// AddBytes adds a file with the given contents to the archive.
+func (a *Archive) AddBytes(archivePath string, contents []byte) error {
+	return a.add(archivePath, archiveFilePermissions, bytes.NewReader(contents))
+}
+
+// AddFile adds a file to the archive, streaming it so large files aren't held in memory.
+func (a *Archive) AddFile(sourceFile, archivePath string) error {
+	file, err := os.Open(sourceFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	return a.add(archivePath, info.Mode(), file)
+}
+
+// AddDirectory adds the regular files in a directory, and its subdirectories, to the archive under the given path.
+// Paths in skip, relative to the source directory, aren't added.
+func (a *Archive) AddDirectory(sourceDirectory, archivePath string, skip []string) error {
+	return filepath.WalkDir(sourceDirectory, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relativePath, err := filepath.Rel(sourceDirectory, path)
+		if err != nil {
+			return err
+		}
+
+		if slices.Contains(skip, relativePath) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		return a.AddFile(path, filepath.Join(archivePath, relativePath))
+	})
+}
+
+// Close finishes writing the archive.
+func (a *Archive) Close() error {
+	return a.zipWriter.Close()
+}
+
+func (a *Archive) add(archivePath string, mode fs.FileMode, contents io.Reader) error {
+	header := &zip.FileHeader{
+		Name:     filepath.ToSlash(archivePath),
+		Method:   zip.Deflate,
+		Modified: archiveModified,
+	}
+
+	header.SetMode(mode)
+
+	writer, err := a.zipWriter.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+
+	checksum := sha256.New()
+
+	_, err = io.Copy(io.MultiWriter(writer, checksum), contents)
+	if err != nil {
+		return err
+	}
+
+	a.Checksums[header.Name] = hex.EncodeToString(checksum.Sum(nil))
+
+	return nil
+}
+
+// FileChecksum returns the SHA-256 checksum of a file in a zip archive.
+func FileChecksum(file *zip.File) (string, error) {
+	contents, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer contents.Close()
+
+	checksum := sha256.New()
+
+	_, err = io.Copy(checksum, contents)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(checksum.Sum(nil)), nil
+}
+
+// PathChecksum returns the SHA-256 checksum of the file at path.
+func PathChecksum(path string) (string, error) {
+	contents, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer contents.Close()
+
+	checksum := sha256.New()
+
+	_, err = io.Copy(checksum, contents)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(checksum.Sum(nil)), nil
+}
+
+// FormatFileSize returns a human-readable representation of a file size in bytes.
+func FormatFileSize(size int64) string {
+	const unit = 1024
+
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	div, exp := int64(unit), 0
+
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %cB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+// GetPortProcess returns the name and process ID of the process listening on a TCP port, such as "nginx (pid 123)",
+// or an empty string if it can't be found. It relies on lsof so it doesn't find anything on Windows.
+func GetPortProcess(port string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, "lsof", "-nP", "-iTCP:"+port, "-sTCP:LISTEN", "-Fpc").Output()
+	if err != nil {
+		return ""
+	}
+
+	return parseLsofProcess(string(output))
+}
+
+// parseLsofProcess returns the first process in lsof's field output, where "p" lines hold process IDs and "c" lines
+// hold command names.
+func parseLsofProcess(output string) string {
+	pid := ""
+
+	for _, line := range strings.Split(output, "\n") {
+		switch {
+		case strings.HasPrefix(line, "p") && pid == "":
+			pid = strings.TrimPrefix(line, "p")
+		case strings.HasPrefix(line, "c") && pid != "":
+			return fmt.Sprintf("%s (pid %s)", strings.TrimPrefix(line, "c"), pid)
+		}
+	}
+
+	return ""
+}
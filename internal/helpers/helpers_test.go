@@ -10,6 +10,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -220,6 +221,46 @@ func TestCopyFile(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+func TestCopyDirectory(t *testing.T) {
+	sourceDir := t.TempDir()
+	destinationDir := filepath.Join(t.TempDir(), "copy")
+
+	files := map[string]string{
+		"wp-content/plugins/my-plugin/my-plugin.php": "<?php",
+		"wp-content/plugins/my-plugin/.git/HEAD":     "ref: refs/heads/main",
+		"wp-config.php":                              "<?php",
+		"index.php":                                  "<?php",
+	}
+
+	for file, contents := range files {
+		err := os.MkdirAll(filepath.Join(sourceDir, filepath.Dir(file)), 0750)
+		assert.NoError(t, err)
+
+		err = os.WriteFile(filepath.Join(sourceDir, file), []byte(contents), 0600)
+		assert.NoError(t, err)
+	}
+
+	err := CopyDirectory(sourceDir, destinationDir, []string{"wp-config.php", filepath.Join("wp-content", "plugins", "my-plugin", ".git")})
+	assert.NoError(t, err)
+
+	for file, contents := range files {
+		copied, err := os.ReadFile(filepath.Join(destinationDir, file))
+
+		if file == "wp-config.php" || strings.Contains(file, ".git") {
+			assert.True(t, os.IsNotExist(err), file)
+
+			continue
+		}
+
+		assert.NoError(t, err)
+		assert.Equal(t, contents, string(copied))
+	}
+
+	info, err := os.Stat(filepath.Join(destinationDir, "index.php"))
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
+
 func TestDownloadFile(t *testing.T) {
 	destinationPath, err := os.Getwd()
 	if err != nil {
@@ -341,6 +382,98 @@ func createTempZipFile(zipFile string) error {
 
 	return nil
 }
+func TestZipFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	zipFile := filepath.Join(tempDir, "test.zip")
+
+	err := ZipFiles(zipFile, map[string][]byte{
+		"file1.txt":        []byte("Test data for file1"),
+		"folder/file2.txt": []byte("Test data for file2"),
+	})
+	assert.NoError(t, err)
+
+	err = UnZipFile(zipFile, tempDir)
+	assert.NoError(t, err)
+
+	contents, err := os.ReadFile(filepath.Join(tempDir, "file1.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Test data for file1", string(contents))
+
+	contents, err = os.ReadFile(filepath.Join(tempDir, "folder", "file2.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Test data for file2", string(contents))
+}
+
+func TestArchive(t *testing.T) {
+	sourceDir := t.TempDir()
+	destinationDir := t.TempDir()
+
+	files := map[string]string{
+		"my-plugin/my-plugin.php":   "<?php",
+		"my-plugin/assets/app.js":   "app",
+		"my-plugin/.git/HEAD":       "ref: refs/heads/main",
+		"other-plugin/other.php":    "<?php",
+		"mounted-plugin/plugin.php": "<?php",
+	}
+
+	for file, contents := range files {
+		err := os.MkdirAll(filepath.Join(sourceDir, filepath.Dir(file)), 0750)
+		assert.NoError(t, err)
+
+		err = os.WriteFile(filepath.Join(sourceDir, file), []byte(contents), 0600)
+		assert.NoError(t, err)
+	}
+
+	createArchive := func(zipFile string) map[string]string {
+		file, err := os.Create(zipFile)
+		assert.NoError(t, err)
+
+		archive := NewArchive(file)
+
+		err = archive.AddDirectory(sourceDir, "wp-content/plugins", []string{"mounted-plugin", filepath.Join("my-plugin", ".git")})
+		assert.NoError(t, err)
+
+		err = archive.AddBytes("settings.json", []byte("{}"))
+		assert.NoError(t, err)
+
+		assert.NoError(t, archive.Close())
+		assert.NoError(t, file.Close())
+
+		return archive.Checksums
+	}
+
+	checksums := createArchive(filepath.Join(destinationDir, "first.zip"))
+
+	assert.Equal(t, map[string]string{
+		"wp-content/plugins/my-plugin/assets/app.js": "a172cedcae47474b615c54d510a5d84a8dea3032e958587430b413538be3f333",
+		"wp-content/plugins/my-plugin/my-plugin.php": "fe5bcb54c56e0b9f456a060364dd28b2248f3a0e21c168d4ce9d009b73e83e3c",
+		"wp-content/plugins/other-plugin/other.php":  "fe5bcb54c56e0b9f456a060364dd28b2248f3a0e21c168d4ce9d009b73e83e3c",
+		"settings.json": "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a",
+	}, checksums)
+
+	zipReader, err := zip.OpenReader(filepath.Join(destinationDir, "first.zip"))
+	assert.NoError(t, err)
+
+	defer zipReader.Close()
+
+	for _, zippedFile := range zipReader.File {
+		checksum, err := FileChecksum(zippedFile)
+		assert.NoError(t, err)
+		assert.Equal(t, checksums[zippedFile.Name], checksum)
+	}
+
+	// Archiving the same files again produces an identical archive
+	createArchive(filepath.Join(destinationDir, "second.zip"))
+
+	first, err := os.ReadFile(filepath.Join(destinationDir, "first.zip"))
+	assert.NoError(t, err)
+
+	second, err := os.ReadFile(filepath.Join(destinationDir, "second.zip"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, first, second)
+}
+
 func TestArrayContains(t *testing.T) {
 	var testCases = []struct {
 		name        string
@@ -373,3 +506,75 @@ func TestArrayContains(t *testing.T) {
 		assert.Equal(t, test.expected, result, test.name)
 	}
 }
+
+func TestPathChecksum(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "file.txt")
+
+	err := os.WriteFile(file, []byte("kana"), 0600)
+	assert.NoError(t, err)
+
+	checksum, err := PathChecksum(file)
+	assert.NoError(t, err)
+	assert.Equal(t, "0c0902428f07fd49a08684c62e34020e5c4f67b184fdc878aa4bf5c7082bf6da", checksum)
+
+	_, err = PathChecksum(filepath.Join(t.TempDir(), "missing.txt"))
+	assert.Error(t, err)
+}
+
+func TestFormatFileSize(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		size     int64
+		expected string
+	}{
+		{
+			name:     "Sizes under a kilobyte are shown in bytes",
+			size:     512,
+			expected: "512 B",
+		},
+		{
+			name:     "Kilobytes are rounded to one decimal",
+			size:     1536,
+			expected: "1.5 KB",
+		},
+		{
+			name:     "Gigabytes are shown correctly",
+			size:     3 * 1024 * 1024 * 1024,
+			expected: "3.0 GB",
+		},
+	}
+
+	for _, test := range testCases {
+		result := FormatFileSize(test.size)
+		assert.Equal(t, test.expected, result, test.name)
+	}
+}
+
+func TestParseLsofProcess(t *testing.T) {
+	testCases := []struct {
+		name     string
+		output   string
+		expected string
+	}{
+		{
+			name:     "Single process",
+			output:   "p123\ncnginx\n",
+			expected: "nginx (pid 123)",
+		},
+		{
+			name:     "Only the first process is returned",
+			output:   "p456\ncApache\np789\nchttpd\n",
+			expected: "Apache (pid 456)",
+		},
+		{
+			name:     "No output",
+			output:   "",
+			expected: "",
+		},
+	}
+
+	for _, test := range testCases {
+		result := parseLsofProcess(test.output)
+		assert.Equal(t, test.expected, result, test.name)
+	}
+}
@@ -0,0 +1,90 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Entry is a single event in a site's history, such as it being started or a plugin being installed.
+type Entry struct {
+	Time    time.Time `json:"time"`
+	Event   string    `json:"event"`
+	Details string    `json:"details,omitempty"`
+}
+
+const (
+	historyFileName = "history.jsonl"
+
+	// maxLineSize is the longest entry that can be read back. Longer lines are skipped.
+	maxLineSize = 64 * 1024
+)
+
+// Record adds an event to the end of the site's history. The site's directory must already exist.
+func Record(siteDirectory, event, details string) error {
+	contents, err := json.Marshal(Entry{
+		Time:    time.Now().UTC(),
+		Event:   event,
+		Details: details,
+	})
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(filepath.Join(siteDirectory, historyFileName), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	_, err = file.Write(append(contents, '\n'))
+
+	return err
+}
+
+// Get returns the site's history, oldest first. If limit is greater than zero only the newest entries are returned.
+// Entries that can't be read, such as one cut short when writing it failed, are skipped.
+func Get(siteDirectory string, limit int) ([]Entry, error) {
+	entries := []Entry{}
+
+	file, err := os.Open(filepath.Join(siteDirectory, historyFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return entries, nil
+		}
+
+		return entries, err
+	}
+
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineSize)
+
+	for scanner.Scan() {
+		var entry Entry
+
+		if json.Unmarshal(scanner.Bytes(), &entry) == nil {
+			entries = append(entries, entry)
+		}
+	}
+
+	if limit > 0 && len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+
+	return entries, scanner.Err()
+}
+
+// Clear removes the site's history.
+func Clear(siteDirectory string) error {
+	err := os.Remove(filepath.Join(siteDirectory, historyFileName))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
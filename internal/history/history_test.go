@@ -0,0 +1,61 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecord(t *testing.T) {
+	siteDirectory := t.TempDir()
+
+	entries, err := Get(siteDirectory, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+
+	assert.NoError(t, Record(siteDirectory, "started", "https://example.kana.sh"))
+	assert.NoError(t, Record(siteDirectory, "plugin installed", "query-monitor 3.16.4"))
+	assert.NoError(t, Record(siteDirectory, "stopped", ""))
+
+	entries, err = Get(siteDirectory, 0)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 3)
+	assert.Equal(t, "started", entries[0].Event)
+	assert.Equal(t, "https://example.kana.sh", entries[0].Details)
+	assert.False(t, entries[0].Time.IsZero())
+
+	entries, err = Get(siteDirectory, 2)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "plugin installed", entries[0].Event)
+	assert.Equal(t, "stopped", entries[1].Event)
+
+	err = Clear(siteDirectory)
+	assert.NoError(t, err)
+
+	entries, err = Get(siteDirectory, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestGetSkipsBrokenEntries(t *testing.T) {
+	siteDirectory := t.TempDir()
+
+	assert.NoError(t, Record(siteDirectory, "started", ""))
+
+	file, err := os.OpenFile(filepath.Join(siteDirectory, historyFileName), os.O_APPEND|os.O_WRONLY, 0600)
+	assert.NoError(t, err)
+
+	_, err = file.WriteString("{\"time\":\"2024-01-01T00:00:00Z\",\"ev\n")
+	assert.NoError(t, err)
+	assert.NoError(t, file.Close())
+
+	assert.NoError(t, Record(siteDirectory, "stopped", ""))
+
+	entries, err := Get(siteDirectory, 0)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "stopped", entries[1].Event)
+}
@@ -0,0 +1,171 @@
+package lock
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// Holder describes the kana command holding a site's lock.
+type Holder struct {
+	PID     int       `json:"pid"`
+	Command string    `json:"command"`
+	Since   time.Time `json:"since"`
+}
+
+// Lock is a site's lock, held until it is released or the process exits.
+type Lock struct {
+	file *os.File
+}
+
+const lockFileName = "kana.lock"
+
+// ErrLocked is returned by Acquire when another command holds the site's lock and waiting wasn't asked for.
+var ErrLocked = errors.New("the site is locked by another kana command")
+
+// Acquire locks the site so only one command can change it at a time. If another command holds the lock it either
+// waits for it to be released, calling onWait first with the command holding it, or returns ErrLocked. The lock is
+// released by the operating system if the process exits without releasing it. The site's directory must already exist.
+func Acquire(siteDirectory, command string, wait bool, onWait func(Holder)) (*Lock, error) {
+	lockFile := filepath.Join(siteDirectory, lockFileName)
+
+	for {
+		file, err := os.OpenFile(lockFile, os.O_CREATE|os.O_RDWR, 0600)
+		if err != nil {
+			return nil, err
+		}
+
+		err = syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			holder := readHolder(file)
+
+			if !wait {
+				file.Close()
+
+				return nil, fmt.Errorf("%w: %s", ErrLocked, holder)
+			}
+
+			if onWait != nil {
+				onWait(holder)
+
+				// Only tell the user once if the lock has to be taken again
+				onWait = nil
+			}
+
+			err = syscall.Flock(int(file.Fd()), syscall.LOCK_EX)
+		}
+
+		if err != nil {
+			file.Close()
+
+			return nil, err
+		}
+
+		// The command holding the lock may have destroyed the site or replaced the lock file while this one waited
+		replaced, err := isReplaced(file, lockFile)
+		if err != nil {
+			file.Close()
+
+			if os.IsNotExist(err) {
+				return nil, fmt.Errorf("the site was removed by another kana command")
+			}
+
+			return nil, err
+		}
+
+		if replaced {
+			file.Close()
+
+			continue
+		}
+
+		err = writeHolder(file, command)
+		if err != nil {
+			file.Close()
+
+			return nil, err
+		}
+
+		return &Lock{file: file}, nil
+	}
+}
+
+// Release unlocks the site for the next command.
+func (l *Lock) Release() error {
+	if l == nil || l.file == nil {
+		return nil
+	}
+
+	// Empty the file first so a command reading it between the unlock and the close doesn't report a stale holder
+	_ = l.file.Truncate(0)
+
+	err := syscall.Flock(int(l.file.Fd()), syscall.LOCK_UN)
+
+	closeErr := l.file.Close()
+	l.file = nil
+
+	if err != nil {
+		return err
+	}
+
+	return closeErr
+}
+
+// String describes the holder for messages, such as `kana start` (pid 1234, running for 2m3s).
+func (h Holder) String() string {
+	if h.PID == 0 {
+		return "unknown command"
+	}
+
+	return fmt.Sprintf("`kana %s` (pid %d, running for %s)", h.Command, h.PID, time.Since(h.Since).Round(time.Second))
+}
+
+// isReplaced returns true if the lock file has been removed or replaced since the given file was opened.
+func isReplaced(file *os.File, lockFile string) (bool, error) {
+	current, err := os.Stat(lockFile)
+	if err != nil {
+		return false, err
+	}
+
+	locked, err := file.Stat()
+	if err != nil {
+		return false, err
+	}
+
+	return !os.SameFile(current, locked), nil
+}
+
+func readHolder(file *os.File) Holder {
+	var holder Holder
+
+	contents, err := os.ReadFile(file.Name())
+	if err == nil {
+		_ = json.Unmarshal(contents, &holder)
+	}
+
+	return holder
+}
+
+func writeHolder(file *os.File, command string) error {
+	contents, err := json.Marshal(Holder{
+		PID:     os.Getpid(),
+		Command: command,
+		Since:   time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	err = file.Truncate(0)
+	if err != nil {
+		return err
+	}
+
+	_, err = file.WriteAt(contents, 0)
+
+	return err
+}
@@ -0,0 +1,57 @@
+package lock
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAcquire(t *testing.T) {
+	siteDirectory := t.TempDir()
+
+	siteLock, err := Acquire(siteDirectory, "start", false, nil)
+	assert.NoError(t, err)
+
+	// A second lock fails fast while the first is held and reports who holds it
+	_, err = Acquire(siteDirectory, "stop", false, nil)
+	assert.ErrorIs(t, err, ErrLocked)
+	assert.Contains(t, err.Error(), "`kana start`")
+	assert.Contains(t, err.Error(), "pid")
+
+	waited := make(chan Holder, 1)
+	acquired := make(chan *Lock)
+
+	go func() {
+		waitingLock, waitErr := Acquire(siteDirectory, "stop", true, func(holder Holder) {
+			waited <- holder
+		})
+		assert.NoError(t, waitErr)
+
+		acquired <- waitingLock
+	}()
+
+	holder := <-waited
+	assert.Equal(t, "start", holder.Command)
+	assert.Equal(t, os.Getpid(), holder.PID)
+
+	assert.NoError(t, siteLock.Release())
+
+	waitingLock := <-acquired
+	assert.NoError(t, waitingLock.Release())
+
+	siteLock, err = Acquire(siteDirectory, "destroy", false, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, siteLock.Release())
+}
+
+func TestRelease(t *testing.T) {
+	var siteLock *Lock
+
+	assert.NoError(t, siteLock.Release())
+
+	siteLock, err := Acquire(t.TempDir(), "start", false, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, siteLock.Release())
+	assert.NoError(t, siteLock.Release())
+}
@@ -0,0 +1,121 @@
+package registry
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ChrisWiegman/kana/internal/lock"
+)
+
+// Site is a site known to Kana, saved so it can be listed without reading every site's folder.
+type Site struct {
+	Name      string    `json:"name"`
+	Directory string    `json:"directory"` // The site's own folder, holding its WordPress files and database
+	Link      string    `json:"link"`      // The project directory the site is run from or, for named sites, its own folder
+	Created   time.Time `json:"created"`
+	Updated   time.Time `json:"updated"`
+	// Unavailable is set, but not saved, when the site's files were moved with `kana relocate` to a folder that can't
+	// currently be found, such as one on an external disk that isn't connected
+	Unavailable bool `json:"-"`
+}
+
+const registryFileName = "sites.json"
+
+// Load returns every registered site sorted by name. There are none until the first site is registered.
+func Load(appDirectory string) ([]Site, error) {
+	registeredSites, err := read(appDirectory)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []Site{}, nil
+		}
+
+		return []Site{}, err
+	}
+
+	sites := make([]Site, 0, len(registeredSites))
+
+	for _, site := range registeredSites {
+		sites = append(sites, site)
+	}
+
+	slices.SortFunc(sites, func(a, b Site) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return sites, nil
+}
+
+// Update changes the registered sites, keyed by name. Other Kana commands can't change the registry until it is done and
+// the registry is replaced in a single step so it is never left half written.
+func Update(appDirectory string, change func(sites map[string]Site)) error {
+	registryLock, err := lock.Acquire(appDirectory, "update the site registry", true, nil)
+	if err != nil {
+		return err
+	}
+
+	defer registryLock.Release() //nolint:errcheck
+
+	sites, err := read(appDirectory)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if sites == nil {
+		sites = map[string]Site{}
+	}
+
+	change(sites)
+
+	contents, err := json.MarshalIndent(sites, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	registryFile := filepath.Join(appDirectory, registryFileName)
+
+	err = os.WriteFile(registryFile+".tmp", contents, 0600)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(registryFile+".tmp", registryFile)
+}
+
+// Register adds the site to the registry, replacing any site already registered with its name but keeping when it was
+// first registered.
+func Register(appDirectory string, site Site) error {
+	site.Updated = time.Now().UTC()
+	site.Created = site.Updated
+
+	return Update(appDirectory, func(sites map[string]Site) {
+		if registeredSite, ok := sites[site.Name]; ok && !registeredSite.Created.IsZero() {
+			site.Created = registeredSite.Created
+		}
+
+		sites[site.Name] = site
+	})
+}
+
+// Unregister removes the named site from the registry.
+func Unregister(appDirectory, name string) error {
+	return Update(appDirectory, func(sites map[string]Site) {
+		delete(sites, name)
+	})
+}
+
+func read(appDirectory string) (map[string]Site, error) {
+	contents, err := os.ReadFile(filepath.Join(appDirectory, registryFileName))
+	if err != nil {
+		return nil, err
+	}
+
+	sites := map[string]Site{}
+
+	err = json.Unmarshal(contents, &sites)
+
+	return sites, err
+}
@@ -0,0 +1,73 @@
+package registry
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegister(t *testing.T) {
+	appDirectory := t.TempDir()
+
+	sites, err := Load(appDirectory)
+	assert.NoError(t, err)
+	assert.Empty(t, sites)
+
+	assert.NoError(t, Register(appDirectory, Site{Name: "zebra", Directory: "/sites/zebra", Link: "/projects/zebra"}))
+	assert.NoError(t, Register(appDirectory, Site{Name: "apple", Directory: "/sites/apple", Link: "/sites/apple"}))
+
+	sites, err = Load(appDirectory)
+	assert.NoError(t, err)
+	assert.Len(t, sites, 2)
+	assert.Equal(t, "apple", sites[0].Name)
+	assert.Equal(t, "zebra", sites[1].Name)
+	assert.Equal(t, "/projects/zebra", sites[1].Link)
+	assert.False(t, sites[1].Created.IsZero())
+
+	created := sites[1].Created
+
+	// Registering a site again replaces it, other than when it was created
+	assert.NoError(t, Register(appDirectory, Site{Name: "zebra", Directory: "/sites/zebra", Link: "/sites/zebra"}))
+
+	sites, err = Load(appDirectory)
+	assert.NoError(t, err)
+	assert.Len(t, sites, 2)
+	assert.Equal(t, "/sites/zebra", sites[1].Link)
+	assert.Equal(t, created, sites[1].Created)
+
+	assert.NoError(t, Unregister(appDirectory, "apple"))
+
+	sites, err = Load(appDirectory)
+	assert.NoError(t, err)
+	assert.Len(t, sites, 1)
+	assert.Equal(t, "zebra", sites[0].Name)
+
+	_, err = os.Stat(filepath.Join(appDirectory, registryFileName+".tmp"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestRegisterConcurrently(t *testing.T) {
+	appDirectory := t.TempDir()
+	names := []string{"one", "two", "three", "four", "five", "six"}
+
+	var wg sync.WaitGroup
+
+	for _, name := range names {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			assert.NoError(t, Register(appDirectory, Site{Name: name}))
+		}()
+	}
+
+	wg.Wait()
+
+	sites, err := Load(appDirectory)
+	assert.NoError(t, err)
+	assert.Len(t, sites, len(names))
+}
@@ -0,0 +1,304 @@
+package settings
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/ChrisWiegman/kana/internal/helpers"
+	"github.com/ChrisWiegman/kana/internal/registry"
+
+	"github.com/mitchellh/go-homedir"
+)
+
+// DefaultAppDirectory returns the directory Kana keeps its config, certificates and sites in when neither KANA_HOME nor
+// XDG_CONFIG_HOME is set.
+func DefaultAppDirectory() (string, error) {
+	home, err := homedir.Dir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, configFolderName), nil
+}
+
+// getAppDirectory returns the directory Kana keeps its config, certificates and sites in. KANA_HOME is used if it is set,
+// then XDG_CONFIG_HOME, with ~/.config/kana used otherwise.
+func getAppDirectory(home string) (string, error) {
+	if kanaHome := os.Getenv("KANA_HOME"); kanaHome != "" {
+		kanaHome, err := homedir.Expand(kanaHome)
+		if err != nil {
+			return "", err
+		}
+
+		return filepath.Abs(kanaHome)
+	}
+
+	// The XDG spec says relative paths are invalid and should be ignored
+	if configHome := os.Getenv("XDG_CONFIG_HOME"); filepath.IsAbs(configHome) {
+		return filepath.Join(configHome, "kana"), nil
+	}
+
+	return filepath.Join(home, configFolderName), nil
+}
+
+// getSitesDirectory returns the directory Kana keeps its sites, with their WordPress files and databases, in. Sites are
+// kept in the app directory when KANA_HOME is set, or when no XDG base directory is, and in XDG_DATA_HOME otherwise, with
+// ~/.local/share used if only XDG_CONFIG_HOME is set, so only config is kept in XDG_CONFIG_HOME.
+func getSitesDirectory(home, appDirectory string) string {
+	if os.Getenv("KANA_HOME") != "" {
+		return filepath.Join(appDirectory, "sites")
+	}
+
+	if dataHome := os.Getenv("XDG_DATA_HOME"); filepath.IsAbs(dataHome) {
+		return filepath.Join(dataHome, "kana", "sites")
+	}
+
+	if filepath.IsAbs(os.Getenv("XDG_CONFIG_HOME")) {
+		return filepath.Join(home, ".local", "share", "kana", "sites")
+	}
+
+	return filepath.Join(appDirectory, "sites")
+}
+
+// GetUnmigratedAppDirectory returns the default app directory if it still has sites in it while KANA_HOME or the XDG
+// base directories point Kana to another directory for sites without any, such as when XDG_CONFIG_HOME was set for
+// another tool, so the sites don't seem to silently disappear. An empty string is returned otherwise.
+func (s *Settings) GetUnmigratedAppDirectory() string {
+	defaultDirectory, err := DefaultAppDirectory()
+	if err != nil {
+		return ""
+	}
+
+	return getUnmigratedAppDirectory(s.Get("sitesDirectory"), defaultDirectory)
+}
+
+func getUnmigratedAppDirectory(sitesDirectory, defaultDirectory string) string {
+	defaultSitesDirectory := filepath.Join(defaultDirectory, "sites")
+
+	if sitesDirectory == defaultSitesDirectory || !hasSites(defaultSitesDirectory) || hasSites(sitesDirectory) {
+		return ""
+	}
+
+	return defaultDirectory
+}
+
+// hasSites returns true if there are any sites in the sites directory.
+func hasSites(sitesDirectory string) bool {
+	sites, err := os.ReadDir(sitesDirectory)
+
+	return err == nil && len(sites) > 0
+}
+
+// MigrateAppDirectory moves the sites, certificates and config from another app directory, such as the default one used
+// before KANA_HOME was set, to the current app directory, with the sites moved to the current sites directory. Links to
+// sites' own folders are updated for their new location. The names of the files and folders moved are returned.
+func (s *Settings) MigrateAppDirectory(from string) ([]string, error) {
+	moved := []string{}
+	to := s.Get("appDirectory")
+	toSites := s.Get("sitesDirectory")
+
+	from, err := filepath.Abs(from)
+	if err != nil {
+		return moved, err
+	}
+
+	fromSites := filepath.Join(from, "sites")
+
+	if from == to && fromSites == toSites {
+		return moved, fmt.Errorf("kana is already using %s. Set KANA_HOME to the directory to move it to first", to)
+	}
+
+	for _, directory := range []string{to, toSites} {
+		if strings.HasPrefix(directory, from+string(filepath.Separator)) && directory != fromSites {
+			return moved, fmt.Errorf("the app directory, %s, can't be moved inside itself", from)
+		}
+	}
+
+	entries, err := os.ReadDir(from)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return moved, fmt.Errorf("there is nothing to move as %s doesn't exist", from)
+		}
+
+		return moved, err
+	}
+
+	if fromSites != toSites && hasSites(toSites) {
+		return moved, fmt.Errorf("%s already has sites in it. Move them out of the way before migrating to it", toSites)
+	}
+
+	for _, entry := range entries {
+		destination := filepath.Join(to, entry.Name())
+
+		if entry.Name() == "sites" {
+			destination = toSites
+		}
+
+		// The lock is only held while a command runs so there is nothing in it worth moving
+		if entry.Name() == "kana.lock" || destination == filepath.Join(from, entry.Name()) {
+			continue
+		}
+
+		err = os.MkdirAll(filepath.Dir(destination), os.FileMode(defaultDirPermissions))
+		if err != nil {
+			return moved, err
+		}
+
+		err = moveFiles(filepath.Join(from, entry.Name()), destination)
+		if err != nil {
+			return moved, err
+		}
+
+		moved = append(moved, entry.Name())
+	}
+
+	err = relinkMovedSites(fromSites, toSites, to)
+	if err != nil {
+		return moved, err
+	}
+
+	if from != to {
+		_ = os.Remove(filepath.Join(from, "kana.lock"))
+		_ = os.Remove(from)
+	}
+
+	return moved, nil
+}
+
+// moveFiles moves a file or folder, even to another disk, merging folders that already exist at the destination, such
+// as the config folder created when Kana first ran with a new app directory. Files at the destination are replaced.
+func moveFiles(source, destination string) error {
+	sourceInfo, err := os.Lstat(source)
+	if err != nil {
+		return err
+	}
+
+	destinationInfo, err := os.Lstat(destination)
+	if err == nil {
+		if sourceInfo.IsDir() && destinationInfo.IsDir() {
+			entries, err := os.ReadDir(source)
+			if err != nil {
+				return err
+			}
+
+			for _, entry := range entries {
+				err = moveFiles(filepath.Join(source, entry.Name()), filepath.Join(destination, entry.Name()))
+				if err != nil {
+					return err
+				}
+			}
+
+			return os.Remove(source)
+		}
+
+		err = os.RemoveAll(destination)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = os.Rename(source, destination)
+	if !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+
+	// Files can't be renamed from one disk to another so they are copied instead. Nothing is removed unless everything
+	// was copied.
+	err = copyFiles(source, destination)
+	if err != nil {
+		_ = os.RemoveAll(destination)
+
+		return err
+	}
+
+	return os.RemoveAll(source)
+}
+
+// copyFiles copies a file, symbolic link or folder with everything in it, keeping symbolic links as they are rather than
+// copying what they point to. Anything else, such as a socket, can't be copied and returns an error.
+func copyFiles(source, destination string) error {
+	return filepath.WalkDir(source, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relativePath, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(destination, relativePath)
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case entry.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm())
+		case entry.Type()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+
+			return os.Symlink(link, target)
+		case entry.Type().IsRegular():
+			err = helpers.CopyFile(path, target)
+			if err != nil {
+				return err
+			}
+
+			return os.Chmod(target, info.Mode().Perm())
+		}
+
+		return fmt.Errorf("%s can't be moved to another disk as it isn't a file, folder or symbolic link", path)
+	})
+}
+
+// relinkMovedSites updates the links of sites linked to their own folder, such as named sites, and the site registry in
+// the app directory for the sites' new folders.
+func relinkMovedSites(from, to, appDirectory string) error {
+	sites, err := os.ReadDir(to)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	for _, site := range sites {
+		siteDirectory := filepath.Join(to, site.Name())
+
+		link, err := readSiteLink(siteDirectory)
+		if err != nil || moveDirectoryPrefix(link, from, to) == link {
+			continue
+		}
+
+		err = writeSiteLink(appDirectory, siteDirectory, moveDirectoryPrefix(link, from, to))
+		if err != nil {
+			return err
+		}
+	}
+
+	return registry.Update(appDirectory, func(registeredSites map[string]registry.Site) {
+		for name, site := range registeredSites {
+			site.Directory = moveDirectoryPrefix(site.Directory, from, to)
+			site.Link = moveDirectoryPrefix(site.Link, from, to)
+
+			registeredSites[name] = site
+		}
+	})
+}
+
+// moveDirectoryPrefix returns the path moved from one directory to another if it is inside the first directory.
+func moveDirectoryPrefix(path, from, to string) string {
+	if path != from && !strings.HasPrefix(path, from+string(filepath.Separator)) {
+		return path
+	}
+
+	return to + strings.TrimPrefix(path, from)
+}
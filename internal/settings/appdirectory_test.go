@@ -0,0 +1,190 @@
+package settings
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/ChrisWiegman/kana/internal/registry"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAppDirectory(t *testing.T) {
+	home := t.TempDir()
+	kanaHome := t.TempDir()
+	configHome := t.TempDir()
+
+	var tests = []struct {
+		name, kanaHome, configHome, expected string
+	}{
+		{"default", "", "", filepath.Join(home, configFolderName)},
+		{"xdg config home", "", configHome, filepath.Join(configHome, "kana")},
+		{"relative xdg config home is ignored", "", "config", filepath.Join(home, configFolderName)},
+		{"kana home", kanaHome, configHome, kanaHome},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("KANA_HOME", test.kanaHome)
+			t.Setenv("XDG_CONFIG_HOME", test.configHome)
+
+			appDirectory, err := getAppDirectory(home)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, appDirectory)
+		})
+	}
+}
+
+func TestGetSitesDirectory(t *testing.T) {
+	home := t.TempDir()
+	appDirectory := filepath.Join(home, configFolderName)
+	dataHome := t.TempDir()
+
+	var tests = []struct {
+		name, kanaHome, configHome, dataHome, expected string
+	}{
+		{"default", "", "", "", filepath.Join(appDirectory, "sites")},
+		{"xdg config home", "", t.TempDir(), "", filepath.Join(home, ".local", "share", "kana", "sites")},
+		{"xdg data home", "", "", dataHome, filepath.Join(dataHome, "kana", "sites")},
+		{"relative xdg data home is ignored", "", "", "data", filepath.Join(appDirectory, "sites")},
+		{"kana home", t.TempDir(), "", dataHome, filepath.Join(appDirectory, "sites")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("KANA_HOME", test.kanaHome)
+			t.Setenv("XDG_CONFIG_HOME", test.configHome)
+			t.Setenv("XDG_DATA_HOME", test.dataHome)
+
+			assert.Equal(t, test.expected, getSitesDirectory(home, appDirectory))
+		})
+	}
+}
+
+func TestGetUnmigratedAppDirectory(t *testing.T) {
+	defaultDirectory := t.TempDir()
+	sitesDirectory := filepath.Join(t.TempDir(), "sites")
+
+	assert.Equal(t, "", getUnmigratedAppDirectory(sitesDirectory, defaultDirectory))
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(defaultDirectory, "sites", "my-site"), 0750))
+
+	assert.Equal(t, defaultDirectory, getUnmigratedAppDirectory(sitesDirectory, defaultDirectory))
+	assert.Equal(t, "", getUnmigratedAppDirectory(filepath.Join(defaultDirectory, "sites"), defaultDirectory))
+
+	// Once there are sites in the new directory the old one is assumed to have been left behind on purpose
+	assert.NoError(t, os.MkdirAll(filepath.Join(sitesDirectory, "new-site"), 0750))
+
+	assert.Equal(t, "", getUnmigratedAppDirectory(sitesDirectory, defaultDirectory))
+}
+
+func TestMigrateAppDirectory(t *testing.T) {
+	from := t.TempDir()
+	to := filepath.Join(t.TempDir(), "kana")
+	projectDirectory := filepath.Join(t.TempDir(), "my-plugin")
+
+	s := &Settings{
+		settings: []Setting{
+			{name: "appDirectory", currentValue: from},
+			{name: "sitesDirectory", currentValue: filepath.Join(from, "sites")},
+		},
+	}
+
+	assert.NoError(t, s.UnlinkSite("named-site"))
+	assert.NoError(t, s.LinkSite("linked-site", projectDirectory))
+	assert.NoError(t, os.MkdirAll(filepath.Join(from, "certs"), 0750))
+	assert.NoError(t, os.WriteFile(filepath.Join(from, "certs", "kana.root.pem"), []byte("certificate"), 0600))
+
+	// Running Kana with the new directory creates its config folder before anything is moved
+	assert.NoError(t, os.MkdirAll(filepath.Join(to, "config"), 0750))
+	assert.NoError(t, os.WriteFile(filepath.Join(to, "config", "kana.json"), []byte("{}"), 0600))
+
+	// Sites are kept apart from the config when XDG_DATA_HOME is set
+	toSites := filepath.Join(t.TempDir(), "kana", "sites")
+
+	s.settings[0].currentValue = to
+	s.settings[1].currentValue = toSites
+
+	moved, err := s.MigrateAppDirectory(from)
+	assert.NoError(t, err)
+	assert.Contains(t, moved, "certs")
+	assert.Contains(t, moved, "sites")
+
+	_, err = os.Stat(from)
+	assert.True(t, os.IsNotExist(err))
+
+	_, err = os.Stat(filepath.Join(to, "sites"))
+	assert.True(t, os.IsNotExist(err))
+
+	contents, err := os.ReadFile(filepath.Join(to, "certs", "kana.root.pem"))
+	assert.NoError(t, err)
+	assert.Equal(t, "certificate", string(contents))
+
+	link, err := s.GetSiteDirectoryLink("named-site")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(toSites, "named-site"), link)
+
+	link, err = s.GetSiteDirectoryLink("linked-site")
+	assert.NoError(t, err)
+	assert.Equal(t, projectDirectory, link)
+
+	sites, err := registry.Load(to)
+	assert.NoError(t, err)
+	assert.Len(t, sites, 2)
+	assert.Equal(t, filepath.Join(toSites, "named-site"), sites[1].Directory)
+	assert.Equal(t, filepath.Join(toSites, "named-site"), sites[1].Link)
+
+	// Sites can be moved back from the data directory once XDG_DATA_HOME is unset
+	s.settings[1].currentValue = filepath.Join(to, "sites")
+
+	moved, err = s.MigrateAppDirectory(filepath.Dir(toSites))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"sites"}, moved)
+
+	link, err = s.GetSiteDirectoryLink("named-site")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(to, "sites", "named-site"), link)
+
+	_, err = s.MigrateAppDirectory(to)
+	assert.Error(t, err)
+
+	// Only the sites are moved when XDG_DATA_HOME is set and the app directory stays the same
+	s.settings[1].currentValue = toSites
+
+	moved, err = s.MigrateAppDirectory(to)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"sites"}, moved)
+
+	contents, err = os.ReadFile(filepath.Join(to, "certs", "kana.root.pem"))
+	assert.NoError(t, err)
+	assert.Equal(t, "certificate", string(contents))
+
+	link, err = s.GetSiteDirectoryLink("named-site")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(toSites, "named-site"), link)
+}
+
+func TestCopyFiles(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "sites")
+	destination := filepath.Join(t.TempDir(), "sites")
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(source, "my-site", "wordpress"), 0750))
+	assert.NoError(t, os.WriteFile(filepath.Join(source, "my-site", "link.json"), []byte("{}"), 0600))
+	assert.NoError(t, os.Symlink("/home/jane/my-plugin", filepath.Join(source, "my-site", "wordpress", "my-plugin")))
+
+	assert.NoError(t, copyFiles(source, destination))
+
+	contents, err := os.ReadFile(filepath.Join(destination, "my-site", "link.json"))
+	assert.NoError(t, err)
+	assert.Equal(t, "{}", string(contents))
+
+	link, err := os.Readlink(filepath.Join(destination, "my-site", "wordpress", "my-plugin"))
+	assert.NoError(t, err)
+	assert.Equal(t, "/home/jane/my-plugin", link)
+
+	// Anything that isn't a file, folder or symbolic link stops the copy so the source is never removed without it
+	assert.NoError(t, syscall.Mkfifo(filepath.Join(source, "my-site", "pipe"), 0600))
+	assert.Error(t, copyFiles(source, filepath.Join(t.TempDir(), "sites")))
+}
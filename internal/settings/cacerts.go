@@ -0,0 +1,54 @@
+package settings
+
+import (
+	"bytes"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ReadCACertificates reads the PEM files of the caCertificates setting, with relative paths being relative to the
+// given directory, and returns their certificates as one PEM bundle. Each file must hold at least one certificate.
+func ReadCACertificates(files []string, directory string) ([]byte, error) {
+	var bundle bytes.Buffer
+
+	for _, file := range files {
+		file = strings.TrimSpace(file)
+
+		if file == "" {
+			continue
+		}
+
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(directory, file)
+		}
+
+		contents, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("the CA certificate, %s, could not be read: %s", file, err)
+		}
+
+		certificates := 0
+
+		for block, rest := pem.Decode(contents); block != nil; block, rest = pem.Decode(rest) {
+			if block.Type != "CERTIFICATE" {
+				continue
+			}
+
+			err = pem.Encode(&bundle, block)
+			if err != nil {
+				return nil, err
+			}
+
+			certificates++
+		}
+
+		if certificates == 0 {
+			return nil, fmt.Errorf("the CA certificate, %s, is not valid. Use a PEM file holding one or more certificates", file)
+		}
+	}
+
+	return bundle.Bytes(), nil
+}
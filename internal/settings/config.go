@@ -3,12 +3,10 @@ package settings
 import (
 	"encoding/json"
 	"fmt"
-	"os"
+	"strconv"
 	"strings"
 
 	"github.com/ChrisWiegman/kana/internal/console"
-
-	"github.com/aquasecurity/table"
 )
 
 // ListSettings Lists all settings for the config command.
@@ -18,9 +16,10 @@ func ListSettings(settings *Settings, consoleOutput *console.Console) {
 		return
 	}
 
-	settingsTable := table.New(os.Stdout)
-
-	settingsTable.SetHeaders("Setting", "Global Value", "Local Value")
+	settingsTable := console.NewTable(
+		console.TableColumn{Header: "Setting"},
+		console.TableColumn{Header: "Global Value"},
+		console.TableColumn{Header: "Local Value"})
 
 	globalSettings := settings.GetAll("global")
 	localSettings := settings.GetAll("local")
@@ -35,7 +34,10 @@ func ListSettings(settings *Settings, consoleOutput *console.Console) {
 
 		if settings.settings[i].settingType == "slice" { //nolint:goconst
 			globalOutput = strings.Join(globalSettings[settings.settings[i].name].([]string), "\n")
-			localOutput = strings.Join(localSettings[settings.settings[i].name].([]string), "\n")
+
+			if settings.settings[i].hasLocal {
+				localOutput = strings.Join(localSettings[settings.settings[i].name].([]string), "\n")
+			}
 		}
 
 		if !settings.settings[i].hasLocal {
@@ -43,11 +45,35 @@ func ListSettings(settings *Settings, consoleOutput *console.Console) {
 		}
 
 		settingsTable.AddRow(settings.settings[i].name,
-			consoleOutput.Bold(globalOutput),
-			consoleOutput.Bold(localOutput))
+			console.Cell{Value: globalOutput, Style: consoleOutput.Bold},
+			console.Cell{Value: localOutput, Style: consoleOutput.Bold})
+	}
+
+	consoleOutput.PrintTable(settingsTable)
+}
+
+// ListAllSettings Lists every setting with its current value, default, where the value came from and a description.
+func ListAllSettings(settings *Settings, consoleOutput *console.Console) {
+	settingsTable := console.NewTable(
+		console.TableColumn{Header: "Setting"},
+		console.TableColumn{Header: "Value"},
+		console.TableColumn{Header: "Default"},
+		console.TableColumn{Header: "Source"},
+		console.TableColumn{Header: "Description"})
+
+	for i := range settings.settings {
+		if !settings.settings[i].hasGlobal && !settings.settings[i].hasLocal {
+			continue
+		}
+
+		settingsTable.AddRow(settings.settings[i].name,
+			getValueCell(typedValue(settings.settings[i], settings.settings[i].currentValue), consoleOutput.Bold),
+			getValueCell(typedValue(settings.settings[i], settings.settings[i].defaultValue), nil),
+			settings.settings[i].source,
+			settings.settings[i].description)
 	}
 
-	settingsTable.Render()
+	consoleOutput.PrintTable(settingsTable)
 }
 
 func PrintSingleSetting(name string, kanaSettings *Settings, consoleOutput *console.Console) {
@@ -71,6 +97,41 @@ func PrintSingleSetting(name string, kanaSettings *Settings, consoleOutput *cons
 	}
 }
 
+// getValueCell returns a table cell displaying a typed setting value, keeping its type for JSON output.
+func getValueCell(value interface{}, style func(string) string) console.Cell {
+	cell := console.Cell{Value: value, Style: style}
+
+	if sliceValue, ok := value.([]string); ok {
+		cell.Text = strings.Join(sliceValue, "\n")
+	}
+
+	return cell
+}
+
+// typedValue converts a stored setting value to its setting's type.
+func typedValue(setting Setting, value string) interface{} {
+	switch setting.settingType {
+	case "bool":
+		boolValue, _ := strconv.ParseBool(value)
+
+		return boolValue
+	case "int":
+		intValue, _ := strconv.ParseInt(value, 10, 64)
+
+		return intValue
+	case "slice":
+		sliceValue := []string{}
+
+		if value != "" {
+			sliceValue = strings.Split(value, ",")
+		}
+
+		return sliceValue
+	}
+
+	return value
+}
+
 // printJSONSettings Prints out all settings in JSON format.
 func printJSONSettings(settings *Settings) {
 	type JSONSettings struct {
@@ -11,6 +11,11 @@ var defaults = []Setting{
 		defaultValue: "",
 		settingType:  "string",
 	},
+	{
+		name:         "sitesDirectory",
+		defaultValue: "",
+		settingType:  "string",
+	},
 	{
 		name:         "workingDirectory",
 		defaultValue: "",
@@ -36,8 +41,14 @@ var defaults = []Setting{
 		defaultValue: "false",
 		settingType:  "bool",
 	},
+	{
+		name:         "isCI",
+		defaultValue: "false",
+		settingType:  "bool",
+	},
 	{
 		name:         "activate",
+		description:  "Activate the plugin or theme being developed when the site starts.",
 		defaultValue: "true",
 		settingType:  "bool",
 		hasLocal:     true,
@@ -50,36 +61,186 @@ var defaults = []Setting{
 	},
 	{
 		name:         "adminEmail",
+		description:  "The email address of the default WordPress admin account.",
 		defaultValue: "admin@sites.kana.sh",
 		settingType:  "string",
 		hasGlobal:    true,
 	},
 	{
 		name:         "adminPassword",
+		description:  "The password of the default WordPress admin account.",
 		defaultValue: "password",
 		settingType:  "string",
 		hasGlobal:    true,
 	},
 	{
 		name:         "adminUser",
+		description:  "The username of the default WordPress admin account.",
 		defaultValue: "admin",
 		settingType:  "string",
 		hasGlobal:    true,
 	},
 	{
 		name:         "automaticLogin",
+		description:  "Log in the admin user automatically when opening the dashboard.",
 		defaultValue: "true",
 		settingType:  "bool",
 		hasLocal:     true,
 		hasGlobal:    true,
 	},
+	{
+		name:         "autoResume",
+		description:  "Start the sites that were running again, without asking, after Docker restarts.",
+		defaultValue: "false",
+		settingType:  "bool",
+		hasGlobal:    true,
+	},
+	{
+		name:         "backupInterval",
+		description:  "The number of days between scheduled database backups. 0 disables them.",
+		defaultValue: "0",
+		settingType:  "int",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
+	{
+		name:         "backupRemoteAccessKey",
+		description:  "The access key used to push backups to an S3-compatible remote.",
+		defaultValue: "",
+		settingType:  "string",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
+	{
+		name:         "backupRemoteBucket",
+		description:  "The bucket on the S3-compatible remote where backups are pushed.",
+		defaultValue: "",
+		settingType:  "string",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
+	{
+		name:         "backupRemoteEndpoint",
+		description:  "The endpoint of the S3-compatible remote used for backups.",
+		defaultValue: "",
+		settingType:  "string",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
+	{
+		name:         "backupRemoteRegion",
+		description:  "The region of the S3-compatible remote used for backups.",
+		defaultValue: "us-east-1",
+		settingType:  "string",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
+	{
+		name:         "backupRetention",
+		description:  "The number of scheduled backups to keep. 0 keeps all backups.",
+		defaultValue: "5",
+		settingType:  "int",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
+	{
+		name:         "browser",
+		description:  "The browser used to open sites. Leave empty to use your default browser.",
+		defaultValue: "",
+		settingType:  "string",
+		hasGlobal:    true,
+	},
+	{
+		name:         "caCertificates",
+		description:  "PEM files of extra CA certificates, such as a company proxy's, trusted in the site's containers.",
+		defaultValue: "",
+		settingType:  "slice",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
+	{
+		name:         "catchMail",
+		description:  "Send all of the site's email to Mailpit, overriding SMTP and email API plugins, so it never reaches real people.",
+		defaultValue: "true",
+		settingType:  "bool",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
+	{
+		name:         "ciPort",
+		description:  "The port the site is served on, without SSL, in CI mode.",
+		defaultValue: "8080",
+		settingType:  "int",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
+	{
+		name:         "cliImage",
+		description:  "A Docker image used to run wp-cli instead of the official WordPress CLI image.",
+		defaultValue: "",
+		settingType:  "string",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
+	{
+		name:         "colorOverrides",
+		description:  "Colors to change from the selected theme, in the form element=color.",
+		defaultValue: "",
+		settingType:  "slice",
+		hasGlobal:    true,
+	},
+	{
+		name:         "colorTheme",
+		description:  "The colors used for Kana's output.",
+		defaultValue: "default",
+		settingType:  "string",
+		validValues: []string{
+			"colorblind",
+			"default",
+			"high-contrast"},
+		hasGlobal: true,
+	},
+	{
+		name:         "composerVersion",
+		description:  "The version of Composer used by kana composer, such as 2 or 2.7.",
+		defaultValue: "2",
+		settingType:  "string",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
+	{
+		name:         "corsCredentials",
+		description:  "Allow cross-origin requests from the corsOrigins setting to include cookies and other credentials.",
+		defaultValue: "false",
+		settingType:  "bool",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
+	{
+		name:         "corsHeaders",
+		description:  "The request headers cross-origin requests from the corsOrigins setting may use.",
+		defaultValue: "Authorization,Content-Type,X-WP-Nonce",
+		settingType:  "slice",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
+	{
+		name:         "corsOrigins",
+		description:  "Origins, such as http://localhost:3000, allowed to make cross-origin requests to the site. Use * to allow any origin.",
+		defaultValue: "",
+		settingType:  "slice",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
 	{
 		name:         "database",
+		description:  "The database server used by the site.",
 		defaultValue: "mariadb",
 		settingType:  "string",
 		validValues: []string{
 			"mariadb",
 			"mysql",
+			"postgres",
 			"sqlite"},
 		hasLocal:     true,
 		hasGlobal:    true,
@@ -90,6 +251,7 @@ var defaults = []Setting{
 	},
 	{
 		name:         "databaseClient",
+		description:  "The application used to open the database with `kana open --database`.",
 		defaultValue: "phpmyadmin",
 		settingType:  "string",
 		validValues: []string{
@@ -98,8 +260,17 @@ var defaults = []Setting{
 		hasLocal:  true,
 		hasGlobal: true,
 	},
+	{
+		name:         "databasePort",
+		description:  "The port the database is published on for database clients. 0 publishes it on a random free port.",
+		defaultValue: "0",
+		settingType:  "int",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
 	{
 		name:         "databaseVersion",
+		description:  "The version of the database server used by the site.",
 		defaultValue: mariadbVersion,
 		settingType:  "string",
 		hasGlobal:    true,
@@ -107,6 +278,7 @@ var defaults = []Setting{
 	},
 	{
 		name:         "environment",
+		description:  "The WP_ENVIRONMENT_TYPE of the site.",
 		defaultValue: "local",
 		settingType:  "string",
 		validValues: []string{
@@ -121,8 +293,55 @@ var defaults = []Setting{
 			Usage: "Sets the WP_ENVIRONMENT_TYPE for the site.",
 		},
 	},
+	{
+		name:         "extraUsers",
+		description:  "Additional users, in the form username=role, created when seeding users.",
+		defaultValue: "",
+		settingType:  "slice",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
+	{
+		name:         "headers",
+		description:  "Response headers, in the form Name=value, added to every response from the site by Traefik.",
+		defaultValue: "",
+		settingType:  "slice",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
+	{
+		name:         "httpProxy",
+		description:  "The proxy server for HTTP requests from Kana and the site's containers. Defaults to HTTP_PROXY.",
+		defaultValue: "",
+		settingType:  "string",
+		hasGlobal:    true,
+	},
+	{
+		name:         "httpsProxy",
+		description:  "The proxy server for HTTPS requests from Kana and the site's containers. Defaults to HTTPS_PROXY.",
+		defaultValue: "",
+		settingType:  "string",
+		hasGlobal:    true,
+	},
+	{
+		name:         "installDependencies",
+		description:  "Install and activate the plugins required by the plugin or theme's composer.json and Requires Plugins header.",
+		defaultValue: "true",
+		settingType:  "bool",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
+	{
+		name:         "loginUser",
+		description:  "The username or role to log in as automatically. Leave empty to use the first administrator.",
+		defaultValue: "",
+		settingType:  "string",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
 	{
 		name:         "mailpit",
+		description:  "Run Mailpit alongside the site to catch outgoing email.",
 		defaultValue: "false",
 		settingType:  "bool",
 		hasLocal:     true,
@@ -133,8 +352,17 @@ var defaults = []Setting{
 			Usage:     "Enable Mailpit when starting the container.",
 		},
 	},
+	{
+		name:         "middlewares",
+		description:  "Traefik middlewares, such as redirects, applied to the site in the form name.type.option=value.",
+		defaultValue: "",
+		settingType:  "slice",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
 	{
 		name:         "multisite",
+		description:  "Install the site as a subdomain or subdirectory multisite.",
 		defaultValue: "none",
 		settingType:  "string",
 		validValues: []string{
@@ -149,8 +377,47 @@ var defaults = []Setting{
 			Usage:         "Creates your new site as a multisite installation.",
 		},
 	},
+	{
+		name:         "networkRetries",
+		description:  "Times to retry image pulls and other network requests.",
+		defaultValue: "3",
+		settingType:  "int",
+		hasGlobal:    true,
+	},
+	{
+		name:         "noProxy",
+		description:  "Hosts, separated by commas, reached without the proxy server. Defaults to NO_PROXY.",
+		defaultValue: "",
+		settingType:  "string",
+		hasGlobal:    true,
+	},
+	{
+		name:         "nodeVersion",
+		description:  "The version of Node.js used by kana npm and kana npx, such as 22 or 22.11.",
+		defaultValue: "22",
+		settingType:  "string",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
+	{
+		name:         "permalinks",
+		description:  "The permalink structure set when WordPress is installed, such as /%postname%/. Leave empty for plain permalinks.",
+		defaultValue: "/%postname%/",
+		settingType:  "string",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
+	{
+		name:         "persistentCli",
+		description:  "Keep a wp-cli container running alongside the site.",
+		defaultValue: "false",
+		settingType:  "bool",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
 	{
 		name:         "php",
+		description:  "The PHP version used by the site.",
 		defaultValue: "8.2",
 		settingType:  "string",
 		hasLocal:     true,
@@ -158,6 +425,7 @@ var defaults = []Setting{
 	},
 	{
 		name:         "plugins",
+		description:  "Plugins from WordPress.org to install and activate when the site starts.",
 		defaultValue: "",
 		settingType:  "slice",
 		hasLocal:     true,
@@ -167,8 +435,77 @@ var defaults = []Setting{
 			Usage: "Installs and activates the specified plugins. Multiple plugins should be separated by commas",
 		},
 	},
+	{
+		name:         "projects",
+		description:  "Folders to search for the plugins and themes of a monorepo.",
+		defaultValue: "plugins/*,themes/*",
+		settingType:  "slice",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
+	{
+		name:         "pullHost",
+		description:  "The SSH host, such as user@example.com, kana pull copies the site from.",
+		defaultValue: "",
+		settingType:  "string",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
+	{
+		name:         "pullPath",
+		description:  "The folder WordPress is installed in on the pullHost server, such as /var/www/html.",
+		defaultValue: "",
+		settingType:  "string",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
+	{
+		name:         "pushExclude",
+		description:  "Files and folders, as rsync patterns, kana push never copies to the server.",
+		defaultValue: ".git,node_modules",
+		settingType:  "slice",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
+	{
+		name:         "pushHost",
+		description:  "The SSH host kana push deploys the site to. The pullHost is used if empty.",
+		defaultValue: "",
+		settingType:  "string",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
+	{
+		name:         "pushPath",
+		description:  "The folder WordPress is installed in on the pushHost server. The pullPath is used if empty.",
+		defaultValue: "",
+		settingType:  "string",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
+	{
+		name:         "readOnlyPaths",
+		description:  "Folders in the site, such as wp-content/plugins/premium-plugin, mounted read-only so they can't be changed from the site.",
+		defaultValue: "",
+		settingType:  "slice",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
+	{
+		name:         "redis",
+		description:  "Run Redis for the site and use it as WordPress's object cache.",
+		defaultValue: "false",
+		settingType:  "bool",
+		hasLocal:     true,
+		hasGlobal:    true,
+		hasStartFlag: true,
+		startFlag: StartFlag{
+			Usage: "If true will start Redis and use it as the site's object cache.",
+		},
+	},
 	{
 		name:         "removeDefaultPlugins",
+		description:  "Remove Akismet and Hello Dolly when the site starts.",
 		defaultValue: "false",
 		settingType:  "bool",
 		hasLocal:     true,
@@ -179,8 +516,53 @@ var defaults = []Setting{
 			Usage:     "If true will remove the default plugins installed with WordPress (Akismet and Hello Dolly) when starting a site.",
 		},
 	},
+	{
+		name:         "removeDefaultThemes",
+		description:  "Remove the Twenty themes bundled with WordPress, other than the active theme, when the site starts.",
+		defaultValue: "false",
+		settingType:  "bool",
+		hasLocal:     true,
+		hasGlobal:    true,
+		hasStartFlag: true,
+		startFlag: StartFlag{
+			Usage: "If true will remove the unused default themes installed with WordPress when starting a site.",
+		},
+	},
+	{
+		name:         "routes",
+		description:  "Paths of the site's domain, in the form /path=target, sent to a host port or URL instead of WordPress.",
+		defaultValue: "",
+		settingType:  "slice",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
+	{
+		name:         "safeImport",
+		description:  "Make imported databases safe for development using the safeImportOptions and safeImportPlugins settings.",
+		defaultValue: "true",
+		settingType:  "bool",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
+	{
+		name:         "safeImportOptions",
+		description:  "Options, in the form option=value or option.key=value, set after importing a database if the site has them.",
+		defaultValue: "blog_public=0,woocommerce_stripe_settings.testmode=yes,woocommerce_paypal_settings.testmode=yes",
+		settingType:  "slice",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
+	{
+		name:         "safeImportPlugins",
+		description:  "Plugins, such as backup and offloading plugins that write to production services, deactivated after importing a database.",
+		defaultValue: "amazon-s3-and-cloudfront,backwpup,updraftplus,wp-stateless",
+		settingType:  "slice",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
 	{
 		name:         "scriptDebug",
+		description:  "Enable SCRIPT_DEBUG for the site.",
 		defaultValue: "false",
 		settingType:  "bool",
 		hasLocal:     true,
@@ -191,8 +573,25 @@ var defaults = []Setting{
 			Usage:     "Enable SCRIPT_DEBUG when starting the WordPress site.",
 		},
 	},
+	{
+		name:         "seedUsers",
+		description:  "Create a user with known credentials for each core role when the site starts.",
+		defaultValue: "false",
+		settingType:  "bool",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
+	{
+		name:         "sshAgent",
+		description:  "Share your SSH agent with the wp-cli, Composer and npm containers for private Git repositories and remote wp-cli aliases.",
+		defaultValue: "false",
+		settingType:  "bool",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
 	{
 		name:         "ssl",
+		description:  "Serve the site over https.",
 		defaultValue: "false",
 		settingType:  "bool",
 		hasLocal:     true,
@@ -203,8 +602,58 @@ var defaults = []Setting{
 			Usage:     "Whether the site should default to SSL (https) or not.",
 		},
 	},
+	{
+		name:         "starterContent",
+		description:  "Content added to the site when WordPress is first installed.",
+		defaultValue: "none",
+		settingType:  "string",
+		validValues: []string{
+			"block-patterns",
+			"none",
+			"theme-unit-test"},
+		hasLocal:     true,
+		hasGlobal:    true,
+		hasStartFlag: true,
+		startFlag: StartFlag{
+			Usage: "Add content to the site when WordPress is first installed: theme-unit-test, block-patterns or none.",
+		},
+	},
+	{
+		name:         "syncPlugins",
+		description:  "Set to strict to deactivate and delete plugins that aren't in the plugins setting when the site starts.",
+		defaultValue: "additive",
+		settingType:  "string",
+		validValues: []string{
+			"additive",
+			"strict"},
+		hasLocal:  true,
+		hasGlobal: true,
+	},
+	{
+		name:         "telemetry",
+		description:  "Send anonymous usage metrics.",
+		defaultValue: "false",
+		settingType:  "bool",
+		hasGlobal:    true,
+	},
+	{
+		name:         "telemetryEndpoint",
+		description:  "The URL usage metrics are sent to.",
+		defaultValue: "",
+		settingType:  "string",
+		hasGlobal:    true,
+	},
+	{
+		name:         "testCommand",
+		description:  "The command kana test runs in the plugin or theme's folder against each version of WordPress.",
+		defaultValue: "",
+		settingType:  "string",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
 	{
 		name:         "theme",
+		description:  "A theme from WordPress.org to install and activate when the site starts.",
 		defaultValue: "",
 		settingType:  "string",
 		hasLocal:     true,
@@ -216,17 +665,19 @@ var defaults = []Setting{
 	},
 	{
 		name:         "type",
+		description:  "Whether the working directory is a site, plugin, theme or a monorepo of plugins and themes.",
 		defaultValue: "site",
 		settingType:  "string",
 		validValues: []string{
 			"site",
 			"plugin",
-			"theme"},
+			"theme",
+			"monorepo"},
 		hasLocal:     true,
 		hasGlobal:    true,
 		hasStartFlag: true,
 		startFlag: StartFlag{
-			Usage: "Set the type of the installation, `site`, `plugin` or `theme`.",
+			Usage: "Set the type of the installation, `site`, `plugin`, `theme` or `monorepo`.",
 		},
 	},
 	{
@@ -236,12 +687,50 @@ var defaults = []Setting{
 	},
 	{
 		name:         "updateInterval",
+		description:  "The number of days between checks for updated Docker images. 0 disables the check.",
 		defaultValue: "7",
 		settingType:  "int",
 		hasGlobal:    true,
 	},
+	{
+		name:         "updateServer",
+		description:  "Run a mock update server that releases published with 'kana updates publish' are offered from.",
+		defaultValue: "false",
+		settingType:  "bool",
+		hasLocal:     true,
+		hasGlobal:    true,
+		hasStartFlag: true,
+		startFlag: StartFlag{
+			Usage: "If true will run a mock update server that plugin releases can be published to with 'kana updates publish'.",
+		},
+	},
+	{
+		name:         "wordpressAPI",
+		description:  "How WordPress.org API requests are made: live, through a shared cache that also works offline, or mock.",
+		defaultValue: "live",
+		settingType:  "string",
+		validValues: []string{
+			"cache",
+			"live",
+			"mock"},
+		hasLocal:     true,
+		hasGlobal:    true,
+		hasStartFlag: true,
+		startFlag: StartFlag{
+			Usage: "How WordPress.org API requests are made: live, cache to cache them for offline use or mock for fixed replies.",
+		},
+	},
+	{
+		name:         "wpCliVersion",
+		description:  "The version of wp-cli used by the site. Leave empty to use the latest version.",
+		defaultValue: "",
+		settingType:  "string",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
 	{
 		name:         "wpdebug",
+		description:  "Enable WP_DEBUG for the site.",
 		defaultValue: "false",
 		settingType:  "bool",
 		hasLocal:     true,
@@ -252,8 +741,17 @@ var defaults = []Setting{
 			Usage:     "Enable WP_Debug when starting the WordPress site.",
 		},
 	},
+	{
+		name:         "wpCliConfig",
+		description:  "A wp-cli config file to use instead of the project's wp-cli.local.yml or wp-cli.yml.",
+		defaultValue: "",
+		settingType:  "string",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
 	{
 		name:         "xdebug",
+		description:  "Enable Xdebug for the site.",
 		defaultValue: "false",
 		settingType:  "bool",
 		hasLocal:     true,
@@ -274,6 +772,7 @@ const (
 	domain                 = "sites.kana.sh"
 	mariadbVersion         = "11"
 	mysqlVersion           = "8"
+	postgresVersion        = "16"
 	rootCert               = "kana.root.pem"
 	rootKey                = "kana.root.key"
 	siteCert               = "kana.site.pem"
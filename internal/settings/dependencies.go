@@ -0,0 +1,142 @@
+package settings
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var requiresPluginsHeader = regexp.MustCompile(`Requires Plugins:\s*(.*)`)
+
+const wpackagistPluginPrefix = "wpackagist-plugin/"
+
+// GetPluginDependencies returns the WordPress.org plugins required by the plugins and themes being developed,
+// from the wpackagist requirements in their composer.json files and the "Requires Plugins" header of plugins.
+// Plugins being developed in the same site aren't included as they're already mapped into it.
+func (s *Settings) GetPluginDependencies() ([]string, error) {
+	dependencies := []string{}
+	directories := []string{}
+	projectNames := []string{}
+
+	switch s.Get("type") {
+	case "plugin", "theme":
+		directories = append(directories, s.Get("workingDirectory"))
+		projectNames = append(projectNames, s.Get("name"))
+	case "monorepo":
+		projects, err := s.GetProjects()
+		if err != nil {
+			return dependencies, err
+		}
+
+		for _, project := range projects {
+			directories = append(directories, project.Path)
+			projectNames = append(projectNames, project.Name)
+		}
+	default:
+		return dependencies, nil
+	}
+
+	for _, directory := range directories {
+		composerDependencies, err := readComposerDependencies(directory)
+		if err != nil {
+			return dependencies, err
+		}
+
+		headerDependencies, err := readRequiresPlugins(directory)
+		if err != nil {
+			return dependencies, err
+		}
+
+		for _, dependency := range append(composerDependencies, headerDependencies...) {
+			if !slices.Contains(dependencies, dependency) && !slices.Contains(projectNames, dependency) {
+				dependencies = append(dependencies, dependency)
+			}
+		}
+	}
+
+	slices.Sort(dependencies)
+
+	return dependencies, nil
+}
+
+// readComposerDependencies returns the wpackagist plugins required by the directory's composer.json file, if any.
+func readComposerDependencies(directory string) ([]string, error) {
+	dependencies := []string{}
+
+	contents, err := os.ReadFile(filepath.Join(directory, "composer.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return dependencies, nil
+		}
+
+		return dependencies, err
+	}
+
+	var composer struct {
+		Require    map[string]string `json:"require"`
+		RequireDev map[string]string `json:"require-dev"`
+	}
+
+	err = json.Unmarshal(contents, &composer)
+	if err != nil {
+		return dependencies, fmt.Errorf("unable to read %s: %s", filepath.Join(directory, "composer.json"), err)
+	}
+
+	for _, requirements := range []map[string]string{composer.Require, composer.RequireDev} {
+		for requirement := range requirements {
+			if strings.HasPrefix(requirement, wpackagistPluginPrefix) {
+				dependencies = append(dependencies, strings.TrimPrefix(requirement, wpackagistPluginPrefix))
+			}
+		}
+	}
+
+	return dependencies, nil
+}
+
+// readRequiresPlugins returns the plugins listed in the "Requires Plugins" header of the directory's main plugin file.
+func readRequiresPlugins(directory string) ([]string, error) {
+	dependencies := []string{}
+
+	items, _ := os.ReadDir(directory)
+
+	for _, item := range items {
+		if item.IsDir() || filepath.Ext(item.Name()) != ".php" {
+			continue
+		}
+
+		file := filepath.Join(directory, item.Name())
+
+		projectType, err := readProjectHeader(file)
+		if err != nil {
+			return dependencies, err
+		}
+
+		if projectType != "plugin" {
+			continue
+		}
+
+		contents, err := os.ReadFile(file)
+		if err != nil {
+			return dependencies, err
+		}
+
+		match := requiresPluginsHeader.FindStringSubmatch(string(contents))
+		if match == nil {
+			return dependencies, nil
+		}
+
+		for _, dependency := range strings.Split(match[1], ",") {
+			if strings.TrimSpace(dependency) != "" {
+				dependencies = append(dependencies, strings.TrimSpace(dependency))
+			}
+		}
+
+		return dependencies, nil
+	}
+
+	return dependencies, nil
+}
@@ -0,0 +1,56 @@
+package settings
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSettings_GetPluginDependencies(t *testing.T) {
+	workingDirectory := t.TempDir()
+
+	files := map[string]string{
+		"plugins/my-plugin/my-plugin.php": "<?php\n/**\n * Plugin Name: My Plugin\n * Requires Plugins: woocommerce, my-theme-helper ,\n */",
+		"plugins/my-plugin/composer.json": `{"require": {"php": ">=7.4", "wpackagist-plugin/query-monitor": "*"},
+			"require-dev": {"wpackagist-plugin/debug-bar": "*", "wpackagist-theme/storefront": "*"}}`,
+		"plugins/my-theme-helper/helper.php": "<?php\n/**\n * Plugin Name: My Theme Helper\n * Requires Plugins: woocommerce\n */",
+		"themes/my-theme/style.css":          "/*\nTheme Name: My Theme\n*/",
+		"themes/my-theme/composer.json":      `{"require": {"wpackagist-plugin/advanced-custom-fields": "^6.0"}}`,
+	}
+
+	for file, contents := range files {
+		err := os.MkdirAll(filepath.Join(workingDirectory, filepath.Dir(file)), 0750)
+		assert.NoError(t, err)
+
+		err = os.WriteFile(filepath.Join(workingDirectory, file), []byte(contents), 0600)
+		assert.NoError(t, err)
+	}
+
+	s := &Settings{
+		settings: []Setting{
+			{name: "name", currentValue: "my-site"},
+			{name: "type", currentValue: "monorepo"},
+			{name: "workingDirectory", currentValue: workingDirectory},
+			{name: "projects", settingType: "slice", currentValue: "plugins/*,themes/*"},
+		},
+	}
+
+	dependencies, err := s.GetPluginDependencies()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"advanced-custom-fields", "debug-bar", "query-monitor", "woocommerce"}, dependencies)
+
+	s.settings[1].currentValue = "plugin"
+	s.settings[2].currentValue = filepath.Join(workingDirectory, "plugins", "my-plugin")
+
+	dependencies, err = s.GetPluginDependencies()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"debug-bar", "my-theme-helper", "query-monitor", "woocommerce"}, dependencies)
+
+	s.settings[1].currentValue = "site"
+
+	dependencies, err = s.GetPluginDependencies()
+	assert.NoError(t, err)
+	assert.Empty(t, dependencies)
+}
@@ -0,0 +1,172 @@
+package settings
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/helpers"
+
+	"github.com/knadh/koanf/v2"
+)
+
+// DiffSettings Lists the settings the current site overrides from the global config or, if another site is given,
+// the site settings that differ between the two sites.
+func DiffSettings(settings *Settings, otherSite string, consoleOutput *console.Console) error {
+	siteName := settings.Get("name")
+
+	diffTable := console.NewTable(
+		console.TableColumn{Header: "Setting"},
+		console.TableColumn{Header: "Global"},
+		console.TableColumn{Header: siteName, Key: "Site"})
+
+	first := settings.getLayeredValues(settings.global)
+	second := settings.getLayeredValues(settings.global, settings.local, settings.personal)
+
+	if otherSite != "" {
+		otherSite = helpers.SanitizeSiteName(otherSite)
+
+		otherConfig, otherPersonal, err := settings.loadSiteConfig(otherSite)
+		if err != nil {
+			return err
+		}
+
+		diffTable = console.NewTable(
+			console.TableColumn{Header: "Setting"},
+			console.TableColumn{Header: siteName, Key: "Site"},
+			console.TableColumn{Header: otherSite, Key: "OtherSite"})
+
+		first = second
+		second = settings.getLayeredValues(settings.global, otherConfig, otherPersonal)
+	}
+
+	differences := 0
+
+	for i := range settings.settings {
+		name := settings.settings[i].name
+
+		if !settings.settings[i].hasLocal || reflect.DeepEqual(first[name], second[name]) {
+			continue
+		}
+
+		// A site that doesn't set a value is using the global one so there's nothing to report
+		if otherSite == "" && !isSetIn(name, settings.local, settings.personal) {
+			continue
+		}
+
+		diffTable.AddRow(name,
+			getValueCell(first[name], nil),
+			getValueCell(second[name], consoleOutput.Bold))
+
+		differences++
+	}
+
+	if differences == 0 && !consoleOutput.JSON {
+		if otherSite != "" {
+			consoleOutput.Println(fmt.Sprintf("%s and %s use the same site settings.", siteName, otherSite))
+		} else {
+			consoleOutput.Println(fmt.Sprintf("%s doesn't override any global settings.", siteName))
+		}
+
+		return nil
+	}
+
+	consoleOutput.PrintTable(diffTable)
+
+	return nil
+}
+
+// getLayeredValues returns the typed value of each setting with the given config layers applied over the defaults in order.
+func (s *Settings) getLayeredValues(layers ...Koanf) map[string]interface{} {
+	values := make(map[string]interface{})
+
+	for i := range s.settings {
+		values[s.settings[i].name] = typedValue(s.settings[i], s.settings[i].defaultValue)
+
+		for _, layer := range layers {
+			if layer == nil || !layer.Exists(s.settings[i].name) {
+				continue
+			}
+
+			switch s.settings[i].settingType {
+			case "bool":
+				values[s.settings[i].name] = layer.Bool(s.settings[i].name)
+			case "int":
+				values[s.settings[i].name] = layer.Int64(s.settings[i].name)
+			case "slice":
+				values[s.settings[i].name] = typedValue(s.settings[i], strings.Join(layer.Strings(s.settings[i].name), ","))
+			default:
+				values[s.settings[i].name] = layer.String(s.settings[i].name)
+			}
+		}
+	}
+
+	return values
+}
+
+// isSetIn returns true if any of the given configs contain the setting.
+func isSetIn(name string, configs ...Koanf) bool {
+	for _, config := range configs {
+		if config != nil && config.Exists(name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// loadSiteConfig loads the site and personal config files of another site using the link saved when the site was created.
+func (s *Settings) loadSiteConfig(name string) (siteConfig, personalConfig Koanf, err error) {
+	siteConfig = koanf.New(".")
+	personalConfig = koanf.New(".")
+
+	content, err := os.ReadFile(filepath.Join(s.Get("sitesDirectory"), name, "link.json"))
+	if err != nil {
+		return siteConfig, personalConfig, fmt.Errorf("the site %s could not be found. Use `kana list` to see all sites", name)
+	}
+
+	var siteLink map[string]string
+
+	err = json.Unmarshal(content, &siteLink)
+	if err != nil {
+		return siteConfig, personalConfig, err
+	}
+
+	for _, settingsType := range []string{"local", "personal"} {
+		configFile := getConfigFile(settingsType, siteLink["link"], s.Get("appDirectory"))
+
+		_, err = os.Stat(configFile)
+		if err != nil {
+			// Sites without a config file use the global settings
+			if os.IsNotExist(err) {
+				continue
+			}
+
+			return siteConfig, personalConfig, err
+		}
+
+		config, _, err := readConfigFile(configFile)
+		if err != nil {
+			return siteConfig, personalConfig, err
+		}
+
+		ko := siteConfig
+
+		if settingsType == "personal" {
+			ko = personalConfig
+
+			removeLockedSettings(config, getLockedSettings(siteConfig))
+		}
+
+		err = ko.Load(configProvider{config: config}, nil)
+		if err != nil {
+			return siteConfig, personalConfig, err
+		}
+	}
+
+	return siteConfig, personalConfig, nil
+}
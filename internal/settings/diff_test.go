@@ -0,0 +1,34 @@
+package settings
+
+import (
+	"testing"
+
+	"github.com/knadh/koanf/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSettings_getLayeredValues(t *testing.T) {
+	s := new(Settings)
+
+	for i := range defaults {
+		defaults[i].currentValue = defaults[i].defaultValue
+		s.settings = append(s.settings, defaults[i])
+	}
+
+	global := koanf.New(".")
+	_ = global.Set("php", "8.1")
+	_ = global.Set("ssl", true)
+
+	local := koanf.New(".")
+	_ = local.Set("php", "8.3")
+	_ = local.Set("plugins", []string{"query-monitor"})
+
+	values := s.getLayeredValues(global, local, nil)
+
+	assert.Equal(t, "8.3", values["php"])
+	assert.Equal(t, true, values["ssl"])
+	assert.Equal(t, []string{"query-monitor"}, values["plugins"])
+	assert.Equal(t, []string{}, values["colorOverrides"])
+	assert.Equal(t, int64(5), values["backupRetention"])
+	assert.Equal(t, "mariadb", values["database"])
+}
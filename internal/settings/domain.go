@@ -6,12 +6,18 @@ func (s *Settings) GetURL() string {
 	return fmt.Sprintf("%s://%s", s.GetProtocol(), s.GetDomain())
 }
 
+// GetDomain returns the domain the site is served on. In CI mode the site is published directly on a local port
+// instead of through Traefik so it doesn't depend on DNS or a trusted certificate.
 func (s *Settings) GetDomain() string {
+	if s.GetBool("isCI") {
+		return fmt.Sprintf("localhost:%s", s.Get("ciPort"))
+	}
+
 	return fmt.Sprintf("%s.%s", s.Get("name"), domain)
 }
 
 func (s *Settings) GetProtocol() string {
-	if s.GetBool("ssl") {
+	if s.GetBool("ssl") && !s.GetBool("isCI") {
 		return "https"
 	}
 
@@ -36,6 +36,28 @@ func TestGetURL(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:        "CI mode uses the CI port without SSL",
+			expectedURL: "http://localhost:8080",
+			settingsArray: []Setting{
+				{
+					name:         "name",
+					currentValue: "test",
+				},
+				{
+					name:         "ssl",
+					currentValue: "true",
+				},
+				{
+					name:         "isCI",
+					currentValue: "true",
+				},
+				{
+					name:         "ciPort",
+					currentValue: "8080",
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -0,0 +1,245 @@
+package settings
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// EditConfig opens the global or local config file in the user's editor and saves it only once it validates.
+// It returns false if the file was left unchanged.
+func (s *Settings) EditConfig(settingsType string, consoleOutput *console.Console) (bool, error) {
+	configFile := getConfigFile(settingsType, s.Get("workingDirectory"), s.Get("appDirectory"))
+
+	original, err := os.ReadFile(configFile)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return false, err
+		}
+
+		original, err = json.MarshalIndent(s.GetAll(settingsType), "", "\t")
+		if err != nil {
+			return false, err
+		}
+	}
+
+	tempFile, err := os.CreateTemp("", "kana-*.json")
+	if err != nil {
+		return false, err
+	}
+
+	defer os.Remove(tempFile.Name())
+
+	_, err = tempFile.Write(original)
+	tempFile.Close()
+
+	if err != nil {
+		return false, err
+	}
+
+	for {
+		err = runEditor(tempFile.Name())
+		if err != nil {
+			return false, err
+		}
+
+		edited, err := os.ReadFile(tempFile.Name())
+		if err != nil {
+			return false, err
+		}
+
+		if bytes.Equal(bytes.TrimSpace(edited), bytes.TrimSpace(original)) {
+			return false, nil
+		}
+
+		err = s.ValidateConfig(settingsType, edited)
+		if err == nil {
+			err = os.MkdirAll(filepath.Dir(configFile), os.FileMode(defaultDirPermissions))
+			if err != nil {
+				return false, err
+			}
+
+			return true, os.WriteFile(configFile, edited, os.FileMode(defaultFilePermissions))
+		}
+
+		consoleOutput.Warn(fmt.Sprintf("The edited configuration is not valid:\n%s", err))
+
+		if consoleOutput.JSON || !consoleOutput.PromptConfirm("Would you like to reopen the editor to fix it?", false) {
+			return false, fmt.Errorf("edit canceled. Your changes to %s were not saved", configFile)
+		}
+	}
+}
+
+// ValidateConfig checks the contents of a config file against the known settings for the given scope.
+func (s *Settings) ValidateConfig(settingsType string, contents []byte) error {
+	values := map[string]interface{}{}
+
+	err := json.Unmarshal(contents, &values)
+	if err != nil {
+		var syntaxError *json.SyntaxError
+
+		if errors.As(err, &syntaxError) {
+			line := bytes.Count(contents[:syntaxError.Offset], []byte("\n")) + 1
+
+			return fmt.Errorf("invalid JSON on line %d: %s", line, syntaxError)
+		}
+
+		return fmt.Errorf("invalid JSON: %s", err)
+	}
+
+	validationErrors := []error{}
+
+	delete(values, configVersionKey)
+
+	if locked, ok := values[lockedKey]; ok && settingsType == "local" {
+		validationErrors = append(validationErrors, s.validateLocked(locked)...)
+
+		delete(values, lockedKey)
+	}
+
+	for i := range s.settings {
+		value, ok := values[s.settings[i].name]
+		if !ok {
+			continue
+		}
+
+		delete(values, s.settings[i].name)
+
+		if (settingsType == "local" && !s.settings[i].hasLocal) || (settingsType == "global" && !s.settings[i].hasGlobal) {
+			validationErrors = append(validationErrors, fmt.Errorf("%s cannot be set in the %s config", s.settings[i].name, settingsType))
+			continue
+		}
+
+		value, err = normalizeConfigValue(s.settings[i], value)
+		if err == nil {
+			err = s.validate(s.settings[i].name, value)
+
+			var fieldErrors validator.ValidationErrors
+
+			// The validator's own messages don't include the setting name so replace them with our own
+			if errors.As(err, &fieldErrors) {
+				err = fmt.Errorf("the %s value, %v, is not valid", s.settings[i].name, value)
+			}
+		}
+
+		if err != nil {
+			validationErrors = append(validationErrors, err)
+		}
+	}
+
+	for name := range values {
+		validationErrors = append(validationErrors, fmt.Errorf("%s is not a valid setting", name))
+	}
+
+	return errors.Join(validationErrors...)
+}
+
+// validateLocked checks that the locked list only contains settings a site can set.
+func (s *Settings) validateLocked(locked interface{}) []error {
+	items, ok := locked.([]interface{})
+	if !ok {
+		return []error{fmt.Errorf("the value for %s must be a list of setting names", lockedKey)}
+	}
+
+	validationErrors := []error{}
+
+	for _, item := range items {
+		name, _ := item.(string)
+		isValid := false
+
+		for i := range s.settings {
+			if strings.EqualFold(s.settings[i].name, name) && s.settings[i].hasLocal {
+				isValid = true
+			}
+		}
+
+		if !isValid {
+			validationErrors = append(validationErrors, fmt.Errorf("%v in %s is not a setting that can be set for a site", item, lockedKey))
+		}
+	}
+
+	return validationErrors
+}
+
+// normalizeConfigValue converts a decoded JSON value to the type expected by the setting.
+func normalizeConfigValue(setting Setting, value interface{}) (interface{}, error) {
+	switch setting.settingType {
+	case "bool":
+		if _, ok := value.(bool); !ok {
+			return nil, fmt.Errorf("the value for %s must be a boolean", setting.name)
+		}
+	case "int":
+		if _, ok := value.(float64); !ok {
+			return nil, fmt.Errorf("the value for %s must be an integer", setting.name)
+		}
+	case "slice":
+		items, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("the value for %s must be a list", setting.name)
+		}
+
+		sliceValue := []string{}
+
+		for _, item := range items {
+			stringItem, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("the value for %s must be a list of strings", setting.name)
+			}
+
+			sliceValue = append(sliceValue, stringItem)
+		}
+
+		return sliceValue, nil
+	default:
+		if _, ok := value.(string); !ok {
+			return nil, fmt.Errorf("the value for %s must be a string", setting.name)
+		}
+	}
+
+	return value, nil
+}
+
+// runEditor opens the given file in the user's preferred editor and waits for it to close.
+func runEditor(file string) error {
+	editor := os.Getenv("VISUAL")
+
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+
+	if editor == "" {
+		editor = "vi"
+
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+
+	editorParts := strings.Fields(editor)
+	editorParts = append(editorParts, file)
+
+	editorCommand := execCommand(editorParts[0], editorParts[1:]...) //nolint:gosec
+
+	editorCommand.Stdin = os.Stdin
+	editorCommand.Stdout = os.Stdout
+	editorCommand.Stderr = os.Stderr
+
+	err := editorCommand.Run()
+	if err != nil {
+		return fmt.Errorf(
+			"unable to run the editor %s: %s. Set the EDITOR environment variable to choose a different editor",
+			editorParts[0],
+			err)
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package settings
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSettings_ValidateConfig(t *testing.T) {
+	s := new(Settings)
+
+	for i := range defaults {
+		defaults[i].currentValue = defaults[i].defaultValue
+		s.settings = append(s.settings, defaults[i])
+	}
+
+	tests := []struct {
+		name         string
+		settingsType string
+		contents     string
+		errorMessage string
+	}{
+		{"Valid global config", "global", `{"ssl": true, "plugins": ["query-monitor"], "backupRetention": 3}`, ""},
+		{"Valid local config", "local", `{"multisite": "none", "xdebug": false}`, ""},
+		{"Invalid JSON", "global", "{\n\t\"ssl\": true,\n}", "invalid JSON on line 3"},
+		{"Unknown setting", "global", `{"notASetting": true}`, "notASetting is not a valid setting"},
+		{"Global only setting in local config", "local", `{"adminUser": "kana"}`, "adminUser cannot be set in the local config"},
+		{"Wrong bool type", "global", `{"ssl": "yes"}`, "the value for ssl must be a boolean"},
+		{"Wrong slice type", "global", `{"plugins": "query-monitor"}`, "the value for plugins must be a list"},
+		{"Invalid value", "global", `{"multisite": "everything"}`, "the multisite value, everything, is not valid"},
+		{"Locked settings in local config", "local", `{"multisite": "none", "locked": ["php", "multisite"]}`, ""},
+		{
+			"Invalid locked setting",
+			"local",
+			`{"locked": ["php", "adminUser"]}`,
+			"adminUser in locked is not a setting that can be set for a site",
+		},
+		{"Locked settings in global config", "global", `{"locked": ["php"]}`, "locked is not a valid setting"},
+		{"Invalid email", "global", `{"adminEmail": "kana"}`, "the adminEmail value, kana, is not valid"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.ValidateConfig(tt.settingsType, []byte(tt.contents))
+
+			if tt.errorMessage == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorContains(t, err, tt.errorMessage)
+		})
+	}
+}
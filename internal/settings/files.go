@@ -1,7 +1,9 @@
 package settings
 
 import (
+	"bytes"
 	_ "embed"
+	"fmt"
 	"os"
 	"path/filepath"
 	"text/template"
@@ -19,14 +21,26 @@ var DynamicToml string
 //go:embed templates/traefik.toml
 var TraefikToml string
 
+//go:embed templates/wordpress-api.conf
+var WordPressAPIConf string
+
 //go:embed templates/kana-local-development.php
 var KanaWordPressPlugin string
 
+//go:embed templates/autostart.plist
+var AutostartPlist string
+
+//go:embed templates/autostart.service
+var AutostartSystemdService string
+
+//go:embed templates/wp-tests-config.php
+var WPTestsConfigFile string
+
 var configFiles = []File{
 	{
 		Name:        "dynamic.toml",
 		Template:    DynamicToml,
-		LocalPath:   "config/traefik",
+		LocalPath:   "config/traefik/dynamic",
 		Permissions: os.FileMode(defaultFilePermissions),
 	},
 	{
@@ -35,15 +49,16 @@ var configFiles = []File{
 		LocalPath:   "config/traefik",
 		Permissions: os.FileMode(defaultFilePermissions),
 	},
+	{
+		Name:        "default.conf",
+		Template:    WordPressAPIConf,
+		LocalPath:   "config/wordpress-api",
+		Permissions: os.FileMode(defaultFilePermissions),
+	},
 }
 
 // EnsureKanaPlugin ensures the Kana plugin file is in place and ready to go.
-func EnsureKanaPlugin(siteDirectory, version, siteName string) error {
-	pluginVars := PluginVersion{
-		Version:  version,
-		SiteName: siteName,
-	}
-
+func EnsureKanaPlugin(siteDirectory string, pluginVars PluginVersion) error {
 	tmpl := template.Must(template.New("kanaPlugin").Parse(KanaWordPressPlugin))
 
 	pluginPath := filepath.Join(siteDirectory, "wp-content", "mu-plugins")
@@ -64,6 +79,39 @@ func EnsureKanaPlugin(siteDirectory, version, siteName string) error {
 	return tmpl.Execute(myFile, pluginVars)
 }
 
+// GetAutostartService returns the launchd agent, on macOS, or systemd user service, on Linux, that runs the autostart agent.
+func GetAutostartService(goos string, service AutostartService) (string, error) {
+	serviceTemplate := ""
+
+	switch goos {
+	case "darwin":
+		serviceTemplate = AutostartPlist
+	case "linux":
+		serviceTemplate = AutostartSystemdService
+	default:
+		return "", fmt.Errorf("starting and stopping sites automatically is only available on macOS and Linux")
+	}
+
+	tmpl := template.Must(template.New("autostart").Parse(serviceTemplate))
+
+	var contents bytes.Buffer
+
+	err := tmpl.Execute(&contents, service)
+
+	return contents.String(), err
+}
+
+// GetWPTestsConfig returns the wp-tests-config.php file the WordPress test suite uses to connect to the test database.
+func GetWPTestsConfig(config WPTestsConfig) (string, error) {
+	tmpl := template.Must(template.New("wpTestsConfig").Parse(WPTestsConfigFile))
+
+	var contents bytes.Buffer
+
+	err := tmpl.Execute(&contents, config)
+
+	return contents.String(), err
+}
+
 // GetDefaultFilePermissions returns the default directory permissions and the default file permissions.
 func GetDefaultFilePermissions() (dirPerms, filePerms int) {
 	return defaultDirPermissions, defaultFilePermissions
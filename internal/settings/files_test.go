@@ -3,6 +3,7 @@ package settings
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -11,22 +12,131 @@ func TestEnsureKanaPlugin(t *testing.T) {
 	version := "1.0.0"
 	siteName := "example.com"
 
-	err := EnsureKanaPlugin(siteDirectory, version, siteName)
+	err := EnsureKanaPlugin(siteDirectory, PluginVersion{
+		Version:      version,
+		SiteName:     siteName,
+		CatchMail:    true,
+		UpdateServer: "http://kana-example-updates",
+		WordPressAPI: "http://kana-wordpress-api/cache",
+	})
 	if err != nil {
 		t.Errorf("EnsureKanaPlugin returned an error: %v", err)
 	}
 
 	pluginPath := filepath.Join(siteDirectory, "wp-content", "mu-plugins", "kana-local-development.php")
-	_, err = os.Stat(pluginPath)
+	contents, err := os.ReadFile(pluginPath)
 	if err != nil {
 		t.Errorf("Failed to create Kana plugin file: %v", err)
 	}
 
+	if !strings.Contains(string(contents), "pre_wp_mail") {
+		t.Errorf("Expected the Kana plugin to catch all email")
+	}
+
+	if !strings.Contains(string(contents), "define( 'KANA_UPDATE_SERVER', 'http://kana-example-updates' );") {
+		t.Errorf("Expected the Kana plugin to offer updates from the mock update server")
+	}
+
+	if !strings.Contains(string(contents), "define( 'KANA_WORDPRESS_API', 'http://kana-wordpress-api/cache' );") {
+		t.Errorf("Expected the Kana plugin to send WordPress.org API requests to the cache")
+	}
+
+	err = EnsureKanaPlugin(siteDirectory, PluginVersion{Version: version, SiteName: siteName})
+	if err != nil {
+		t.Errorf("EnsureKanaPlugin returned an error: %v", err)
+	}
+
+	contents, err = os.ReadFile(pluginPath)
+	if err != nil {
+		t.Errorf("Failed to create Kana plugin file: %v", err)
+	}
+
+	if strings.Contains(string(contents), "pre_wp_mail") {
+		t.Errorf("Expected the Kana plugin not to catch all email when catchMail is off")
+	}
+
+	if strings.Contains(string(contents), "KANA_UPDATE_SERVER") {
+		t.Errorf("Expected the Kana plugin not to use a mock update server when updateServer is off")
+	}
+
+	if strings.Contains(string(contents), "KANA_WORDPRESS_API") {
+		t.Errorf("Expected the Kana plugin to send WordPress.org API requests to WordPress.org when wordpressAPI is live")
+	}
+
 	err = os.RemoveAll("./wp-content")
 	if err != nil {
 		t.Errorf("EnsureKanaPlugin returned an error: %v", err)
 	}
 }
+func TestGetAutostartService(t *testing.T) {
+	service := AutostartService{
+		Label:        "com.chriswiegman.kana.autostart",
+		Executable:   "/usr/local/bin/kana",
+		AppDirectory: "/home/kana/.config/kana",
+		LogFile:      "/home/kana/.config/kana/autostart.log",
+		Path:         "/usr/local/bin:/usr/bin",
+		Restart:      true,
+	}
+
+	tests := []struct {
+		goos     string
+		expected string
+	}{
+		{"darwin", "<string>--restart</string>"},
+		{"linux", `ExecStart="/usr/local/bin/kana" autostart agent --restart`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goos, func(t *testing.T) {
+			contents, err := GetAutostartService(tt.goos, service)
+			if err != nil {
+				t.Fatalf("GetAutostartService returned an error: %v", err)
+			}
+
+			if !strings.Contains(contents, tt.expected) {
+				t.Errorf("Expected the service to contain %q, got %s", tt.expected, contents)
+			}
+		})
+	}
+
+	service.Restart = false
+
+	contents, err := GetAutostartService("linux", service)
+	if err != nil {
+		t.Fatalf("GetAutostartService returned an error: %v", err)
+	}
+
+	if strings.Contains(contents, "--restart") {
+		t.Errorf("Expected the service not to restart sites, got %s", contents)
+	}
+
+	_, err = GetAutostartService("windows", service)
+	if err == nil {
+		t.Errorf("Expected an error for an unsupported operating system")
+	}
+}
+
+func TestGetWPTestsConfig(t *testing.T) {
+	contents, err := GetWPTestsConfig(WPTestsConfig{
+		WordPressPath: "/var/www/html",
+		DatabaseHost:  "kana-my-plugin-database",
+		DatabaseName:  "wordpress_test",
+	})
+	if err != nil {
+		t.Fatalf("GetWPTestsConfig returned an error: %v", err)
+	}
+
+	for _, expected := range []string{
+		"define( 'ABSPATH', '/var/www/html/' );",
+		"define( 'DB_NAME', 'wordpress_test' );",
+		"define( 'DB_HOST', 'kana-my-plugin-database' );",
+	} {
+		if !strings.Contains(contents, expected) {
+			t.Errorf("Expected the config to contain %q, got %s", expected, contents)
+		}
+	}
+}
+
 func TestGetDefaultFilePermissions(t *testing.T) {
 	dirPerms, filePerms := GetDefaultFilePermissions()
 
@@ -47,6 +47,10 @@ func processStartFlags(cmd *cobra.Command, settings *Settings) error {
 					return err
 				}
 
+				if settings.isLocked(settings.settings[i].name) {
+					return fmt.Errorf("the %s setting is locked by the site's .kana.json file and can't be changed with a flag", settings.settings[i].name)
+				}
+
 				if settings.settings[i].settingType == "slice" {
 					strings.Split(cmd.Flags().Lookup("plugins").Value.String(), ",")
 				} else {
@@ -54,6 +58,8 @@ func processStartFlags(cmd *cobra.Command, settings *Settings) error {
 					if err != nil {
 						return err
 					}
+
+					settings.settings[i].source = sourceFlag
 				}
 			}
 		}
@@ -5,16 +5,17 @@ import (
 	"os"
 	"path/filepath"
 
-	kjson "github.com/knadh/koanf/parsers/json"
-	"github.com/knadh/koanf/providers/file"
 	"github.com/knadh/koanf/v2"
 )
 
 func getConfigFile(settingsType, workingDirectory, appDirectory string) string {
 	configFile := filepath.Join(appDirectory, "config", "kana.json")
 
-	if settingsType == "local" { //nolint:goconst
+	switch settingsType {
+	case "local": //nolint:goconst
 		configFile = filepath.Join(workingDirectory, ".kana.json")
+	case "personal":
+		configFile = filepath.Join(workingDirectory, ".kana.local.json")
 	}
 
 	return configFile
@@ -26,6 +27,13 @@ func loadKoanfOptions(settingsType string, settings *Settings) error {
 	configFile := getConfigFile(settingsType, settings.Get("workingDirectory"), settings.Get("appDirectory"))
 	configFileExists := true
 
+	// Config files older versions of Kana saved somewhere else are read from there until `kana migrate-config` moves them
+	oldFile, _ := getMovedConfigFile(settingsType, settings.Get("workingDirectory"), settings.Get("appDirectory"))
+	if oldFile != "" {
+		configFile = oldFile
+		settings.outdatedConfigFiles = append(settings.outdatedConfigFiles, oldFile)
+	}
+
 	_, err := os.Stat(configFile)
 	if err != nil && os.IsNotExist(err) {
 		configFileExists = false
@@ -37,15 +45,40 @@ func loadKoanfOptions(settingsType string, settings *Settings) error {
 		}
 	}
 
-	if settingsType != "local" || configFileExists {
-		err = ko.Load(file.Provider(configFile), kjson.Parser())
+	if settingsType == "global" || configFileExists {
+		config, result, err := readConfigFile(configFile)
 		if err != nil {
 			return err
 		}
+
+		if len(result.Changes) > 0 && oldFile == "" {
+			settings.outdatedConfigFiles = append(settings.outdatedConfigFiles, configFile)
+		}
+
+		// Personal settings can't override anything the team has locked in the site's config
+		if settingsType == "personal" {
+			settings.ignoredOverrides = removeLockedSettings(config, getLockedSettings(settings.local))
+		}
+
+		err = ko.Load(configProvider{config: config}, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	source := sourceGlobal
+
+	switch settingsType {
+	case "local":
+		source = sourceSite
+	case "personal":
+		source = sourcePersonal
 	}
 
 	for i := range settings.settings {
 		if ko.Exists(settings.settings[i].name) {
+			previousValue := settings.settings[i].currentValue
+
 			switch settings.settings[i].settingType {
 			case "bool":
 				err = settings.Set(settings.settings[i].name, ko.Bool(settings.settings[i].name))
@@ -68,18 +101,42 @@ func loadKoanfOptions(settingsType string, settings *Settings) error {
 					return err
 				}
 			}
+
+			// Kana writes every setting to the config files it saves, so a default value is only credited to the file if it
+			// changed the value, such as a site setting back to the default a global setting changed. Any other value in the
+			// file was set there on purpose.
+			if settings.settings[i].currentValue != previousValue ||
+				settings.settings[i].currentValue != settings.settings[i].defaultValue {
+				settings.settings[i].source = source
+			}
 		}
 	}
 
-	if settingsType == "local" {
+	switch settingsType {
+	case "local":
 		settings.local = ko
-	} else {
+	case "personal":
+		settings.personal = ko
+	default:
 		settings.global = ko
 	}
 
 	return nil
 }
 
+// configProvider provides the already migrated contents of a config file to koanf.
+type configProvider struct {
+	config map[string]interface{}
+}
+
+func (p configProvider) ReadBytes() ([]byte, error) {
+	return json.Marshal(p.config)
+}
+
+func (p configProvider) Read() (map[string]interface{}, error) {
+	return p.config, nil
+}
+
 func writeKoanfSettings(settingsType string, settings *Settings) error {
 	configFile := getConfigFile(settingsType, settings.Get("workingDirectory"), settings.Get("appDirectory"))
 	if settingsType == "global" {
@@ -90,6 +147,7 @@ func writeKoanfSettings(settingsType string, settings *Settings) error {
 	}
 
 	allSettings := settings.GetAll(settingsType)
+	allSettings[configVersionKey] = currentConfigVersion
 
 	f, _ := os.Create(configFile)
 	defer f.Close()
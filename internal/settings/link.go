@@ -0,0 +1,264 @@
+package settings
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ChrisWiegman/kana/internal/helpers"
+	"github.com/ChrisWiegman/kana/internal/registry"
+)
+
+// GetSiteDirectoryLink returns the directory the named site is linked to. Sites started with the name flag are
+// linked to their own folder in the app directory.
+func (s *Settings) GetSiteDirectoryLink(name string) (string, error) {
+	link, err := readSiteLink(filepath.Join(s.Get("sitesDirectory"), helpers.SanitizeSiteName(name)))
+	if err != nil && os.IsNotExist(err) {
+		return "", fmt.Errorf("the site %s could not be found. Use `kana list` to see all sites", name)
+	}
+
+	return link, err
+}
+
+// LinkSite links the named site to the given directory so that running Kana in the directory uses the site.
+func (s *Settings) LinkSite(name, directory string) error {
+	return writeSiteLink(s.Get("appDirectory"), filepath.Join(s.Get("sitesDirectory"), helpers.SanitizeSiteName(name)), directory)
+}
+
+// UnlinkSite links the named site back to its own folder in the app directory so it no longer uses any project directory.
+func (s *Settings) UnlinkSite(name string) error {
+	siteDirectory := filepath.Join(s.Get("sitesDirectory"), helpers.SanitizeSiteName(name))
+
+	return writeSiteLink(s.Get("appDirectory"), siteDirectory, siteDirectory)
+}
+
+// UnregisterSite removes the named site from the sites Kana knows about, such as once it has been destroyed.
+func (s *Settings) UnregisterSite(name string) error {
+	return registry.Unregister(s.Get("appDirectory"), helpers.SanitizeSiteName(name))
+}
+
+// GetRegisteredSites returns every site Kana knows about, sorted by name. Sites in the app directory that haven't been
+// registered, such as those created by versions of Kana from before the registry, are added to it and sites whose folders
+// have since been removed are dropped. Sites whose files were moved to a folder that can't currently be found are kept and
+// marked as unavailable.
+func (s *Settings) GetRegisteredSites() ([]registry.Site, error) {
+	appDirectory := s.Get("appDirectory")
+	sitesDirectory := s.Get("sitesDirectory")
+
+	registeredSites, err := registry.Load(appDirectory)
+	if err != nil {
+		return registeredSites, err
+	}
+
+	sites := []registry.Site{}
+	knownSites := map[string]bool{}
+	removedSites := []string{}
+
+	for _, site := range registeredSites {
+		exists, available := getSiteDirectoryStatus(site.Directory)
+		if !exists {
+			removedSites = append(removedSites, site.Name)
+
+			continue
+		}
+
+		site.Unavailable = !available
+
+		sites = append(sites, site)
+		knownSites[site.Name] = true
+	}
+
+	siteDirectories, err := os.ReadDir(sitesDirectory)
+	if err != nil && !os.IsNotExist(err) {
+		return sites, err
+	}
+
+	addedSites := []registry.Site{}
+
+	for _, siteDirectory := range siteDirectories {
+		// Sites moved with `kana relocate` leave a link to their files in place of their folder
+		isSiteDirectory := siteDirectory.IsDir() || siteDirectory.Type()&os.ModeSymlink != 0
+		if !isSiteDirectory || knownSites[siteDirectory.Name()] {
+			continue
+		}
+
+		directory := filepath.Join(sitesDirectory, siteDirectory.Name())
+
+		exists, available := getSiteDirectoryStatus(directory)
+		if !exists {
+			continue
+		}
+
+		// The project the site is linked to can't be read until its files can be found again so it is listed but only
+		// registered once they can
+		if !available {
+			sites = append(sites, registry.Site{
+				Name:        siteDirectory.Name(),
+				Directory:   directory,
+				Link:        directory,
+				Unavailable: true,
+			})
+
+			continue
+		}
+
+		link, err := readSiteLink(directory)
+		if err != nil {
+			continue
+		}
+
+		addedSites = append(addedSites, registry.Site{
+			Name:      siteDirectory.Name(),
+			Directory: directory,
+			Link:      link,
+			Created:   time.Now().UTC(),
+			Updated:   time.Now().UTC(),
+		})
+	}
+
+	sites = append(sites, addedSites...)
+
+	slices.SortFunc(sites, func(a, b registry.Site) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	if len(addedSites) == 0 && len(removedSites) == 0 {
+		return sites, nil
+	}
+
+	return sites, registry.Update(appDirectory, func(registrySites map[string]registry.Site) {
+		for _, name := range removedSites {
+			delete(registrySites, name)
+		}
+
+		for _, site := range addedSites {
+			registrySites[site.Name] = site
+		}
+	})
+}
+
+// GetSiteProjectInfo returns the type of the named site's linked directory and whether the plugins or themes
+// being developed in it are activated when the site starts.
+func (s *Settings) GetSiteProjectInfo(name string) (siteType string, activate bool, err error) {
+	link, err := s.GetSiteDirectoryLink(name)
+	if err != nil {
+		return "", false, err
+	}
+
+	// Named sites aren't linked to a project
+	if strings.HasPrefix(link, s.Get("sitesDirectory")) {
+		return "site", false, nil
+	}
+
+	siteConfig, personalConfig, err := s.loadSiteConfig(name)
+	if err != nil {
+		return "", false, err
+	}
+
+	values := s.getLayeredValues(s.global, siteConfig, personalConfig)
+
+	projectPatterns, _ := values["projects"].([]string)
+
+	siteType, err = detectSiteType(link, projectPatterns)
+	if err != nil {
+		return "", false, err
+	}
+
+	activate, _ = values["activate"].(bool)
+
+	return siteType, activate && siteType != "site", nil
+}
+
+// getLinkedSiteName returns the name of the site linked to the directory or an empty string if there isn't one.
+// The site named after the directory is checked first as that is the site Kana creates by default.
+func getLinkedSiteName(directory, sitesDirectory string) string {
+	defaultName := helpers.SanitizeSiteName(filepath.Base(directory))
+
+	link, err := readSiteLink(filepath.Join(sitesDirectory, defaultName))
+	if err == nil && link == directory {
+		return defaultName
+	}
+
+	sites, err := os.ReadDir(sitesDirectory)
+	if err != nil {
+		return ""
+	}
+
+	for _, site := range sites {
+		link, err := readSiteLink(filepath.Join(sitesDirectory, site.Name()))
+		if err == nil && link == directory {
+			return site.Name()
+		}
+	}
+
+	return ""
+}
+
+// getSiteDirectoryStatus reports whether the site's folder in the app directory still exists and whether its files can be
+// read. The files of a site moved with `kana relocate` can't be read while the folder they were moved to can't be found,
+// such as when it is on an external disk that isn't connected, but the site still exists.
+func getSiteDirectoryStatus(siteDirectory string) (exists, available bool) {
+	info, err := os.Lstat(siteDirectory)
+	if err != nil {
+		return !os.IsNotExist(err), false
+	}
+
+	if info.Mode()&os.ModeSymlink != 0 {
+		_, err = os.Stat(siteDirectory)
+		if err != nil {
+			return true, false
+		}
+	}
+
+	_, err = os.Stat(filepath.Join(siteDirectory, "link.json"))
+	if err != nil {
+		return !os.IsNotExist(err), false
+	}
+
+	return true, true
+}
+
+func readSiteLink(siteDirectory string) (string, error) {
+	content, err := os.ReadFile(filepath.Join(siteDirectory, "link.json"))
+	if err != nil {
+		return "", err
+	}
+
+	var siteLink map[string]string
+
+	err = json.Unmarshal(content, &siteLink)
+	if err != nil {
+		return "", err
+	}
+
+	return siteLink["link"], nil
+}
+
+// writeSiteLink links the site to the given directory and registers it so it is listed even if its folder is moved out of
+// the app directory.
+func writeSiteLink(appDirectory, siteDirectory, link string) error {
+	err := os.MkdirAll(siteDirectory, os.FileMode(defaultDirPermissions))
+	if err != nil {
+		return err
+	}
+
+	jsonBytes, err := json.MarshalIndent(map[string]string{"link": link}, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(filepath.Join(siteDirectory, "link.json"), jsonBytes, os.FileMode(defaultFilePermissions))
+	if err != nil {
+		return err
+	}
+
+	return registry.Register(appDirectory, registry.Site{
+		Name:      filepath.Base(siteDirectory),
+		Directory: siteDirectory,
+		Link:      link,
+	})
+}
@@ -0,0 +1,129 @@
+package settings
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ChrisWiegman/kana/internal/registry"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetLinkedSiteName(t *testing.T) {
+	appDirectory := t.TempDir()
+	projectDirectory := filepath.Join(t.TempDir(), "my-plugin")
+
+	assert.Equal(t, "", getLinkedSiteName(projectDirectory, filepath.Join(appDirectory, "sites")))
+
+	s := &Settings{
+		settings: []Setting{
+			{name: "appDirectory", currentValue: appDirectory},
+			{name: "sitesDirectory", currentValue: filepath.Join(appDirectory, "sites")},
+		},
+	}
+
+	err := writeSiteLink(appDirectory, filepath.Join(appDirectory, "sites", "named-site"), filepath.Join(appDirectory, "sites", "named-site"))
+	assert.NoError(t, err)
+
+	err = s.LinkSite("named-site", projectDirectory)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "named-site", getLinkedSiteName(projectDirectory, filepath.Join(appDirectory, "sites")))
+
+	link, err := s.GetSiteDirectoryLink("named-site")
+	assert.NoError(t, err)
+	assert.Equal(t, projectDirectory, link)
+
+	err = s.UnlinkSite("named-site")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "", getLinkedSiteName(projectDirectory, filepath.Join(appDirectory, "sites")))
+
+	_, err = s.GetSiteDirectoryLink("missing-site")
+	assert.Error(t, err)
+}
+
+func TestGetRegisteredSites(t *testing.T) {
+	appDirectory := t.TempDir()
+	projectDirectory := filepath.Join(t.TempDir(), "my-plugin")
+
+	s := &Settings{
+		settings: []Setting{
+			{name: "appDirectory", currentValue: appDirectory},
+			{name: "sitesDirectory", currentValue: filepath.Join(appDirectory, "sites")},
+		},
+	}
+
+	err := s.LinkSite("linked-site", projectDirectory)
+	assert.NoError(t, err)
+
+	// Sites created before the registry only have a link file
+	olderSiteDirectory := filepath.Join(appDirectory, "sites", "older-site")
+
+	assert.NoError(t, os.MkdirAll(olderSiteDirectory, 0750))
+	assert.NoError(t, os.WriteFile(filepath.Join(olderSiteDirectory, "link.json"), []byte(`{"link": "/projects/older-site"}`), 0600))
+
+	sites, err := s.GetRegisteredSites()
+	assert.NoError(t, err)
+	assert.Len(t, sites, 2)
+	assert.Equal(t, "linked-site", sites[0].Name)
+	assert.Equal(t, projectDirectory, sites[0].Link)
+	assert.Equal(t, "older-site", sites[1].Name)
+	assert.Equal(t, "/projects/older-site", sites[1].Link)
+
+	registeredSites, err := registry.Load(appDirectory)
+	assert.NoError(t, err)
+	assert.Len(t, registeredSites, 2)
+
+	// Sites whose folders have been removed are dropped
+	assert.NoError(t, os.RemoveAll(filepath.Join(appDirectory, "sites", "linked-site")))
+
+	sites, err = s.GetRegisteredSites()
+	assert.NoError(t, err)
+	assert.Len(t, sites, 1)
+	assert.Equal(t, "older-site", sites[0].Name)
+
+	registeredSites, err = registry.Load(appDirectory)
+	assert.NoError(t, err)
+	assert.Len(t, registeredSites, 1)
+
+	// Sites moved to a disk that isn't connected are kept but can't be used
+	externalDisk := t.TempDir()
+	relocatedSite := filepath.Join(externalDisk, "older-site")
+	olderSiteLink := filepath.Join(appDirectory, "sites", "older-site")
+
+	assert.NoError(t, os.Rename(olderSiteDirectory, relocatedSite))
+	assert.NoError(t, os.Symlink(relocatedSite, olderSiteLink))
+
+	// Sites moved before the registry are found through the link left in the app directory
+	movedSite := filepath.Join(externalDisk, "moved-site")
+
+	assert.NoError(t, os.MkdirAll(movedSite, 0750))
+	assert.NoError(t, os.WriteFile(filepath.Join(movedSite, "link.json"), []byte(`{"link": "/projects/moved-site"}`), 0600))
+	assert.NoError(t, os.Symlink(movedSite, filepath.Join(appDirectory, "sites", "moved-site")))
+
+	sites, err = s.GetRegisteredSites()
+	assert.NoError(t, err)
+	assert.Len(t, sites, 2)
+	assert.Equal(t, "moved-site", sites[0].Name)
+	assert.Equal(t, "/projects/moved-site", sites[0].Link)
+	assert.False(t, sites[0].Unavailable)
+	assert.Equal(t, "older-site", sites[1].Name)
+	assert.False(t, sites[1].Unavailable)
+
+	assert.NoError(t, os.RemoveAll(externalDisk))
+
+	sites, err = s.GetRegisteredSites()
+	assert.NoError(t, err)
+	assert.Len(t, sites, 2)
+	assert.Equal(t, "moved-site", sites[0].Name)
+	assert.True(t, sites[0].Unavailable)
+	assert.Equal(t, "older-site", sites[1].Name)
+	assert.Equal(t, "/projects/older-site", sites[1].Link)
+	assert.True(t, sites[1].Unavailable)
+
+	registeredSites, err = registry.Load(appDirectory)
+	assert.NoError(t, err)
+	assert.Len(t, registeredSites, 2)
+}
@@ -0,0 +1,105 @@
+package settings
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Header is a response header Traefik adds to every response from the site.
+type Header struct {
+	Name  string
+	Value string
+}
+
+// Middleware is a Traefik middleware applied to the site, such as a redirect, and its options.
+type Middleware struct {
+	Name    string
+	Options map[string]string // Traefik's label options for the middleware, such as redirectregex.regex, without the prefix
+}
+
+var (
+	headerNamePattern       = regexp.MustCompile(`^[A-Za-z0-9-]+$`)
+	middlewareNamePattern   = regexp.MustCompile(`^[A-Za-z0-9-]+$`)
+	middlewareOptionPattern = regexp.MustCompile(`^[A-Za-z]+(\.[A-Za-z0-9]+)*$`)
+)
+
+// ParseHeaders parses the headers setting, where each header is in the form Name=value.
+func ParseHeaders(entries []string) ([]Header, error) {
+	headers := []Header{}
+
+	for _, entry := range entries {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+
+		name, value, found := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		value = strings.TrimSpace(value)
+
+		if !found || !headerNamePattern.MatchString(name) {
+			return headers, fmt.Errorf("the header, %s, is not valid. Headers must be in the form Name=value", entry)
+		}
+
+		headers = append(headers, Header{Name: name, Value: value})
+	}
+
+	return headers, nil
+}
+
+// ValidateCORSOrigins checks that each origin in the corsOrigins setting is * or a scheme and host, such as http://localhost:3000.
+func ValidateCORSOrigins(origins []string) error {
+	for _, origin := range origins {
+		origin = strings.TrimSpace(origin)
+
+		if origin == "" || origin == "*" {
+			continue
+		}
+
+		originURL, err := url.Parse(origin)
+		if err != nil ||
+			(originURL.Scheme != "http" && originURL.Scheme != "https") ||
+			originURL.Host == "" ||
+			strings.TrimSuffix(originURL.Path, "/") != "" ||
+			originURL.RawQuery != "" {
+			return fmt.Errorf("the CORS origin, %s, is not valid. Origins must be a scheme and host, such as http://localhost:3000, or *", origin)
+		}
+	}
+
+	return nil
+}
+
+// ParseMiddlewares parses the middlewares setting, where each entry sets one option of a middleware in the form
+// name.type.option=value, such as old-blog.redirectregex.regex=^https://example.com/blog/(.*). Middlewares are
+// returned in the order they are first used.
+func ParseMiddlewares(entries []string) ([]Middleware, error) {
+	middlewares := []Middleware{}
+	found := map[string]int{}
+
+	for _, entry := range entries {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+
+		key, value, isValid := strings.Cut(entry, "=")
+		name, option, _ := strings.Cut(strings.TrimSpace(key), ".")
+
+		if !isValid || !middlewareNamePattern.MatchString(name) || !middlewareOptionPattern.MatchString(option) {
+			return middlewares, fmt.Errorf(
+				"the middleware, %s, is not valid. Middlewares must be in the form name.type.option=value", entry)
+		}
+
+		index, ok := found[name]
+		if !ok {
+			index = len(middlewares)
+			found[name] = index
+
+			middlewares = append(middlewares, Middleware{Name: name, Options: map[string]string{}})
+		}
+
+		middlewares[index].Options[option] = strings.TrimSpace(value)
+	}
+
+	return middlewares, nil
+}
@@ -0,0 +1,55 @@
+package settings
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseHeaders(t *testing.T) {
+	headers, err := ParseHeaders([]string{
+		"Strict-Transport-Security=max-age=31536000; includeSubDomains",
+		" X-Frame-Options = DENY ",
+		"X-Empty=",
+		""})
+	assert.NoError(t, err)
+	assert.Equal(t, []Header{
+		{Name: "Strict-Transport-Security", Value: "max-age=31536000; includeSubDomains"},
+		{Name: "X-Frame-Options", Value: "DENY"},
+		{Name: "X-Empty", Value: ""},
+	}, headers)
+
+	for _, invalid := range []string{"X-Frame-Options", "=DENY", "X Frame Options=DENY"} {
+		_, err = ParseHeaders([]string{invalid})
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestParseMiddlewares(t *testing.T) {
+	middlewares, err := ParseMiddlewares([]string{
+		"old-blog.redirectregex.regex=^https://example.com/blog/(.*)",
+		"compress.compress=true",
+		"old-blog.redirectregex.replacement=https://example.com/news/${1}",
+		""})
+	assert.NoError(t, err)
+	assert.Equal(t, []Middleware{
+		{Name: "old-blog", Options: map[string]string{
+			"redirectregex.regex":       "^https://example.com/blog/(.*)",
+			"redirectregex.replacement": "https://example.com/news/${1}",
+		}},
+		{Name: "compress", Options: map[string]string{"compress": "true"}},
+	}, middlewares)
+
+	for _, invalid := range []string{"old-blog.redirectregex.regex", "old-blog=true", ".compress=true", "old blog.compress=true"} {
+		_, err = ParseMiddlewares([]string{invalid})
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestValidateCORSOrigins(t *testing.T) {
+	assert.NoError(t, ValidateCORSOrigins([]string{"http://localhost:3000", "https://app.example.com/", "*", ""}))
+
+	for _, invalid := range []string{"localhost:3000", "ftp://localhost", "http://localhost:3000/app", "http://localhost?debug=1"} {
+		assert.Error(t, ValidateCORSOrigins([]string{invalid}), invalid)
+	}
+}
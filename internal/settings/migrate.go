@@ -0,0 +1,358 @@
+package settings
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// A migration upgrades the contents of a config file written by an older version of Kana.
+// It returns a description of each change made so nothing is changed silently.
+type migration struct {
+	version     int
+	description string
+	migrate     func(config map[string]interface{}) []string
+}
+
+// MigrationResult describes the changes needed to bring a single config file up to date.
+type MigrationResult struct {
+	File        string
+	FromVersion int
+	Changes     []string
+}
+
+const (
+	configVersionKey     = "configVersion"
+	currentConfigVersion = 2
+	lockedKey            = "locked"
+)
+
+// Setting names used by older versions of Kana and the settings that replaced them.
+var renamedSettings = map[string]string{
+	"adminLogin":      "automaticLogin",
+	"imageUpdateDays": "updateInterval",
+	"mariadb":         "databaseVersion",
+}
+
+// The names used to describe each setting type to users.
+var settingTypeNames = map[string]string{
+	"bool":  "boolean",
+	"int":   "integer",
+	"slice": "list",
+}
+
+// A configFileMove moves a config file from where an older version of Kana saved it to where it is read from now. Files
+// are only moved if there isn't already a file where Kana reads it from.
+type configFileMove struct {
+	settingsType string // The config file being moved: global, local or personal
+	description  string
+	oldFile      func(workingDirectory, appDirectory string) string
+}
+
+// configFileMoves are the old locations and names of config files, checked in order.
+var configFileMoves = []configFileMove{
+	{
+		settingsType: "global",
+		description:  "Move the global config into the config folder",
+		oldFile: func(_, appDirectory string) string {
+			return filepath.Join(appDirectory, "kana.json")
+		},
+	},
+}
+
+// Settings that have been removed entirely and are safe to drop.
+var removedSettings = []string{
+	"local",
+}
+
+// migrations must stay in version order. Never change a released migration, add a new one instead.
+var migrations = []migration{
+	{
+		version:     1,
+		description: "Rename settings from older versions of Kana",
+		migrate:     migrateSettingNames,
+	},
+	{
+		version:     2,
+		description: "Store settings as their correct types",
+		migrate:     migrateSettingTypes,
+	},
+}
+
+// migrateConfig applies every migration newer than the config's version and stamps it with the current version.
+func migrateConfig(config map[string]interface{}) (fromVersion int, changes []string) {
+	fromVersion = getConfigVersion(config)
+	changes = []string{}
+
+	for _, m := range migrations {
+		if m.version <= fromVersion {
+			continue
+		}
+
+		for _, change := range m.migrate(config) {
+			changes = append(changes, fmt.Sprintf("%s: %s", m.description, change))
+		}
+	}
+
+	config[configVersionKey] = currentConfigVersion
+
+	return fromVersion, changes
+}
+
+func getConfigVersion(config map[string]interface{}) int {
+	switch version := config[configVersionKey].(type) {
+	case float64:
+		return int(version)
+	case int:
+		return version
+	}
+
+	return 0
+}
+
+// migrateSettingNames renames legacy and incorrectly cased settings and removes settings that no longer exist.
+func migrateSettingNames(config map[string]interface{}) []string {
+	changes := []string{}
+
+	// The admin settings used to be nested as admin.email, admin.username and admin.password
+	if admin, ok := config["admin"].(map[string]interface{}); ok {
+		adminSettings := map[string]string{
+			"email":    "adminEmail",
+			"password": "adminPassword",
+			"username": "adminUser",
+		}
+
+		for _, key := range sortedKeys(admin) {
+			if newName, ok := adminSettings[key]; ok {
+				config[newName] = admin[key]
+				changes = append(changes, fmt.Sprintf("renamed admin.%s to %s", key, newName))
+			}
+		}
+
+		delete(config, "admin")
+	}
+
+	for _, key := range sortedKeys(config) {
+		newName := ""
+
+		for oldName, replacement := range renamedSettings {
+			if strings.EqualFold(key, oldName) {
+				newName = replacement
+			}
+		}
+
+		for i := range defaults {
+			if strings.EqualFold(key, defaults[i].name) && (defaults[i].hasGlobal || defaults[i].hasLocal) {
+				newName = defaults[i].name
+			}
+		}
+
+		if slices.ContainsFunc(removedSettings, func(removed string) bool { return strings.EqualFold(key, removed) }) {
+			delete(config, key)
+			changes = append(changes, fmt.Sprintf("removed %s as it is no longer used", key))
+
+			continue
+		}
+
+		if newName == "" || newName == key {
+			continue
+		}
+
+		// Don't overwrite a setting that already uses the new name
+		if _, exists := config[newName]; !exists {
+			config[newName] = config[key]
+			changes = append(changes, fmt.Sprintf("renamed %s to %s", key, newName))
+		} else {
+			changes = append(changes, fmt.Sprintf("removed %s as it has been replaced by %s", key, newName))
+		}
+
+		delete(config, key)
+	}
+
+	return changes
+}
+
+// migrateSettingTypes converts values that older versions of Kana saved as strings to their setting's type.
+func migrateSettingTypes(config map[string]interface{}) []string {
+	changes := []string{}
+
+	for i := range defaults {
+		value, ok := config[defaults[i].name]
+		if !ok {
+			continue
+		}
+
+		stringValue, isString := value.(string)
+		if !isString {
+			continue
+		}
+
+		var newValue interface{}
+
+		switch defaults[i].settingType {
+		case "bool":
+			boolValue, err := strconv.ParseBool(stringValue)
+			if err != nil {
+				continue
+			}
+
+			newValue = boolValue
+		case "int":
+			intValue, err := strconv.ParseInt(stringValue, 10, 64)
+			if err != nil {
+				continue
+			}
+
+			newValue = intValue
+		case "slice":
+			sliceValue := []string{}
+
+			for _, item := range strings.Split(stringValue, ",") {
+				if strings.TrimSpace(item) != "" {
+					sliceValue = append(sliceValue, strings.TrimSpace(item))
+				}
+			}
+
+			newValue = sliceValue
+		default:
+			continue
+		}
+
+		config[defaults[i].name] = newValue
+		changes = append(changes,
+			fmt.Sprintf("changed %s from the string %q to a %s", defaults[i].name, stringValue, settingTypeNames[defaults[i].settingType]))
+	}
+
+	return changes
+}
+
+// getMovedConfigFile returns the old location of a config file, and a description of the move, if it hasn't been moved
+// to where Kana reads it from now. An empty string is returned if there is nothing to move.
+func getMovedConfigFile(settingsType, workingDirectory, appDirectory string) (oldFile, description string) {
+	_, err := os.Stat(getConfigFile(settingsType, workingDirectory, appDirectory))
+	if !os.IsNotExist(err) {
+		return "", ""
+	}
+
+	for _, move := range configFileMoves {
+		if move.settingsType != settingsType {
+			continue
+		}
+
+		oldFile = move.oldFile(workingDirectory, appDirectory)
+
+		_, err = os.Stat(oldFile)
+		if err == nil {
+			return oldFile, move.description
+		}
+	}
+
+	return "", ""
+}
+
+// readConfigFile reads a config file and returns its migrated contents along with the changes made to them.
+func readConfigFile(configFile string) (config map[string]interface{}, result MigrationResult, err error) {
+	result = MigrationResult{File: configFile}
+
+	contents, err := os.ReadFile(configFile)
+	if err != nil {
+		return config, result, err
+	}
+
+	err = json.Unmarshal(contents, &config)
+	if err != nil {
+		return config, result, fmt.Errorf("unable to read %s: %s", configFile, err)
+	}
+
+	result.FromVersion, result.Changes = migrateConfig(config)
+
+	return config, result, nil
+}
+
+// MigrateConfigFiles updates the global, site and personal config files to the current format.
+// When dryRun is true the changes are returned without being saved.
+func (s *Settings) MigrateConfigFiles(dryRun bool) ([]MigrationResult, error) {
+	results := []MigrationResult{}
+
+	for _, settingsType := range []string{"global", "local", "personal"} {
+		configFile := getConfigFile(settingsType, s.Get("workingDirectory"), s.Get("appDirectory"))
+		oldFile, moveDescription := getMovedConfigFile(settingsType, s.Get("workingDirectory"), s.Get("appDirectory"))
+
+		readFile := configFile
+
+		if oldFile != "" {
+			readFile = oldFile
+		}
+
+		_, err := os.Stat(readFile)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+
+			return results, err
+		}
+
+		config, result, err := readConfigFile(readFile)
+		if err != nil {
+			return results, err
+		}
+
+		if oldFile != "" {
+			result.File = configFile
+			result.Changes = append([]string{fmt.Sprintf("%s: moved from %s", moveDescription, oldFile)}, result.Changes...)
+		}
+
+		results = append(results, result)
+
+		if dryRun || (result.FromVersion == currentConfigVersion && oldFile == "") {
+			continue
+		}
+
+		jsonBytes, err := json.MarshalIndent(config, "", "\t")
+		if err != nil {
+			return results, err
+		}
+
+		err = os.MkdirAll(filepath.Dir(configFile), os.FileMode(defaultDirPermissions))
+		if err != nil {
+			return results, err
+		}
+
+		err = os.WriteFile(configFile, jsonBytes, os.FileMode(defaultFilePermissions))
+		if err != nil {
+			return results, err
+		}
+
+		if oldFile != "" {
+			err = os.Remove(oldFile)
+			if err != nil {
+				return results, err
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// GetOutdatedConfigFiles returns the config files that had to be migrated when they were loaded.
+func (s *Settings) GetOutdatedConfigFiles() []string {
+	return s.outdatedConfigFiles
+}
+
+func sortedKeys(values map[string]interface{}) []string {
+	keys := make([]string, 0, len(values))
+
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
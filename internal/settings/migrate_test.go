@@ -0,0 +1,137 @@
+package settings
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrateConfig(t *testing.T) {
+	tests := []struct {
+		name            string
+		config          map[string]interface{}
+		expected        map[string]interface{}
+		expectedChanges int
+	}{
+		{
+			name: "Legacy setting names are renamed",
+			config: map[string]interface{}{
+				"admin":           map[string]interface{}{"email": "me@example.com", "username": "me"},
+				"adminLogin":      false,
+				"imageUpdateDays": float64(3),
+				"mariadb":         "10",
+			},
+			expected: map[string]interface{}{
+				"adminEmail":      "me@example.com",
+				"adminUser":       "me",
+				"automaticLogin":  false,
+				"updateInterval":  float64(3),
+				"databaseVersion": "10",
+				configVersionKey:  currentConfigVersion,
+			},
+			expectedChanges: 5,
+		},
+		{
+			name: "Settings are renamed to their correct case",
+			config: map[string]interface{}{
+				"PHP":                  "8.1",
+				"removedefaultplugins": true,
+				"local":                true,
+			},
+			expected: map[string]interface{}{
+				"php":                  "8.1",
+				"removeDefaultPlugins": true,
+				configVersionKey:       currentConfigVersion,
+			},
+			expectedChanges: 3,
+		},
+		{
+			name: "String values are converted to their setting type",
+			config: map[string]interface{}{
+				"ssl":             "true",
+				"backupRetention": "3",
+				"plugins":         "query-monitor, debug-bar",
+				"php":             "8.1",
+			},
+			expected: map[string]interface{}{
+				"ssl":             true,
+				"backupRetention": int64(3),
+				"plugins":         []string{"query-monitor", "debug-bar"},
+				"php":             "8.1",
+				configVersionKey:  currentConfigVersion,
+			},
+			expectedChanges: 3,
+		},
+		{
+			name: "Current configs are left alone",
+			config: map[string]interface{}{
+				"PHP":            "8.1",
+				configVersionKey: float64(currentConfigVersion),
+			},
+			expected: map[string]interface{}{
+				"PHP":            "8.1",
+				configVersionKey: currentConfigVersion,
+			},
+			expectedChanges: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, changes := migrateConfig(tt.config)
+
+			assert.Equal(t, tt.expected, tt.config)
+			assert.Len(t, changes, tt.expectedChanges)
+		})
+	}
+}
+
+func TestMigrateConfigFileLocation(t *testing.T) {
+	appDirectory := t.TempDir()
+	oldFile := filepath.Join(appDirectory, "kana.json")
+	configFile := getConfigFile("global", "", appDirectory)
+
+	s := &Settings{
+		settings: []Setting{
+			{name: "appDirectory", currentValue: appDirectory},
+			{name: "workingDirectory", currentValue: t.TempDir()},
+		},
+	}
+
+	err := os.WriteFile(oldFile, []byte(`{"php": "8.1"}`), os.FileMode(defaultFilePermissions))
+	assert.NoError(t, err)
+
+	movedFile, _ := getMovedConfigFile("global", "", appDirectory)
+	assert.Equal(t, oldFile, movedFile)
+
+	results, err := s.MigrateConfigFiles(true)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, configFile, results[0].File)
+	assert.Contains(t, results[0].Changes[0], oldFile)
+	assert.FileExists(t, oldFile)
+	assert.NoFileExists(t, configFile)
+
+	_, err = s.MigrateConfigFiles(false)
+	assert.NoError(t, err)
+	assert.NoFileExists(t, oldFile)
+
+	jsonBytes, err := os.ReadFile(configFile)
+	assert.NoError(t, err)
+
+	var config map[string]interface{}
+
+	err = json.Unmarshal(jsonBytes, &config)
+	assert.NoError(t, err)
+	assert.Equal(t, "8.1", config["php"])
+
+	// A config file already in the new location is never replaced by an old one
+	err = os.WriteFile(oldFile, []byte(`{"php": "7.4"}`), os.FileMode(defaultFilePermissions))
+	assert.NoError(t, err)
+
+	movedFile, _ = getMovedConfigFile("global", "", appDirectory)
+	assert.Equal(t, "", movedFile)
+}
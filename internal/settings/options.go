@@ -0,0 +1,42 @@
+package settings
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OptionOverride is a WordPress option, or a key of an option that holds an array such as a payment gateway's settings,
+// that Kana changes after importing a database.
+type OptionOverride struct {
+	Option string
+	Key    string
+	Value  string
+}
+
+// ParseOptionOverrides parses the safeImportOptions setting, where each override is in the form option=value or
+// option.key=value.
+func ParseOptionOverrides(entries []string) ([]OptionOverride, error) {
+	overrides := []OptionOverride{}
+
+	for _, entry := range entries {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+
+		name, value, found := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		value = strings.TrimSpace(value)
+
+		option, key, _ := strings.Cut(name, ".")
+
+		if !found || option == "" || strings.HasSuffix(name, ".") {
+			return overrides, fmt.Errorf(
+				"the option override, %s, is not valid. Option overrides must be in the form option=value or option.key=value",
+				entry)
+		}
+
+		overrides = append(overrides, OptionOverride{Option: option, Key: key, Value: value})
+	}
+
+	return overrides, nil
+}
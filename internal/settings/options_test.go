@@ -0,0 +1,21 @@
+package settings
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseOptionOverrides(t *testing.T) {
+	overrides, err := ParseOptionOverrides([]string{"blog_public=0", " woocommerce_stripe_settings.testmode = yes ", ""})
+	assert.NoError(t, err)
+	assert.Equal(t, []OptionOverride{
+		{Option: "blog_public", Value: "0"},
+		{Option: "woocommerce_stripe_settings", Key: "testmode", Value: "yes"},
+	}, overrides)
+
+	for _, invalid := range []string{"blog_public", "=0", ".testmode=yes", "woocommerce_stripe_settings.=yes"} {
+		_, err = ParseOptionOverrides([]string{invalid})
+		assert.Error(t, err, invalid)
+	}
+}
@@ -0,0 +1,52 @@
+package settings
+
+import (
+	"fmt"
+	"strings"
+)
+
+// networkFlag marks a plugin in the plugins setting to be network activated on multisite installations.
+const networkFlag = "--network"
+
+// ParsePlugin parses an entry in the plugins setting, which is a plugin's slug optionally followed by --network.
+func ParsePlugin(entry string) (slug string, network bool, err error) {
+	fields := strings.Fields(entry)
+
+	if len(fields) == 0 {
+		return "", false, fmt.Errorf("the plugins setting contains an empty plugin")
+	}
+
+	for _, flag := range fields[1:] {
+		if flag != networkFlag {
+			return fields[0], false, fmt.Errorf("the plugin %s has an invalid flag, %s. Only %s is supported", fields[0], flag, networkFlag)
+		}
+
+		network = true
+	}
+
+	return fields[0], network, nil
+}
+
+// FormatPlugin returns the entry in the plugins setting for the plugin.
+func FormatPlugin(slug string, network bool) string {
+	if network {
+		return fmt.Sprintf("%s %s", slug, networkFlag)
+	}
+
+	return slug
+}
+
+func validatePlugins(plugins []string) error {
+	for _, plugin := range plugins {
+		if strings.TrimSpace(plugin) == "" {
+			continue
+		}
+
+		_, _, err := ParsePlugin(plugin)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
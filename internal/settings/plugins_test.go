@@ -0,0 +1,36 @@
+package settings
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePlugin(t *testing.T) {
+	tests := []struct {
+		entry       string
+		slug        string
+		network     bool
+		expectError bool
+	}{
+		{"query-monitor", "query-monitor", false, false},
+		{"query-monitor --network", "query-monitor", true, false},
+		{" debug-bar  --network ", "debug-bar", true, false},
+		{"query-monitor --activate", "query-monitor", false, true},
+		{"", "", false, true},
+	}
+
+	for _, tt := range tests {
+		slug, network, err := ParsePlugin(tt.entry)
+
+		assert.Equal(t, tt.expectError, err != nil, tt.entry)
+		assert.Equal(t, tt.slug, slug, tt.entry)
+		assert.Equal(t, tt.network, network, tt.entry)
+
+		if !tt.expectError {
+			reparsedSlug, reparsedNetwork, _ := ParsePlugin(FormatPlugin(slug, network))
+			assert.Equal(t, tt.slug, reparsedSlug)
+			assert.Equal(t, tt.network, reparsedNetwork)
+		}
+	}
+}
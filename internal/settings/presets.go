@@ -0,0 +1,148 @@
+package settings
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+//go:embed templates/presets/*.json
+var builtInPresets embed.FS
+
+// Preset is a recipe of plugins, a theme, options, content and wp-cli commands applied to a site with `kana preset apply`.
+type Preset struct {
+	Name        string                 `json:"-"`
+	Source      string                 `json:"-"`
+	Description string                 `json:"description"`
+	Plugins     []string               `json:"plugins"`
+	Theme       string                 `json:"theme"`
+	Options     map[string]interface{} `json:"options"`
+	Content     []string               `json:"content"`
+	Commands    [][]string             `json:"commands"`
+}
+
+const (
+	presetSourceBuiltIn = "built-in"
+	presetSourceCustom  = "custom"
+	presetSourceFile    = "file"
+)
+
+// GetPresets returns the built-in presets and any custom presets in the app's presets directory.
+// Custom presets replace built-in presets with the same name.
+func (s *Settings) GetPresets() ([]Preset, error) {
+	presets := map[string]Preset{}
+
+	builtIns, err := builtInPresets.ReadDir("templates/presets")
+	if err != nil {
+		return []Preset{}, err
+	}
+
+	for _, file := range builtIns {
+		contents, err := builtInPresets.ReadFile(filepath.ToSlash(filepath.Join("templates/presets", file.Name())))
+		if err != nil {
+			return []Preset{}, err
+		}
+
+		preset, err := parsePreset(strings.TrimSuffix(file.Name(), ".json"), presetSourceBuiltIn, contents)
+		if err != nil {
+			return []Preset{}, err
+		}
+
+		presets[preset.Name] = preset
+	}
+
+	customFiles, err := filepath.Glob(filepath.Join(s.getPresetsDirectory(), "*.json"))
+	if err != nil {
+		return []Preset{}, err
+	}
+
+	for _, file := range customFiles {
+		preset, err := readPresetFile(file, presetSourceCustom)
+		if err != nil {
+			return []Preset{}, err
+		}
+
+		presets[preset.Name] = preset
+	}
+
+	presetList := make([]Preset, 0, len(presets))
+
+	for _, preset := range presets {
+		presetList = append(presetList, preset)
+	}
+
+	sort.Slice(presetList, func(i, j int) bool {
+		return presetList[i].Name < presetList[j].Name
+	})
+
+	return presetList, nil
+}
+
+// GetPreset returns the preset with the given name or, if a JSON file exists at the given path, the preset in that file.
+func (s *Settings) GetPreset(nameOrFile string) (Preset, error) {
+	if strings.HasSuffix(nameOrFile, ".json") {
+		if _, err := os.Stat(nameOrFile); err == nil {
+			return readPresetFile(nameOrFile, presetSourceFile)
+		}
+	}
+
+	presets, err := s.GetPresets()
+	if err != nil {
+		return Preset{}, err
+	}
+
+	for _, preset := range presets {
+		if preset.Name == nameOrFile {
+			return preset, nil
+		}
+	}
+
+	return Preset{}, fmt.Errorf("the preset %s could not be found. Use `kana preset list` to see all presets", nameOrFile)
+}
+
+func (s *Settings) getPresetsDirectory() string {
+	return filepath.Join(s.Get("appDirectory"), "presets")
+}
+
+func readPresetFile(file, source string) (Preset, error) {
+	contents, err := os.ReadFile(file)
+	if err != nil {
+		return Preset{}, err
+	}
+
+	return parsePreset(strings.TrimSuffix(filepath.Base(file), ".json"), source, contents)
+}
+
+// parsePreset reads a preset, rejecting unknown fields so typos in shared recipes don't go unnoticed.
+func parsePreset(name, source string, contents []byte) (Preset, error) {
+	preset := Preset{}
+
+	decoder := json.NewDecoder(bytes.NewReader(contents))
+	decoder.DisallowUnknownFields()
+
+	err := decoder.Decode(&preset)
+	if err != nil {
+		return preset, fmt.Errorf("the preset %s is not valid: %s", name, err)
+	}
+
+	err = validatePlugins(preset.Plugins)
+	if err != nil {
+		return preset, fmt.Errorf("the preset %s is not valid: %s", name, err)
+	}
+
+	for _, command := range preset.Commands {
+		if len(command) == 0 {
+			return preset, fmt.Errorf("the preset %s is not valid: it contains an empty command", name)
+		}
+	}
+
+	preset.Name = name
+	preset.Source = source
+
+	return preset, nil
+}
@@ -0,0 +1,80 @@
+package settings
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSettings_GetPresets(t *testing.T) {
+	appDirectory := t.TempDir()
+
+	s := &Settings{
+		settings: []Setting{
+			{name: "appDirectory", currentValue: appDirectory},
+		},
+	}
+
+	presets, err := s.GetPresets()
+	assert.NoError(t, err)
+
+	names := []string{}
+
+	for _, preset := range presets {
+		names = append(names, preset.Name)
+		assert.Equal(t, presetSourceBuiltIn, preset.Source)
+		assert.NotEmpty(t, preset.Description, preset.Name)
+	}
+
+	assert.Equal(t, []string{"bbpress", "buddypress", "classic-editor", "woocommerce"}, names)
+
+	err = os.MkdirAll(filepath.Join(appDirectory, "presets"), 0750)
+	assert.NoError(t, err)
+
+	err = os.WriteFile(
+		filepath.Join(appDirectory, "presets", "woocommerce.json"),
+		[]byte(`{"description": "My store", "plugins": ["woocommerce", "query-monitor"]}`),
+		0600)
+	assert.NoError(t, err)
+
+	preset, err := s.GetPreset("woocommerce")
+	assert.NoError(t, err)
+	assert.Equal(t, presetSourceCustom, preset.Source)
+	assert.Equal(t, []string{"woocommerce", "query-monitor"}, preset.Plugins)
+
+	presetFile := filepath.Join(t.TempDir(), "team.json")
+
+	err = os.WriteFile(presetFile, []byte(`{"description": "Team", "commands": [["rewrite", "flush"]]}`), 0600)
+	assert.NoError(t, err)
+
+	preset, err = s.GetPreset(presetFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "team", preset.Name)
+	assert.Equal(t, presetSourceFile, preset.Source)
+
+	_, err = s.GetPreset("missing")
+	assert.Error(t, err)
+}
+
+func TestParsePreset(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		valid    bool
+	}{
+		{"Valid preset", `{"plugins": ["bbpress --network"], "options": {"blogname": "Test"}}`, true},
+		{"Unknown field", `{"plugin": ["bbpress"]}`, false},
+		{"Invalid plugin flag", `{"plugins": ["bbpress --activate"]}`, false},
+		{"Empty command", `{"commands": [[]]}`, false},
+		{"Invalid JSON", `{"plugins": [`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parsePreset("test", presetSourceFile, []byte(tt.contents))
+			assert.Equal(t, tt.valid, err == nil)
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package settings
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/ChrisWiegman/kana/internal/helpers"
+)
+
+// Project is a plugin or theme found in a monorepo.
+type Project struct {
+	Name string
+	Path string
+	Type string
+}
+
+var projectHeader = regexp.MustCompile(`(Plugin|Theme) Name: .*`)
+
+// GetProjects returns the plugins and themes found in the working directory using the patterns in the projects setting.
+func (s *Settings) GetProjects() ([]Project, error) {
+	return findProjects(s.Get("workingDirectory"), s.GetSlice("projects"))
+}
+
+// findProjects returns the plugins and themes in the directory that match the given patterns.
+func findProjects(directory string, patterns []string) ([]Project, error) {
+	projects := []Project{}
+	found := map[string]bool{}
+
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(filepath.Join(directory, pattern))
+		if err != nil {
+			return projects, err
+		}
+
+		for _, match := range matches {
+			info, err := os.Stat(match)
+			if err != nil || !info.IsDir() || found[match] {
+				continue
+			}
+
+			projectType, err := getProjectType(match)
+			if err != nil {
+				return projects, err
+			}
+
+			if projectType == "" {
+				continue
+			}
+
+			found[match] = true
+
+			projects = append(projects, Project{
+				Name: helpers.SanitizeSiteName(filepath.Base(match)),
+				Path: match,
+				Type: projectType,
+			})
+		}
+	}
+
+	sort.Slice(projects, func(i, j int) bool {
+		return projects[i].Path < projects[j].Path
+	})
+
+	return projects, nil
+}
+
+// findProjectDirectory returns the directory of the project containing the given directory so that running Kana from
+// a subdirectory, such as a plugin's src folder, uses the project rather than creating a new site for the subdirectory.
+// The given directory is returned if it is already a project or if no project is found before reaching the stop directory.
+func findProjectDirectory(directory, sitesDirectory, stopDirectory string) (string, error) {
+	isProject, err := isProjectDirectory(directory, sitesDirectory)
+	if err != nil || isProject {
+		return directory, err
+	}
+
+	for parent := filepath.Dir(directory); parent != filepath.Dir(parent) && parent != stopDirectory; parent = filepath.Dir(parent) {
+		isProject, err = isProjectDirectory(parent, sitesDirectory)
+		if err != nil {
+			return directory, err
+		}
+
+		if isProject {
+			return parent, nil
+		}
+	}
+
+	return directory, nil
+}
+
+// isProjectDirectory returns true if the directory is an existing Kana site, has a Kana config file,
+// is a WordPress site or contains a plugin or theme.
+func isProjectDirectory(directory, sitesDirectory string) (bool, error) {
+	if getLinkedSiteName(directory, sitesDirectory) != "" {
+		return true, nil
+	}
+
+	for _, marker := range []string{".kana.json", ".kana.local.json", filepath.Join("wp-includes", "version.php")} {
+		exists, err := helpers.PathExists(filepath.Join(directory, marker))
+		if err != nil || exists {
+			return exists, err
+		}
+	}
+
+	projectType, err := getProjectType(directory)
+
+	return projectType != "", err
+}
+
+// detectSiteType returns the type of site Kana should start in the directory based on its contents.
+func detectSiteType(directory string, projectPatterns []string) (string, error) {
+	isSite, err := helpers.PathExists(filepath.Join(directory, "wp-includes", "version.php"))
+	if err != nil || isSite {
+		return "site", err
+	}
+
+	detectedType, err := getProjectType(directory)
+	if err != nil || detectedType != "" {
+		return detectedType, err
+	}
+
+	projects, err := findProjects(directory, projectPatterns)
+	if err != nil {
+		return "site", err
+	}
+
+	if len(projects) > 0 {
+		return "monorepo", nil
+	}
+
+	return "site", nil
+}
+
+// getProjectType returns "plugin" or "theme" if the directory contains a plugin or theme header, or an empty string if not.
+func getProjectType(directory string) (string, error) {
+	items, _ := os.ReadDir(directory)
+
+	for _, item := range items {
+		if item.IsDir() || (item.Name() != "style.css" && filepath.Ext(item.Name()) != ".php") {
+			continue
+		}
+
+		projectType, err := readProjectHeader(filepath.Join(directory, item.Name()))
+		if err != nil || projectType != "" {
+			return projectType, err
+		}
+	}
+
+	return "", nil
+}
+
+// GetMainPluginFile returns the name of the file in the directory with the plugin's header, such as my-plugin.php.
+func GetMainPluginFile(directory string) (string, error) {
+	items, _ := os.ReadDir(directory)
+
+	for _, item := range items {
+		if item.IsDir() || filepath.Ext(item.Name()) != ".php" {
+			continue
+		}
+
+		projectType, err := readProjectHeader(filepath.Join(directory, item.Name()))
+		if err != nil {
+			return "", err
+		}
+
+		if projectType == "plugin" {
+			return item.Name(), nil
+		}
+	}
+
+	return "", fmt.Errorf("%s does not contain a plugin", directory)
+}
+
+func readProjectHeader(file string) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+
+	defer f.Close()
+
+	reader := bufio.NewReader(f)
+
+	line, err := helpers.ReadLine(reader)
+
+	for err == nil {
+		for _, match := range projectHeader.FindAllStringSubmatch(line, -1) {
+			if match[1] == "Theme" {
+				return "theme", nil
+			}
+
+			return "plugin", nil
+		}
+
+		line, err = helpers.ReadLine(reader)
+	}
+
+	// We don't care if we've reached the end of the file without finding a header.
+	if err == io.EOF {
+		err = nil
+	}
+
+	return "", err
+}
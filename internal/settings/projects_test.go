@@ -0,0 +1,129 @@
+package settings
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSettings_GetProjects(t *testing.T) {
+	workingDirectory := t.TempDir()
+
+	files := map[string]string{
+		"plugins/my-plugin/my-plugin.php": "<?php\n/**\n * Plugin Name: My Plugin\n */",
+		"plugins/not-a-plugin/README.md":  "# Not a plugin",
+		"themes/my-theme/style.css":       "/*\nTheme Name: My Theme\n*/",
+		"themes/my-theme/functions.php":   "<?php",
+	}
+
+	for file, contents := range files {
+		err := os.MkdirAll(filepath.Join(workingDirectory, filepath.Dir(file)), 0750)
+		assert.NoError(t, err)
+
+		err = os.WriteFile(filepath.Join(workingDirectory, file), []byte(contents), 0600)
+		assert.NoError(t, err)
+	}
+
+	s := &Settings{
+		settings: []Setting{
+			{name: "workingDirectory", currentValue: workingDirectory},
+			{name: "projects", settingType: "slice", currentValue: "plugins/*,themes/*"},
+		},
+	}
+
+	projects, err := s.GetProjects()
+	assert.NoError(t, err)
+
+	assert.Equal(t, []Project{
+		{Name: "my-plugin", Path: filepath.Join(workingDirectory, "plugins", "my-plugin"), Type: "plugin"},
+		{Name: "my-theme", Path: filepath.Join(workingDirectory, "themes", "my-theme"), Type: "theme"},
+	}, projects)
+}
+
+func TestFindProjectDirectory(t *testing.T) {
+	homeDirectory := t.TempDir()
+	appDirectory := filepath.Join(homeDirectory, ".config", "kana")
+
+	files := map[string]string{
+		"my-plugin/my-plugin.php":              "<?php\n/**\n * Plugin Name: My Plugin\n */",
+		"my-plugin/src/includes/class-foo.php": "<?php",
+		"my-site/.kana.json":                   "{}",
+		"my-site/wp-content/themes/README.md":  "# Themes",
+		"linked-site/wp-config.php":            "<?php",
+		"no-project/src/index.php":             "<?php",
+	}
+
+	for file, contents := range files {
+		err := os.MkdirAll(filepath.Join(homeDirectory, filepath.Dir(file)), 0750)
+		assert.NoError(t, err)
+
+		err = os.WriteFile(filepath.Join(homeDirectory, file), []byte(contents), 0600)
+		assert.NoError(t, err)
+	}
+
+	err := os.MkdirAll(filepath.Join(appDirectory, "sites", "linked-site"), 0750)
+	assert.NoError(t, err)
+
+	err = os.WriteFile(
+		filepath.Join(appDirectory, "sites", "linked-site", "link.json"),
+		[]byte(`{"link":"`+filepath.Join(homeDirectory, "linked-site")+`"}`),
+		0600)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		directory string
+		expected  string
+	}{
+		{"A project directory is used as is", "my-plugin", "my-plugin"},
+		{"A plugin is found from a nested folder", "my-plugin/src/includes", "my-plugin"},
+		{"A site config is found from a nested folder", "my-site/wp-content/themes", "my-site"},
+		{"An existing site is used as is", "linked-site", "linked-site"},
+		{"The current folder is used if no project is found", "no-project/src", "no-project/src"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			directory, err := findProjectDirectory(filepath.Join(homeDirectory, tt.directory), filepath.Join(appDirectory, "sites"), homeDirectory)
+			assert.NoError(t, err)
+			assert.Equal(t, filepath.Join(homeDirectory, tt.expected), directory)
+		})
+	}
+}
+
+func TestDetectSiteType(t *testing.T) {
+	directory := t.TempDir()
+
+	files := map[string]string{
+		"plugin/plugin.php":                        "<?php\n/**\n * Plugin Name: My Plugin\n */",
+		"theme/style.css":                          "/*\nTheme Name: My Theme\n*/",
+		"wordpress/wp-includes/version.php":        "<?php",
+		"monorepo/plugins/my-plugin/my-plugin.php": "<?php\n/**\n * Plugin Name: My Plugin\n */",
+		"empty/README.md":                          "# Nothing here",
+	}
+
+	for file, contents := range files {
+		err := os.MkdirAll(filepath.Join(directory, filepath.Dir(file)), 0750)
+		assert.NoError(t, err)
+
+		err = os.WriteFile(filepath.Join(directory, file), []byte(contents), 0600)
+		assert.NoError(t, err)
+	}
+
+	expected := map[string]string{
+		"plugin":    "plugin",
+		"theme":     "theme",
+		"wordpress": "site",
+		"monorepo":  "monorepo",
+		"empty":     "site",
+		"missing":   "site",
+	}
+
+	for folder, siteType := range expected {
+		detectedType, err := detectSiteType(filepath.Join(directory, folder), []string{"plugins/*", "themes/*"})
+		assert.NoError(t, err)
+		assert.Equal(t, siteType, detectedType, folder)
+	}
+}
@@ -0,0 +1,74 @@
+package settings
+
+import (
+	"os"
+	"strings"
+)
+
+// Proxy is the proxy server Kana, and the site's containers, use to reach the internet.
+type Proxy struct {
+	HTTP, HTTPS, NoProxy string
+}
+
+// proxyVariables are the environment variables holding each proxy setting.
+var proxyVariables = map[string]string{
+	"httpProxy":  "HTTP_PROXY",
+	"httpsProxy": "HTTPS_PROXY",
+	"noProxy":    "NO_PROXY",
+}
+
+// GetProxy returns the httpProxy, httpsProxy and noProxy settings. The HTTP_PROXY, HTTPS_PROXY and NO_PROXY
+// environment variables, or their lowercase versions, are used for any that aren't set.
+func (s *Settings) GetProxy() Proxy {
+	return Proxy{
+		HTTP:    s.getProxySetting("httpProxy"),
+		HTTPS:   s.getProxySetting("httpsProxy"),
+		NoProxy: s.getProxySetting("noProxy"),
+	}
+}
+
+// IsSet returns true if a proxy server is used for either HTTP or HTTPS.
+func (p Proxy) IsSet() bool {
+	return p.HTTP != "" || p.HTTPS != ""
+}
+
+func (s *Settings) getProxySetting(name string) string {
+	if value := s.Get(name); value != "" {
+		return value
+	}
+
+	if value := os.Getenv(proxyVariables[name]); value != "" {
+		return value
+	}
+
+	return os.Getenv(strings.ToLower(proxyVariables[name]))
+}
+
+// loadProxy sets the proxy environment variables from the proxy settings so Kana's own requests, such as checking that
+// an image exists or downloading the WordPress test suite, go through the proxy too. Proxy settings that aren't set take
+// their values from the environment variables instead so `kana config` shows the proxy being used.
+func loadProxy(settings *Settings) error {
+	for i := range settings.settings {
+		variable, isProxySetting := proxyVariables[settings.settings[i].name]
+		if !isProxySetting {
+			continue
+		}
+
+		if settings.settings[i].currentValue == "" {
+			settings.settings[i].currentValue = settings.getProxySetting(settings.settings[i].name)
+
+			if settings.settings[i].currentValue != "" {
+				settings.settings[i].source = sourceEnvironment
+			}
+
+			continue
+		}
+
+		err := os.Setenv(variable, settings.settings[i].currentValue)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
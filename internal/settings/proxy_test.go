@@ -0,0 +1,42 @@
+package settings
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetProxy(t *testing.T) {
+	t.Setenv("HTTP_PROXY", "")
+	t.Setenv("HTTPS_PROXY", "")
+	t.Setenv("NO_PROXY", "")
+	t.Setenv("https_proxy", "http://proxy.example.com:3128")
+	t.Setenv("no_proxy", "intranet.example.com")
+
+	s := &Settings{
+		settings: []Setting{
+			{name: "httpProxy", currentValue: "http://localhost:8080"},
+			{name: "httpsProxy"},
+			{name: "noProxy"},
+		},
+	}
+
+	assert.Equal(t, Proxy{
+		HTTP:    "http://localhost:8080",
+		HTTPS:   "http://proxy.example.com:3128",
+		NoProxy: "intranet.example.com",
+	}, s.GetProxy())
+	assert.True(t, s.GetProxy().IsSet())
+
+	// Kana's own requests use the proxy settings too
+	assert.NoError(t, loadProxy(s))
+	assert.Equal(t, "http://localhost:8080", os.Getenv("HTTP_PROXY"))
+	assert.Empty(t, os.Getenv("HTTPS_PROXY"))
+
+	// Settings taken from the environment say so but aren't saved to the config
+	assert.Equal(t, "http://proxy.example.com:3128", s.Get("httpsProxy"))
+	assert.Equal(t, sourceEnvironment, s.settings[1].source)
+	assert.Equal(t, sourceEnvironment, s.settings[2].source)
+	assert.Equal(t, "", s.settings[0].source)
+}
@@ -0,0 +1,33 @@
+package settings
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// ParseReadOnlyPaths parses the readOnlyPaths setting, where each path is relative to the site's WordPress folder, such
+// as wp-content/plugins/premium-plugin. The cleaned paths are returned.
+func ParseReadOnlyPaths(entries []string) ([]string, error) {
+	paths := []string{}
+
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+
+		if entry == "" {
+			continue
+		}
+
+		cleanPath := path.Clean(strings.ReplaceAll(entry, "\\", "/"))
+
+		if path.IsAbs(cleanPath) || cleanPath == "." || cleanPath == ".." || strings.HasPrefix(cleanPath, "../") {
+			return paths, fmt.Errorf(
+				"the read-only path, %s, is not valid. Paths must be inside the site's WordPress folder, such as wp-content/plugins/my-plugin",
+				entry)
+		}
+
+		paths = append(paths, cleanPath)
+	}
+
+	return paths, nil
+}
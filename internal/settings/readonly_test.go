@@ -0,0 +1,23 @@
+package settings
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseReadOnlyPaths(t *testing.T) {
+	paths, err := ParseReadOnlyPaths([]string{"wp-content/plugins/premium-plugin/", " wp-content/themes/../mu-plugins/shared ", ""})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"wp-content/plugins/premium-plugin", "wp-content/mu-plugins/shared"}, paths)
+
+	for _, invalid := range []string{
+		"/var/www/html/wp-content",
+		"../other-site",
+		"wp-content/../..",
+		".",
+	} {
+		_, err = ParseReadOnlyPaths([]string{invalid})
+		assert.Error(t, err, invalid)
+	}
+}
@@ -0,0 +1,41 @@
+package settings
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// remoteHostPattern matches SSH destinations such as user@example.com or a Host from the user's SSH config.
+	remoteHostPattern = regexp.MustCompile(`^([A-Za-z0-9._-]+@)?[A-Za-z0-9._-]+$`)
+	// remotePathPattern matches server paths that can be passed to ssh and rsync without quoting, such as /var/www/html
+	// or ~/public_html.
+	remotePathPattern = regexp.MustCompile(`^(~/|/)?[A-Za-z0-9._/-]+$`)
+)
+
+// ValidateRemoteHost checks a setting, such as pullHost, is an SSH destination such as user@example.com. Options, such
+// as the port, can be set for the host in the user's SSH config.
+func ValidateRemoteHost(name, host string) error {
+	if host != "" && (!remoteHostPattern.MatchString(host) || strings.HasPrefix(host, "-")) {
+		return fmt.Errorf(
+			"the %s setting, %s, is not valid. Use user@host or a Host from your SSH config, where options such as the port can be set",
+			name,
+			host)
+	}
+
+	return nil
+}
+
+// ValidateRemotePath checks a setting, such as pullPath, is the folder WordPress is installed in on a server, such as
+// /var/www/html or ~/public_html.
+func ValidateRemotePath(name, path string) error {
+	if path != "" && (!remotePathPattern.MatchString(path) || strings.Contains(path, "..")) {
+		return fmt.Errorf(
+			"the %s setting, %s, is not valid. Use the folder WordPress is installed in on the server, such as /var/www/html",
+			name,
+			path)
+	}
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package settings
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateRemote(t *testing.T) {
+	for _, host := range []string{"", "example.com", "deploy@example.com", "production"} {
+		assert.NoError(t, ValidateRemoteHost("pullHost", host), host)
+	}
+
+	for _, host := range []string{"-oProxyCommand=touch", "user@host name", "host;ls", "user@"} {
+		assert.Error(t, ValidateRemoteHost("pullHost", host), host)
+	}
+
+	for _, path := range []string{"", "/var/www/html", "~/public_html", "sites/example.com/current"} {
+		assert.NoError(t, ValidateRemotePath("pullPath", path), path)
+	}
+
+	for _, path := range []string{"/var/www/my site", "~/public_html;rm -rf ~", "/var/www/../..", "$HOME/www"} {
+		assert.Error(t, ValidateRemotePath("pullPath", path), path)
+	}
+}
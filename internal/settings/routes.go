@@ -0,0 +1,67 @@
+package settings
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Route sends requests for a path of the site's domain to another app instead of WordPress.
+type Route struct {
+	Path string
+	URL  string
+}
+
+var routePathPattern = regexp.MustCompile(`^/[A-Za-z0-9._~/-]*$`)
+
+// ParseRoutes parses the routes setting, where each route is in the form /path=target. The target is either a port
+// on the host, such as 3000, or the URL of another server, such as http://192.168.1.20:8000.
+func ParseRoutes(entries []string) ([]Route, error) {
+	routes := []Route{}
+
+	for _, entry := range entries {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+
+		path, target, found := strings.Cut(entry, "=")
+		path = strings.TrimSpace(path)
+		target = strings.TrimSpace(target)
+
+		if !found || path == "/" || !routePathPattern.MatchString(path) {
+			return routes, fmt.Errorf(
+				"the route, %s, is not valid. Routes must be in the form /path=target where target is a host port or URL", entry)
+		}
+
+		targetURL, err := getRouteURL(target)
+		if err != nil {
+			return routes, fmt.Errorf("the route, %s, is not valid. %s", entry, err)
+		}
+
+		routes = append(routes, Route{Path: path, URL: targetURL})
+	}
+
+	return routes, nil
+}
+
+// getRouteURL returns the URL Traefik should send a route's requests to. Ports are on the host so are reached
+// through the host.docker.internal name that Kana adds to the Traefik container.
+func getRouteURL(target string) (string, error) {
+	port, err := strconv.Atoi(target)
+	if err == nil {
+		if port < 1 || port > 65535 {
+			return "", fmt.Errorf("%d is not a valid port", port)
+		}
+
+		return fmt.Sprintf("http://host.docker.internal:%d", port), nil
+	}
+
+	targetURL, err := url.Parse(target)
+	if err != nil || (targetURL.Scheme != "http" && targetURL.Scheme != "https") || targetURL.Host == "" {
+		return "", fmt.Errorf("the target, %s, must be a port or an http or https URL", target)
+	}
+
+	return strings.TrimSuffix(target, "/"), nil
+}
@@ -0,0 +1,29 @@
+package settings
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRoutes(t *testing.T) {
+	routes, err := ParseRoutes([]string{"/app/=3000", " /api = http://192.168.1.20:8000/ ", ""})
+	assert.NoError(t, err)
+	assert.Equal(t, []Route{
+		{Path: "/app/", URL: "http://host.docker.internal:3000"},
+		{Path: "/api", URL: "http://192.168.1.20:8000"},
+	}, routes)
+
+	for _, invalid := range []string{
+		"/app",
+		"app=3000",
+		"/=3000",
+		"/app`)=3000",
+		"/app=70000",
+		"/app=localhost:3000",
+		"/app=ftp://example.com",
+	} {
+		_, err = ParseRoutes([]string{invalid})
+		assert.Error(t, err, invalid)
+	}
+}
@@ -1,17 +1,17 @@
 package settings
 
 import (
-	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
 	"reflect"
-	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 
+	"github.com/ChrisWiegman/kana/internal/console"
 	"github.com/ChrisWiegman/kana/internal/docker"
 	"github.com/ChrisWiegman/kana/internal/helpers"
 
@@ -26,10 +26,11 @@ func Load(kanaSettings *Settings, version string, cmd *cobra.Command) error {
 
 	for i := range defaults {
 		defaults[i].currentValue = defaults[i].defaultValue
+		defaults[i].source = sourceDefault
 		kanaSettings.settings = append(kanaSettings.settings, defaults[i])
 	}
 
-	settings["appDirectory"], settings["workingDirectory"], err = getStaticDirectories()
+	settings["appDirectory"], settings["sitesDirectory"], settings["workingDirectory"], err = getStaticDirectories(cmd)
 	if err != nil {
 		return err
 	}
@@ -38,11 +39,20 @@ func Load(kanaSettings *Settings, version string, cmd *cobra.Command) error {
 		settings["siteDirectory"],
 		settings["isNamed"],
 		settings["isNew"],
-		err = getSiteInfo(settings["workingDirectory"].(string), settings["appDirectory"].(string), cmd)
+		err = getSiteInfo(settings["workingDirectory"].(string), settings["sitesDirectory"].(string), cmd)
 	if err != nil {
 		return err
 	}
 
+	// Sites created with `kana clone` keep their config in their own folder rather than the current one
+	if settings["isNamed"].(bool) {
+		clonedConfig := getConfigFile("local", settings["siteDirectory"].(string), settings["appDirectory"].(string))
+
+		if _, err = os.Stat(clonedConfig); err == nil {
+			settings["workingDirectory"] = settings["siteDirectory"]
+		}
+	}
+
 	for key, value := range settings {
 		err = kanaSettings.Set(key, value)
 		if err != nil {
@@ -50,7 +60,12 @@ func Load(kanaSettings *Settings, version string, cmd *cobra.Command) error {
 		}
 	}
 
-	err = saveLocalLinkConfig(cmd, settings["siteDirectory"].(string), settings["workingDirectory"].(string), settings["isNamed"].(bool))
+	err = saveLocalLinkConfig(
+		cmd,
+		settings["appDirectory"].(string),
+		settings["siteDirectory"].(string),
+		settings["workingDirectory"].(string),
+		settings["isNamed"].(bool))
 	if err != nil {
 		return err
 	}
@@ -65,89 +80,60 @@ func Load(kanaSettings *Settings, version string, cmd *cobra.Command) error {
 		return err
 	}
 
-	err = ensureStaticConfigFiles(settings["appDirectory"].(string))
+	err = loadKoanfOptions("personal", kanaSettings)
 	if err != nil {
 		return err
 	}
 
-	err = loadDetectedType(kanaSettings)
+	err = ensureStaticConfigFiles(settings["appDirectory"].(string))
 	if err != nil {
 		return err
 	}
 
-	return processStartFlags(cmd, kanaSettings)
-}
-
-func loadDetectedType(settings *Settings) error {
-	var err error
-	var isSite bool
-	oldType := settings.Get("type")
-	workingDirectory := settings.Get("workingDirectory")
-
-	isSite, err = helpers.PathExists(filepath.Join(workingDirectory, "wp-includes", "version.php"))
+	err = loadDetectedType(kanaSettings)
 	if err != nil {
 		return err
 	}
 
-	if isSite {
+	err = processStartFlags(cmd, kanaSettings)
+	if err != nil {
 		return err
 	}
 
-	items, _ := os.ReadDir(workingDirectory)
+	loadDatabaseVersion(kanaSettings)
 
-	for _, item := range items {
-		if item.IsDir() {
-			continue
-		}
+	return loadProxy(kanaSettings)
+}
 
-		if item.Name() == "style.css" || filepath.Ext(item.Name()) == ".php" {
-			var f *os.File
-			var line string
+// loadDatabaseVersion uses the default MySQL or PostgreSQL version for sites using them that haven't pinned a database
+// version, as the default databaseVersion is a MariaDB version that has no matching MySQL or PostgreSQL image.
+func loadDatabaseVersion(settings *Settings) {
+	databaseVersions := map[string]string{
+		"mysql":    mysqlVersion,
+		"postgres": postgresVersion,
+	}
 
-			f, err = os.Open(filepath.Join(workingDirectory, item.Name()))
-			if err != nil {
-				return err
-			}
+	databaseVersion, ok := databaseVersions[settings.Get("database")]
+	if !ok {
+		return
+	}
 
-			reader := bufio.NewReader(f)
-			line, err = helpers.ReadLine(reader)
-
-			for err == nil {
-				exp := regexp.MustCompile(`(Plugin|Theme) Name: .*`)
-
-				for _, match := range exp.FindAllStringSubmatch(line, -1) {
-					if match[1] == "Theme" {
-						err = settings.Set("type", "theme")
-						if err != nil {
-							return err
-						}
-					} else {
-						err = settings.Set("type", "plugin")
-						if err != nil {
-							return err
-						}
-					}
-
-					if oldType != settings.Get("type") {
-						err = settings.Set("typeDetected", true)
-						if err != nil {
-							return err
-						}
-					}
-
-					return err
-				}
-				line, err = helpers.ReadLine(reader)
-			}
+	for i := range settings.settings {
+		if settings.settings[i].name == "databaseVersion" && settings.settings[i].source == sourceDefault {
+			settings.settings[i].currentValue = databaseVersion
 		}
 	}
+}
+
+func loadDetectedType(settings *Settings) error {
+	oldType := settings.Get("type")
 
-	// We don't care if it is an empty folder.
-	if err == io.EOF {
-		err = nil //nolint:ineffassign,wastedassign
+	detectedType, err := detectSiteType(settings.Get("workingDirectory"), settings.GetSlice("projects"))
+	if err != nil {
+		return err
 	}
 
-	err = settings.Set("type", "site")
+	err = settings.Set("type", detectedType)
 	if err != nil {
 		return err
 	}
@@ -159,7 +145,15 @@ func loadDetectedType(settings *Settings) error {
 		}
 	}
 
-	return err
+	return nil
+}
+
+// Clone returns a copy of the settings that can be changed without affecting the original, such as for a temporary site.
+func (s *Settings) Clone() *Settings {
+	clone := *s
+	clone.settings = slices.Clone(s.settings)
+
+	return &clone
 }
 
 func (s *Settings) Get(name string) string {
@@ -196,30 +190,37 @@ func (s *Settings) GetAll(settingsType string) map[string]interface{} {
 			continue
 		}
 
+		currentValue := s.settings[i].currentValue
+
+		// Values from environment variables, such as HTTP_PROXY, are never saved to a config file
+		if s.settings[i].source == sourceEnvironment {
+			currentValue = s.settings[i].defaultValue
+		}
+
 		switch s.settings[i].settingType {
 		case "bool":
-			boolValue, _ := strconv.ParseBool(s.settings[i].currentValue)
+			boolValue, _ := strconv.ParseBool(currentValue)
 			if koSettings != nil && koSettings.Exists(s.settings[i].name) {
 				boolValue = koSettings.Bool(s.settings[i].name)
 			}
 
 			allSettings[s.settings[i].name] = boolValue
 		case "int":
-			intValue, _ := strconv.ParseInt(s.settings[i].currentValue, 10, 64)
+			intValue, _ := strconv.ParseInt(currentValue, 10, 64)
 			if koSettings != nil && koSettings.Exists(s.settings[i].name) {
 				intValue = koSettings.Int64(s.settings[i].name)
 			}
 
 			allSettings[s.settings[i].name] = intValue
 		case "slice":
-			sliceVal := strings.Split(s.settings[i].currentValue, ",")
+			sliceVal := strings.Split(currentValue, ",")
 			if koSettings != nil && koSettings.Exists(s.settings[i].name) {
 				sliceVal = koSettings.Strings(s.settings[i].name)
 			}
 
 			allSettings[s.settings[i].name] = sliceVal
 		default:
-			stringValue := s.settings[i].currentValue
+			stringValue := currentValue
 			if koSettings != nil && koSettings.Exists(s.settings[i].name) {
 				stringValue = koSettings.String(s.settings[i].name)
 			}
@@ -312,23 +313,121 @@ func (s *Settings) Set(name string, value interface{}, setVars ...bool) error {
 func (s *Settings) WriteLocalSettings(localSettings map[string]interface{}) error {
 	configFile := filepath.Join(s.Get("workingDirectory"), ".kana.json")
 
-	allSettings := s.GetAll("local")
-
-	for setting, value := range localSettings {
-		allSettings[setting] = value
+	jsonBytes, err := s.MarshalLocalSettings(localSettings)
+	if err != nil {
+		return err
 	}
 
 	f, _ := os.Create(configFile)
 	defer f.Close()
 
-	jsonBytes, err := json.MarshalIndent(allSettings, "", "\t")
+	_, err = f.Write(jsonBytes)
+
+	return err
+}
+
+// ApplyLocalConfig replaces the site's .kana.json file with the given config, after migrating and validating it, and uses its
+// settings for the rest of the command.
+func (s *Settings) ApplyLocalConfig(contents []byte) error {
+	config := map[string]interface{}{}
+
+	err := json.Unmarshal(contents, &config)
+	if err != nil {
+		return fmt.Errorf("invalid JSON: %s", err)
+	}
+
+	migrateConfig(config)
+
+	jsonBytes, err := json.MarshalIndent(config, "", "\t")
 	if err != nil {
 		return err
 	}
 
-	_, err = f.Write(jsonBytes)
+	err = s.ValidateConfig("local", jsonBytes)
+	if err != nil {
+		return err
+	}
 
-	return err
+	for i := range s.settings {
+		value, ok := config[s.settings[i].name]
+		if !ok {
+			continue
+		}
+
+		value, err = normalizeConfigValue(s.settings[i], value)
+		if err != nil {
+			return err
+		}
+
+		// JSON numbers are decoded as floats
+		if floatValue, ok := value.(float64); ok {
+			value = int64(floatValue)
+		}
+
+		err = s.Set(s.settings[i].name, value)
+		if err != nil {
+			return err
+		}
+	}
+
+	return os.WriteFile(
+		filepath.Join(s.Get("workingDirectory"), ".kana.json"),
+		jsonBytes,
+		os.FileMode(defaultFilePermissions))
+}
+
+// MarshalLocalSettings returns the site's .kana.json file with the given settings changed.
+func (s *Settings) MarshalLocalSettings(localSettings map[string]interface{}) ([]byte, error) {
+	allSettings := s.GetAll("local")
+
+	for setting, value := range localSettings {
+		allSettings[setting] = value
+	}
+
+	allSettings[configVersionKey] = currentConfigVersion
+
+	if s.local != nil && s.local.Exists(lockedKey) {
+		allSettings[lockedKey] = s.local.Strings(lockedKey)
+	}
+
+	return json.MarshalIndent(allSettings, "", "\t")
+}
+
+// GetIgnoredOverrides returns the settings in .kana.local.json that were ignored because the site's config locks them.
+func (s *Settings) GetIgnoredOverrides() []string {
+	return s.ignoredOverrides
+}
+
+// isLocked returns true if the site's config prevents the setting from being overridden locally.
+func (s *Settings) isLocked(name string) bool {
+	return isLockedIn(getLockedSettings(s.local), name)
+}
+
+// getLockedSettings returns the settings a site's config locks.
+func getLockedSettings(siteConfig Koanf) []string {
+	if siteConfig == nil || !siteConfig.Exists(lockedKey) {
+		return []string{}
+	}
+
+	return siteConfig.Strings(lockedKey)
+}
+
+func isLockedIn(locked []string, name string) bool {
+	return slices.ContainsFunc(locked, func(lockedName string) bool { return strings.EqualFold(lockedName, name) })
+}
+
+// removeLockedSettings removes any locked settings from a personal config and returns the names of those removed.
+func removeLockedSettings(config map[string]interface{}, locked []string) []string {
+	removed := []string{}
+
+	for _, key := range sortedKeys(config) {
+		if isLockedIn(locked, key) {
+			delete(config, key)
+			removed = append(removed, key)
+		}
+	}
+
+	return removed
 }
 
 func (s *Settings) validate(name string, value interface{}) error {
@@ -363,34 +462,178 @@ func (s *Settings) validate(name string, value interface{}) error {
 		switch name {
 		case "adminEmail":
 			return validate.Var(stringVal, "email")
-		case "updateInterval":
+		case "updateInterval", "backupInterval", "backupRetention", "networkRetries":
 			return validate.Var(stringVal, "gte=0")
-		case "databaseVersion":
-			if docker.ValidateImage(s.Get("database"), stringVal) != nil {
-				databaseURL := "https://hub.docker.com/_/mariadb"
+		case "databasePort":
+			return validate.Var(stringVal, "gte=0,lte=65535")
+		case "colorOverrides":
+			overrides, ok := value.([]string)
+			if !ok {
+				overrides = strings.Split(stringVal, ",")
+			}
 
-				if s.Get("database") == "mysql" {
-					databaseURL = "https://hub.docker.com/_/mysql"
-				}
+			return console.ValidateColorOverrides(overrides)
+		case "plugins", "safeImportPlugins":
+			plugins, ok := value.([]string)
+			if !ok {
+				plugins = strings.Split(stringVal, ",")
+			}
 
-				return fmt.Errorf(
-					"the database version in your configuration, %s, is invalid. See %s for a list of supported versions",
-					stringVal, databaseURL)
+			return validatePlugins(plugins)
+		case "safeImportOptions":
+			overrides, ok := value.([]string)
+			if !ok {
+				overrides = strings.Split(stringVal, ",")
+			}
+
+			_, err := ParseOptionOverrides(overrides)
+
+			return err
+		case "extraUsers":
+			users, ok := value.([]string)
+			if !ok {
+				users = strings.Split(stringVal, ",")
+			}
+
+			_, err := ParseExtraUsers(users)
+
+			return err
+		case "corsOrigins":
+			origins, ok := value.([]string)
+			if !ok {
+				origins = strings.Split(stringVal, ",")
+			}
+
+			return ValidateCORSOrigins(origins)
+		case "headers":
+			headers, ok := value.([]string)
+			if !ok {
+				headers = strings.Split(stringVal, ",")
+			}
+
+			_, err := ParseHeaders(headers)
+
+			return err
+		case "middlewares":
+			middlewares, ok := value.([]string)
+			if !ok {
+				middlewares = strings.Split(stringVal, ",")
+			}
+
+			_, err := ParseMiddlewares(middlewares)
+
+			return err
+		case "routes":
+			routes, ok := value.([]string)
+			if !ok {
+				routes = strings.Split(stringVal, ",")
+			}
+
+			_, err := ParseRoutes(routes)
+
+			return err
+		case "caCertificates":
+			files, ok := value.([]string)
+			if !ok {
+				files = strings.Split(stringVal, ",")
 			}
-		case "php":
-			if docker.ValidateImage("wordpress", fmt.Sprintf("php%s", stringVal)) != nil {
+
+			_, err := ReadCACertificates(files, s.Get("workingDirectory"))
+
+			return err
+		case "readOnlyPaths":
+			paths, ok := value.([]string)
+			if !ok {
+				paths = strings.Split(stringVal, ",")
+			}
+
+			_, err := ParseReadOnlyPaths(paths)
+
+			return err
+		case "pullHost", "pushHost":
+			return ValidateRemoteHost(name, stringVal)
+		case "pullPath", "pushPath":
+			return ValidateRemotePath(name, stringVal)
+		case "permalinks":
+			if stringVal != "" && (!strings.HasPrefix(stringVal, "/") || !strings.Contains(stringVal, "%")) {
 				return fmt.Errorf(
-					"the PHP version in your configuration, %s, is invalid. See https://hub.docker.com/_/wordpress for a list of supported versions",
+					"the permalinks value, %s, is not valid. Use a structure such as /%%postname%%/ or leave it empty for plain permalinks",
 					stringVal)
 			}
+		case "telemetryEndpoint", "httpProxy", "httpsProxy":
+			return validate.Var(stringVal, "omitempty,url")
+		case "composerVersion", "databaseVersion", "nodeVersion", "php", "wpCliVersion":
+			return s.validateImageVersion(name, stringVal)
+		}
+	}
+
+	return nil
+}
+
+// validateImageVersion checks that the Docker image exists for a setting that selects the version of an image.
+// If Docker Hub can't be reached the network error is returned instead so the version isn't reported as invalid.
+func (s *Settings) validateImageVersion(name, value string) error {
+	var err error
+
+	switch name {
+	case "composerVersion":
+		err = docker.ValidateImage("composer", value)
+		if err != nil && !errors.Is(err, docker.ErrNetwork) {
+			return fmt.Errorf(
+				"the Composer version in your configuration, %s, is invalid. See https://hub.docker.com/_/composer for a list of supported versions",
+				value)
+		}
+	case "databaseVersion":
+		err = docker.ValidateImage(s.Get("database"), value)
+		if err != nil && !errors.Is(err, docker.ErrNetwork) {
+			databaseURL := fmt.Sprintf("https://hub.docker.com/_/%s", s.Get("database"))
+
+			return fmt.Errorf(
+				"the database version in your configuration, %s, is invalid. See %s for a list of supported versions",
+				value, databaseURL)
+		}
+	case "nodeVersion":
+		err = docker.ValidateImage("node", value)
+		if err != nil && !errors.Is(err, docker.ErrNetwork) {
+			return fmt.Errorf(
+				"the Node.js version in your configuration, %s, is invalid. See https://hub.docker.com/_/node for a list of supported versions",
+				value)
+		}
+	case "php":
+		err = docker.ValidateImage("wordpress", fmt.Sprintf("php%s", value))
+		if err != nil && !errors.Is(err, docker.ErrNetwork) {
+			return fmt.Errorf(
+				"the PHP version in your configuration, %s, is invalid. See https://hub.docker.com/_/wordpress for a list of supported versions",
+				value)
+		}
+	case "wpCliVersion":
+		if value == "" {
+			return nil
+		}
+
+		err = docker.ValidateImage("wordpress", fmt.Sprintf("cli-%s-php%s", value, s.Get("php")))
+		if err != nil && !errors.Is(err, docker.ErrNetwork) {
+			return fmt.Errorf(
+				"the wp-cli version in your configuration, %s, is invalid. See https://hub.docker.com/_/wordpress for a list of supported versions",
+				value)
 		}
 	}
 
+	if err != nil {
+		return fmt.Errorf("unable to check the %s setting with Docker Hub: %w", name, err)
+	}
+
 	return nil
 }
 
-func getSiteInfo(workingDirectory, appDirectory string, cmd *cobra.Command) (name, siteDirectory string, isNamed, isNew bool, err error) {
+func getSiteInfo(workingDirectory, sitesDirectory string, cmd *cobra.Command) (name, siteDirectory string, isNamed, isNew bool, err error) {
 	name = helpers.SanitizeSiteName(filepath.Base(workingDirectory))
+
+	// Directories linked to a site with `kana link` use that site rather than one named after the directory
+	if linkedName := getLinkedSiteName(workingDirectory, sitesDirectory); linkedName != "" {
+		name = linkedName
+	}
+
 	isStartCommand := cmd.Use == "start"
 
 	// Don't run this on commands that wouldn't possibly use it.
@@ -415,9 +658,10 @@ func getSiteInfo(workingDirectory, appDirectory string, cmd *cobra.Command) (nam
 	}
 
 	// We can set the site directory here now that we have the correct name.
-	siteDirectory = filepath.Join(appDirectory, "sites", name)
+	siteDirectory = filepath.Join(sitesDirectory, name)
 
-	_, err = os.Stat(siteDirectory)
+	// Lstat is used so sites moved to a disk that isn't connected aren't mistaken for new sites
+	_, err = os.Lstat(siteDirectory)
 	if err != nil && os.IsNotExist(err) {
 		if os.IsNotExist(err) {
 			isNew = true
@@ -426,59 +670,56 @@ func getSiteInfo(workingDirectory, appDirectory string, cmd *cobra.Command) (nam
 		}
 	}
 
-	return name, siteDirectory, isNamed, isNew, nil
+	return name, resolveSiteDirectory(siteDirectory), isNamed, isNew, nil
 }
 
-func getStaticDirectories() (app, working string, err error) {
+func getStaticDirectories(cmd *cobra.Command) (app, sites, working string, err error) {
 	cwd, err := os.Getwd()
 	if err != nil {
-		return app, working, err
+		return app, sites, working, err
 	}
 
 	working = cwd
 
 	home, err := homedir.Dir()
 	if err != nil {
-		return app, working, err
+		return app, sites, working, err
 	}
 
-	app = filepath.Join(home, configFolderName)
+	app, err = getAppDirectory(home)
+	if err != nil {
+		return app, sites, working, err
+	}
 
 	err = os.MkdirAll(app, os.FileMode(defaultDirPermissions))
+	if err != nil {
+		return app, sites, working, err
+	}
 
-	return app, working, err
-}
-
-func saveLocalLinkConfig(cmd *cobra.Command, siteDirectory, workingDirectory string, isNamedSite bool) error {
-	siteLink := map[string]string{
-		"link": workingDirectory}
+	sites = getSitesDirectory(home, app)
 
-	if isNamedSite {
-		siteLink["link"] = siteDirectory
+	// Named sites aren't tied to the current directory so there's no project to look for
+	nameFlag := cmd.Flags().Lookup("name")
+	if nameFlag != nil && nameFlag.Changed {
+		return app, sites, working, nil
 	}
 
-	linkConfigFile := filepath.Join(siteDirectory, "link.json")
+	working, err = findProjectDirectory(cwd, sites, home)
 
-	_, err := os.Stat(linkConfigFile)
+	return app, sites, working, err
+}
 
-	if err != nil && os.IsNotExist(err) && cmd.Use == "start" {
-		err := os.MkdirAll(filepath.Dir(linkConfigFile), defaultDirPermissions)
-		if err != nil {
-			return err
-		}
+func saveLocalLinkConfig(cmd *cobra.Command, appDirectory, siteDirectory, workingDirectory string, isNamedSite bool) error {
+	link := workingDirectory
 
-		f, _ := os.Create(linkConfigFile)
-		defer f.Close()
+	if isNamedSite {
+		link = siteDirectory
+	}
 
-		jsonBytes, err := json.MarshalIndent(siteLink, "", "\t")
-		if err != nil {
-			return err
-		}
+	_, err := os.Stat(filepath.Join(siteDirectory, "link.json"))
 
-		_, err = f.Write(jsonBytes)
-		if err != nil {
-			return err
-		}
+	if err != nil && os.IsNotExist(err) && cmd.Use == "start" {
+		return writeSiteLink(appDirectory, siteDirectory, link)
 	}
 
 	return nil
@@ -49,3 +49,73 @@ func TestSettings_GetBool(t *testing.T) {
 		})
 	}
 }
+
+func TestSettings_Clone(t *testing.T) {
+	s := &Settings{
+		settings: []Setting{
+			{name: "name", currentValue: "plugin"},
+		},
+	}
+
+	clone := s.Clone()
+	clone.settings[0].currentValue = "plugin-test-wp6-4"
+
+	if s.Get("name") != "plugin" {
+		t.Errorf("Changing the clone changed the original name to %q", s.Get("name"))
+	}
+
+	if clone.Get("name") != "plugin-test-wp6-4" {
+		t.Errorf("Got %q, expected %q", clone.Get("name"), "plugin-test-wp6-4")
+	}
+}
+
+func TestLoadDatabaseVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		database string
+		version  string
+		source   string
+		expected string
+	}{
+		{"mariadb default", "mariadb", mariadbVersion, sourceDefault, mariadbVersion},
+		{"mysql default", "mysql", mariadbVersion, sourceDefault, mysqlVersion},
+		{"mysql pinned", "mysql", "8.0.36", sourceSite, "8.0.36"},
+		{"postgres default", "postgres", mariadbVersion, sourceDefault, postgresVersion},
+		{"sqlite default", "sqlite", mariadbVersion, sourceDefault, mariadbVersion},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Settings{
+				settings: []Setting{
+					{name: "database", currentValue: tt.database},
+					{name: "databaseVersion", currentValue: tt.version, source: tt.source},
+				},
+			}
+
+			loadDatabaseVersion(s)
+
+			if got := s.Get("databaseVersion"); got != tt.expected {
+				t.Errorf("Got %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRemoveLockedSettings(t *testing.T) {
+	config := map[string]interface{}{
+		"PHP":       "8.3",
+		"multisite": "subdomain",
+		"xdebug":    true,
+	}
+
+	removed := removeLockedSettings(config, []string{"php", "multisite"})
+
+	if len(removed) != 2 || removed[0] != "PHP" || removed[1] != "multisite" {
+		t.Errorf("Got %v, expected [PHP multisite]", removed)
+	}
+
+	if len(config) != 1 || config["xdebug"] != true {
+		t.Errorf("Got %v, expected only xdebug to remain", config)
+	}
+}
@@ -0,0 +1,154 @@
+package settings
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// EnsureSiteDirectory returns an error if the site's files were moved with `kana relocate` to a folder that can't be
+// found, such as one on an external disk that isn't connected.
+func EnsureSiteDirectory(siteDirectory string) error {
+	target, err := os.Readlink(siteDirectory)
+	if err != nil {
+		return nil
+	}
+
+	_, err = os.Stat(target)
+	if os.IsNotExist(err) {
+		return fmt.Errorf(
+			"the site's files are in %s, which can't be found. Connect the disk they are on or run `kana destroy` to remove the site",
+			target)
+	}
+
+	return err
+}
+
+// RelocateSite moves the current site's files, such as its WordPress files and database, to a folder named after the
+// site in the given directory, leaving a link to them in the app directory. An empty directory moves them back to the
+// app directory. The folder the site's files are now in is returned.
+func (s *Settings) RelocateSite(directory string) (string, error) {
+	linkDirectory := filepath.Join(s.Get("sitesDirectory"), s.Get("name"))
+	current := s.Get("siteDirectory")
+
+	if s.GetBool("isNew") {
+		return current, fmt.Errorf("the site %s hasn't been started yet. Start it before moving its files", s.Get("name"))
+	}
+
+	err := EnsureSiteDirectory(current)
+	if err != nil {
+		return current, err
+	}
+
+	destination := linkDirectory
+
+	if directory != "" {
+		destination, err = validateSiteLocation(directory, s.Get("name"), s.Get("appDirectory"), s.Get("sitesDirectory"))
+		if err != nil {
+			return current, err
+		}
+	}
+
+	if destination == current {
+		return current, fmt.Errorf("the site's files are already in %s", current)
+	}
+
+	if destination != linkDirectory {
+		if _, err = os.Lstat(destination); err == nil {
+			return current, fmt.Errorf("%s already exists. Move it out of the way or choose another directory", destination)
+		}
+	}
+
+	// The link has to go before files moved back to the app directory can take its place
+	if current != linkDirectory {
+		err = os.Remove(linkDirectory)
+		if err != nil {
+			return current, err
+		}
+	}
+
+	err = moveFiles(current, destination)
+	if err != nil {
+		if current != linkDirectory {
+			_ = os.Symlink(current, linkDirectory)
+		}
+
+		return current, err
+	}
+
+	if destination != linkDirectory {
+		err = os.Symlink(destination, linkDirectory)
+		if err != nil {
+			return destination, err
+		}
+	}
+
+	return destination, s.Set("siteDirectory", destination)
+}
+
+// RemoveSiteDirectory removes the current site's files along with the link to them left in the app directory if they
+// were moved with `kana relocate`.
+func (s *Settings) RemoveSiteDirectory() error {
+	err := os.RemoveAll(s.Get("siteDirectory"))
+	if err != nil {
+		return err
+	}
+
+	return os.RemoveAll(filepath.Join(s.Get("sitesDirectory"), s.Get("name")))
+}
+
+// resolveSiteDirectory returns the folder the site's files are in, following the link left in the app directory by
+// `kana relocate`. The link itself is returned if the folder it points to can't be found.
+func resolveSiteDirectory(siteDirectory string) string {
+	target, err := os.Readlink(siteDirectory)
+	if err != nil {
+		return siteDirectory
+	}
+
+	_, err = os.Stat(target)
+	if err != nil {
+		return siteDirectory
+	}
+
+	return target
+}
+
+// validateSiteLocation checks the site's files can be moved to the directory, which must be an existing folder, Kana can
+// write to, outside of the app and sites directories. The folder the site's files would be moved to is returned.
+func validateSiteLocation(directory, name string, kanaDirectories ...string) (string, error) {
+	if !filepath.IsAbs(directory) {
+		return "", fmt.Errorf("the directory to move the site's files to, %s, must be an absolute path", directory)
+	}
+
+	directory = filepath.Clean(directory)
+
+	for _, kanaDirectory := range kanaDirectories {
+		if directory == kanaDirectory || strings.HasPrefix(directory, kanaDirectory+string(filepath.Separator)) {
+			return "", fmt.Errorf("%s is in Kana's own directories. Use `kana relocate --reset` to move the site's files back there", directory)
+		}
+	}
+
+	info, err := os.Stat(directory)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("%s doesn't exist. Create it, or connect the disk it is on, and try again", directory)
+		}
+
+		return "", err
+	}
+
+	if !info.IsDir() {
+		return "", fmt.Errorf("%s isn't a directory", directory)
+	}
+
+	testFile, err := os.CreateTemp(directory, ".kana-*")
+	if err != nil {
+		return "", fmt.Errorf("kana can't write to %s: %w", directory, err)
+	}
+
+	_ = testFile.Close()
+	_ = os.Remove(testFile.Name())
+
+	return filepath.Join(directory, name), nil
+}
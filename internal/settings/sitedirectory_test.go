@@ -0,0 +1,75 @@
+package settings
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRelocateSite(t *testing.T) {
+	appDirectory := t.TempDir()
+	externalDisk := t.TempDir()
+	linkDirectory := filepath.Join(appDirectory, "sites", "client-site")
+
+	assert.NoError(t, os.MkdirAll(linkDirectory, 0750))
+	assert.NoError(t, os.WriteFile(filepath.Join(linkDirectory, "database.sql"), []byte("database"), 0600))
+
+	s := &Settings{
+		settings: []Setting{
+			{name: "appDirectory", currentValue: appDirectory},
+			{name: "sitesDirectory", currentValue: filepath.Join(appDirectory, "sites")},
+			{name: "siteDirectory", currentValue: linkDirectory},
+			{name: "name", currentValue: "client-site"},
+			{name: "isNew", currentValue: "false", settingType: "bool"},
+		},
+	}
+
+	_, err := s.RelocateSite(filepath.Join(appDirectory, "backups"))
+	assert.Error(t, err)
+
+	location, err := s.RelocateSite(externalDisk)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(externalDisk, "client-site"), location)
+	assert.Equal(t, location, s.Get("siteDirectory"))
+	assert.Equal(t, location, resolveSiteDirectory(linkDirectory))
+
+	// The site's files can still be reached through the app directory
+	contents, err := os.ReadFile(filepath.Join(linkDirectory, "database.sql"))
+	assert.NoError(t, err)
+	assert.Equal(t, "database", string(contents))
+
+	_, err = s.RelocateSite(externalDisk)
+	assert.Error(t, err)
+
+	location, err = s.RelocateSite("")
+	assert.NoError(t, err)
+	assert.Equal(t, linkDirectory, location)
+
+	info, err := os.Lstat(linkDirectory)
+	assert.NoError(t, err)
+	assert.True(t, info.IsDir())
+
+	_, err = os.Stat(filepath.Join(externalDisk, "client-site"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestEnsureSiteDirectory(t *testing.T) {
+	appDirectory := t.TempDir()
+	externalDisk := filepath.Join(t.TempDir(), "client-site")
+	linkDirectory := filepath.Join(appDirectory, "client-site")
+
+	assert.NoError(t, os.MkdirAll(externalDisk, 0750))
+	assert.NoError(t, os.Symlink(externalDisk, linkDirectory))
+
+	assert.NoError(t, EnsureSiteDirectory(appDirectory))
+	assert.NoError(t, EnsureSiteDirectory(linkDirectory))
+	assert.Equal(t, externalDisk, resolveSiteDirectory(linkDirectory))
+
+	// Disconnecting the disk leaves the link pointing nowhere
+	assert.NoError(t, os.RemoveAll(externalDisk))
+
+	assert.Error(t, EnsureSiteDirectory(linkDirectory))
+	assert.Equal(t, linkDirectory, resolveSiteDirectory(linkDirectory))
+}
@@ -39,50 +39,402 @@ function action_phpmailer_init( $phpmailer ) {
 	$phpmailer->Port = 1025;
 }
 
+{{- if .CatchMail }}
+
+/**
+ * Send email through a standard PHPMailer instance so SMTP plugins can't swap in their own mailer.
+ *
+ * Runs last so it also undoes any plugin that short-circuits wp_mail to send through an email API.
+ *
+ * @param null|bool $result Whether to short-circuit wp_mail.
+ *
+ * @return null Always null so wp_mail sends the email itself.
+ */
+function filter_pre_wp_mail( $result ) {
+	global $phpmailer;
+
+	if ( is_object( $phpmailer ) && 'PHPMailer\PHPMailer\PHPMailer' !== get_class( $phpmailer ) ) {
+		$phpmailer = null;
+	}
+
+	return null;
+}
+
+/**
+ * Override any SMTP settings from plugins so all email goes to Mailpit.
+ *
+ * @param PHPMailer $phpmailer The PHPMailer instance (passed by reference).
+ */
+function action_phpmailer_catch_all( $phpmailer ) {
+	action_phpmailer_init( $phpmailer );
+
+	$phpmailer->SMTPAuth    = false;
+	$phpmailer->SMTPSecure  = '';
+	$phpmailer->SMTPAutoTLS = false;
+	$phpmailer->Username    = '';
+	$phpmailer->Password    = '';
+}
+
+add_filter( 'pre_wp_mail', '\KanaCLI\filter_pre_wp_mail', PHP_INT_MAX );
+add_action( 'phpmailer_init', '\KanaCLI\action_phpmailer_catch_all', PHP_INT_MAX );
+{{- else }}
+
 add_action( 'phpmailer_init', '\KanaCLI\action_phpmailer_init' );
+{{- end }}
+
+{{- if .UpdateServer }}
+
+define( 'KANA_UPDATE_SERVER', '{{ .UpdateServer }}' );
 
 /**
- * Login to the WordPress admin automatically when visiting a WordPress admin URL.
+ * Offer the releases published to Kana's mock update server as updates to the plugins they're for.
+ *
+ * Runs last so it replaces whatever the plugin's own updater, or WordPress.org, offered.
+ *
+ * @param object $transient The update_plugins transient.
+ *
+ * @return object The transient with the published releases added.
  */
-function login_to_admin() {
-	if ( ! getenv('IS_KANA_ENVIRONMENT') === true
-		|| ! getenv('KANA_ADMIN_LOGIN') === true
-		|| ! is_admin()
-		|| is_user_logged_in() ) {
-		return;
+function filter_mock_plugin_updates( $transient ) {
+	if ( ! is_object( $transient ) || ! function_exists( 'get_plugins' ) ) {
+		return $transient;
+	}
+
+	$response = wp_remote_get( KANA_UPDATE_SERVER . '/index.json', array( 'timeout' => 5 ) );
+	$releases = json_decode( wp_remote_retrieve_body( $response ), true );
+
+	if ( ! is_array( $releases ) ) {
+		return $transient;
+	}
+
+	foreach ( get_plugins() as $file => $plugin ) {
+		$slug = dirname( $file );
+
+		if ( ! isset( $releases[ $slug ] ) ) {
+			continue;
+		}
+
+		$update = (object) array(
+			'id'          => $file,
+			'slug'        => $slug,
+			'plugin'      => $file,
+			'new_version' => $releases[ $slug ]['version'],
+			'package'     => $releases[ $slug ]['package'],
+			'url'         => KANA_UPDATE_SERVER,
+		);
+
+		unset( $transient->response[ $file ], $transient->no_update[ $file ] );
+
+		if ( version_compare( $releases[ $slug ]['version'], $plugin['Version'], '>' ) ) {
+			$transient->response[ $file ] = $update;
+		} else {
+			$transient->no_update[ $file ] = $update;
+		}
+	}
+
+	return $transient;
+}
+
+/**
+ * Allow packages to be downloaded from the mock update server, which isn't a public host.
+ *
+ * @param bool   $external Whether the host is allowed.
+ * @param string $host     The host being requested.
+ *
+ * @return bool True for the mock update server.
+ */
+function filter_mock_update_host( $external, $host ) {
+	if ( wp_parse_url( KANA_UPDATE_SERVER, PHP_URL_HOST ) === $host ) {
+		return true;
+	}
+
+	return $external;
+}
+
+add_filter( 'pre_set_site_transient_update_plugins', '\KanaCLI\filter_mock_plugin_updates', PHP_INT_MAX );
+add_filter( 'http_request_host_is_external', '\KanaCLI\filter_mock_update_host', 10, 2 );
+{{- end }}
+
+{{- if .WordPressAPI }}
+
+define( 'KANA_WORDPRESS_API', '{{ .WordPressAPI }}' );
+
+/**
+ * Send requests to WordPress.org through Kana's cache of its API, or its mock replies, instead.
+ *
+ * @param false|array|WP_Error $response A response to short-circuit the request with.
+ * @param array                $args     The request's arguments.
+ * @param string               $url      The URL being requested.
+ *
+ * @return false|array|WP_Error The response from Kana's cache, or the original value for other hosts.
+ */
+function filter_wordpress_api_request( $response, $args, $url ) {
+	$host = wp_parse_url( $url, PHP_URL_HOST );
+
+	if ( false !== $response || ! in_array( $host, array( 'api.wordpress.org', 'downloads.wordpress.org' ), true ) ) {
+		return $response;
 	}
 
-	$kana_error = '<p>Kana could not find a valid admin user to login to your site.</p>';
+	// The cache is on Kana's private network so WordPress would otherwise refuse to download packages from it.
+	$args['reject_unsafe_urls'] = false;
 
+	return wp_remote_request( KANA_WORDPRESS_API . '/' . preg_replace( '#^https?://#', '', $url ), $args );
+}
+
+add_filter( 'pre_http_request', '\KanaCLI\filter_wordpress_api_request', PHP_INT_MAX, 3 );
+{{- end }}
+
+/**
+ * Find the user to login automatically.
+ *
+ * @param string $login The username or role to login as. Defaults to the first administrator.
+ *
+ * @return WP_User|WP_Error The user to login or an error if no matching user exists.
+ */
+function get_login_user( $login ) {
+	if ( '' !== $login ) {
+		$user = get_user_by( 'login', $login );
+
+		if ( $user ) {
+			return $user;
+		}
+	}
 
 	$args = array(
-		'role'    => 'administrator',
+		'role'    => '' === $login ? 'administrator' : $login,
 		'orderby' => 'id',
 		'order'   => 'ASC',
-		'number'  => '2',
+		'number'  => '1',
 	);
 
 	$users = get_users( $args );
 
 	if ( empty( $users ) ) {
-		wp_die( $kana_error, 200 );
+		return new \WP_Error( 'kana_login', sprintf( 'There is no user or role named "%s".', esc_html( $login ) ) );
 	}
 
-	$user = $users[0];
+	return $users[0];
+}
 
-	if (! is_wp_error( $user)) {
-		wp_set_current_user( $user->ID, $user->user_login );
-		wp_set_auth_cookie( $user->ID );
+/**
+ * Login to the WordPress admin automatically when visiting a WordPress admin URL.
+ *
+ * Adding kana_login=<user or role> to any URL switches to that user, even if already logged in.
+ */
+function login_to_admin() {
+	static $logging_in = false;
+
+	if ( ! getenv('IS_KANA_ENVIRONMENT') === true
+		|| ! getenv('KANA_ADMIN_LOGIN') === true
+		|| $logging_in ) {
+		return;
+	}
+
+	// Never log in visitors to a site shared with `kana share` as anyone with the public URL could reach the dashboard.
+	if ( ! empty( $_SERVER['HTTP_CF_CONNECTING_IP'] ) ) {
+		return;
+	}
 
-		if ( isset( $_SERVER['REQUEST_URI'] ) ) {
-			wp_safe_redirect( $_SERVER['REQUEST_URI'] );
-			exit();
+	$login = isset( $_GET['kana_login'] ) ? sanitize_user( wp_unslash( $_GET['kana_login'] ) ) : '';
+
+	if ( '' === $login ) {
+		if ( ! is_admin() || is_user_logged_in() ) {
+			return;
 		}
+
+		$login = (string) getenv( 'KANA_LOGIN_USER' );
 	}
 
+	$logging_in = true;
+
+	$kana_error = '<p>Kana could not find a valid user to login to your site.</p>';
+
+	$user = get_login_user( $login );
+
 	if ( is_wp_error( $user ) ) {
 		wp_die( $user->get_error_message() . $kana_error, 200 );
 	}
+
+	if ( get_current_user_id() !== $user->ID ) {
+		wp_set_current_user( $user->ID, $user->user_login );
+		wp_set_auth_cookie( $user->ID );
+	}
+
+	if ( isset( $_SERVER['REQUEST_URI'] ) ) {
+		wp_safe_redirect( remove_query_arg( 'kana_login', $_SERVER['REQUEST_URI'] ) );
+		exit();
+	}
 }
 
 add_action( 'set_current_user', '\KanaCLI\login_to_admin' );
+
+/**
+ * Profile the request if Kana asked for it with the X-Kana-Profile header.
+ *
+ * The hook timings, query counts and memory use are saved in a transient named after the header's value so that
+ * `kana profile request` can read them with wp-cli once the request has finished.
+ */
+function start_profile() {
+	if ( ! getenv( 'IS_KANA_ENVIRONMENT' ) || empty( $_SERVER['HTTP_X_KANA_PROFILE'] ) ) {
+		return;
+	}
+
+	$profile_id = preg_replace( '/[^a-z0-9]/', '', strtolower( $_SERVER['HTTP_X_KANA_PROFILE'] ) );
+
+	if ( '' === $profile_id ) {
+		return;
+	}
+
+	// Queries made before the mu-plugins are loaded, such as the autoloaded options, aren't saved.
+	if ( ! defined( 'SAVEQUERIES' ) ) {
+		define( 'SAVEQUERIES', true );
+	}
+
+	$GLOBALS['kana_profile'] = array(
+		'id'      => $profile_id,
+		'hooks'   => array(),
+		'running' => array(),
+	);
+
+	add_action( 'all', '\KanaCLI\profile_hook' );
+	add_action( 'shutdown', '\KanaCLI\save_profile', PHP_INT_MAX );
+}
+
+/**
+ * Time a hook by adding callbacks to run first and last on it the first time it fires.
+ *
+ * The timings include any hooks fired by the hook's callbacks.
+ */
+function profile_hook() {
+	$hook = current_filter();
+
+	if ( isset( $GLOBALS['kana_profile']['hooks'][ $hook ] ) ) {
+		return;
+	}
+
+	$GLOBALS['kana_profile']['hooks'][ $hook ] = array(
+		'calls' => 0,
+		'time'  => 0,
+	);
+
+	add_filter(
+		$hook,
+		function ( $value = null ) use ( $hook ) {
+			$GLOBALS['kana_profile']['running'][ $hook ][] = microtime( true );
+
+			return $value;
+		},
+		PHP_INT_MIN
+	);
+
+	add_filter(
+		$hook,
+		function ( $value = null ) use ( $hook ) {
+			if ( ! empty( $GLOBALS['kana_profile']['running'][ $hook ] ) ) {
+				$started = array_pop( $GLOBALS['kana_profile']['running'][ $hook ] );
+
+				$GLOBALS['kana_profile']['hooks'][ $hook ]['calls']++;
+				$GLOBALS['kana_profile']['hooks'][ $hook ]['time'] += microtime( true ) - $started;
+			}
+
+			return $value;
+		},
+		PHP_INT_MAX
+	);
+}
+
+/**
+ * Save the slowest hooks and queries, along with the totals for the request, for Kana to read.
+ */
+function save_profile() {
+	global $wpdb;
+
+	$profile = $GLOBALS['kana_profile'];
+	$limit   = 50;
+
+	$hooks = array();
+
+	foreach ( $profile['hooks'] as $hook => $timing ) {
+		if ( $timing['calls'] > 0 ) {
+			$hooks[] = array(
+				'hook'  => $hook,
+				'calls' => $timing['calls'],
+				'time'  => $timing['time'],
+			);
+		}
+	}
+
+	usort(
+		$hooks,
+		function ( $a, $b ) {
+			return $b['time'] <=> $a['time'];
+		}
+	);
+
+	$queries = array();
+
+	foreach ( (array) $wpdb->queries as $query ) {
+		$queries[] = array(
+			'query'  => $query[0],
+			'time'   => (float) $query[1],
+			'caller' => $query[2],
+		);
+	}
+
+	usort(
+		$queries,
+		function ( $a, $b ) {
+			return $b['time'] <=> $a['time'];
+		}
+	);
+
+	$report = array(
+		'url'         => isset( $_SERVER['REQUEST_URI'] ) ? $_SERVER['REQUEST_URI'] : '',
+		'status'      => http_response_code(),
+		'time'        => microtime( true ) - $_SERVER['REQUEST_TIME_FLOAT'],
+		'memory'      => memory_get_peak_usage(),
+		'query_count' => $wpdb->num_queries,
+		'query_time'  => array_sum( array_column( $queries, 'time' ) ),
+		'hooks'       => array_slice( $hooks, 0, $limit ),
+		'queries'     => array_slice( $queries, 0, $limit ),
+	);
+
+	remove_action( 'all', '\KanaCLI\profile_hook' );
+
+	set_transient( 'kana_profile_' . $profile['id'], $report, HOUR_IN_SECONDS );
+}
+
+start_profile();
+
+/**
+ * Send WordPress's HTTP requests through the proxy server Kana passes on to the site's containers.
+ *
+ * WordPress only reads its own WP_PROXY_* constants so they're defined from the proxy environment variables.
+ */
+function define_proxy() {
+	$proxy = getenv( 'HTTPS_PROXY' ) ? getenv( 'HTTPS_PROXY' ) : getenv( 'HTTP_PROXY' );
+
+	if ( ! getenv( 'IS_KANA_ENVIRONMENT' ) || empty( $proxy ) || defined( 'WP_PROXY_HOST' ) ) {
+		return;
+	}
+
+	$proxy_url = wp_parse_url( $proxy );
+
+	if ( empty( $proxy_url['host'] ) ) {
+		return;
+	}
+
+	define( 'WP_PROXY_HOST', $proxy_url['host'] );
+	define( 'WP_PROXY_PORT', isset( $proxy_url['port'] ) ? $proxy_url['port'] : 80 );
+
+	if ( isset( $proxy_url['user'] ) ) {
+		define( 'WP_PROXY_USERNAME', urldecode( $proxy_url['user'] ) );
+		define( 'WP_PROXY_PASSWORD', isset( $proxy_url['pass'] ) ? urldecode( $proxy_url['pass'] ) : '' );
+	}
+
+	if ( getenv( 'NO_PROXY' ) ) {
+		define( 'WP_PROXY_BYPASS_HOSTS', getenv( 'NO_PROXY' ) );
+	}
+}
+
+define_proxy();
@@ -0,0 +1,28 @@
+<?php
+/**
+ * The WordPress test suite's config, written by Kana for `kana test`.
+ *
+ * The test suite empties the database it is given before each run so it has its own rather than the site's.
+ */
+
+define( 'ABSPATH', '{{ .WordPressPath }}/' );
+
+define( 'WP_DEFAULT_THEME', 'default' );
+define( 'WP_DEBUG', true );
+
+define( 'DB_NAME', '{{ .DatabaseName }}' );
+define( 'DB_USER', 'wordpress' );
+define( 'DB_PASSWORD', 'wordpress' );
+define( 'DB_HOST', '{{ .DatabaseHost }}' );
+define( 'DB_CHARSET', 'utf8' );
+define( 'DB_COLLATE', '' );
+
+$table_prefix = 'wptests_';
+
+define( 'WP_TESTS_DOMAIN', 'example.org' );
+define( 'WP_TESTS_EMAIL', 'admin@example.org' );
+define( 'WP_TESTS_TITLE', 'Test Blog' );
+
+define( 'WP_PHP_BINARY', 'php' );
+
+define( 'WPLANG', '' );
@@ -16,23 +16,48 @@ type File struct {
 
 // PluginVersion represents the name and version of a plugin to allow for better templating.
 type PluginVersion struct {
-	SiteName string
-	Version  string
+	SiteName     string
+	Version      string
+	CatchMail    bool
+	UpdateServer string // The URL of the site's mock update server, if it has one
+	WordPressAPI string // The URL WordPress.org API requests are sent to instead of WordPress.org, if any
+}
+
+// AutostartService represents the launchd agent or systemd service that runs Kana's autostart agent.
+type AutostartService struct {
+	Label        string
+	Executable   string
+	AppDirectory string
+	LogFile      string
+	Path         string // The PATH the agent runs with so it can find Docker
+	Restart      bool   // Whether the agent starts the sites that were running at shutdown again at login
+}
+
+// WPTestsConfig holds the values written to the WordPress test suite's wp-tests-config.php file.
+type WPTestsConfig struct {
+	WordPressPath string // Where WordPress is installed in the site's containers
+	DatabaseHost  string
+	DatabaseName  string
 }
 
 // A collection of all settings values used by Kana.
 type Settings struct {
-	settings []Setting
-	global   Koanf
-	local    Koanf
+	settings            []Setting
+	global              Koanf
+	local               Koanf
+	personal            Koanf
+	ignoredOverrides    []string
+	outdatedConfigFiles []string
 }
 
 // An individual setting and its associated data.
 type Setting struct {
 	defaultValue string
+	description  string
 	name         string
 	settingType  string
 	currentValue string
+	source       string
 	hasLocal     bool
 	hasGlobal    bool
 	hasStartFlag bool
@@ -40,6 +65,16 @@ type Setting struct {
 	validValues  []string
 }
 
+// Where the current value of a setting came from.
+const (
+	sourceDefault     = "default"
+	sourceEnvironment = "environment"
+	sourceFlag        = "flag"
+	sourceGlobal      = "global"
+	sourcePersonal    = "personal"
+	sourceSite        = "site"
+)
+
 // StartFlag represents the data needed to programmatically create a start flag.
 type StartFlag struct {
 	ShortName     string
@@ -0,0 +1,35 @@
+package settings
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ExtraUser is a test user, beyond the one for each core role, that Kana creates when seeding users.
+type ExtraUser struct {
+	Username string
+	Role     string
+}
+
+// ParseExtraUsers parses the extraUsers setting, where each user is in the form username=role.
+func ParseExtraUsers(entries []string) ([]ExtraUser, error) {
+	extraUsers := []ExtraUser{}
+
+	for _, entry := range entries {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+
+		username, role, found := strings.Cut(entry, "=")
+		username = strings.TrimSpace(username)
+		role = strings.TrimSpace(role)
+
+		if !found || username == "" || role == "" {
+			return extraUsers, fmt.Errorf("the extra user, %s, is not valid. Extra users must be in the form username=role", entry)
+		}
+
+		extraUsers = append(extraUsers, ExtraUser{Username: username, Role: role})
+	}
+
+	return extraUsers, nil
+}
@@ -0,0 +1,21 @@
+package settings
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseExtraUsers(t *testing.T) {
+	extraUsers, err := ParseExtraUsers([]string{"shop-manager=shop_manager", " reviewer = editor ", ""})
+	assert.NoError(t, err)
+	assert.Equal(t, []ExtraUser{
+		{Username: "shop-manager", Role: "shop_manager"},
+		{Username: "reviewer", Role: "editor"},
+	}, extraUsers)
+
+	for _, invalid := range []string{"reviewer", "=editor", "reviewer="} {
+		_, err = ParseExtraUsers([]string{invalid})
+		assert.Error(t, err, invalid)
+	}
+}
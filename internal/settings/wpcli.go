@@ -0,0 +1,68 @@
+package settings
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The wp-cli config files wp-cli itself looks for in a project, in order of precedence.
+var wpCliConfigFiles = []string{
+	"wp-cli.local.yml",
+	"wp-cli.yml",
+}
+
+// WPCliConfig is the project's wp-cli config file and the WordPress path set in it.
+// Everything else in the file, such as url, apache_modules, custom commands and aliases, is left to wp-cli.
+type WPCliConfig struct {
+	File string `yaml:"-"`
+	Path string `yaml:"path"`
+}
+
+// GetWPCliConfig returns the wp-cli config file set in the wpCliConfig setting or, if that isn't set,
+// the wp-cli.local.yml or wp-cli.yml file in the working directory. File is empty if the project has neither.
+func (s *Settings) GetWPCliConfig() (WPCliConfig, error) {
+	config := WPCliConfig{}
+
+	if s.Get("wpCliConfig") != "" {
+		config.File = s.Get("wpCliConfig")
+
+		if !filepath.IsAbs(config.File) {
+			config.File = filepath.Join(s.Get("workingDirectory"), config.File)
+		}
+
+		if _, err := os.Stat(config.File); err != nil {
+			return WPCliConfig{}, fmt.Errorf("the wp-cli config file %s could not be found", config.File)
+		}
+	} else {
+		for _, file := range wpCliConfigFiles {
+			if _, err := os.Stat(filepath.Join(s.Get("workingDirectory"), file)); err == nil {
+				config.File = filepath.Join(s.Get("workingDirectory"), file)
+				break
+			}
+		}
+
+		if config.File == "" {
+			return config, nil
+		}
+	}
+
+	contents, err := os.ReadFile(config.File)
+	if err != nil {
+		return WPCliConfig{}, err
+	}
+
+	err = yaml.Unmarshal(contents, &config)
+	if err != nil {
+		return WPCliConfig{}, fmt.Errorf("unable to read the wp-cli config file %s: %s", config.File, err)
+	}
+
+	// wp-cli treats a relative path as relative to the config file
+	if config.Path != "" && !filepath.IsAbs(config.Path) {
+		config.Path = filepath.Join(filepath.Dir(config.File), config.Path)
+	}
+
+	return config, nil
+}
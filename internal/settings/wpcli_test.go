@@ -0,0 +1,66 @@
+package settings
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSettings_GetWPCliConfig(t *testing.T) {
+	workingDirectory := t.TempDir()
+
+	s := &Settings{
+		settings: []Setting{
+			{name: "workingDirectory", currentValue: workingDirectory},
+			{name: "wpCliConfig", currentValue: ""},
+		},
+	}
+
+	config, err := s.GetWPCliConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, WPCliConfig{}, config)
+
+	err = os.WriteFile(filepath.Join(workingDirectory, "wp-cli.yml"), []byte("path: web/wp\nurl: https://example.com\n"), 0600)
+	assert.NoError(t, err)
+
+	config, err = s.GetWPCliConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(workingDirectory, "wp-cli.yml"), config.File)
+	assert.Equal(t, filepath.Join(workingDirectory, "web", "wp"), config.Path)
+
+	err = os.WriteFile(filepath.Join(workingDirectory, "wp-cli.local.yml"), []byte("require:\n  - commands.php\n"), 0600)
+	assert.NoError(t, err)
+
+	config, err = s.GetWPCliConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(workingDirectory, "wp-cli.local.yml"), config.File)
+	assert.Equal(t, "", config.Path)
+
+	err = os.MkdirAll(filepath.Join(workingDirectory, "config"), 0750)
+	assert.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(workingDirectory, "config", "cli.yml"), []byte("path: ../wordpress\n"), 0600)
+	assert.NoError(t, err)
+
+	s.settings[1].currentValue = "config/cli.yml"
+
+	config, err = s.GetWPCliConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(workingDirectory, "config", "cli.yml"), config.File)
+	assert.Equal(t, filepath.Join(workingDirectory, "wordpress"), config.Path)
+
+	s.settings[1].currentValue = "missing.yml"
+
+	_, err = s.GetWPCliConfig()
+	assert.Error(t, err)
+
+	s.settings[1].currentValue = ""
+
+	err = os.WriteFile(filepath.Join(workingDirectory, "wp-cli.local.yml"), []byte("path: [\n"), 0600)
+	assert.NoError(t, err)
+
+	_, err = s.GetWPCliConfig()
+	assert.Error(t, err)
+}
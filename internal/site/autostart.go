@@ -0,0 +1,336 @@
+package site
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ChrisWiegman/kana/internal/batch"
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/settings"
+
+	"github.com/mitchellh/go-homedir"
+)
+
+const (
+	autostartLabel     = "com.chriswiegman.kana.autostart"
+	autostartUnit      = "kana-autostart.service"
+	autostartSitesFile = "autostart.json"
+	autostartLogFile   = "autostart.log"
+
+	// Docker Desktop is often still starting when the agent starts at login so it is given a few minutes to come up
+	autostartDockerAttempts = 60
+	autostartDockerWait     = 5 * time.Second
+)
+
+// getAutostartFile returns the path of the launchd agent or systemd user service that runs the autostart agent.
+func getAutostartFile() (string, error) {
+	home, err := homedir.Dir()
+	if err != nil {
+		return "", err
+	}
+
+	switch runtime.GOOS {
+	case "darwin":
+		return filepath.Join(home, "Library", "LaunchAgents", fmt.Sprintf("%s.plist", autostartLabel)), nil
+	case "linux":
+		return filepath.Join(home, ".config", "systemd", "user", autostartUnit), nil
+	}
+
+	return "", fmt.Errorf("starting and stopping sites automatically is only available on macOS and Linux")
+}
+
+// InstallAutostart installs the agent that stops running sites cleanly at logout or shutdown and, if restart is true,
+// starts them again at login. It returns the path of the installed launchd agent or systemd user service.
+func (s *Site) InstallAutostart(restart bool) (string, error) {
+	autostartFile, err := getAutostartFile()
+	if err != nil {
+		return "", err
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+
+	executable, err = filepath.EvalSymlinks(executable)
+	if err != nil {
+		return "", err
+	}
+
+	contents, err := settings.GetAutostartService(runtime.GOOS, settings.AutostartService{
+		Label:        autostartLabel,
+		Executable:   executable,
+		AppDirectory: s.settings.Get("appDirectory"),
+		LogFile:      filepath.Join(s.settings.Get("appDirectory"), autostartLogFile),
+		Path:         os.Getenv("PATH"),
+		Restart:      restart,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	dirPermissions, filePermissions := settings.GetDefaultFilePermissions()
+
+	err = os.MkdirAll(filepath.Dir(autostartFile), os.FileMode(dirPermissions))
+	if err != nil {
+		return "", err
+	}
+
+	// Replace any agent that is already running without letting it stop the running sites
+	killAutostartAgent()
+
+	err = os.WriteFile(autostartFile, []byte(contents), os.FileMode(filePermissions))
+	if err != nil {
+		return "", err
+	}
+
+	commands := [][]string{
+		{"systemctl", "--user", "daemon-reload"},
+		{"systemctl", "--user", "enable", autostartUnit},
+		{"systemctl", "--user", "restart", autostartUnit},
+	}
+
+	if runtime.GOOS == "darwin" {
+		commands = [][]string{
+			{"launchctl", "bootstrap", fmt.Sprintf("gui/%d", os.Getuid()), autostartFile},
+		}
+	}
+
+	for _, command := range commands {
+		output, err := Command(command[0], command[1:]...).CombinedOutput()
+		if err != nil {
+			return "", fmt.Errorf("unable to load the autostart agent with %s: %s", command[0], output)
+		}
+	}
+
+	return autostartFile, nil
+}
+
+// UninstallAutostart removes the autostart agent. The running sites are left running.
+func (s *Site) UninstallAutostart() error {
+	autostartFile, err := getAutostartFile()
+	if err != nil {
+		return err
+	}
+
+	killAutostartAgent()
+
+	if runtime.GOOS == "linux" {
+		_ = Command("systemctl", "--user", "disable", autostartUnit).Run()
+	}
+
+	err = os.Remove(autostartFile)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if runtime.GOOS == "linux" {
+		return Command("systemctl", "--user", "daemon-reload").Run()
+	}
+
+	return nil
+}
+
+// killAutostartAgent stops the autostart agent, if it is running, without giving it the chance to stop the sites.
+func killAutostartAgent() {
+	if runtime.GOOS == "darwin" {
+		target := fmt.Sprintf("gui/%d/%s", os.Getuid(), autostartLabel)
+
+		_ = Command("launchctl", "kill", "SIGKILL", target).Run()
+		_ = Command("launchctl", "bootout", target).Run()
+
+		return
+	}
+
+	_ = Command("systemctl", "--user", "kill", "--signal=SIGKILL", autostartUnit).Run()
+}
+
+// RunAutostartAgent starts the sites that were running at the last shutdown again, if restart is true, and then waits
+// until it is stopped at logout or shutdown to stop every running site so their databases shut down cleanly.
+func (s *Site) RunAutostartAgent(restart bool, consoleOutput *console.Console) error {
+	if restart {
+		s.startAutostartSites(consoleOutput)
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+
+	<-signals
+
+	return s.stopAutostartSites(consoleOutput)
+}
+
+// startAutostartSites starts the sites that were running when the agent last stopped them.
+func (s *Site) startAutostartSites(consoleOutput *console.Console) {
+	sitesFile := filepath.Join(s.settings.Get("appDirectory"), autostartSitesFile)
+
+	contents, err := os.ReadFile(sitesFile)
+	if err != nil {
+		return
+	}
+
+	_ = os.Remove(sitesFile)
+
+	var sites []SiteInfo
+
+	err = json.Unmarshal(contents, &sites)
+	if err != nil || len(sites) == 0 {
+		return
+	}
+
+	err = s.waitForDocker(consoleOutput)
+	if err != nil {
+		consoleOutput.Warn(fmt.Sprintf("Unable to start the sites that were running at shutdown: %s", err))
+
+		return
+	}
+
+	for i := range sites {
+		consoleOutput.Println(fmt.Sprintf("Starting %s.", sites[i].Name))
+
+		err = s.runKanaForSite(&sites[i], "start", "--open=false")
+		if err != nil {
+			consoleOutput.Warn(fmt.Sprintf("Unable to start %s: %s", sites[i].Name, err))
+		}
+	}
+}
+
+// stopAutostartSites stops every running site, saving the list of them so they can be started again at login.
+func (s *Site) stopAutostartSites(consoleOutput *console.Console) error {
+	// Docker may have already stopped, and taken the sites with it, if the agent is the last thing to be stopped
+	err := s.EnsureDocker(consoleOutput)
+	if err != nil {
+		return nil
+	}
+
+	siteList, err := s.GetSiteList(true)
+	if err != nil {
+		return err
+	}
+
+	runningSites := []SiteInfo{}
+
+	for i := range siteList {
+		if siteList[i].Running {
+			runningSites = append(runningSites, siteList[i])
+		}
+	}
+
+	contents, err := json.Marshal(runningSites)
+	if err != nil {
+		return err
+	}
+
+	_, filePermissions := settings.GetDefaultFilePermissions()
+
+	err = os.WriteFile(filepath.Join(s.settings.Get("appDirectory"), autostartSitesFile), contents, os.FileMode(filePermissions))
+	if err != nil {
+		return err
+	}
+
+	for i := range runningSites {
+		consoleOutput.Println(fmt.Sprintf("Stopping %s.", runningSites[i].Name))
+
+		err = s.runKanaForSite(&runningSites[i], "stop")
+		if err != nil {
+			consoleOutput.Warn(fmt.Sprintf("Unable to stop %s: %s", runningSites[i].Name, err))
+		}
+	}
+
+	return nil
+}
+
+// waitForDocker waits for Docker to be available, such as while Docker Desktop starts at login.
+func (s *Site) waitForDocker(consoleOutput *console.Console) (err error) {
+	for attempt := 0; attempt < autostartDockerAttempts; attempt++ {
+		err = s.EnsureDocker(consoleOutput)
+		if err == nil {
+			return nil
+		}
+
+		time.Sleep(autostartDockerWait)
+	}
+
+	return err
+}
+
+// runKanaForSite runs a Kana command for the given site the same way it would be run from the site's folder.
+func (s *Site) runKanaForSite(siteInfo *SiteInfo, args ...string) error {
+	siteCommand, err := s.getKanaCommandForSite(siteInfo, args...)
+	if err != nil {
+		return err
+	}
+
+	siteCommand.Stdout = os.Stdout
+	siteCommand.Stderr = os.Stderr
+
+	return siteCommand.Run()
+}
+
+// RunKanaForSites runs a Kana command for each of the given sites, with no more than concurrency running at once, and
+// calls onDone as each finishes. Each site's output is kept to itself, rather than being interleaved with the others, with
+// the message a failed site ended with used as its error.
+func (s *Site) RunKanaForSites(
+	sites []SiteInfo,
+	concurrency int,
+	onDone func(result batch.Result, finished, total int),
+	args ...string) []batch.Result {
+	siteNames := make([]string, len(sites))
+	siteInfo := make(map[string]*SiteInfo, len(sites))
+
+	for i := range sites {
+		siteNames[i] = sites[i].Name
+		siteInfo[sites[i].Name] = &sites[i]
+	}
+
+	// JSON output lets the error be read back without the labels and colors added for a terminal
+	args = append(args, "--output-json")
+
+	return batch.Run(siteNames, concurrency, func(siteName string) error {
+		siteCommand, err := s.getKanaCommandForSite(siteInfo[siteName], args...)
+		if err != nil {
+			return err
+		}
+
+		output, err := siteCommand.CombinedOutput()
+		if err != nil {
+			lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+
+			var message console.Message
+
+			if json.Unmarshal([]byte(lines[len(lines)-1]), &message) == nil && message.Message != "" {
+				return errors.New(message.Message)
+			}
+		}
+
+		return err
+	}, onDone)
+}
+
+// getKanaCommandForSite returns a Kana command that runs for the given site as if it were run from the site's folder.
+func (s *Site) getKanaCommandForSite(siteInfo *SiteInfo, args ...string) (*exec.Cmd, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+
+	siteCommand := Command(executable, args...)
+	siteCommand.Dir = siteInfo.Path
+
+	// Named sites don't have a folder of their own to be run from
+	if siteInfo.Path == "" {
+		siteCommand = Command(executable, append(args, fmt.Sprintf("--name=%s", siteInfo.Name))...)
+		siteCommand.Dir = filepath.Join(s.settings.Get("sitesDirectory"), siteInfo.Name)
+	}
+
+	return siteCommand, nil
+}
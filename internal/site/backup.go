@@ -0,0 +1,424 @@
+package site
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/helpers"
+	"github.com/ChrisWiegman/kana/internal/storage"
+)
+
+type BackupInfo struct {
+	Name    string
+	Path    string
+	Size    int64
+	Created time.Time
+	Full    bool // A full backup of the site, created with CreateFullBackup, rather than of its database
+}
+
+const backupTimeFormat = "20060102-150405"
+
+// getBackupDirectory returns the directory used to store the site's backups, creating it if needed.
+func (s *Site) getBackupDirectory() (string, error) {
+	backupDirectory := filepath.Join(s.settings.Get("siteDirectory"), "backups")
+
+	err := os.MkdirAll(backupDirectory, os.FileMode(defaultDirPermissions))
+	if err != nil {
+		return "", err
+	}
+
+	return backupDirectory, nil
+}
+
+// GetBackup returns the backup with the given name.
+func (s *Site) GetBackup(name string) (BackupInfo, error) {
+	backups, err := s.GetBackups()
+	if err != nil {
+		return BackupInfo{}, err
+	}
+
+	for _, backup := range backups {
+		if backup.Name == name {
+			return backup, nil
+		}
+	}
+
+	return BackupInfo{}, fmt.Errorf("the backup %s could not be found. Use `kana backup list` to see available backups", name)
+}
+
+// GetBackups returns all backups for the current site, newest first.
+func (s *Site) GetBackups() ([]BackupInfo, error) {
+	backups := []BackupInfo{}
+
+	backupDirectory, err := s.getBackupDirectory()
+	if err != nil {
+		return backups, err
+	}
+
+	files, err := os.ReadDir(backupDirectory)
+	if err != nil {
+		return backups, err
+	}
+
+	prefix := fmt.Sprintf("kana-%s-", s.settings.Get("name"))
+
+	for _, file := range files {
+		if file.IsDir() || !strings.HasPrefix(file.Name(), prefix) {
+			continue
+		}
+
+		timeStamp := strings.TrimPrefix(file.Name(), prefix)
+		timeStamp = strings.TrimSuffix(timeStamp, filepath.Ext(timeStamp))
+
+		created, err := time.ParseInLocation(backupTimeFormat, timeStamp, time.Local)
+		if err != nil {
+			continue
+		}
+
+		info, err := file.Info()
+		if err != nil {
+			return backups, err
+		}
+
+		backups = append(backups, BackupInfo{
+			Name:    file.Name(),
+			Path:    filepath.Join(backupDirectory, file.Name()),
+			Size:    info.Size(),
+			Created: created,
+			Full:    filepath.Ext(file.Name()) == ".zip",
+		})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Created.After(backups[j].Created)
+	})
+
+	return backups, nil
+}
+
+// CreateBackup writes a dated dump of the site's database to the backup directory.
+func (s *Site) CreateBackup(consoleOutput *console.Console) (string, error) {
+	backupDirectory, err := s.getBackupDirectory()
+	if err != nil {
+		return "", err
+	}
+
+	backupName := fmt.Sprintf("kana-%s-%s", s.settings.Get("name"), time.Now().Format(backupTimeFormat))
+
+	isUsingSQLite, err := s.isUsingSQLite()
+	if err != nil {
+		return "", err
+	}
+
+	if isUsingSQLite {
+		backupFile := filepath.Join(backupDirectory, backupName+".sqlite")
+
+		err = helpers.CopyFile(
+			s.GetSQLiteDatabaseFile(),
+			backupFile)
+		if err != nil {
+			return backupFile, err
+		}
+
+		s.recordHistory("backup created", filepath.Base(backupFile))
+
+		return backupFile, nil
+	}
+
+	backupName += ".sql"
+
+	err = s.dumpDatabase(filepath.Join("backups", backupName), consoleOutput)
+	if err != nil {
+		return "", fmt.Errorf("database backup failed: %s", err)
+	}
+
+	s.recordHistory("backup created", backupName)
+
+	return filepath.Join(backupDirectory, backupName), nil
+}
+
+// CreateFullBackup writes an archive of the whole site, with its database, wp-content folder, including the plugins and
+// themes being developed, and config, that RestoreBackup can rebuild the site from. The archive is saved to the backup
+// directory unless a file or an existing folder, absolute or relative to the current directory, is given. Saving it
+// outside of the site's directory keeps it when the site is destroyed.
+func (s *Site) CreateFullBackup(destination, version string, consoleOutput *console.Console) (string, error) {
+	backupName := fmt.Sprintf("kana-%s-%s.zip", s.settings.Get("name"), time.Now().Format(backupTimeFormat))
+
+	backupFile, err := s.getFullBackupFile(destination, backupName)
+	if err != nil {
+		return "", err
+	}
+
+	file, err := os.Create(backupFile)
+	if err != nil {
+		return "", err
+	}
+
+	defer file.Close()
+
+	err = s.writeExportArchive(helpers.NewArchive(file), ExportParts, version, consoleOutput)
+	if err != nil {
+		_ = file.Close()
+		_ = os.Remove(backupFile)
+
+		return "", fmt.Errorf("full backup failed: %s", err)
+	}
+
+	s.recordHistory("backup created", filepath.Base(backupFile))
+
+	return backupFile, nil
+}
+
+// getFullBackupFile returns the path a full backup with the given name is saved to.
+func (s *Site) getFullBackupFile(destination, backupName string) (string, error) {
+	if destination == "" {
+		backupDirectory, err := s.getBackupDirectory()
+		if err != nil {
+			return "", err
+		}
+
+		return filepath.Join(backupDirectory, backupName), nil
+	}
+
+	if !filepath.IsAbs(destination) {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return "", err
+		}
+
+		destination = filepath.Join(cwd, destination)
+	}
+
+	info, err := os.Stat(destination)
+	if err == nil && info.IsDir() {
+		return filepath.Join(destination, backupName), nil
+	}
+
+	return destination, nil
+}
+
+// maybeBackup creates a new backup if the configured backup interval has passed since the last one.
+func (s *Site) maybeBackup(consoleOutput *console.Console) error {
+	interval := s.settings.GetInt("backupInterval")
+	if interval <= 0 {
+		return nil
+	}
+
+	backups, err := s.GetBackups()
+	if err != nil {
+		return err
+	}
+
+	hours := 24 * interval
+
+	// Full backups are made by hand so only the scheduled database backups count
+	databaseBackups := slices.DeleteFunc(backups, func(backup BackupInfo) bool { return backup.Full })
+
+	if len(databaseBackups) > 0 && databaseBackups[0].Created.After(time.Now().Add(time.Duration(-hours)*time.Hour)) {
+		return nil
+	}
+
+	defer consoleOutput.StartPhase("Scheduled backup")()
+
+	consoleOutput.Println("Creating a scheduled backup of the site database.")
+
+	_, err = s.CreateBackup(consoleOutput)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.PruneBackups(s.settings.GetInt("backupRetention"))
+
+	return err
+}
+
+// PruneBackups removes all but the newest database backups as defined by the retention count and returns the removed
+// backups. Full backups are never removed as they're made by hand, such as before destroying the site.
+func (s *Site) PruneBackups(retention int64) ([]BackupInfo, error) {
+	removed := []BackupInfo{}
+
+	backups, err := s.GetBackups()
+	if err != nil {
+		return removed, err
+	}
+
+	for _, backup := range getPrunableBackups(backups, retention) {
+		err = os.Remove(backup.Path)
+		if err != nil {
+			return removed, err
+		}
+
+		removed = append(removed, backup)
+	}
+
+	return removed, nil
+}
+
+// getPrunableBackups returns the database backups, from a list sorted newest first, that are older than the newest
+// retention of them. Nothing is returned if retention isn't positive.
+func getPrunableBackups(backups []BackupInfo, retention int64) []BackupInfo {
+	prunable := []BackupInfo{}
+
+	if retention <= 0 {
+		return prunable
+	}
+
+	kept := int64(0)
+
+	for _, backup := range backups {
+		if backup.Full {
+			continue
+		}
+
+		if kept < retention {
+			kept++
+
+			continue
+		}
+
+		prunable = append(prunable, backup)
+	}
+
+	return prunable
+}
+
+// PushBackup uploads the given backup or export file to the configured remote backup target.
+func (s *Site) PushBackup(filePath string) error {
+	target := storage.S3Target{
+		Endpoint:  s.settings.Get("backupRemoteEndpoint"),
+		Bucket:    s.settings.Get("backupRemoteBucket"),
+		Region:    s.settings.Get("backupRemoteRegion"),
+		AccessKey: s.settings.Get("backupRemoteAccessKey"),
+	}
+
+	if target.AccessKey != "" {
+		secretKey, err := storage.GetSecretKey(target.AccessKey)
+		if err != nil {
+			return err
+		}
+
+		target.SecretKey = secretKey
+	}
+
+	err := target.Validate()
+	if err != nil {
+		return err
+	}
+
+	return target.Upload(filePath, fmt.Sprintf("%s/%s", s.settings.Get("name"), filepath.Base(filePath)))
+}
+
+// RestoreBackup replaces the site's current database with the named backup. A full backup, which can also be given as
+// the path to its archive, replaces the site's wp-content files and config too.
+func (s *Site) RestoreBackup(name string, consoleOutput *console.Console) error {
+	if filepath.Ext(name) == ".zip" {
+		return s.restoreFullBackup(name, consoleOutput)
+	}
+
+	backup, err := s.GetBackup(name)
+	if err != nil {
+		return err
+	}
+
+	isUsingSQLite, err := s.isUsingSQLite()
+	if err != nil {
+		return err
+	}
+
+	if filepath.Ext(backup.Name) == ".sqlite" {
+		if !isUsingSQLite {
+			return fmt.Errorf("the backup %s is a SQLite database and cannot be restored to a site that doesn't use SQLite", name)
+		}
+
+		err = helpers.CopyFile(
+			backup.Path,
+			s.GetSQLiteDatabaseFile())
+		if err != nil {
+			return err
+		}
+
+		s.recordHistory("backup restored", backup.Name)
+
+		return nil
+	}
+
+	if isUsingSQLite {
+		return fmt.Errorf("the backup %s is a SQL dump and cannot be restored to a SQLite site", name)
+	}
+
+	err = s.restoreDatabaseDump(filepath.Join("backups", backup.Name), consoleOutput)
+	if err != nil {
+		return fmt.Errorf("backup restore failed: %s", err)
+	}
+
+	s.recordHistory("backup restored", backup.Name)
+
+	return nil
+}
+
+// restoreFullBackup restores every part of a full backup, from the site's backups or the given archive, into the site.
+// Differences from the site the backup came from, such as its PHP version, are shown as warnings as it's the same site
+// being put back.
+func (s *Site) restoreFullBackup(name string, consoleOutput *console.Console) error {
+	backupFile := name
+
+	backup, err := s.GetBackup(name)
+	if err == nil {
+		backupFile = backup.Path
+	}
+
+	_, err = s.importArchive(backupFile, true, consoleOutput)
+	if err != nil {
+		return fmt.Errorf("backup restore failed: %s", err)
+	}
+
+	s.recordHistory("backup restored", filepath.Base(backupFile))
+
+	return nil
+}
+
+// dumpDatabase writes a SQL dump of the site's MariaDB, MySQL or PostgreSQL database to the given path, relative to the
+// site's directory so the WordPress container can reach it through its /Site mount.
+func (s *Site) dumpDatabase(relativePath string, consoleOutput *console.Console) error {
+	// wp db export only works with MariaDB and MySQL so PostgreSQL databases are dumped with pg_dump
+	if s.settings.Get("database") == "postgres" {
+		return s.streamExport(filepath.Join(s.settings.Get("siteDirectory"), relativePath), consoleOutput)
+	}
+
+	return s.wpCliOrError([]string{"db", "export", "--add-drop-table", path.Join("/Site", filepath.ToSlash(relativePath))}, consoleOutput)
+}
+
+// restoreDatabaseDump replaces the site's MariaDB, MySQL or PostgreSQL database with the SQL dump at the given path,
+// relative to the site's directory.
+func (s *Site) restoreDatabaseDump(relativePath string, consoleOutput *console.Console) error {
+	if s.settings.Get("database") == "postgres" {
+		err := s.resetDatabase(consoleOutput)
+		if err != nil {
+			return err
+		}
+
+		return s.streamImport(filepath.Join(s.settings.Get("siteDirectory"), relativePath), consoleOutput)
+	}
+
+	commands := [][]string{
+		{"db", "drop", "--yes"},
+		{"db", "create"},
+		{"db", "import", path.Join("/Site", filepath.ToSlash(relativePath))},
+	}
+
+	for _, command := range commands {
+		err := s.wpCliStreamOrError(command, consoleOutput)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
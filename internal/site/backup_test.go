@@ -0,0 +1,43 @@
+package site
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPrunableBackups(t *testing.T) {
+	now := time.Now()
+
+	backups := []BackupInfo{
+		{Name: "kana-site-5.sql", Created: now},
+		{Name: "kana-site-4.zip", Created: now.Add(-1 * time.Hour), Full: true},
+		{Name: "kana-site-3.sql", Created: now.Add(-2 * time.Hour)},
+		{Name: "kana-site-2.zip", Created: now.Add(-3 * time.Hour), Full: true},
+		{Name: "kana-site-1.sql", Created: now.Add(-4 * time.Hour)},
+	}
+
+	tests := []struct {
+		name      string
+		retention int64
+		expected  []string
+	}{
+		{"keeps full backups", 1, []string{"kana-site-3.sql", "kana-site-1.sql"}},
+		{"keeps the newest database backups", 2, []string{"kana-site-1.sql"}},
+		{"keeps everything within the retention", 3, []string{}},
+		{"prunes nothing without a retention", 0, []string{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pruned := []string{}
+
+			for _, backup := range getPrunableBackups(backups, test.retention) {
+				pruned = append(pruned, backup.Name)
+			}
+
+			assert.Equal(t, test.expected, pruned)
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package site
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+)
+
+const bisectProbeTimeout = 60 * time.Second
+
+// BisectProbe checks whether the problem being bisected happens with only the given plugins active.
+type BisectProbe func(active []string) (broken bool, err error)
+
+// BisectResult is the outcome of bisecting the site's active plugins.
+type BisectResult struct {
+	Plugins []string `json:"plugins"` // The plugins that cause the problem. More than one means they conflict.
+	Steps   int      `json:"steps"`   // The number of times the problem was checked for
+}
+
+// BisectPlugins deactivates halves of the site's active plugins, checking for the problem with the probe each time,
+// until it finds the plugin, or the plugins that conflict with each other, causing it. The plugins that were active
+// when it started are always active again when it returns.
+func (s *Site) BisectPlugins(probe BisectProbe, consoleOutput *console.Console) (result BisectResult, err error) {
+	plugins, err := s.GetExtensions("plugin", consoleOutput)
+	if err != nil {
+		return result, err
+	}
+
+	original := []string{}
+
+	for _, plugin := range plugins {
+		if plugin.Status == "active" {
+			original = append(original, plugin.Name)
+		}
+	}
+
+	if len(original) == 0 {
+		return result, fmt.Errorf("there are no active plugins to bisect")
+	}
+
+	defer func() {
+		restoreErr := s.setActivePlugins(original, original, consoleOutput)
+		if err == nil {
+			err = restoreErr
+		}
+	}()
+
+	check := func(active []string) (bool, error) {
+		result.Steps++
+
+		consoleOutput.Println(fmt.Sprintf("Step %d: checking with %d of %d plugin(s) active.", result.Steps, len(active), len(original)))
+
+		err := s.setActivePlugins(active, original, consoleOutput)
+		if err != nil {
+			return false, err
+		}
+
+		return probe(active)
+	}
+
+	broken, err := check(original)
+	if err != nil {
+		return result, err
+	}
+
+	if !broken {
+		return result, fmt.Errorf("the problem doesn't happen with all of the plugins active so there is nothing to bisect")
+	}
+
+	broken, err = check([]string{})
+	if err != nil {
+		return result, err
+	}
+
+	if broken {
+		return result, fmt.Errorf("the problem still happens with every plugin deactivated so it isn't caused by a plugin")
+	}
+
+	result.Plugins, err = bisectPlugins(original, []string{}, check)
+
+	return result, err
+}
+
+// GetURLProbe returns a probe that treats the problem as happening when a page of the site, given as a path or a
+// URL on the site's domain, doesn't return the status or doesn't contain the text.
+func (s *Site) GetURLProbe(pageURL string, status int, contains string) (BisectProbe, error) {
+	probeURL, err := s.getSitePageURL(pageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(active []string) (bool, error) {
+		statusCode, body, err := getPage(probeURL, http.Header{}, bisectProbeTimeout)
+		if err != nil {
+			return false, err
+		}
+
+		return statusCode != status || !strings.Contains(string(body), contains), nil
+	}, nil
+}
+
+// bisectPlugins finds the fewest of the candidates that cause the problem when they're active along with the
+// plugins that must stay active for it to happen.
+func bisectPlugins(candidates, required []string, check BisectProbe) ([]string, error) {
+	if len(candidates) == 1 {
+		return candidates, nil
+	}
+
+	first := candidates[:len(candidates)/2]
+	second := candidates[len(candidates)/2:]
+
+	broken, err := check(slices.Concat(required, first))
+	if err != nil {
+		return []string{}, err
+	}
+
+	if broken {
+		return bisectPlugins(first, required, check)
+	}
+
+	broken, err = check(slices.Concat(required, second))
+	if err != nil {
+		return []string{}, err
+	}
+
+	if broken {
+		return bisectPlugins(second, required, check)
+	}
+
+	// Neither half causes the problem on its own so plugins from each half conflict with each other
+	fromFirst, err := bisectPlugins(first, slices.Concat(required, second), check)
+	if err != nil {
+		return []string{}, err
+	}
+
+	fromSecond, err := bisectPlugins(second, slices.Concat(required, fromFirst), check)
+	if err != nil {
+		return []string{}, err
+	}
+
+	return slices.Concat(fromFirst, fromSecond), nil
+}
+
+// setActivePlugins activates the given plugins and deactivates the rest of the plugins being bisected.
+func (s *Site) setActivePlugins(active, bisected []string, consoleOutput *console.Console) error {
+	inactive := []string{}
+
+	for _, plugin := range bisected {
+		if !slices.Contains(active, plugin) {
+			inactive = append(inactive, plugin)
+		}
+	}
+
+	if len(inactive) > 0 {
+		err := s.ManageExtensions("plugin", "deactivate", inactive, false, consoleOutput)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(active) > 0 {
+		return s.ManageExtensions("plugin", "activate", active, false, consoleOutput)
+	}
+
+	return nil
+}
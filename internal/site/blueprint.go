@@ -0,0 +1,423 @@
+package site
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/settings"
+)
+
+const (
+	blueprintVersion      = 1
+	blueprintDatabaseFile = "blueprint.sql"
+
+	// DefaultBlueprintFile is the name blueprints are saved as when no file is given.
+	DefaultBlueprintFile = "kana-blueprint.json"
+)
+
+// Blueprint is a single file describing a site, its config, the versions of WordPress, its plugins and theme and,
+// optionally, its database, that `kana blueprint apply` uses to recreate the site anywhere.
+type Blueprint struct {
+	BlueprintVersion int              `json:"blueprintVersion"`
+	KanaVersion      string           `json:"kanaVersion"`
+	Domain           string           `json:"domain"`
+	WordPressVersion string           `json:"wordPressVersion"`
+	PHPVersion       string           `json:"phpVersion"`
+	Settings         json.RawMessage  `json:"settings"`
+	Plugins          []ManifestPlugin `json:"plugins"`
+	Theme            BlueprintTheme   `json:"theme"`
+	Database         string           `json:"database,omitempty"`
+}
+
+// BlueprintTheme is the theme active on the site a blueprint came from.
+type BlueprintTheme struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// ExportBlueprint writes a blueprint of the site, including a dump of its database if withDatabase is true, to a file.
+// The blueprint is saved to DefaultBlueprintFile in the current directory unless a path, absolute or relative to the current
+// directory, is given.
+func (s *Site) ExportBlueprint(args []string, withDatabase bool, version string, consoleOutput *console.Console) (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+
+	blueprintFile := filepath.Join(cwd, DefaultBlueprintFile)
+
+	if len(args) == 1 {
+		blueprintFile = args[0]
+
+		if !filepath.IsAbs(blueprintFile) {
+			blueprintFile = filepath.Join(cwd, blueprintFile)
+		}
+	}
+
+	blueprint, err := s.getBlueprint(withDatabase, version, consoleOutput)
+	if err != nil {
+		return "", err
+	}
+
+	blueprintJSON, err := json.MarshalIndent(blueprint, "", "\t")
+	if err != nil {
+		return "", err
+	}
+
+	_, filePermissions := settings.GetDefaultFilePermissions()
+
+	return blueprintFile, os.WriteFile(blueprintFile, blueprintJSON, os.FileMode(filePermissions))
+}
+
+// getBlueprint returns the blueprint of the running site.
+func (s *Site) getBlueprint(withDatabase bool, version string, consoleOutput *console.Console) (Blueprint, error) {
+	blueprint := Blueprint{
+		BlueprintVersion: blueprintVersion,
+		KanaVersion:      version,
+		Domain:           s.settings.GetDomain(),
+		PHPVersion:       s.settings.Get("php"),
+		Plugins:          []ManifestPlugin{},
+	}
+
+	consoleOutput.Println("Adding the site's config.")
+
+	localSettings, err := s.getExportedConfig(consoleOutput)
+	if err != nil {
+		return blueprint, err
+	}
+
+	blueprint.Settings, err = s.settings.MarshalLocalSettings(localSettings)
+	if err != nil {
+		return blueprint, err
+	}
+
+	blueprint.WordPressVersion, err = s.getWordPressVersion(consoleOutput)
+	if err != nil {
+		return blueprint, err
+	}
+
+	consoleOutput.Println("Adding the plugins and theme.")
+
+	blueprint.Plugins, blueprint.Theme, err = s.getBlueprintExtensions(consoleOutput)
+	if err != nil {
+		return blueprint, err
+	}
+
+	if withDatabase {
+		consoleOutput.Println("Adding the database.")
+
+		blueprint.Database, err = s.getBlueprintDatabase(consoleOutput)
+		if err != nil {
+			return blueprint, fmt.Errorf("unable to export the site's database: %s", err)
+		}
+	}
+
+	return blueprint, nil
+}
+
+// getBlueprintExtensions returns the plugins that can be installed from WordPress.org, and the active theme, of the site.
+// Plugins and themes being developed in the site are part of the project rather than the blueprint so aren't included.
+func (s *Site) getBlueprintExtensions(consoleOutput *console.Console) ([]ManifestPlugin, BlueprintTheme, error) {
+	plugins := []ManifestPlugin{}
+	theme := BlueprintTheme{}
+
+	mountedPlugins, err := s.getMountedProjects("plugin")
+	if err != nil {
+		return plugins, theme, err
+	}
+
+	installedPlugins, err := s.GetExtensions("plugin", consoleOutput)
+	if err != nil {
+		return plugins, theme, err
+	}
+
+	for _, plugin := range installedPlugins {
+		_, isMounted := mountedPlugins[plugin.Name]
+
+		// Must-use plugins and drop-ins can't be installed with wp-cli
+		if isMounted || !slices.Contains([]string{"active", "active-network", "inactive"}, plugin.Status) {
+			continue
+		}
+
+		plugins = append(plugins, ManifestPlugin{
+			Name:    plugin.Name,
+			Version: plugin.Version,
+			Status:  plugin.Status,
+		})
+	}
+
+	installedThemes, err := s.GetExtensions("theme", consoleOutput)
+	if err != nil {
+		return plugins, theme, err
+	}
+
+	for _, installedTheme := range installedThemes {
+		if installedTheme.Status == "active" {
+			theme = BlueprintTheme{Name: installedTheme.Name, Version: installedTheme.Version}
+		}
+	}
+
+	return plugins, theme, nil
+}
+
+// getBlueprintDatabase returns a dump of the site's MariaDB, MySQL or PostgreSQL database.
+func (s *Site) getBlueprintDatabase(consoleOutput *console.Console) (string, error) {
+	isUsingSQLite, err := s.isUsingSQLite()
+	if err != nil {
+		return "", err
+	}
+
+	if isUsingSQLite {
+		return "", fmt.Errorf("the database of a site using SQLite can't be added to a blueprint")
+	}
+
+	err = s.dumpDatabase(blueprintDatabaseFile, consoleOutput)
+	if err != nil {
+		return "", err
+	}
+
+	databaseFile := filepath.Join(s.settings.Get("siteDirectory"), blueprintDatabaseFile)
+
+	defer os.Remove(databaseFile)
+
+	database, err := os.ReadFile(databaseFile)
+
+	return string(database), err
+}
+
+// ReadBlueprint reads a blueprint file, rejecting unknown fields so typos in shared blueprints don't go unnoticed.
+func ReadBlueprint(file string) (Blueprint, error) {
+	blueprint := Blueprint{}
+
+	contents, err := os.ReadFile(file)
+	if err != nil {
+		return blueprint, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(contents))
+	decoder.DisallowUnknownFields()
+
+	err = decoder.Decode(&blueprint)
+	if err != nil {
+		return blueprint, fmt.Errorf("the blueprint %s is not valid: %s", file, err)
+	}
+
+	if blueprint.BlueprintVersion < 1 || blueprint.BlueprintVersion > blueprintVersion {
+		return blueprint, fmt.Errorf(
+			"the blueprint was created by a version of Kana, %s, that this version can't apply. Please update Kana and try again",
+			blueprint.KanaVersion)
+	}
+
+	if len(blueprint.Settings) == 0 {
+		return blueprint, fmt.Errorf("the blueprint %s is not valid: it has no settings", file)
+	}
+
+	return blueprint, nil
+}
+
+// ApplyBlueprint recreates the site from a blueprint. The blueprint's config replaces the site's .kana.json file and the site
+// is started, or restarted, with it before the blueprint's database is imported and its versions of WordPress, plugins and
+// theme are installed. Plugins that can't be installed, such as those not on WordPress.org, are reported but don't stop the
+// rest of the blueprint being applied.
+func (s *Site) ApplyBlueprint(ctx context.Context, blueprint *Blueprint, consoleOutput *console.Console) error {
+	// New sites need to be linked to their folder as `kana start` would
+	if s.settings.GetBool("isNew") {
+		err := s.linkNewSite()
+		if err != nil {
+			return err
+		}
+	}
+
+	// Named sites keep their config in their own folder, as sites created with `kana clone` do
+	if s.settings.GetBool("isNamed") {
+		err := s.settings.Set("workingDirectory", s.settings.Get("siteDirectory"))
+		if err != nil {
+			return err
+		}
+	}
+
+	err := s.settings.ApplyLocalConfig(blueprint.Settings)
+	if err != nil {
+		return fmt.Errorf("the blueprint's settings are not valid: %s", err)
+	}
+
+	err = s.StartSite(ctx, false, consoleOutput)
+	if err != nil {
+		return err
+	}
+
+	err = s.applyBlueprintWordPressVersion(blueprint.WordPressVersion, consoleOutput)
+	if err != nil {
+		return err
+	}
+
+	if blueprint.Database != "" {
+		err = s.applyBlueprintDatabase(blueprint, consoleOutput)
+		if err != nil {
+			return fmt.Errorf("unable to import the blueprint's database: %s", err)
+		}
+	}
+
+	err = s.applyBlueprintPlugins(blueprint.Plugins, consoleOutput)
+	if err != nil {
+		return err
+	}
+
+	err = s.applyBlueprintTheme(blueprint.Theme, consoleOutput)
+	if err != nil {
+		return err
+	}
+
+	s.recordHistory("blueprint applied", fmt.Sprintf("WordPress %s, PHP %s", blueprint.WordPressVersion, blueprint.PHPVersion))
+
+	return nil
+}
+
+// linkNewSite saves the link between a new site and its folder, or for named sites their own folder in the app directory.
+func (s *Site) linkNewSite() error {
+	if s.settings.GetBool("isNamed") {
+		return s.settings.UnlinkSite(s.settings.Get("name"))
+	}
+
+	return s.settings.LinkSite(s.settings.Get("name"), s.settings.Get("workingDirectory"))
+}
+
+// applyBlueprintWordPressVersion installs the blueprint's version of WordPress if the site is running a different one.
+func (s *Site) applyBlueprintWordPressVersion(version string, consoleOutput *console.Console) error {
+	if version == "" {
+		return nil
+	}
+
+	currentVersion, err := s.getWordPressVersion(consoleOutput)
+	if err != nil || currentVersion == version {
+		return err
+	}
+
+	consoleOutput.Println(fmt.Sprintf("Installing WordPress %s.", version))
+
+	commands := [][]string{
+		{"core", "update", fmt.Sprintf("--version=%s", version), "--force"},
+		{"core", "update-db"},
+	}
+
+	for _, command := range commands {
+		err = s.wpCliStreamOrError(command, consoleOutput)
+		if err != nil {
+			return fmt.Errorf("unable to install WordPress %s: %s", version, err)
+		}
+	}
+
+	return nil
+}
+
+// applyBlueprintDatabase replaces the site's database with the blueprint's, replacing the domain it was exported from.
+func (s *Site) applyBlueprintDatabase(blueprint *Blueprint, consoleOutput *console.Console) error {
+	consoleOutput.Println("Importing the blueprint's database.")
+
+	_, filePermissions := settings.GetDefaultFilePermissions()
+	databaseFile := filepath.Join(s.settings.Get("siteDirectory"), blueprintDatabaseFile)
+
+	err := os.WriteFile(databaseFile, []byte(blueprint.Database), os.FileMode(filePermissions))
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(databaseFile)
+
+	err = s.restoreDatabaseDump(blueprintDatabaseFile, consoleOutput)
+	if err != nil {
+		return err
+	}
+
+	if blueprint.Domain != "" && blueprint.Domain != s.settings.GetDomain() {
+		return s.replaceDomain(blueprint.Domain, consoleOutput)
+	}
+
+	return nil
+}
+
+// applyBlueprintPlugins installs the blueprint's version of each of its plugins that the site doesn't already have and
+// activates or deactivates them to match the blueprint.
+func (s *Site) applyBlueprintPlugins(plugins []ManifestPlugin, consoleOutput *console.Console) error {
+	installedPlugins, err := s.GetExtensions("plugin", consoleOutput)
+	if err != nil {
+		return err
+	}
+
+	installedVersions := map[string]string{}
+
+	for _, plugin := range installedPlugins {
+		installedVersions[plugin.Name] = plugin.Version
+	}
+
+	for _, plugin := range plugins {
+		if installedVersions[plugin.Name] != plugin.Version {
+			consoleOutput.Println(
+				fmt.Sprintf("Installing plugin:  %s %s", consoleOutput.Bold(consoleOutput.Blue(plugin.Name)), plugin.Version))
+
+			err = s.wpCliOrError(
+				[]string{"plugin", "install", plugin.Name, fmt.Sprintf("--version=%s", plugin.Version), "--force"},
+				consoleOutput)
+			if err != nil {
+				consoleOutput.Warn(fmt.Sprintf("Unable to install the plugin %s %s: %s", plugin.Name, plugin.Version, err))
+
+				continue
+			}
+		}
+
+		command := []string{"plugin", "deactivate", plugin.Name}
+
+		switch plugin.Status {
+		case "active":
+			command = []string{"plugin", "activate", plugin.Name}
+		case "active-network":
+			command = []string{"plugin", "activate", plugin.Name, "--network"}
+		}
+
+		err = s.wpCliOrError(command, consoleOutput)
+		if err != nil {
+			consoleOutput.Warn(fmt.Sprintf("Unable to %s the plugin %s: %s", command[1], plugin.Name, err))
+		}
+	}
+
+	return nil
+}
+
+// applyBlueprintTheme installs the blueprint's version of its theme, if the site doesn't already have it, and activates it.
+func (s *Site) applyBlueprintTheme(theme BlueprintTheme, consoleOutput *console.Console) error {
+	if theme.Name == "" {
+		return nil
+	}
+
+	installedThemes, err := s.GetExtensions("theme", consoleOutput)
+	if err != nil {
+		return err
+	}
+
+	for _, installedTheme := range installedThemes {
+		if installedTheme.Name == theme.Name && (installedTheme.Version == theme.Version || theme.Version == "") {
+			return s.wpCliOrError([]string{"theme", "activate", theme.Name}, consoleOutput)
+		}
+	}
+
+	consoleOutput.Println(fmt.Sprintf("Installing theme:  %s %s", consoleOutput.Bold(consoleOutput.Blue(theme.Name)), theme.Version))
+
+	command := []string{"theme", "install", theme.Name, "--force", "--activate"}
+
+	if theme.Version != "" {
+		command = append(command, fmt.Sprintf("--version=%s", theme.Version))
+	}
+
+	err = s.wpCliOrError(command, consoleOutput)
+	if err != nil {
+		return fmt.Errorf("unable to install the theme %s: %s", theme.Name, err)
+	}
+
+	return nil
+}
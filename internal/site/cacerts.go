@@ -0,0 +1,160 @@
+package site
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ChrisWiegman/kana/internal/docker"
+	"github.com/ChrisWiegman/kana/internal/helpers"
+	"github.com/ChrisWiegman/kana/internal/settings"
+
+	"github.com/docker/docker/api/types/mount"
+)
+
+const (
+	caCertificateFile       = "kana-ca.crt"
+	caBundleFile            = "ca-bundle.crt"
+	caIniFile               = "kana-ca.ini"
+	caContainerDirectory    = "/kana/ca"
+	systemCABundle          = "/etc/ssl/certs/ca-certificates.crt"
+	systemCACertificatesDir = "/usr/local/share/ca-certificates"
+)
+
+// getCACertificateMounts returns the mounts that give the WordPress and CLI containers the CA certificates the site
+// trusts: Kana's root certificate, so the site can call other Kana sites over HTTPS, and any in the caCertificates
+// setting, such as a company proxy's that intercepts HTTPS. PHP is set to use the system's trust store, which the
+// certificates are added to, for curl and OpenSSL.
+func (s *Site) getCACertificateMounts() ([]mount.Mount, error) {
+	caDirectory := s.getCADirectory()
+
+	err := os.MkdirAll(caDirectory, os.FileMode(defaultDirPermissions))
+	if err != nil {
+		return []mount.Mount{}, err
+	}
+
+	var certificates bytes.Buffer
+
+	rootCert := filepath.Join(s.settings.Get("appDirectory"), "certs", s.settings.Get("rootCert"))
+
+	hasRootCert, err := helpers.PathExists(rootCert)
+	if err != nil {
+		return []mount.Mount{}, err
+	}
+
+	if hasRootCert {
+		var rootCertContents []byte
+
+		rootCertContents, err = os.ReadFile(rootCert)
+		if err != nil {
+			return []mount.Mount{}, err
+		}
+
+		certificates.Write(rootCertContents)
+	}
+
+	extraCertificates, err := settings.ReadCACertificates(s.settings.GetSlice("caCertificates"), s.settings.Get("workingDirectory"))
+	if err != nil {
+		return []mount.Mount{}, err
+	}
+
+	certificates.Write(extraCertificates)
+
+	_, filePermissions := settings.GetDefaultFilePermissions()
+	certificateFile := filepath.Join(caDirectory, caCertificateFile)
+
+	err = os.WriteFile(certificateFile, certificates.Bytes(), os.FileMode(filePermissions))
+	if err != nil {
+		return []mount.Mount{}, err
+	}
+
+	iniFile := filepath.Join(s.settings.Get("siteDirectory"), "config", caIniFile)
+
+	err = os.MkdirAll(filepath.Dir(iniFile), os.FileMode(defaultDirPermissions))
+	if err != nil {
+		return []mount.Mount{}, err
+	}
+
+	iniSettings := fmt.Sprintf("curl.cainfo = %[1]s\nopenssl.cafile = %[1]s\n", systemCABundle)
+
+	err = os.WriteFile(iniFile, []byte(iniSettings), 0o644) //nolint:gosec
+	if err != nil {
+		return []mount.Mount{}, err
+	}
+
+	return []mount.Mount{
+		{
+			Type:   mount.TypeBind,
+			Source: caDirectory,
+			Target: caContainerDirectory,
+		},
+		{
+			Type:     mount.TypeBind,
+			Source:   certificateFile,
+			Target:   filepath.ToSlash(filepath.Join(systemCACertificatesDir, caCertificateFile)),
+			ReadOnly: true,
+		},
+		{
+			Type:     mount.TypeBind,
+			Source:   iniFile,
+			Target:   filepath.ToSlash(filepath.Join("/usr/local/etc/php/conf.d", caIniFile)),
+			ReadOnly: true,
+		},
+	}, nil
+}
+
+// trustCACertificates adds the site's CA certificates to the WordPress container's trust store. The resulting bundle
+// is saved to the site directory so the CLI, Composer and Node containers, which only run for a command, trust the same
+// certificates without having to rebuild their own.
+func (s *Site) trustCACertificates() error {
+	output, err := s.WordPress("update-ca-certificates", false, true)
+	if err != nil {
+		return err
+	}
+
+	if output.ExitCode != 0 {
+		return fmt.Errorf("unable to add the CA certificates to the site: %s", strings.TrimSpace(output.StdErr))
+	}
+
+	// Copied as the host's user so the file can be replaced and removed on the host
+	output, err = s.WordPress(fmt.Sprintf("cp %s %s/%s", systemCABundle, caContainerDirectory, caBundleFile), false, false)
+	if err != nil {
+		return err
+	}
+
+	if output.ExitCode != 0 {
+		return fmt.Errorf("unable to save the site's CA certificates: %s", strings.TrimSpace(output.StdErr))
+	}
+
+	return nil
+}
+
+// addCACertificates gives a container the trust store saved from the WordPress container, so tools such as Composer
+// and npm trust the same certificates as the site. Nothing is added before the site has first been started.
+func (s *Site) addCACertificates(container *docker.ContainerConfig) {
+	bundleFile := filepath.Join(s.getCADirectory(), caBundleFile)
+
+	hasBundle, err := helpers.PathExists(bundleFile)
+	if err != nil || !hasBundle {
+		return
+	}
+
+	container.Volumes = append(container.Volumes, mount.Mount{
+		Type:     mount.TypeBind,
+		Source:   bundleFile,
+		Target:   systemCABundle,
+		ReadOnly: true,
+	})
+
+	// Node has its own list of certificates and only adds those from the system that it's told about
+	container.Env = append(container.Env,
+		fmt.Sprintf("SSL_CERT_FILE=%s", systemCABundle),
+		fmt.Sprintf("NODE_EXTRA_CA_CERTS=%s", systemCABundle))
+}
+
+// getCADirectory returns the folder, in the site directory, holding the CA certificates the site trusts.
+func (s *Site) getCADirectory() string {
+	return filepath.Join(s.settings.Get("siteDirectory"), "ca")
+}
@@ -1,62 +1,152 @@
 package site
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
+	"os"
 	"os/exec"
+	"path"
+	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/ChrisWiegman/kana/internal/console"
 	"github.com/ChrisWiegman/kana/internal/docker"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/mount"
 )
 
+// wpCliConfigDirectory is where the directory holding the project's wp-cli config file is mounted in the CLI container.
+const wpCliConfigDirectory = "/kana/wp-cli"
+
+// execContainers are the site containers that `kana exec` and `kana shell` can run commands in.
+var execContainers = []string{"database", "mailpit", "redis", "wordpress"}
+
+// shellCommand opens bash where the container has it, such as the WordPress container, and sh in those that don't.
+var shellCommand = []string{"sh", "-c", "if command -v bash > /dev/null 2>&1; then exec bash; else exec sh; fi"}
+
 func Command(name string, arg ...string) *exec.Cmd {
 	return exec.Command(name, arg...)
 }
 
-// RunWPCli Runs a wp-cli command returning it's output and any errors.
-func (s *Site) WPCli(command []string, interactive bool, consoleOutput *console.Console) (statusCode int64, output string, err error) {
-	mounts := s.dockerClient.ContainerGetMounts(fmt.Sprintf("kana-%s-wordpress", s.settings.Get("name")))
+// getTypeFromMounts returns the type of a running site from the plugins and themes mounted into it.
+// It returns an empty string if no plugins or themes are mounted.
+func (s *Site) getTypeFromMounts(mounts []types.MountPoint) string {
+	mountedType := ""
+	projects := 0
 
 	for _, mount := range mounts {
-		if strings.Contains(mount.Destination, "/var/www/html/wp-content/plugins/") {
-			err = s.settings.Set("type", "plugin")
-			if err != nil {
-				return 1, "", err
+		for _, projectType := range []string{"plugin", "theme"} {
+			if !strings.Contains(mount.Destination, fmt.Sprintf("/var/www/html/wp-content/%ss/", projectType)) {
+				continue
 			}
-		}
 
-		if strings.Contains(mount.Destination, "/var/www/html/wp-content/themes/") {
-			err = s.settings.Set("type", "theme")
-			if err != nil {
-				return 1, "", err
+			projects++
+			mountedType = projectType
+
+			// A single plugin or theme is always mounted using the site's name
+			if path.Base(mount.Destination) != s.settings.Get("name") {
+				return "monorepo"
 			}
 		}
 	}
 
-	wordPressDirectory, err := s.getWordPressDirectory()
+	if projects > 1 {
+		return "monorepo"
+	}
+
+	return mountedType
+}
+
+// RunWPCli Runs a wp-cli command returning it's output and any errors. Most commands are run for Kana's own use, such
+// as reading an option's value, so the output is only shown as it runs in verbose mode.
+func (s *Site) WPCli(command []string, interactive bool, consoleOutput *console.Console) (statusCode int64, output string, err error) {
+	return s.runWPCli(command, interactive, consoleOutput.Debug && !consoleOutput.JSON, consoleOutput)
+}
+
+// wpCliStream runs a long wp-cli command, such as a core download or a database import, showing its output as it runs,
+// unless using JSON output, so it isn't silent for minutes.
+func (s *Site) wpCliStream(command []string, consoleOutput *console.Console) (statusCode int64, output string, err error) {
+	return s.runWPCli(command, false, !consoleOutput.JSON, consoleOutput)
+}
+
+func (s *Site) runWPCli(
+	command []string,
+	interactive, showOutput bool,
+	consoleOutput *console.Console) (statusCode int64, output string, err error) {
+	mounts := s.dockerClient.ContainerGetMounts(fmt.Sprintf("kana-%s-wordpress", s.settings.Get("name")))
+
+	mountedType := s.getTypeFromMounts(mounts)
+	if mountedType != "" {
+		err = s.settings.Set("type", mountedType)
+		if err != nil {
+			return 1, "", err
+		}
+	}
+
+	container, err := s.getCliContainer(consoleOutput)
 	if err != nil {
 		return 1, "", err
 	}
 
-	appVolumes, err := s.getWordPressMounts(wordPressDirectory)
+	wordPressPath, err := s.getWordPressPath()
 	if err != nil {
 		return 1, "", err
 	}
 
 	fullCommand := []string{
 		"wp",
-		"--path=/var/www/html",
+		fmt.Sprintf("--path=%s", wordPressPath),
 	}
 
 	fullCommand = append(fullCommand, command...)
 
+	var liveOutput io.Writer
+
+	if showOutput {
+		liveOutput = os.Stdout
+	}
+
+	// PostgreSQL sites need PHP's PostgreSQL extension, which is only built once in the long-lived CLI container
+	isUsingPostgres := slices.Contains(container.Env, "KANA_POSTGRES=true")
+
+	// Interactive commands always get their own container so they can have a TTY
+	if (s.settings.GetBool("persistentCli") || isUsingPostgres) && !interactive {
+		return s.runPersistentCli(&container, fullCommand, liveOutput)
+	}
+
+	container.Command = fullCommand
+
+	code, output, err := s.dockerClient.ContainerRunAndClean(&container, interactive, liveOutput)
+	if err != nil {
+		return code, "", err
+	}
+
+	return code, output, nil
+}
+
+// getCliContainer returns the container configuration used to run wp-cli against the site, making sure its image is available.
+func (s *Site) getCliContainer(consoleOutput *console.Console) (docker.ContainerConfig, error) {
+	wordPressDirectory, err := s.getWordPressDirectory()
+	if err != nil {
+		return docker.ContainerConfig{}, err
+	}
+
+	appVolumes, err := s.getWordPressMounts(wordPressDirectory)
+	if err != nil {
+		return docker.ContainerConfig{}, err
+	}
+
 	envVars := []string{
 		"IS_KANA_ENVIRONMENT=true",
 	}
 
 	isUsingSQLite, err := s.isUsingSQLite()
 	if err != nil {
-		return 1, "", err
+		return docker.ContainerConfig{}, err
 	}
 
 	if isUsingSQLite {
@@ -70,34 +160,164 @@ func (s *Site) WPCli(command []string, interactive bool, consoleOutput *console.
 			"WORDPRESS_ADMIN_USER=admin")
 	}
 
+	isUsingPostgres, err := s.isUsingPostgres()
+	if err != nil {
+		return docker.ContainerConfig{}, err
+	}
+
+	if isUsingPostgres {
+		envVars = append(envVars, "KANA_POSTGRES=true")
+	}
+
 	container := docker.ContainerConfig{
 		Name:        fmt.Sprintf("kana-%s-wordpress_cli", s.settings.Get("name")),
-		Image:       fmt.Sprintf("wordpress:cli-php%s", s.settings.Get("php")),
+		Image:       s.getCliImage(),
 		NetworkName: "kana",
 		HostName:    fmt.Sprintf("kana-%s-wordpress_cli", s.settings.Get("name")),
-		Command:     fullCommand,
 		Env:         envVars,
 		Labels: map[string]string{
 			"kana.site": s.settings.Get("name"),
 		},
 		Volumes: appVolumes,
+		Init:    true,
 	}
 
 	if s.settings.GetBool("AutomaticLogin") {
 		container.Env = append(container.Env, "KANA_ADMIN_LOGIN=true")
 	}
 
-	err = s.dockerClient.EnsureImage(container.Image, s.settings.Get("appDirectory"), s.settings.GetInt("updateInterval"), consoleOutput)
+	container.Env = append(container.Env, s.getGitEnv(getGitSafeDirectories(s.settings.Get("workingDirectory"), appVolumes))...)
+
+	s.addProxy(&container)
+	s.addCACertificates(&container)
+
+	err = s.addSSHAgent(&container)
 	if err != nil {
-		return 1, "", err
+		return docker.ContainerConfig{}, err
 	}
 
-	code, output, err := s.dockerClient.ContainerRunAndClean(&container, interactive)
+	wpCliConfig, err := s.settings.GetWPCliConfig()
 	if err != nil {
-		return code, "", err
+		return docker.ContainerConfig{}, err
 	}
 
-	return code, output, nil
+	// Mount the config file's whole directory so files it requires, such as custom commands, are available too
+	if wpCliConfig.File != "" {
+		container.Volumes = append(container.Volumes, mount.Mount{
+			Type:     mount.TypeBind,
+			Source:   filepath.Dir(wpCliConfig.File),
+			Target:   wpCliConfigDirectory,
+			ReadOnly: true,
+		})
+
+		container.Env = append(container.Env,
+			fmt.Sprintf("WP_CLI_CONFIG_PATH=%s", path.Join(wpCliConfigDirectory, filepath.Base(wpCliConfig.File))))
+	}
+
+	err = s.dockerClient.EnsureImage(
+		context.Background(),
+		container.Image,
+		s.settings.Get("appDirectory"),
+		s.settings.GetInt("updateInterval"),
+		consoleOutput)
+
+	return container, err
+}
+
+// getCliImage returns the image used to run wp-cli. This is the cliImage setting if it is set or, otherwise,
+// the official WordPress CLI image for the site's PHP version and, if set, the wpCliVersion setting.
+func (s *Site) getCliImage() string {
+	if s.settings.Get("cliImage") != "" {
+		return s.settings.Get("cliImage")
+	}
+
+	if s.settings.Get("wpCliVersion") != "" {
+		return fmt.Sprintf("wordpress:cli-%s-php%s", s.settings.Get("wpCliVersion"), s.settings.Get("php"))
+	}
+
+	return fmt.Sprintf("wordpress:cli-php%s", s.settings.Get("php"))
+}
+
+// getWordPressPath returns the path of WordPress in the CLI container. This is the path set in the project's wp-cli config,
+// such as a WordPress core subdirectory, when it is inside the site's WordPress directory.
+func (s *Site) getWordPressPath() (string, error) {
+	wpCliConfig, err := s.settings.GetWPCliConfig()
+	if err != nil || wpCliConfig.Path == "" {
+		return "/var/www/html", err
+	}
+
+	wordPressDirectory, err := s.getWordPressDirectory()
+	if err != nil {
+		return "", err
+	}
+
+	relativePath, err := filepath.Rel(wordPressDirectory, wpCliConfig.Path)
+	if err != nil || relativePath == ".." || strings.HasPrefix(relativePath, ".."+string(filepath.Separator)) {
+		return "/var/www/html", nil
+	}
+
+	return path.Join("/var/www/html", filepath.ToSlash(relativePath)), nil
+}
+
+// runPersistentCli Runs a wp-cli command in the site's long-lived CLI container, starting the container if needed.
+func (s *Site) runPersistentCli(container *docker.ContainerConfig, command []string, liveOutput io.Writer) (int64, string, error) {
+	container.Name = fmt.Sprintf("kana-%s-cli", s.settings.Get("name"))
+	container.HostName = container.Name
+
+	// Keep the container idle, exiting promptly when the site is stopped
+	container.Command = []string{
+		"sh",
+		"-c",
+		"trap 'exit 0' TERM; while true; do sleep 1; done",
+	}
+
+	_, err := s.dockerClient.ContainerRun(context.Background(), container, false, true)
+	if err != nil {
+		return 1, "", err
+	}
+
+	if slices.Contains(container.Env, "KANA_POSTGRES=true") {
+		err = s.ensurePostgresCli(container.Name)
+		if err != nil {
+			return 1, "", err
+		}
+	}
+
+	var buffer bytes.Buffer
+
+	var output io.Writer = &buffer
+
+	if liveOutput != nil {
+		output = io.MultiWriter(&buffer, liveOutput)
+	}
+
+	code, err := s.dockerClient.ContainerExecStream(container.Name, false, command, nil, output, output)
+
+	return int64(code), buffer.String(), err
+}
+
+// Exec Runs an arbitrary command in one of the site's containers, streaming its output to the terminal, and returns its exit code.
+func (s *Site) Exec(containerName string, command []string, root bool) (int, error) {
+	if !slices.Contains(execContainers, containerName) {
+		return 1, fmt.Errorf("invalid container %s. Valid containers are %s", containerName, strings.Join(execContainers, ", "))
+	}
+
+	return s.dockerClient.ContainerExecStream(
+		fmt.Sprintf("kana-%s-%s", s.settings.Get("name"), containerName),
+		root,
+		command,
+		os.Stdin,
+		os.Stdout,
+		os.Stderr)
+}
+
+// Shell Opens an interactive shell in one of the site's containers and returns its exit code once the user leaves it.
+func (s *Site) Shell(containerName string, root bool) (int, error) {
+	if !slices.Contains(execContainers, containerName) {
+		return 1, fmt.Errorf("invalid container %s. Valid containers are %s", containerName, strings.Join(execContainers, ", "))
+	}
+
+	return s.dockerClient.ContainerExecInteractive(fmt.Sprintf("kana-%s-%s", s.settings.Get("name"), containerName), root, shellCommand)
 }
 
 // runCli Runs an arbitrary CLI command against the site's WordPress container.
@@ -0,0 +1,212 @@
+package site
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/helpers"
+)
+
+const cloneDatabaseFile = "clone.sql"
+
+// CloneSite copies the site, which must be running, to a new named site and starts it. The WordPress files, including the
+// plugins and themes being developed, and the database are copied, the domain in the database is replaced with the new
+// site's and the site's running config is saved in the new site's folder so it starts the same way each time.
+// If the clone fails the new site is removed. The new site's name and URL are returned.
+func (s *Site) CloneSite(ctx context.Context, target string, consoleOutput *console.Console) (name, siteURL string, err error) {
+	name = helpers.SanitizeSiteName(target)
+
+	if name == s.settings.Get("name") {
+		return "", "", fmt.Errorf("a site can't be cloned to itself. Please choose a different name for the new site")
+	}
+
+	_, err = os.Stat(filepath.Join(s.settings.Get("sitesDirectory"), name))
+	if err == nil {
+		return "", "", fmt.Errorf("a site named %s already exists. Please choose a different name or destroy it first", name)
+	}
+
+	config, err := s.getExportedConfig(consoleOutput)
+	if err != nil {
+		return "", "", err
+	}
+
+	// The new site isn't linked to a project so it always runs as a plain site
+	config["type"] = DefaultType
+
+	cloneSite, err := s.newNamedSite(name)
+	if err != nil {
+		return "", "", err
+	}
+
+	err = s.cloneTo(ctx, cloneSite, config, consoleOutput)
+	if err != nil {
+		_ = cloneSite.StopSite()
+		_ = os.RemoveAll(cloneSite.settings.Get("siteDirectory"))
+
+		return "", "", err
+	}
+
+	s.recordHistory("cloned", fmt.Sprintf("to %s", name))
+	cloneSite.recordHistory("cloned", fmt.Sprintf("from %s", s.settings.Get("name")))
+
+	return name, cloneSite.settings.GetURL(), nil
+}
+
+// cloneTo copies the site's config, files and database to the new site and starts it.
+func (s *Site) cloneTo(ctx context.Context, cloneSite *Site, config map[string]interface{}, consoleOutput *console.Console) error {
+	err := cloneSite.applyClonedConfig(config)
+	if err != nil {
+		return err
+	}
+
+	err = s.copyWordPressFiles(cloneSite, consoleOutput)
+	if err != nil {
+		return fmt.Errorf("unable to copy the site's files: %s", err)
+	}
+
+	isUsingSQLite, err := s.isUsingSQLite()
+	if err != nil {
+		return err
+	}
+
+	// SQLite databases are copied along with the WordPress files
+	if !isUsingSQLite {
+		err = s.copyDatabaseDump(cloneSite, consoleOutput)
+		if err != nil {
+			return fmt.Errorf("unable to copy the site's database: %s", err)
+		}
+	}
+
+	err = cloneSite.StartSite(ctx, false, consoleOutput)
+	if err != nil {
+		return err
+	}
+
+	if !isUsingSQLite {
+		consoleOutput.Println("Importing the database into the new site.")
+
+		err = cloneSite.restoreDatabaseDump(cloneDatabaseFile, consoleOutput)
+		if err != nil {
+			return fmt.Errorf("unable to import the site's database: %s", err)
+		}
+
+		_ = os.Remove(filepath.Join(cloneSite.settings.Get("siteDirectory"), cloneDatabaseFile))
+	}
+
+	return cloneSite.replaceDomain(s.settings.GetDomain(), consoleOutput)
+}
+
+// newNamedSite returns a copy of the site under the given name, linked to its own folder in the app directory so it has
+// its own WordPress installation.
+func (s *Site) newNamedSite(name string) (*Site, error) {
+	namedSettings := s.settings.Clone()
+
+	namedSettingValues := map[string]interface{}{
+		"name":          name,
+		"siteDirectory": filepath.Join(s.settings.Get("sitesDirectory"), name),
+		"isNamed":       true,
+		"isNew":         true,
+	}
+
+	for setting, value := range namedSettingValues {
+		err := namedSettings.Set(setting, value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := namedSettings.UnlinkSite(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Site{
+		dockerClient:           s.dockerClient,
+		maxVerificationRetries: s.maxVerificationRetries,
+		settings:               namedSettings,
+	}, nil
+}
+
+// applyClonedConfig uses the config of the site being cloned for the new site and saves it in the new site's folder,
+// where it is loaded from whenever the new site is used.
+func (s *Site) applyClonedConfig(config map[string]interface{}) error {
+	err := s.settings.Set("workingDirectory", s.settings.Get("siteDirectory"))
+	if err != nil {
+		return err
+	}
+
+	for setting, value := range config {
+		err = s.settings.Set(setting, value)
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.settings.WriteLocalSettings(config)
+}
+
+// copyWordPressFiles copies the site's WordPress files, and the plugins and themes being developed in it, to the new site.
+// wp-config.php isn't copied as each site's is created by its own WordPress container.
+func (s *Site) copyWordPressFiles(cloneSite *Site, consoleOutput *console.Console) error {
+	consoleOutput.Println("Copying the WordPress files.")
+
+	wordPressDirectory, err := s.getWordPressDirectory()
+	if err != nil {
+		return err
+	}
+
+	cloneWordPressDirectory, err := cloneSite.getWordPressDirectory()
+	if err != nil {
+		return err
+	}
+
+	skip := []string{"wp-config.php"}
+	projects := map[string]string{}
+
+	for _, extensionType := range []string{"plugin", "theme"} {
+		mountedProjects, err := s.getMountedProjects(extensionType)
+		if err != nil {
+			return err
+		}
+
+		for projectName, projectPath := range mountedProjects {
+			projectDirectory := filepath.Join("wp-content", extensionType+"s", projectName)
+
+			skip = append(skip, projectDirectory)
+			projects[projectDirectory] = projectPath
+		}
+	}
+
+	err = helpers.CopyDirectory(wordPressDirectory, cloneWordPressDirectory, skip)
+	if err != nil {
+		return err
+	}
+
+	for projectDirectory, projectPath := range projects {
+		err = helpers.CopyDirectory(projectPath, filepath.Join(cloneWordPressDirectory, projectDirectory), []string{".git"})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// copyDatabaseDump exports the site's database to the new site's folder, ready to be imported once the new site is running.
+func (s *Site) copyDatabaseDump(cloneSite *Site, consoleOutput *console.Console) error {
+	consoleOutput.Println("Exporting the database.")
+
+	err := s.dumpDatabase(cloneDatabaseFile, consoleOutput)
+	if err != nil {
+		return err
+	}
+
+	dumpFile := filepath.Join(s.settings.Get("siteDirectory"), cloneDatabaseFile)
+
+	defer os.Remove(dumpFile)
+
+	return helpers.CopyFile(dumpFile, filepath.Join(cloneSite.settings.Get("siteDirectory"), cloneDatabaseFile))
+}
@@ -0,0 +1,83 @@
+package site
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/docker"
+	"github.com/ChrisWiegman/kana/internal/settings"
+
+	"github.com/docker/docker/api/types/mount"
+)
+
+const (
+	composerDirectory      = "/app"
+	composerCacheDirectory = "/composer-cache"
+)
+
+// Composer runs a Composer command in the project's directory with the user's terminal attached and returns its exit
+// code. The site doesn't need to be running. Composer's cache is kept in the app directory and shared by every site so
+// packages already downloaded for one project don't need to be downloaded again for the next.
+func (s *Site) Composer(command []string, consoleOutput *console.Console) (int64, error) {
+	cacheDirectory := filepath.Join(s.settings.Get("appDirectory"), "cache", "composer")
+	dirPermissions, _ := settings.GetDefaultFilePermissions()
+
+	err := os.MkdirAll(cacheDirectory, os.FileMode(dirPermissions))
+	if err != nil {
+		return 1, err
+	}
+
+	container := docker.ContainerConfig{
+		Name:     fmt.Sprintf("kana-%s-composer", s.settings.Get("name")),
+		Image:    fmt.Sprintf("composer:%s", s.settings.Get("composerVersion")),
+		HostName: fmt.Sprintf("kana-%s-composer", s.settings.Get("name")),
+		Env: []string{
+			fmt.Sprintf("COMPOSER_CACHE_DIR=%s", composerCacheDirectory),
+			"COMPOSER_HOME=/tmp/composer",
+		},
+		Labels: map[string]string{
+			"kana.site": s.settings.Get("name"),
+		},
+		Volumes: []mount.Mount{
+			{
+				Type:   mount.TypeBind,
+				Source: s.settings.Get("workingDirectory"),
+				Target: composerDirectory,
+			},
+			{
+				Type:   mount.TypeBind,
+				Source: cacheDirectory,
+				Target: composerCacheDirectory,
+			},
+		},
+		Command: append([]string{"composer", fmt.Sprintf("--working-dir=%s", composerDirectory)}, command...),
+		Init:    true,
+	}
+
+	container.Env = append(container.Env, s.getGitEnv([]string{composerDirectory})...)
+	s.addProxy(&container)
+	s.addCACertificates(&container)
+
+	err = s.addSSHAgent(&container)
+	if err != nil {
+		return 1, err
+	}
+
+	err = s.dockerClient.EnsureImage(
+		context.Background(),
+		container.Image,
+		s.settings.Get("appDirectory"),
+		s.settings.GetInt("updateInterval"),
+		consoleOutput)
+	if err != nil {
+		return 1, err
+	}
+
+	// Composer runs as the host user, as interactive containers do, so the vendor directory can be edited on the host
+	code, _, err := s.dockerClient.ContainerRunAndClean(&container, true, nil)
+
+	return code, err
+}
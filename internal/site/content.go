@@ -0,0 +1,142 @@
+package site
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/helpers"
+)
+
+const themeUnitTestURL = "https://raw.githubusercontent.com/WordPress/theme-test-data/master/themeunittestdata.wordpress.xml"
+
+// blockPatternsScript creates a page showing every block pattern registered by WordPress and the active theme.
+const blockPatternsScript = `
+$kana_content = '';
+
+foreach ( WP_Block_Patterns_Registry::get_instance()->get_all_registered() as $kana_pattern ) {
+	$kana_content .= '<!-- wp:heading --><h2 class="wp-block-heading">' . esc_html( $kana_pattern['title'] ) . '</h2><!-- /wp:heading -->';
+	$kana_content .= $kana_pattern['content'];
+}
+
+wp_insert_post(
+	array(
+		'post_title'   => 'Block Patterns',
+		'post_content' => $kana_content,
+		'post_status'  => 'publish',
+		'post_type'    => 'page',
+	)
+);
+`
+
+// setPermalinks sets the permalink structure in the permalinks setting on a newly installed site and flushes the
+// rewrite rules. Failures are reported as warnings as the site still works with plain permalinks.
+func (s *Site) setPermalinks(consoleOutput *console.Console) {
+	permalinks := s.settings.Get("permalinks")
+	if permalinks == "" {
+		return
+	}
+
+	err := s.wpCliOrError([]string{"rewrite", "structure", permalinks}, consoleOutput)
+	if err == nil {
+		err = s.wpCliOrError([]string{"rewrite", "flush"}, consoleOutput)
+	}
+
+	if err != nil {
+		consoleOutput.Warn(fmt.Sprintf("Unable to set the permalink structure: %s", err))
+	}
+}
+
+// importStarterContent adds the content in the starterContent setting to a newly installed site.
+// Failures are reported as warnings as the site is still usable without the content.
+func (s *Site) importStarterContent(consoleOutput *console.Console) {
+	content := s.settings.Get("starterContent")
+	if content == "none" {
+		return
+	}
+
+	err := s.addContent(content, consoleOutput)
+	if err != nil {
+		consoleOutput.Warn(fmt.Sprintf("Unable to add the starter content: %s", err))
+	}
+}
+
+// addContent adds the Theme Unit Test content, a page of block patterns or, for anything else,
+// the WXR file at the given URL or path relative to the WordPress directory.
+func (s *Site) addContent(content string, consoleOutput *console.Console) error {
+	switch {
+	case content == "theme-unit-test":
+		consoleOutput.Println("Importing the Theme Unit Test content. This can take a few minutes.")
+		return s.importRemoteWXR(themeUnitTestURL, consoleOutput)
+	case content == "block-patterns":
+		consoleOutput.Println("Creating a page of block patterns.")
+		return s.wpCliOrError([]string{"eval", blockPatternsScript}, consoleOutput)
+	case strings.HasPrefix(content, "http://") || strings.HasPrefix(content, "https://"):
+		consoleOutput.Println(fmt.Sprintf("Importing content from %s.", content))
+		return s.importRemoteWXR(content, consoleOutput)
+	}
+
+	consoleOutput.Println(fmt.Sprintf("Importing content from %s.", content))
+
+	return s.importWXR(path.Join("/var/www/html", filepath.ToSlash(content)), consoleOutput)
+}
+
+// importRemoteWXR downloads a WXR file and imports it.
+func (s *Site) importRemoteWXR(wxrURL string, consoleOutput *console.Console) error {
+	// The site directory is mounted in the container for temporary files
+	file, err := helpers.DownloadFile(wxrURL, s.settings.Get("siteDirectory"))
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(filepath.Join(s.settings.Get("siteDirectory"), file))
+
+	return s.importWXR(fmt.Sprintf("/Site/%s", file), consoleOutput)
+}
+
+// importWXR imports a WXR file, at the given path in the container, using the WordPress importer.
+func (s *Site) importWXR(file string, consoleOutput *console.Console) error {
+	err := s.wpCliStreamOrError([]string{"plugin", "install", "wordpress-importer", "--activate"}, consoleOutput)
+	if err != nil {
+		return err
+	}
+
+	return s.wpCliStreamOrError([]string{"import", file, "--authors=create"}, consoleOutput)
+}
+
+// wpCliOrError runs a wp-cli command and returns its output as an error if it fails.
+func (s *Site) wpCliOrError(command []string, consoleOutput *console.Console) error {
+	code, output, err := s.WPCli(command, false, consoleOutput)
+	if err != nil {
+		return err
+	}
+
+	return getWPCliError(command, code, output, consoleOutput.Debug && !consoleOutput.JSON)
+}
+
+// wpCliStreamOrError runs a long wp-cli command with wpCliStream and returns an error if it fails.
+func (s *Site) wpCliStreamOrError(command []string, consoleOutput *console.Console) error {
+	code, output, err := s.wpCliStream(command, consoleOutput)
+	if err != nil {
+		return err
+	}
+
+	return getWPCliError(command, code, output, !consoleOutput.JSON)
+}
+
+// getWPCliError returns the error for a wp-cli command that exited with the given code, or nil if it succeeded. Output
+// that was already shown as the command ran isn't repeated in the error.
+func getWPCliError(command []string, code int64, output string, shown bool) error {
+	if code == 0 {
+		return nil
+	}
+
+	if shown || strings.TrimSpace(output) == "" {
+		return fmt.Errorf("wp %s exited with status %d", strings.Join(command, " "), code)
+	}
+
+	return fmt.Errorf("%s", strings.TrimSpace(output))
+}
@@ -0,0 +1,77 @@
+package site
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+)
+
+// CoreRollback is the outcome of changing the site to another version of WordPress.
+type CoreRollback struct {
+	Before   string
+	After    string
+	Snapshot string // The export archive taken before rolling back, which can be restored with kana import
+}
+
+// RollbackCore installs the given version of WordPress over the current one and verifies the core files against
+// WordPress.org's checksums. Unless skipped, a snapshot of the database, plugins and themes is taken first.
+func (s *Site) RollbackCore(version string, takeSnapshot bool, kanaVersion string, consoleOutput *console.Console) (CoreRollback, error) {
+	rollback := CoreRollback{}
+
+	if version == "latest" || version == "nightly" || !wordPressVersionPattern.MatchString(version) {
+		return rollback, fmt.Errorf("%s is not a valid WordPress version. Use a version number, such as 6.3 or 6.3.2", version)
+	}
+
+	var err error
+
+	rollback.Before, err = s.getWordPressVersion(consoleOutput)
+	if err != nil {
+		return rollback, err
+	}
+
+	if takeSnapshot {
+		consoleOutput.Println("Taking a snapshot of the database, plugins and themes.")
+
+		rollback.Snapshot, err = s.createSnapshot("rollback", kanaVersion, consoleOutput)
+		if err != nil {
+			return rollback, err
+		}
+	}
+
+	consoleOutput.Println(fmt.Sprintf("Installing WordPress %s.", version))
+
+	commands := [][]string{
+		{"core", "update", fmt.Sprintf("--version=%s", version), "--force"},
+		{"core", "update-db"},
+	}
+
+	for _, command := range commands {
+		err = s.wpCliStreamOrError(command, consoleOutput)
+		if err != nil {
+			return rollback, err
+		}
+	}
+
+	rollback.After, err = s.getWordPressVersion(consoleOutput)
+	if err != nil {
+		return rollback, err
+	}
+
+	s.recordHistory("core rolled back", fmt.Sprintf("WordPress %s to %s", rollback.Before, rollback.After))
+
+	consoleOutput.Println("Verifying the WordPress core files.")
+
+	code, output, err := s.WPCli([]string{"core", "verify-checksums"}, false, consoleOutput)
+	if err != nil {
+		return rollback, err
+	}
+
+	if code != 0 {
+		return rollback, fmt.Errorf("WordPress %s was installed but its files don't match WordPress.org's checksums: %s",
+			rollback.After,
+			strings.TrimSpace(output))
+	}
+
+	return rollback, nil
+}
@@ -1,8 +1,12 @@
 package site
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"os"
+	"path"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -16,6 +20,9 @@ import (
 	"github.com/docker/docker/api/types/mount"
 )
 
+// sqliteShellImage provides the sqlite3 shell for SQLite sites, which the WordPress images don't include.
+const sqliteShellImage = "keinos/sqlite3"
+
 func (s *Site) ExportDatabase(args []string, consoleOutput *console.Console) (string, error) {
 	isUsingSQLite, err := s.isUsingSQLite()
 	if err != nil {
@@ -38,27 +45,9 @@ func (s *Site) ExportDatabase(args []string, consoleOutput *console.Console) (st
 		exportFile = filepath.Join(cwd, args[0])
 	}
 
-	exportCommand := []string{
-		"db",
-		"export",
-		"--add-drop-table",
-		"/Site/export.sql",
-	}
-
-	code, output, err := s.WPCli(exportCommand, false, consoleOutput)
-	if err != nil || code != 0 {
-		errorMessage := ""
-
-		if err != nil {
-			errorMessage = err.Error()
-		}
-
-		return "", fmt.Errorf("database export failed: %s\n%s", errorMessage, output)
-	}
-
-	err = copyFile(filepath.Join(s.settings.Get("siteDirectory"), "export.sql"), exportFile)
+	err = s.streamExport(exportFile, consoleOutput)
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("database export failed: %s", err)
 	}
 
 	return exportFile, nil
@@ -84,74 +73,250 @@ func (s *Site) ImportDatabase(file string, preserve bool, replaceDomain string,
 		return fmt.Errorf("the specified sql file does not exist. Please enter a valid file to import")
 	}
 
-	kanaImportFile := filepath.Join(s.settings.Get("siteDirectory"), "import.sql")
-
-	err = copyFile(rawImportFile, kanaImportFile)
+	err = s.importDatabaseFile(rawImportFile, preserve, replaceDomain, consoleOutput)
 	if err != nil {
 		return err
 	}
 
-	if !preserve {
-		consoleOutput.Println("Dropping the existing database.")
+	s.recordHistory("database imported", filepath.Base(file))
 
-		dropCommand := []string{
-			"db",
-			"drop",
-			"--yes",
-		}
+	return s.MakeImportSafe(consoleOutput)
+}
 
-		createCommand := []string{
-			"db",
-			"create",
+// importDatabaseFile imports an SQL file, replacing the site's database unless preserve is true, and replaces the domain
+// of the site it came from, if given, with the site's domain.
+func (s *Site) importDatabaseFile(file string, preserve bool, replaceDomain string, consoleOutput *console.Console) error {
+	if !preserve {
+		err := s.resetDatabase(consoleOutput)
+		if err != nil {
+			return err
 		}
+	}
 
-		var code int64
-		var output string
+	consoleOutput.Println("Importing the database file.")
 
-		code, output, err = s.WPCli(dropCommand, false, consoleOutput)
-		if err != nil || code != 0 {
-			return fmt.Errorf("drop database failed: %s\n%s", err.Error(), output)
+	err := s.streamImport(file, consoleOutput)
+	if err != nil {
+		return fmt.Errorf("database import failed: %s", err)
+	}
+
+	if replaceDomain != "" {
+		return s.replaceDomain(replaceDomain, consoleOutput)
+	}
+
+	return nil
+}
+
+// replaceDomain replaces the domain of the site a database came from with the site's domain.
+func (s *Site) replaceDomain(oldDomain string, consoleOutput *console.Console) error {
+	consoleOutput.Println("Replacing the old domain name")
+
+	err := s.wpCliOrError([]string{"search-replace", oldDomain, s.settings.GetDomain(), "--all-tables"}, consoleOutput)
+	if err != nil {
+		return fmt.Errorf("replace domain failed: %s", err)
+	}
+
+	return nil
+}
+
+// resetDatabase drops the site's database and creates a new, empty, one.
+func (s *Site) resetDatabase(consoleOutput *console.Console) error {
+	consoleOutput.Println("Dropping the existing database.")
+
+	// wp-cli's db commands only work with MariaDB and MySQL so PostgreSQL databases are emptied by replacing their schema
+	if s.settings.Get("database") == "postgres" {
+		output, err := s.dockerClient.ContainerExec(
+			fmt.Sprintf("kana-%s-database", s.settings.Get("name")),
+			false,
+			[]string{strings.Join(getPostgresCommand(false, "--command='DROP SCHEMA public CASCADE; CREATE SCHEMA public;'"), " ")})
+		if err != nil {
+			return err
 		}
 
-		code, output, err = s.WPCli(createCommand, false, consoleOutput)
-		if err != nil || code != 0 {
-			return fmt.Errorf("create database failed: %s\n%s", err.Error(), output)
+		if output.ExitCode != 0 {
+			return fmt.Errorf("reset database failed: %s", output.StdErr)
 		}
+
+		return nil
 	}
 
-	consoleOutput.Println("Importing the database file.")
+	err := s.wpCliOrError([]string{"db", "drop", "--yes"}, consoleOutput)
+	if err != nil {
+		return fmt.Errorf("drop database failed: %s", err)
+	}
 
-	importCommand := []string{
-		"db",
-		"import",
-		"/Site/import.sql",
+	err = s.wpCliOrError([]string{"db", "create"}, consoleOutput)
+	if err != nil {
+		return fmt.Errorf("create database failed: %s", err)
 	}
 
-	code, output, err := s.WPCli(importCommand, false, consoleOutput)
-	if err != nil || code != 0 {
-		return fmt.Errorf("database import failed: %s\n%s", err.Error(), output)
+	return nil
+}
+
+// streamImport pipes an SQL file into the database server's client, showing the progress of the import as it runs.
+func (s *Site) streamImport(file string, consoleOutput *console.Console) error {
+	importFile, err := os.Open(file)
+	if err != nil {
+		return err
 	}
 
-	if replaceDomain != "" {
-		consoleOutput.Println("Replacing the old domain name")
+	defer importFile.Close()
+
+	fileInfo, err := importFile.Stat()
+	if err != nil {
+		return err
+	}
+
+	if !fileInfo.Mode().IsRegular() {
+		return fmt.Errorf("please enter a valid sql file")
+	}
+
+	progress := consoleOutput.NewProgress("Importing", fileInfo.Size(), false)
+
+	// Checking keys and committing after every statement can make large imports many times slower
+	input := io.MultiReader(
+		strings.NewReader("SET autocommit = 0; SET unique_checks = 0; SET foreign_key_checks = 0;\n"),
+		progress.Reader(importFile),
+		strings.NewReader("\nCOMMIT;\n"))
+	importCommand := s.getDatabaseCommand(false)
+
+	if s.settings.Get("database") == "postgres" {
+		input = progress.Reader(importFile)
+		importCommand = getPostgresCommand(false, "--single-transaction")
+	}
+
+	var errorOutput bytes.Buffer
+
+	code, err := s.dockerClient.ContainerExecStream(
+		fmt.Sprintf("kana-%s-database", s.settings.Get("name")),
+		false,
+		importCommand,
+		input,
+		io.Discard,
+		&errorOutput)
+	if err != nil {
+		return err
+	}
+
+	if code != 0 {
+		return fmt.Errorf("%s", getDatabaseErrors(errorOutput.String()))
+	}
+
+	progress.Done()
+
+	return nil
+}
+
+// streamExport dumps the database to a file, showing the progress of the export compared to the estimated size of the database.
+func (s *Site) streamExport(file string, consoleOutput *console.Console) error {
+	exportFile, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+
+	defer exportFile.Close()
+
+	progress := consoleOutput.NewProgress("Exporting", s.getDatabaseSize(), true)
+
+	exportCommand := s.getDatabaseCommand(true, "--single-transaction", "--quick", "--add-drop-table", "--no-tablespaces", "--skip-dump-date")
+
+	if s.settings.Get("database") == "postgres" {
+		exportCommand = getPostgresCommand(true)
+	}
+
+	var errorOutput bytes.Buffer
+
+	code, err := s.dockerClient.ContainerExecStream(
+		fmt.Sprintf("kana-%s-database", s.settings.Get("name")),
+		false,
+		exportCommand,
+		nil,
+		progress.Writer(exportFile),
+		&errorOutput)
+	if err != nil {
+		return err
+	}
+
+	if code != 0 {
+		return fmt.Errorf("%s", getDatabaseErrors(errorOutput.String()))
+	}
 
-		replaceCommand := []string{
-			"search-replace",
-			replaceDomain,
-			s.settings.GetDomain(),
-			"--all-tables",
+	progress.Done()
+
+	return nil
+}
+
+// getDatabaseCommand returns the command, with the site's credentials, to run the database server's client
+// or, if dump is true, its dump tool against the WordPress database.
+func (s *Site) getDatabaseCommand(dump bool, args ...string) []string {
+	command := "mariadb"
+
+	if dump {
+		command = "mariadb-dump"
+	}
+
+	if s.settings.Get("database") == "mysql" {
+		command = "mysql"
+
+		if dump {
+			command = "mysqldump"
 		}
+	}
 
-		code, output, err := s.WPCli(replaceCommand, false, consoleOutput)
-		if err != nil || code != 0 {
-			return fmt.Errorf("replace domain failed failed: %s\n%s", err.Error(), output)
+	fullCommand := []string{
+		command,
+		"--user=wordpress",
+		"--password=wordpress",
+	}
+
+	fullCommand = append(fullCommand, args...)
+
+	return append(fullCommand, "wordpress")
+}
+
+// getDatabaseSize returns the approximate size of the site's data in bytes, or 0 if it can't be found.
+func (s *Site) getDatabaseSize() int64 {
+	query := "SELECT COALESCE(SUM(data_length), 0) FROM information_schema.tables WHERE table_schema = 'wordpress'"
+	sizeCommand := fmt.Sprintf("%s --skip-column-names --execute=\"%s\"", strings.Join(s.getDatabaseCommand(false), " "), query)
+
+	if s.settings.Get("database") == "postgres" {
+		sizeCommand = strings.Join(
+			getPostgresCommand(false, "--tuples-only", "--no-align", "--command=\"SELECT pg_database_size(current_database())\""), " ")
+	}
+
+	output, err := s.dockerClient.ContainerExec(
+		fmt.Sprintf("kana-%s-database", s.settings.Get("name")),
+		false,
+		[]string{sizeCommand})
+	if err != nil || output.ExitCode != 0 {
+		return 0
+	}
+
+	size, err := strconv.ParseInt(strings.TrimSpace(output.StdOut), 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return size
+}
+
+// getDatabaseErrors returns the errors from the database client's output without the warning about using a password on the command line.
+func getDatabaseErrors(output string) string {
+	errors := []string{}
+
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if !strings.Contains(line, "Using a password on the command line interface can be insecure") {
+			errors = append(errors, line)
 		}
 	}
 
-	return nil
+	return strings.Join(errors, "\n")
 }
 
-func (s *Site) getDatabaseContainer(databaseDir string, appContainers []docker.ContainerConfig) []docker.ContainerConfig {
+func (s *Site) getDatabaseContainer(
+	databaseDir string,
+	appContainers []docker.ContainerConfig,
+	consoleOutput *console.Console) []docker.ContainerConfig {
 	isUsingSQLite, err := s.isUsingSQLite()
 	if err != nil {
 		return appContainers
@@ -168,13 +333,28 @@ func (s *Site) getDatabaseContainer(databaseDir string, appContainers []docker.C
 		"MARIADB_PASSWORD=wordpress",
 	}
 
-	if s.settings.Get("database") == "mysql" {
+	port := "3306"
+	dataDirectory := "/var/lib/mysql"
+
+	switch s.settings.Get("database") {
+	case "mysql":
 		envVars = []string{
 			"MYSQL_ROOT_PASSWORD=password",
 			"MYSQL_DATABASE=wordpress",
 			"MYSQL_USER=wordpress",
 			"MYSQL_PASSWORD=wordpress",
 		}
+	case "postgres":
+		envVars = []string{
+			fmt.Sprintf("POSTGRES_DB=%s", postgresDatabaseName),
+			fmt.Sprintf("POSTGRES_USER=%s", postgresDatabaseUser),
+			fmt.Sprintf("POSTGRES_PASSWORD=%s", postgresDatabasePassword),
+		}
+		port = "5432"
+		dataDirectory = "/var/lib/postgresql/data"
+
+		// PostgreSQL won't initialize a data directory that already has files in it, such as from another database server
+		envVars = append(envVars, fmt.Sprintf("PGDATA=%s/pgdata", dataDirectory))
 	}
 
 	databaseContainer := docker.ContainerConfig{
@@ -183,7 +363,7 @@ func (s *Site) getDatabaseContainer(databaseDir string, appContainers []docker.C
 		NetworkName: "kana",
 		HostName:    fmt.Sprintf("kana-%s-database", s.settings.Get("name")),
 		Ports: []docker.ExposedPorts{
-			{Port: "3306", Protocol: "tcp"},
+			{Port: port, Protocol: "tcp", HostPort: s.getDatabaseHostPort(consoleOutput)},
 		},
 		Env: envVars,
 		Labels: map[string]string{
@@ -191,10 +371,10 @@ func (s *Site) getDatabaseContainer(databaseDir string, appContainers []docker.C
 			"kana.site": s.settings.Get("name"),
 		},
 		Volumes: []mount.Mount{
-			{ // Maps a database folder to the MySQL container for persistence
+			{ // Maps a database folder to the database container for persistence
 				Type:   mount.TypeBind,
 				Source: databaseDir,
-				Target: "/var/lib/mysql",
+				Target: dataDirectory,
 			},
 		},
 	}
@@ -204,6 +384,29 @@ func (s *Site) getDatabaseContainer(databaseDir string, appContainers []docker.C
 	return appContainers
 }
 
+// getDatabaseHostPort returns the port set in the databasePort setting, if it's set and free, or an empty string to
+// publish the database on a random free port.
+func (s *Site) getDatabaseHostPort(consoleOutput *console.Console) string {
+	if s.settings.GetInt("databasePort") == 0 {
+		return ""
+	}
+
+	port := s.settings.Get("databasePort")
+	databaseContainer := fmt.Sprintf("kana-%s-database", s.settings.Get("name"))
+
+	// The site's own database, if it's already running, is using the port
+	if !docker.IsPortAvailable(port) && s.dockerClient.GetPortContainer(port) != databaseContainer {
+		consoleOutput.Warn(fmt.Sprintf(
+			"Port %s, set in the databasePort setting, is already in use by %s so the database will be published on a free port instead. Run 'kana db credentials' to see which.", //nolint:lll
+			port,
+			s.getPortOwner(port)))
+
+		return ""
+	}
+
+	return port
+}
+
 func (s *Site) getDatabaseDirectory() (databaseDirectory string, err error) {
 	databaseDirectory = filepath.Join(s.settings.Get("siteDirectory"), "database")
 
@@ -215,6 +418,70 @@ func (s *Site) getDatabaseDirectory() (databaseDirectory string, err error) {
 	return databaseDirectory, err
 }
 
+// DatabaseCredentials are the details database clients, such as TablePlus or Sequel Ace, need to connect to the
+// site's database from the host.
+type DatabaseCredentials struct {
+	Host         string `json:"host"`
+	Port         string `json:"port"`
+	User         string `json:"user"`
+	Password     string `json:"password"`
+	Database     string `json:"database"`
+	RootPassword string `json:"rootPassword"`
+	scheme       string
+}
+
+// URL returns the credentials as a mysql://, or postgres:// for PostgreSQL sites, URL, which most database clients can
+// open directly.
+func (c DatabaseCredentials) URL() string {
+	scheme := c.scheme
+
+	if scheme == "" {
+		scheme = "mysql"
+	}
+
+	return fmt.Sprintf("%s://%s:%s@%s:%s/%s", scheme, c.User, c.Password, c.Host, c.Port, c.Database)
+}
+
+// GetDatabaseCredentials returns the details needed to connect to the running site's database from the host.
+func (s *Site) GetDatabaseCredentials() (DatabaseCredentials, error) {
+	isUsingSQLite, err := s.isUsingSQLite()
+	if err != nil {
+		return DatabaseCredentials{}, err
+	}
+
+	if isUsingSQLite {
+		return DatabaseCredentials{}, fmt.Errorf(
+			"SQLite sites don't have a database server to connect to. Use 'kana db path' to find the database file instead")
+	}
+
+	port := s.getDatabasePort()
+	if port == "0" {
+		return DatabaseCredentials{}, fmt.Errorf("the site's database isn't running. Please run 'kana start' to start the site")
+	}
+
+	credentials := DatabaseCredentials{
+		Host:         "127.0.0.1",
+		Port:         port,
+		User:         "wordpress",
+		Password:     "wordpress",
+		Database:     "wordpress",
+		RootPassword: "password",
+	}
+
+	isUsingPostgres, err := s.isUsingPostgres()
+	if err != nil {
+		return credentials, err
+	}
+
+	// The wordpress user is PostgreSQL's superuser
+	if isUsingPostgres {
+		credentials.RootPassword = postgresDatabasePassword
+		credentials.scheme = "postgres"
+	}
+
+	return credentials, nil
+}
+
 // getDatabasePort returns the public port for the database attached to the current site.
 func (s *Site) getDatabasePort() string {
 	containers, _ := s.dockerClient.ContainerList(s.settings.Get("name"))
@@ -264,7 +531,82 @@ func (s *Site) maybeSetupSQLite() error {
 		filepath.Join(s.settings.Get("workingDirectory"), "wp-content", "db.php"))
 }
 
+// GetSQLiteDatabaseFile returns the path of the site's SQLite database.
+func (s *Site) GetSQLiteDatabaseFile() string {
+	return filepath.Join(s.settings.Get("workingDirectory"), "wp-content", "database", ".ht.sqlite")
+}
+
+// DatabaseShell opens an interactive shell for the site's database, the sqlite3 shell for SQLite sites, psql for
+// PostgreSQL sites or the MySQL client for the others, and returns its exit code.
+func (s *Site) DatabaseShell(consoleOutput *console.Console) (int64, error) {
+	isUsingSQLite, err := s.isUsingSQLite()
+	if err != nil {
+		return 1, err
+	}
+
+	isUsingPostgres, err := s.isUsingPostgres()
+	if err != nil {
+		return 1, err
+	}
+
+	if isUsingPostgres {
+		code, err := s.Exec("database", getPostgresCommand(false), false)
+
+		return int64(code), err
+	}
+
+	if !isUsingSQLite {
+		code, _, err := s.WPCli([]string{"db", "cli"}, true, consoleOutput)
+
+		return code, err
+	}
+
+	databaseFile := s.GetSQLiteDatabaseFile()
+
+	_, err = os.Stat(databaseFile)
+	if err != nil {
+		return 1, fmt.Errorf("the SQLite database, %s, could not be found: %s", databaseFile, err)
+	}
+
+	container := docker.ContainerConfig{
+		Name:  fmt.Sprintf("kana-%s-sqlite", s.settings.Get("name")),
+		Image: sqliteShellImage,
+		Labels: map[string]string{
+			"kana.site": s.settings.Get("name"),
+		},
+		Volumes: []mount.Mount{
+			{
+				Type:   mount.TypeBind,
+				Source: filepath.Dir(databaseFile),
+				Target: "/database",
+			},
+		},
+		Command: []string{"sqlite3", path.Join("/database", filepath.Base(databaseFile))},
+		Init:    true,
+	}
+
+	err = s.dockerClient.EnsureImage(
+		context.Background(),
+		container.Image,
+		s.settings.Get("appDirectory"),
+		s.settings.GetInt("updateInterval"),
+		consoleOutput)
+	if err != nil {
+		return 1, err
+	}
+
+	code, _, err := s.dockerClient.ContainerRunAndClean(&container, true, nil)
+
+	return code, err
+}
+
+// isUsingSQLite returns true if the running site was started with SQLite or, if it isn't running yet, if the
+// database setting is sqlite.
 func (s *Site) isUsingSQLite() (bool, error) {
+	if !s.IsSiteRunning() {
+		return s.settings.Get("database") == "sqlite", nil
+	}
+
 	output, err := s.WordPress("echo $KANA_SQLITE", false, false)
 	if err != nil {
 		return false, err
@@ -279,16 +621,34 @@ func (s *Site) isUsingSQLite() (bool, error) {
 
 // verifySite verifies if a site is up and running without error.
 func (s *Site) verifyDatabase(consoleOutput *console.Console) error {
+	defer consoleOutput.StartPhase("Database readiness")()
+
 	checkCommand := []string{
 		"db",
 		"check",
 	}
 
+	// wp db check only works with MariaDB and MySQL so PostgreSQL is asked directly
+	checkDatabase := func() (int64, error) {
+		if s.settings.Get("database") == "postgres" {
+			output, err := s.dockerClient.ContainerExec(
+				fmt.Sprintf("kana-%s-database", s.settings.Get("name")),
+				false,
+				[]string{fmt.Sprintf("pg_isready --username=%s --dbname=%s", postgresDatabaseUser, postgresDatabaseName)})
+
+			return int64(output.ExitCode), err
+		}
+
+		code, _, err := s.WPCli(checkCommand, false, consoleOutput)
+
+		return code, err
+	}
+
 	databaseOK := false
 	checkAttempt := 0
 
 	for !databaseOK {
-		code, _, err := s.WPCli(checkCommand, false, consoleOutput)
+		code, err := checkDatabase()
 		if err != nil || code != 0 {
 			checkAttempt++ // Increment the check attempt counter
 			time.Sleep(time.Second)
@@ -0,0 +1,302 @@
+package site
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/helpers"
+)
+
+// ExportParts are the parts of a site that can be included in an export archive.
+var ExportParts = []string{"db", "uploads", "plugins", "themes", "settings"}
+
+const (
+	exportManifestFile    = "kana-export.json"
+	exportManifestVersion = 1
+)
+
+// ExportManifest describes the contents of an export archive and the site they came from so the archive can be verified before
+// it is imported. It doesn't include the time of the export so exporting the same site twice produces the same archive.
+type ExportManifest struct {
+	ManifestVersion  int               `json:"manifestVersion"`
+	KanaVersion      string            `json:"kanaVersion"`
+	Domain           string            `json:"domain"`
+	WordPressVersion string            `json:"wordPressVersion"`
+	PHPVersion       string            `json:"phpVersion"`
+	Database         string            `json:"database"`
+	Parts            []string          `json:"parts"`
+	Plugins          []ManifestPlugin  `json:"plugins"`
+	Files            map[string]string `json:"files"`
+}
+
+// ManifestPlugin is a plugin installed on the site an export archive came from.
+type ManifestPlugin struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Status  string `json:"status"`
+}
+
+// ExportSite writes the selected parts of the site, and a manifest of them, to a zip archive. The archive is named after the site
+// and saved in the current directory unless a path, absolute or relative to the current directory, is given.
+func (s *Site) ExportSite(parts, args []string, version string, consoleOutput *console.Console) (string, error) {
+	for _, part := range parts {
+		if !slices.Contains(ExportParts, part) {
+			return "", fmt.Errorf("%s is not a part of the site that can be exported. Valid parts are %s", part, strings.Join(ExportParts, ", "))
+		}
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+
+	exportFile := filepath.Join(cwd, fmt.Sprintf("kana-%s-export.zip", s.settings.Get("name")))
+
+	if len(args) == 1 {
+		exportFile = args[0]
+
+		if !filepath.IsAbs(exportFile) {
+			exportFile = filepath.Join(cwd, exportFile)
+		}
+	}
+
+	file, err := os.Create(exportFile)
+	if err != nil {
+		return "", err
+	}
+
+	defer file.Close()
+
+	err = s.writeExportArchive(helpers.NewArchive(file), parts, version, consoleOutput)
+	if err != nil {
+		_ = file.Close()
+		_ = os.Remove(exportFile)
+
+		return "", err
+	}
+
+	return exportFile, nil
+}
+
+// writeExportArchive adds the selected parts of the site to the archive, always in the same order, followed by the manifest.
+func (s *Site) writeExportArchive(archive *helpers.Archive, parts []string, version string, consoleOutput *console.Console) error {
+	exportedParts := []string{}
+
+	for _, part := range ExportParts {
+		if !slices.Contains(parts, part) {
+			continue
+		}
+
+		err := s.exportPart(archive, part, consoleOutput)
+		if err != nil {
+			return fmt.Errorf("unable to export the site's %s: %s", part, err)
+		}
+
+		exportedParts = append(exportedParts, part)
+	}
+
+	manifest, err := s.getExportManifest(exportedParts, version, archive.Checksums, consoleOutput)
+	if err != nil {
+		return fmt.Errorf("unable to create the export manifest: %s", err)
+	}
+
+	manifestJSON, err := json.MarshalIndent(manifest, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	err = archive.AddBytes(exportManifestFile, manifestJSON)
+	if err != nil {
+		return err
+	}
+
+	return archive.Close()
+}
+
+// getExportManifest returns the manifest for an archive of the given parts of the site.
+func (s *Site) getExportManifest(
+	parts []string,
+	version string,
+	checksums map[string]string,
+	consoleOutput *console.Console) (ExportManifest, error) {
+	manifest := ExportManifest{
+		ManifestVersion: exportManifestVersion,
+		KanaVersion:     version,
+		Domain:          s.settings.GetDomain(),
+		PHPVersion:      s.settings.Get("php"),
+		Database:        s.settings.Get("database"),
+		Parts:           parts,
+		Plugins:         []ManifestPlugin{},
+		Files:           checksums,
+	}
+
+	isUsingSQLite, err := s.isUsingSQLite()
+	if err != nil {
+		return manifest, err
+	}
+
+	if isUsingSQLite {
+		manifest.Database = "sqlite"
+	}
+
+	isUsingPostgres, err := s.isUsingPostgres()
+	if err != nil {
+		return manifest, err
+	}
+
+	if isUsingPostgres {
+		manifest.Database = "postgres"
+	}
+
+	manifest.WordPressVersion, err = s.getWordPressVersion(consoleOutput)
+	if err != nil {
+		return manifest, err
+	}
+
+	plugins, err := s.GetExtensions("plugin", consoleOutput)
+	if err != nil {
+		return manifest, err
+	}
+
+	for _, plugin := range plugins {
+		manifest.Plugins = append(manifest.Plugins, ManifestPlugin{
+			Name:    plugin.Name,
+			Version: plugin.Version,
+			Status:  plugin.Status,
+		})
+	}
+
+	return manifest, nil
+}
+
+// getWordPressVersion returns the version of WordPress installed on the site.
+func (s *Site) getWordPressVersion(consoleOutput *console.Console) (string, error) {
+	code, output, err := s.WPCli([]string{"core", "version"}, false, consoleOutput)
+	if err != nil {
+		return "", err
+	}
+
+	if code != 0 {
+		return "", fmt.Errorf("unable to get the WordPress version: %s", strings.TrimSpace(output))
+	}
+
+	return strings.TrimSpace(output), nil
+}
+
+// exportPart adds one of the ExportParts to the export archive.
+func (s *Site) exportPart(archive *helpers.Archive, part string, consoleOutput *console.Console) error {
+	wordPressDirectory, err := s.getWordPressDirectory()
+	if err != nil {
+		return err
+	}
+
+	switch part {
+	case "db":
+		return s.exportDatabaseToArchive(archive, consoleOutput)
+	case "uploads":
+		consoleOutput.Println("Adding the uploads.")
+
+		uploadsDirectory := filepath.Join(wordPressDirectory, "wp-content", "uploads")
+		if _, err = os.Stat(uploadsDirectory); os.IsNotExist(err) {
+			return nil
+		}
+
+		return archive.AddDirectory(uploadsDirectory, "wp-content/uploads", []string{})
+	case "plugins", "themes":
+		consoleOutput.Println(fmt.Sprintf("Adding the %s.", part))
+
+		return s.exportExtensionsToArchive(archive, wordPressDirectory, strings.TrimSuffix(part, "s"))
+	case "settings":
+		consoleOutput.Println("Adding the site's config.")
+
+		localSettings, err := s.getExportedConfig(consoleOutput)
+		if err != nil {
+			return err
+		}
+
+		config, err := s.settings.MarshalLocalSettings(localSettings)
+		if err != nil {
+			return err
+		}
+
+		return archive.AddBytes(".kana.json", config)
+	}
+
+	return nil
+}
+
+// exportDatabaseToArchive adds a dump of the site's database, or its SQLite database file, to the export archive.
+func (s *Site) exportDatabaseToArchive(archive *helpers.Archive, consoleOutput *console.Console) error {
+	isUsingSQLite, err := s.isUsingSQLite()
+	if err != nil {
+		return err
+	}
+
+	if isUsingSQLite {
+		consoleOutput.Println("Adding the database.")
+
+		return archive.AddFile(s.GetSQLiteDatabaseFile(), "database.sqlite")
+	}
+
+	databaseFile := filepath.Join(s.settings.Get("siteDirectory"), "export.sql")
+
+	defer os.Remove(databaseFile)
+
+	err = s.streamExport(databaseFile, consoleOutput)
+	if err != nil {
+		return err
+	}
+
+	return archive.AddFile(databaseFile, "database.sql")
+}
+
+// exportExtensionsToArchive adds the site's plugins or themes to the export archive, including those being developed,
+// which are mounted into the site rather than stored in its wp-content folder.
+func (s *Site) exportExtensionsToArchive(archive *helpers.Archive, wordPressDirectory, extensionType string) error {
+	archivePath := fmt.Sprintf("wp-content/%ss", extensionType)
+
+	mountedProjects, err := s.getMountedProjects(extensionType)
+	if err != nil {
+		return err
+	}
+
+	projectNames := slices.Sorted(maps.Keys(mountedProjects))
+
+	for _, name := range projectNames {
+		err = archive.AddDirectory(mountedProjects[name], fmt.Sprintf("%s/%s", archivePath, name), []string{".git"})
+		if err != nil {
+			return err
+		}
+	}
+
+	extensionsDirectory := filepath.Join(wordPressDirectory, "wp-content", extensionType+"s")
+	if _, err = os.Stat(extensionsDirectory); os.IsNotExist(err) {
+		return nil
+	}
+
+	return archive.AddDirectory(extensionsDirectory, archivePath, projectNames)
+}
+
+// getMountedProjects returns the names and paths of the plugins or themes being developed in the site.
+func (s *Site) getMountedProjects(extensionType string) (map[string]string, error) {
+	mountedProjects := map[string]string{}
+
+	projects, err := s.getProjects()
+	if err != nil {
+		return mountedProjects, err
+	}
+
+	for _, project := range projects {
+		if project.Type == extensionType {
+			mountedProjects[project.Name] = project.Path
+		}
+	}
+
+	return mountedProjects, nil
+}
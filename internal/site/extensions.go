@@ -0,0 +1,103 @@
+package site
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+)
+
+// ExtensionInfo describes a plugin or theme installed in the site as reported by wp-cli.
+type ExtensionInfo struct {
+	Name          string `json:"name"`
+	Status        string `json:"status"`
+	Update        string `json:"update"`
+	UpdateVersion string `json:"update_version"`
+	Version       string `json:"version"`
+}
+
+var extensionTypes = []string{"plugin", "theme"}
+
+// extensionActions maps each supported action to the wp-cli subcommand used for the given extension type.
+var extensionActions = map[string]map[string][]string{
+	"activate": {
+		"plugin": {"activate"},
+		"theme":  {"activate"},
+	},
+	"deactivate": {
+		"plugin": {"deactivate"},
+	},
+	"install": {
+		"plugin": {"install"},
+		"theme":  {"install"},
+	},
+	"remove": {
+		"plugin": {"uninstall", "--deactivate"},
+		"theme":  {"delete"},
+	},
+}
+
+// GetExtensions returns the plugins or themes installed in the site.
+func (s *Site) GetExtensions(extensionType string, consoleOutput *console.Console) ([]ExtensionInfo, error) {
+	extensions := []ExtensionInfo{}
+
+	if !slices.Contains(extensionTypes, extensionType) {
+		return extensions, fmt.Errorf("invalid extension type %s. Must be one of %s", extensionType, strings.Join(extensionTypes, ", "))
+	}
+
+	commands := []string{
+		extensionType,
+		"list",
+		"--format=json",
+		"--fields=name,status,update,update_version,version",
+	}
+
+	code, commandOutput, err := s.WPCli(commands, false, consoleOutput)
+	if err != nil {
+		return extensions, err
+	}
+
+	if code != 0 {
+		return extensions, fmt.Errorf("unable to list the installed %ss: %s", extensionType, commandOutput)
+	}
+
+	err = json.Unmarshal([]byte(commandOutput), &extensions)
+
+	return extensions, err
+}
+
+// ManageExtensions runs the given action (activate, deactivate, install or remove) against the named plugins or themes.
+func (s *Site) ManageExtensions(extensionType, action string, names []string, activate bool, consoleOutput *console.Console) error {
+	subCommand, ok := extensionActions[action][extensionType]
+	if !ok {
+		return fmt.Errorf("the %s action is not supported for %ss", action, extensionType)
+	}
+
+	commands := []string{extensionType}
+	commands = append(commands, subCommand...)
+
+	if action == "install" && activate {
+		commands = append(commands, "--activate")
+	}
+
+	commands = append(commands, names...)
+
+	code, output, err := s.WPCli(commands, false, consoleOutput)
+	if err != nil {
+		return err
+	}
+
+	if code != 0 {
+		return fmt.Errorf("unable to %s %s: %s", action, strings.Join(names, ", "), strings.TrimSpace(output))
+	}
+
+	if action == "install" {
+		s.recordExtensionVersions(extensionType, "installed", names, consoleOutput)
+	} else {
+		s.recordHistory(fmt.Sprintf("%s %sd", extensionType, action), strings.Join(names, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package site
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+)
+
+// FlushTargets are the things kana flush can clear. "all" flushes each of the others in turn.
+var FlushTargets = []string{"cache", "rewrites", "opcache", "all"}
+
+// flushCommands maps each wp-cli flush target to the commands that clear it.
+var flushCommands = map[string][][]string{
+	"cache": {
+		{"cache", "flush"},
+		{"transient", "delete", "--all"},
+	},
+	"rewrites": {
+		{"rewrite", "flush"},
+	},
+}
+
+// Flush clears the given target: the object cache and transients, the rewrite rules or PHP's opcache.
+func (s *Site) Flush(target string, consoleOutput *console.Console) error {
+	if !slices.Contains(FlushTargets, target) {
+		return fmt.Errorf("%s is not a valid flush target. Use one of %s", target, strings.Join(FlushTargets, ", "))
+	}
+
+	if target == "all" {
+		for _, flushTarget := range FlushTargets[:len(FlushTargets)-1] {
+			err := s.Flush(flushTarget, consoleOutput)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if target == "opcache" {
+		return s.flushOpcache()
+	}
+
+	for _, command := range flushCommands[target] {
+		err := s.wpCliOrError(command, consoleOutput)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// flushOpcache gracefully restarts Apache in the WordPress container, which empties the opcache shared by its PHP
+// processes without dropping requests. Calling opcache_reset through wp-cli would only reset the CLI's own cache.
+func (s *Site) flushOpcache() error {
+	output, err := s.WordPress("apache2ctl -k graceful", false, true)
+	if err != nil {
+		return err
+	}
+
+	if output.ExitCode != 0 {
+		return fmt.Errorf("unable to flush the opcache: %s", strings.TrimSpace(output.StdErr))
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package site
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/api/types/mount"
+)
+
+// gitConfigKeys are the settings from the user's git config passed on to git in the site's containers.
+var gitConfigKeys = []string{"user.name", "user.email"}
+
+// getGitEnv returns the environment variables that set up git in a container for a project that is a git repository.
+// The project's folders in the container are marked as safe, as git refuses to work in mounted folders owned by another
+// user, and the user's name and email are passed on so commits made by tools such as wp-cli scaffolds or Composer
+// scripts have the right author. Nothing is returned for projects that aren't git repositories.
+//
+// Marking the folders as safe doesn't leave files in .git that the user can't change as the containers git is used in,
+// including the WordPress container, run as the host user. Only commands run with `kana exec --root` or
+// `kana shell --root` run as root there.
+func (s *Site) getGitEnv(safeDirectories []string) []string {
+	identity := s.getGitIdentity()
+	if identity == nil || (len(identity) == 0 && len(safeDirectories) == 0) {
+		return []string{}
+	}
+
+	config := [][]string{}
+
+	for _, directory := range safeDirectories {
+		config = append(config, []string{"safe.directory", directory})
+	}
+
+	config = append(config, identity...)
+
+	// Passing config in the environment, rather than writing a .gitconfig, works for any user the container runs as
+	env := []string{fmt.Sprintf("GIT_CONFIG_COUNT=%d", len(config))}
+
+	for i, setting := range config {
+		env = append(env,
+			fmt.Sprintf("GIT_CONFIG_KEY_%d=%s", i, setting[0]),
+			fmt.Sprintf("GIT_CONFIG_VALUE_%d=%s", i, setting[1]))
+	}
+
+	return env
+}
+
+// getGitIdentity returns the name and email from the user's git config as key and value pairs, or nil if the project
+// isn't a git repository or git isn't installed. It is only read once as it is needed for every container.
+func (s *Site) getGitIdentity() [][]string {
+	if s.gitIdentityLoaded {
+		return s.gitIdentity
+	}
+
+	s.gitIdentityLoaded = true
+
+	workingDirectory := s.settings.Get("workingDirectory")
+
+	err := Command("git", "-C", workingDirectory, "rev-parse", "--is-inside-work-tree").Run()
+	if err != nil {
+		return nil
+	}
+
+	s.gitIdentity = [][]string{}
+
+	for _, key := range gitConfigKeys {
+		output, err := Command("git", "-C", workingDirectory, "config", "--get", key).Output()
+
+		value := strings.TrimSpace(string(output))
+		if err != nil || value == "" {
+			continue
+		}
+
+		s.gitIdentity = append(s.gitIdentity, []string{key, value})
+	}
+
+	return s.gitIdentity
+}
+
+// getGitSafeDirectories returns where the project's files, in workingDirectory, are mounted in a container, which are
+// the folders git needs to trust.
+func getGitSafeDirectories(workingDirectory string, mounts []mount.Mount) []string {
+	directories := []string{}
+
+	for _, volume := range mounts {
+		if volume.Source == workingDirectory || strings.HasPrefix(volume.Source, workingDirectory+string(filepath.Separator)) {
+			directories = append(directories, volume.Target)
+		}
+	}
+
+	return directories
+}
@@ -0,0 +1,94 @@
+package site
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/mount"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetGitEnv(t *testing.T) {
+	var tests = []struct {
+		name            string
+		identity        [][]string
+		safeDirectories []string
+		expected        []string
+	}{
+		{
+			"not a git repository",
+			nil,
+			[]string{"/var/www/html/wp-content/plugins/my-plugin"},
+			[]string{}},
+		{
+			"nothing to set",
+			[][]string{},
+			[]string{},
+			[]string{}},
+		{
+			"safe directories without an identity",
+			[][]string{},
+			[]string{"/var/www/html/wp-content/plugins/my-plugin"},
+			[]string{
+				"GIT_CONFIG_COUNT=1",
+				"GIT_CONFIG_KEY_0=safe.directory",
+				"GIT_CONFIG_VALUE_0=/var/www/html/wp-content/plugins/my-plugin"}},
+		{
+			"safe directories and identity",
+			[][]string{{"user.name", "Jane Doe"}, {"user.email", "jane@example.com"}},
+			[]string{"/var/www/html/wp-content/plugins/my-plugin", "/var/www/html/wp-content/themes/my-theme"},
+			[]string{
+				"GIT_CONFIG_COUNT=4",
+				"GIT_CONFIG_KEY_0=safe.directory",
+				"GIT_CONFIG_VALUE_0=/var/www/html/wp-content/plugins/my-plugin",
+				"GIT_CONFIG_KEY_1=safe.directory",
+				"GIT_CONFIG_VALUE_1=/var/www/html/wp-content/themes/my-theme",
+				"GIT_CONFIG_KEY_2=user.name",
+				"GIT_CONFIG_VALUE_2=Jane Doe",
+				"GIT_CONFIG_KEY_3=user.email",
+				"GIT_CONFIG_VALUE_3=jane@example.com"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := &Site{gitIdentity: test.identity, gitIdentityLoaded: true}
+
+			assert.Equal(t, test.expected, s.getGitEnv(test.safeDirectories))
+		})
+	}
+}
+
+func TestGetGitSafeDirectories(t *testing.T) {
+	var tests = []struct {
+		name     string
+		mounts   []mount.Mount
+		expected []string
+	}{
+		{
+			"no mounts",
+			[]mount.Mount{},
+			[]string{}},
+		{
+			"project folder",
+			[]mount.Mount{
+				{Source: "/home/jane/sites/my-site/wordpress", Target: "/var/www/html"},
+				{Source: "/home/jane/my-plugin", Target: "/var/www/html/wp-content/plugins/my-plugin"}},
+			[]string{"/var/www/html/wp-content/plugins/my-plugin"}},
+		{
+			"folders inside the project",
+			[]mount.Mount{
+				{Source: "/home/jane/my-plugin/plugins/one", Target: "/var/www/html/wp-content/plugins/one"},
+				{Source: "/home/jane/my-plugin/themes/two", Target: "/var/www/html/wp-content/themes/two"}},
+			[]string{"/var/www/html/wp-content/plugins/one", "/var/www/html/wp-content/themes/two"}},
+		{
+			"folders only sharing a prefix",
+			[]mount.Mount{
+				{Source: "/home/jane/my-plugin-old", Target: "/var/www/html/wp-content/plugins/my-plugin-old"}},
+			[]string{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, getGitSafeDirectories("/home/jane/my-plugin", test.mounts))
+		})
+	}
+}
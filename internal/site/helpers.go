@@ -6,19 +6,30 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"os"
+	"net/url"
 	"strings"
+	"time"
 
 	"github.com/ChrisWiegman/kana/internal/docker"
 )
 
 // checkStatusCode returns true on 200 or false.
 func checkStatusCode(checkURL string) (bool, error) {
-	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, checkURL, http.NoBody)
+	statusCode, err := getStatusCode(checkURL)
 	if err != nil {
 		return false, err
 	}
 
+	return statusCode == http.StatusOK || statusCode == http.StatusFound, nil
+}
+
+// getStatusCode returns the HTTP status code of the URL without following any redirects.
+func getStatusCode(checkURL string) (int, error) {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, checkURL, http.NoBody)
+	if err != nil {
+		return 0, err
+	}
+
 	// Ignore SSL check as we're using our self-signed cert for development
 	clientTransport := &http.Transport{
 		TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, //nolint:gosec
@@ -33,7 +44,7 @@ func checkStatusCode(checkURL string) (bool, error) {
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return false, err
+		return 0, err
 	}
 
 	defer func() {
@@ -42,48 +53,61 @@ func checkStatusCode(checkURL string) (bool, error) {
 		}
 	}()
 
-	if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusFound {
-		return true, nil
-	}
-
-	return false, nil
+	return resp.StatusCode, nil
 }
 
-// copyFile Copies a file on the user's host from one place to another.
-func copyFile(src, dest string) error {
-	srcStat, err := os.Stat(src)
+// getPage requests a page of the site with the given headers, without following any redirects, and returns its
+// status code and body.
+func getPage(pageURL string, header http.Header, timeout time.Duration) (int, []byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, http.NoBody)
 	if err != nil {
-		return err
+		return 0, []byte{}, err
 	}
 
-	if !srcStat.Mode().IsRegular() {
-		return fmt.Errorf("please enter a valid sql file")
+	for name, values := range header {
+		req.Header[name] = values
 	}
 
-	source, err := os.Open(src)
+	client := &http.Client{
+		// Ignore SSL check as we're using our self-signed cert for development
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}, //nolint:gosec
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	resp, err := client.Do(req)
 	if err != nil {
-		return err
+		return 0, []byte{}, err
 	}
 
-	defer func() {
-		if err = source.Close(); err != nil {
-			panic(err)
-		}
-	}()
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+
+	return resp.StatusCode, body, err
+}
 
-	destination, err := os.Create(dest)
+// getSitePageURL returns the full URL of a path on the site, or checks that a full URL is on the site's domain.
+func (s *Site) getSitePageURL(pageURL string) (string, error) {
+	siteURL, err := url.Parse(s.settings.GetURL())
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	defer func() {
-		if err = destination.Close(); err != nil {
-			panic(err)
-		}
-	}()
+	requestURL, err := siteURL.Parse(pageURL)
+	if err != nil {
+		return "", fmt.Errorf("the URL, %s, is not valid: %s", pageURL, err)
+	}
 
-	_, err = io.Copy(destination, source)
-	return err
+	if requestURL.Host != siteURL.Host {
+		return "", fmt.Errorf("the URL, %s, must be a page on the site, such as / or %s/wp-admin/", pageURL, siteURL.String())
+	}
+
+	return requestURL.String(), nil
 }
 
 // handleImageError Handles errors related to image detection and provides more helpful error messages.
@@ -95,11 +119,7 @@ func (s *Site) handleImageError(container *docker.ContainerConfig, err error) er
 				"the PHP version in your configuration, %s, is invalid. See https://hub.docker.com/_/wordpress for a list of supported versions",
 				s.settings.Get("php"))
 		case "database":
-			databaseURL := "https://hub.docker.com/_/mariadb"
-
-			if s.settings.Get("database") == "mysql" {
-				databaseURL = "https://hub.docker.com/_/mysql"
-			}
+			databaseURL := fmt.Sprintf("https://hub.docker.com/_/%s", s.settings.Get("database"))
 
 			return fmt.Errorf(
 				"the database version in your configuration, %s, is invalid. See %s for a list of supported versions",
@@ -107,5 +127,13 @@ func (s *Site) handleImageError(container *docker.ContainerConfig, err error) er
 		}
 	}
 
+	// Docker downloads images itself so it doesn't use the proxy server Kana passes on to the site's containers
+	if s.settings.GetProxy().IsSet() {
+		dockerInfo, infoErr := s.dockerClient.Info()
+		if infoErr == nil && dockerInfo.HTTPProxy == "" && dockerInfo.HTTPSProxy == "" {
+			return fmt.Errorf("%w. Docker isn't using a proxy server to download images so set your proxy in Docker's settings too", err)
+		}
+	}
+
 	return err
 }
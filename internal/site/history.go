@@ -0,0 +1,65 @@
+package site
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/history"
+)
+
+// historyCommands are the wp-cli commands, by their first two words, run with `kana wp` that are added to the site's
+// history as they change the site.
+var historyCommands = map[string][]string{
+	"core":           {"download", "install", "multisite-convert", "update", "update-db"},
+	"db":             {"import", "reset"},
+	"plugin":         {"activate", "deactivate", "delete", "install", "uninstall", "update"},
+	"search-replace": {},
+	"theme":          {"activate", "delete", "install", "update"},
+}
+
+// GetHistory returns the events that got the site into its current state, oldest first. If limit is greater than zero
+// only the newest events are returned.
+func (s *Site) GetHistory(limit int) ([]history.Entry, error) {
+	return history.Get(s.settings.Get("siteDirectory"), limit)
+}
+
+// ClearHistory removes the site's history.
+func (s *Site) ClearHistory() error {
+	return history.Clear(s.settings.Get("siteDirectory"))
+}
+
+// RecordWPCliCommand adds a wp-cli command run with `kana wp` to the site's history if it changes the site.
+func (s *Site) RecordWPCliCommand(command []string) {
+	if len(command) == 0 {
+		return
+	}
+
+	subCommands, ok := historyCommands[command[0]]
+	if !ok || (len(subCommands) > 0 && (len(command) < 2 || !slices.Contains(subCommands, command[1]))) {
+		return
+	}
+
+	s.recordHistory("wp-cli command", fmt.Sprintf("wp %s", strings.Join(command, " ")))
+}
+
+// recordHistory adds an event to the site's history. The history is only a record so failing to write it never stops
+// the command that caused the event.
+func (s *Site) recordHistory(event, details string) {
+	_ = history.Record(s.settings.Get("siteDirectory"), event, details)
+}
+
+// recordExtensionVersions adds the installed versions of the named plugins or themes to the site's history.
+func (s *Site) recordExtensionVersions(extensionType, action string, names []string, consoleOutput *console.Console) {
+	extensions, err := s.GetExtensions(extensionType, consoleOutput)
+	if err != nil {
+		return
+	}
+
+	for _, extension := range extensions {
+		if slices.Contains(names, extension.Name) {
+			s.recordHistory(fmt.Sprintf("%s %s", extensionType, action), fmt.Sprintf("%s %s", extension.Name, extension.Version))
+		}
+	}
+}
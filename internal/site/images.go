@@ -0,0 +1,182 @@
+package site
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ChrisWiegman/kana/internal/batch"
+	"github.com/ChrisWiegman/kana/internal/console"
+
+	"github.com/docker/docker/api/types"
+)
+
+// sharedSiteName is shown as the site of containers, such as Traefik, that are shared by every site.
+const sharedSiteName = "(shared)"
+
+// ImageUpdate is the result of downloading the newest version of an image used by running sites.
+type ImageUpdate struct {
+	Image  string   `json:"image"`
+	Status string   `json:"status"` // updated, current, pinned or failed
+	Error  string   `json:"error,omitempty"`
+	Sites  []string `json:"sites"`
+}
+
+// StaleContainer is a running container whose image has a newer download than the one it was started from.
+type StaleContainer struct {
+	Site      string `json:"site"`
+	Container string `json:"container"`
+	Image     string `json:"image"`
+	Restarted bool   `json:"restarted"`
+}
+
+// ImagesReport lists the images used by running sites and the containers left running older versions of them.
+type ImagesReport struct {
+	Images []ImageUpdate    `json:"images"`
+	Stale  []StaleContainer `json:"stale"`
+}
+
+// UpdateImages downloads the newest version of every image used by running sites, whatever the updateInterval setting,
+// and reports the containers still running an older version. Images are only updated within the tag they're pinned to,
+// such as a database version, and images pinned to a digest are left alone. If restart is true stale containers are
+// recreated from their new image with the same configuration. Up to concurrency images are downloaded at once, with
+// onDone called as each finishes.
+func (s *Site) UpdateImages(
+	restart bool,
+	concurrency int,
+	onDone func(result batch.Result, finished, total int),
+	consoleOutput *console.Console) (ImagesReport, error) {
+	report := ImagesReport{Images: []ImageUpdate{}, Stale: []StaleContainer{}}
+
+	containers, err := s.dockerClient.ContainerList("")
+	if err != nil {
+		return report, err
+	}
+
+	globalContainers, err := s.dockerClient.ContainerListGlobal()
+	if err != nil {
+		return report, err
+	}
+
+	containers = append(containers, globalContainers...)
+
+	images := map[string]*ImageUpdate{}
+	imageNames := []string{}
+	containerImages := make([]string, len(containers))
+
+	for i := range containers {
+		imageName := s.getContainerImage(&containers[i])
+		containerImages[i] = imageName
+
+		if _, ok := images[imageName]; !ok {
+			images[imageName] = &ImageUpdate{Image: imageName, Sites: []string{}}
+			imageNames = append(imageNames, imageName)
+		}
+
+		site := getContainerSite(&containers[i])
+
+		if !slices.Contains(images[imageName].Sites, site) {
+			images[imageName].Sites = append(images[imageName].Sites, site)
+		}
+	}
+
+	slices.Sort(imageNames)
+
+	pulls := []string{}
+
+	for _, imageName := range imageNames {
+		if strings.Contains(imageName, "@") {
+			images[imageName].Status = "pinned"
+
+			continue
+		}
+
+		pulls = append(pulls, imageName)
+	}
+
+	// Parallel downloads would interleave their progress so it is only shown when downloading one at a time
+	suppressOutput := consoleOutput.JSON || concurrency > 1
+	appDirectory := s.settings.Get("appDirectory")
+
+	if len(pulls) > 0 {
+		consoleOutput.Println(fmt.Sprintf("Downloading the newest version of %d image(s).", len(pulls)))
+	}
+
+	// Each image's update is only changed by its own task so they don't need to be guarded
+	batch.Run(pulls, concurrency, func(imageName string) error {
+		update := images[imageName]
+
+		before, _ := s.dockerClient.ImageID(imageName)
+
+		pullErr := s.dockerClient.PullImage(context.Background(), imageName, appDirectory, suppressOutput)
+		if pullErr != nil {
+			update.Status = "failed"
+			update.Error = pullErr.Error()
+
+			return pullErr
+		}
+
+		after, _ := s.dockerClient.ImageID(imageName)
+
+		update.Status = "current"
+
+		if after != before {
+			update.Status = "updated"
+		}
+
+		return nil
+	}, onDone)
+
+	for _, imageName := range imageNames {
+		report.Images = append(report.Images, *images[imageName])
+	}
+
+	for i := range containers {
+		current, _ := s.dockerClient.ImageID(containerImages[i])
+		if current == "" || current == containers[i].ImageID {
+			continue
+		}
+
+		stale := StaleContainer{
+			Site:      getContainerSite(&containers[i]),
+			Container: strings.TrimPrefix(containers[i].Names[0], "/"),
+			Image:     containerImages[i],
+		}
+
+		if restart {
+			consoleOutput.Println(fmt.Sprintf("Recreating %s from its new image.", stale.Container))
+
+			err = s.dockerClient.ContainerRecreate(containers[i].ID)
+			if err != nil {
+				return report, err
+			}
+
+			stale.Restarted = true
+		}
+
+		report.Stale = append(report.Stale, stale)
+	}
+
+	return report, nil
+}
+
+// getContainerImage returns the name of the image a container was created from. Docker lists a container by its
+// image's ID once a newer download of the image has taken its name so the name is read from its configuration.
+func (s *Site) getContainerImage(container *types.Container) string {
+	results, err := s.dockerClient.ContainerInspect(container.ID)
+	if err != nil || results.Config == nil || results.Config.Image == "" {
+		return container.Image
+	}
+
+	return results.Config.Image
+}
+
+// getContainerSite returns the name of the site a container belongs to.
+func getContainerSite(container *types.Container) string {
+	if site, ok := container.Labels["kana.site"]; ok {
+		return site
+	}
+
+	return sharedSiteName
+}
@@ -0,0 +1,328 @@
+package site
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/helpers"
+)
+
+// ImportSite restores the parts of a site in an archive created by ExportSite after verifying the archive against its manifest.
+// Unless force is true the import also fails if the archive came from a different version of WordPress or PHP or if its database
+// needs plugins the site doesn't have.
+func (s *Site) ImportSite(file string, force bool, consoleOutput *console.Console) (ExportManifest, error) {
+	manifest, err := s.importArchive(file, force, consoleOutput)
+	if err != nil {
+		return manifest, err
+	}
+
+	s.recordHistory("archive imported", fmt.Sprintf("%s (%s)", filepath.Base(file), strings.Join(manifest.Parts, ", ")))
+
+	// Plugins are restored after the database so only make the site safe once everything is in place
+	if slices.Contains(manifest.Parts, "db") {
+		return manifest, s.MakeImportSafe(consoleOutput)
+	}
+
+	return manifest, nil
+}
+
+// importArchive verifies an archive created by ExportSite, or a full backup, and restores each of its parts into the site.
+func (s *Site) importArchive(file string, force bool, consoleOutput *console.Console) (ExportManifest, error) {
+	if !filepath.IsAbs(file) {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return ExportManifest{}, err
+		}
+
+		file = filepath.Join(cwd, file)
+	}
+
+	zipReader, err := zip.OpenReader(file)
+	if err != nil {
+		return ExportManifest{}, fmt.Errorf("unable to open the archive %s: %s", file, err)
+	}
+
+	defer zipReader.Close()
+
+	manifest, err := readExportManifest(&zipReader.Reader)
+	if err != nil {
+		return manifest, err
+	}
+
+	mismatches, err := s.getManifestMismatches(manifest, consoleOutput)
+	if err != nil {
+		return manifest, err
+	}
+
+	if len(mismatches) > 0 && !force {
+		return manifest, fmt.Errorf(
+			"the archive doesn't match this site:\n- %s\nUse --force to import it anyway",
+			strings.Join(mismatches, "\n- "))
+	}
+
+	for _, mismatch := range mismatches {
+		consoleOutput.Warn(mismatch)
+	}
+
+	for _, part := range manifest.Parts {
+		err = s.importPart(&zipReader.Reader, manifest, part, consoleOutput)
+		if err != nil {
+			return manifest, fmt.Errorf("unable to import the site's %s: %s", part, err)
+		}
+	}
+
+	return manifest, nil
+}
+
+// readExportManifest returns the manifest of an export archive, making sure the archive contains exactly the files
+// listed in it and that none of them have changed.
+func readExportManifest(zipReader *zip.Reader) (ExportManifest, error) {
+	manifest := ExportManifest{}
+
+	manifestFile, err := zipReader.Open(exportManifestFile)
+	if err != nil {
+		return manifest, fmt.Errorf("the archive is not a Kana export as it has no %s manifest", exportManifestFile)
+	}
+
+	defer manifestFile.Close()
+
+	err = json.NewDecoder(manifestFile).Decode(&manifest)
+	if err != nil {
+		return manifest, fmt.Errorf("the archive's manifest is not valid: %s", err)
+	}
+
+	if manifest.ManifestVersion < 1 || manifest.ManifestVersion > exportManifestVersion {
+		return manifest, fmt.Errorf(
+			"the archive was created by a version of Kana, %s, that this version can't import. Please update Kana and try again",
+			manifest.KanaVersion)
+	}
+
+	archivedFiles := []string{}
+
+	for _, file := range zipReader.File {
+		if file.Name == exportManifestFile {
+			continue
+		}
+
+		expectedChecksum, ok := manifest.Files[file.Name]
+		if !ok {
+			return manifest, fmt.Errorf("the archive contains %s, which is not in its manifest", file.Name)
+		}
+
+		checksum, err := helpers.FileChecksum(file)
+		if err != nil {
+			return manifest, err
+		}
+
+		if checksum != expectedChecksum {
+			return manifest, fmt.Errorf("%s in the archive doesn't match its checksum. The archive may be corrupt or have been changed", file.Name)
+		}
+
+		archivedFiles = append(archivedFiles, file.Name)
+	}
+
+	for file := range manifest.Files {
+		if !slices.Contains(archivedFiles, file) {
+			return manifest, fmt.Errorf("the archive is missing %s, which is listed in its manifest", file)
+		}
+	}
+
+	return manifest, nil
+}
+
+// getManifestMismatches returns the differences between the site and the one an archive came from that are likely to break the site.
+func (s *Site) getManifestMismatches(manifest ExportManifest, consoleOutput *console.Console) ([]string, error) {
+	mismatches := []string{}
+
+	wordPressVersion, err := s.getWordPressVersion(consoleOutput)
+	if err != nil {
+		return mismatches, err
+	}
+
+	if manifest.WordPressVersion != wordPressVersion {
+		mismatches = append(mismatches, fmt.Sprintf(
+			"the archive is from WordPress %s but this site runs WordPress %s", manifest.WordPressVersion, wordPressVersion))
+	}
+
+	if manifest.PHPVersion != s.settings.Get("php") {
+		mismatches = append(mismatches, fmt.Sprintf(
+			"the archive is from PHP %s but this site runs PHP %s", manifest.PHPVersion, s.settings.Get("php")))
+	}
+
+	if !slices.Contains(manifest.Parts, "db") {
+		return mismatches, nil
+	}
+
+	isUsingSQLite, err := s.isUsingSQLite()
+	if err != nil {
+		return mismatches, err
+	}
+
+	isUsingPostgres, err := s.isUsingPostgres()
+	if err != nil {
+		return mismatches, err
+	}
+
+	// MariaDB and MySQL dumps can be imported into either but SQLite and PostgreSQL databases only into their own kind
+	if (manifest.Database == "sqlite") != isUsingSQLite || (manifest.Database == "postgres") != isUsingPostgres {
+		return mismatches, fmt.Errorf("the archive's %s database can't be imported into this site's %s database",
+			manifest.Database, s.settings.Get("database"))
+	}
+
+	// Plugins in the archive will be installed by the import itself
+	if slices.Contains(manifest.Parts, "plugins") {
+		return mismatches, nil
+	}
+
+	plugins, err := s.GetExtensions("plugin", consoleOutput)
+	if err != nil {
+		return mismatches, err
+	}
+
+	installedPlugins := []string{}
+
+	for _, plugin := range plugins {
+		installedPlugins = append(installedPlugins, plugin.Name)
+	}
+
+	for _, plugin := range manifest.Plugins {
+		if strings.HasPrefix(plugin.Status, "active") && !slices.Contains(installedPlugins, plugin.Name) {
+			mismatches = append(mismatches, fmt.Sprintf(
+				"the plugin %s is active in the archive's database but isn't installed on this site", plugin.Name))
+		}
+	}
+
+	return mismatches, nil
+}
+
+// importPart restores one of the ExportParts from an export archive.
+func (s *Site) importPart(zipReader *zip.Reader, manifest ExportManifest, part string, consoleOutput *console.Console) error {
+	wordPressDirectory, err := s.getWordPressDirectory()
+	if err != nil {
+		return err
+	}
+
+	switch part {
+	case "db":
+		return s.importDatabaseFromArchive(zipReader, manifest, consoleOutput)
+	case "uploads", "plugins", "themes":
+		consoleOutput.Println(fmt.Sprintf("Restoring the %s.", part))
+
+		// Plugins and themes being developed are mounted into the site so are never overwritten
+		mountedProjects, err := s.getMountedProjects(strings.TrimSuffix(part, "s"))
+		if err != nil {
+			return err
+		}
+
+		for _, file := range zipReader.File {
+			if !strings.HasPrefix(file.Name, fmt.Sprintf("wp-content/%s/", part)) {
+				continue
+			}
+
+			project, _, _ := strings.Cut(strings.TrimPrefix(file.Name, fmt.Sprintf("wp-content/%s/", part)), "/")
+			if _, ok := mountedProjects[project]; ok {
+				continue
+			}
+
+			err = extractArchiveFile(file, wordPressDirectory)
+			if err != nil {
+				return err
+			}
+		}
+	case "settings":
+		consoleOutput.Println("Restoring the site's config.")
+
+		for _, file := range zipReader.File {
+			if file.Name == ".kana.json" {
+				return extractArchiveFile(file, s.settings.Get("workingDirectory"))
+			}
+		}
+	}
+
+	return nil
+}
+
+// importDatabaseFromArchive replaces the site's database with the one in an export archive.
+func (s *Site) importDatabaseFromArchive(zipReader *zip.Reader, manifest ExportManifest, consoleOutput *console.Console) error {
+	for _, file := range zipReader.File {
+		switch file.Name {
+		case "database.sqlite":
+			consoleOutput.Println("Restoring the database.")
+
+			return extractArchiveFile(file, filepath.Dir(s.GetSQLiteDatabaseFile()), filepath.Base(s.GetSQLiteDatabaseFile()))
+		case "database.sql":
+			err := extractArchiveFile(file, s.settings.Get("siteDirectory"), "import.sql")
+			if err != nil {
+				return err
+			}
+
+			databaseFile := filepath.Join(s.settings.Get("siteDirectory"), "import.sql")
+
+			defer os.Remove(databaseFile)
+
+			err = s.resetDatabase(consoleOutput)
+			if err != nil {
+				return err
+			}
+
+			consoleOutput.Println("Importing the database file.")
+
+			err = s.streamImport(databaseFile, consoleOutput)
+			if err != nil {
+				return err
+			}
+
+			if manifest.Domain != s.settings.GetDomain() {
+				return s.replaceDomain(manifest.Domain, consoleOutput)
+			}
+
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// extractArchiveFile writes a file from an archive to the same path in the destination directory or, if given, to the given name in it.
+func extractArchiveFile(file *zip.File, destinationDirectory string, name ...string) error {
+	destination := filepath.Join(destinationDirectory, filepath.FromSlash(file.Name))
+
+	if len(name) > 0 {
+		destination = filepath.Join(destinationDirectory, name[0])
+	}
+
+	relativePath, err := filepath.Rel(destinationDirectory, destination)
+	if err != nil || strings.HasPrefix(relativePath, "..") {
+		return fmt.Errorf("the archive file %s is outside of the site", file.Name)
+	}
+
+	err = os.MkdirAll(filepath.Dir(destination), os.FileMode(defaultDirPermissions))
+	if err != nil {
+		return err
+	}
+
+	contents, err := file.Open()
+	if err != nil {
+		return err
+	}
+
+	defer contents.Close()
+
+	destinationFile, err := os.OpenFile(destination, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, file.Mode().Perm())
+	if err != nil {
+		return err
+	}
+
+	defer destinationFile.Close()
+
+	_, err = io.Copy(destinationFile, contents)
+
+	return err
+}
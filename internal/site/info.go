@@ -0,0 +1,142 @@
+package site
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/settings"
+)
+
+// Info is a summary of a site's effective configuration, to share with a teammate or check at a glance.
+type Info struct {
+	Name        string             `json:"name"`
+	Type        string             `json:"type"`
+	Running     bool               `json:"running"`
+	URLs        InfoURLs           `json:"urls"`
+	Admin       UserCredentials    `json:"admin"`
+	PHP         string             `json:"php"`
+	WordPress   string             `json:"wordpress,omitempty"` // Only known while the site is running
+	Database    InfoDatabase       `json:"database"`
+	Projects    []settings.Project `json:"projects"` // The plugins and themes mounted into the site
+	Services    []ServiceStatus    `json:"services"`
+	Directories InfoDirectories    `json:"directories"`
+}
+
+// InfoURLs are the URLs the site and its services can be opened at.
+type InfoURLs struct {
+	Site       string `json:"site"`
+	Admin      string `json:"admin"`
+	Mailpit    string `json:"mailpit,omitempty"`
+	PHPMyAdmin string `json:"phpmyadmin,omitempty"`
+}
+
+// InfoDatabase is the site's database server, or SQLite file, and how to connect to it.
+type InfoDatabase struct {
+	Engine      string               `json:"engine"`
+	Version     string               `json:"version,omitempty"`
+	File        string               `json:"file,omitempty"`        // SQLite sites only
+	Credentials *DatabaseCredentials `json:"credentials,omitempty"` // Only known while the site is running
+}
+
+// InfoDirectories are the folders on the host that make up the site.
+type InfoDirectories struct {
+	Working   string `json:"working"`
+	Site      string `json:"site"`
+	WordPress string `json:"wordpress"`
+	Logs      string `json:"logs"`
+	Backups   string `json:"backups"`
+}
+
+// GetInfo returns a summary of the site's effective configuration. The WordPress version, database credentials and
+// service URLs are only included while the site is running.
+func (s *Site) GetInfo(consoleOutput *console.Console) (Info, error) {
+	info := Info{
+		Name:    s.settings.Get("name"),
+		Type:    s.settings.Get("type"),
+		Running: s.IsSiteRunning(),
+		URLs: InfoURLs{
+			Site:  s.settings.GetURL(),
+			Admin: s.settings.GetURL() + "/wp-admin/",
+		},
+		Admin: UserCredentials{
+			Username: s.settings.Get("adminUser"),
+			Password: s.settings.Get("adminPassword"),
+			Email:    s.settings.Get("adminEmail"),
+			Role:     "administrator",
+		},
+		PHP: s.settings.Get("php"),
+		Database: InfoDatabase{
+			Engine:  s.settings.Get("database"),
+			Version: s.settings.Get("databaseVersion"),
+		},
+		Directories: InfoDirectories{
+			Working: s.settings.Get("workingDirectory"),
+			Site:    s.settings.Get("siteDirectory"),
+			Logs:    s.getLogDirectory(),
+			Backups: filepath.Join(s.settings.Get("siteDirectory"), "backups"),
+		},
+	}
+
+	var err error
+
+	info.Directories.WordPress, err = s.getWordPressDirectory()
+	if err != nil {
+		return info, err
+	}
+
+	info.Projects, err = s.getProjects()
+	if err != nil {
+		return info, err
+	}
+
+	isUsingSQLite, err := s.isUsingSQLite()
+	if err != nil {
+		return info, err
+	}
+
+	if isUsingSQLite {
+		info.Database = InfoDatabase{
+			Engine: "sqlite",
+			File:   s.GetSQLiteDatabaseFile(),
+		}
+	}
+
+	info.Services, err = s.GetStatus()
+	if err != nil {
+		return info, err
+	}
+
+	if !info.Running {
+		return info, nil
+	}
+
+	info.WordPress, err = s.getWordPressVersion(consoleOutput)
+	if err != nil {
+		return info, err
+	}
+
+	if !isUsingSQLite {
+		credentials, err := s.GetDatabaseCredentials()
+		if err != nil {
+			return info, err
+		}
+
+		info.Database.Credentials = &credentials
+	}
+
+	for _, service := range info.Services {
+		if service.Status != "running" {
+			continue
+		}
+
+		switch service.Service {
+		case "mailpit":
+			info.URLs.Mailpit = fmt.Sprintf("%s://mailpit-%s", s.settings.GetProtocol(), s.settings.GetDomain())
+		case "phpmyadmin":
+			info.URLs.PHPMyAdmin = fmt.Sprintf("%s://phpmyadmin-%s", s.settings.GetProtocol(), s.settings.GetDomain())
+		}
+	}
+
+	return info, nil
+}
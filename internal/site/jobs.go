@@ -0,0 +1,162 @@
+package site
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+)
+
+// Job is a pending or failed Action Scheduler action or a scheduled wp-cron event.
+type Job struct {
+	Source     string `json:"source"` // "action-scheduler" or "cron"
+	ID         string `json:"id"`
+	Hook       string `json:"hook"`
+	Status     string `json:"status"`
+	Group      string `json:"group"`
+	Scheduled  string `json:"scheduled"`
+	Recurrence string `json:"recurrence"`
+}
+
+// retryFailedActionsScript queues a new copy of each failed Action Scheduler action and deletes the failed one,
+// printing the IDs of the actions it retried.
+const retryFailedActionsScript = `$store = ActionScheduler::store();
+foreach ( $store->query_actions( array( 'status' => ActionScheduler_Store::STATUS_FAILED, 'per_page' => -1 ) ) as $id ) {
+	$action = $store->fetch_action( $id );
+	as_enqueue_async_action( $action->get_hook(), $action->get_args(), $action->get_group() );
+	$store->delete_action( $id );
+	echo $id . PHP_EOL;
+}`
+
+// GetJobs returns the site's pending and failed Action Scheduler actions, if Action Scheduler is installed,
+// followed by its scheduled wp-cron events.
+func (s *Site) GetJobs(consoleOutput *console.Console) ([]Job, error) {
+	jobs := []Job{}
+
+	hasActionScheduler, err := s.hasActionScheduler(consoleOutput)
+	if err != nil {
+		return jobs, err
+	}
+
+	if hasActionScheduler {
+		for _, status := range []string{"pending", "failed"} {
+			actions := []struct {
+				ID            json.Number `json:"id"`
+				Hook          string      `json:"hook"`
+				Status        string      `json:"status"`
+				Group         string      `json:"group"`
+				ScheduledDate string      `json:"scheduled_date"`
+				Recurring     string      `json:"recurring"`
+			}{}
+
+			err = s.wpCliJSON([]string{
+				"action-scheduler", "action", "list",
+				fmt.Sprintf("--status=%s", status),
+				"--per_page=0",
+				"--fields=id,hook,status,group,scheduled_date,recurring",
+				"--format=json"},
+				&actions,
+				consoleOutput)
+			if err != nil {
+				return jobs, err
+			}
+
+			for _, action := range actions {
+				jobs = append(jobs, Job{
+					Source:     "action-scheduler",
+					ID:         action.ID.String(),
+					Hook:       action.Hook,
+					Status:     action.Status,
+					Group:      action.Group,
+					Scheduled:  action.ScheduledDate,
+					Recurrence: action.Recurring,
+				})
+			}
+		}
+	}
+
+	events := []struct {
+		Hook       string `json:"hook"`
+		NextRunGMT string `json:"next_run_gmt"`
+		Recurrence string `json:"recurrence"`
+	}{}
+
+	err = s.wpCliJSON([]string{"cron", "event", "list", "--fields=hook,next_run_gmt,recurrence", "--format=json"}, &events, consoleOutput)
+	if err != nil {
+		return jobs, err
+	}
+
+	for _, event := range events {
+		jobs = append(jobs, Job{
+			Source:     "cron",
+			Hook:       event.Hook,
+			Status:     "pending",
+			Scheduled:  event.NextRunGMT,
+			Recurrence: event.Recurrence,
+		})
+	}
+
+	return jobs, nil
+}
+
+// RunJobs runs the wp-cron events that are due and the pending Action Scheduler actions.
+func (s *Site) RunJobs(consoleOutput *console.Console) error {
+	hasActionScheduler, err := s.hasActionScheduler(consoleOutput)
+	if err != nil {
+		return err
+	}
+
+	if hasActionScheduler {
+		err = s.wpCliOrError([]string{"action-scheduler", "run"}, consoleOutput)
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.wpCliOrError([]string{"cron", "event", "run", "--due-now"}, consoleOutput)
+}
+
+// RetryFailedJobs queues the failed Action Scheduler actions to run again, returning how many were retried.
+func (s *Site) RetryFailedJobs(consoleOutput *console.Console) (int, error) {
+	hasActionScheduler, err := s.hasActionScheduler(consoleOutput)
+	if err != nil {
+		return 0, err
+	}
+
+	if !hasActionScheduler {
+		return 0, fmt.Errorf("no active plugin has loaded Action Scheduler so there are no failed actions to retry")
+	}
+
+	code, output, err := s.WPCli([]string{"eval", retryFailedActionsScript}, false, consoleOutput)
+	if err != nil {
+		return 0, err
+	}
+
+	if code != 0 {
+		return 0, fmt.Errorf("unable to retry the failed actions: %s", strings.TrimSpace(output))
+	}
+
+	return len(strings.Fields(output)), nil
+}
+
+// hasActionScheduler returns true if an active plugin, such as WooCommerce, has loaded Action Scheduler.
+func (s *Site) hasActionScheduler(consoleOutput *console.Console) (bool, error) {
+	code, _, err := s.WPCli([]string{"cli", "has-command", "action-scheduler action list"}, false, consoleOutput)
+
+	return code == 0 && err == nil, err
+}
+
+// wpCliJSON runs a wp-cli command and decodes its JSON output into response.
+func (s *Site) wpCliJSON(command []string, response interface{}, consoleOutput *console.Console) error {
+	code, output, err := s.WPCli(command, false, consoleOutput)
+	if err != nil {
+		return err
+	}
+
+	if code != 0 {
+		return fmt.Errorf("unable to run wp %s: %s", strings.Join(command[:2], " "), strings.TrimSpace(output))
+	}
+
+	return json.Unmarshal([]byte(output), response)
+}
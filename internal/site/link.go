@@ -0,0 +1,116 @@
+package site
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ChrisWiegman/kana/internal/helpers"
+)
+
+// LinkInfo describes the site the current directory is linked to.
+type LinkInfo struct {
+	Name   string `json:"name"`
+	Path   string `json:"path"`
+	Linked bool   `json:"linked"`
+}
+
+// GetLinkInfo returns the site linked to the current directory, if any.
+func (s *Site) GetLinkInfo() (LinkInfo, error) {
+	linkInfo := LinkInfo{
+		Name: s.settings.Get("name"),
+	}
+
+	link, err := s.settings.GetSiteDirectoryLink(linkInfo.Name)
+	if err != nil {
+		// A directory that has never been started isn't linked to anything yet
+		if s.settings.GetBool("isNew") {
+			return linkInfo, nil
+		}
+
+		return linkInfo, err
+	}
+
+	linkInfo.Path = link
+	linkInfo.Linked = link == s.settings.Get("workingDirectory")
+
+	return linkInfo, nil
+}
+
+// LinkSite links the current directory to an existing site so that Kana commands run in the directory use that site.
+func (s *Site) LinkSite(name string) (LinkInfo, error) {
+	name = helpers.SanitizeSiteName(name)
+	workingDirectory := s.settings.Get("workingDirectory")
+	linkInfo := LinkInfo{Name: name, Path: workingDirectory, Linked: true}
+
+	link, err := s.settings.GetSiteDirectoryLink(name)
+	if err != nil || link == workingDirectory {
+		return linkInfo, err
+	}
+
+	currentLink, err := s.GetLinkInfo()
+	if err != nil {
+		return linkInfo, err
+	}
+
+	if currentLink.Linked {
+		return linkInfo, fmt.Errorf("the current directory is already linked to %s. Run `kana unlink` first", currentLink.Name)
+	}
+
+	// Only move a site from another project directory if that directory no longer exists
+	if !strings.HasPrefix(link, s.settings.Get("sitesDirectory")) {
+		_, err = os.Stat(link)
+		if err == nil {
+			return linkInfo, fmt.Errorf("the site %s is already linked to %s. Run `kana unlink` from that directory first", name, link)
+		}
+	}
+
+	err = s.ensureStopped(name)
+	if err != nil {
+		return linkInfo, err
+	}
+
+	return linkInfo, s.settings.LinkSite(name, workingDirectory)
+}
+
+// UnlinkSite removes the link between the current directory and its site. The site, its database and
+// the files in the directory are kept.
+func (s *Site) UnlinkSite() error {
+	currentLink, err := s.GetLinkInfo()
+	if err != nil {
+		return err
+	}
+
+	if !currentLink.Linked {
+		return fmt.Errorf("the current directory is not linked to a site")
+	}
+
+	// Sites named after their directory are linked automatically so unlinking them wouldn't change anything
+	if currentLink.Name == helpers.SanitizeSiteName(filepath.Base(currentLink.Path)) {
+		return fmt.Errorf(
+			"%s was created in the current directory and can't be unlinked from it. Use `kana destroy` to remove it",
+			currentLink.Name)
+	}
+
+	err = s.ensureStopped(currentLink.Name)
+	if err != nil {
+		return err
+	}
+
+	return s.settings.UnlinkSite(currentLink.Name)
+}
+
+// ensureStopped returns an error if the named site is running as its files can't be changed while they're mounted.
+func (s *Site) ensureStopped(name string) error {
+	containers, err := s.dockerClient.ContainerList(name)
+	if err != nil {
+		return err
+	}
+
+	if len(containers) != 0 {
+		return fmt.Errorf("the site %s is running. Stop it with `kana stop --name %s` first", name, name)
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package site
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/ChrisWiegman/kana/internal/helpers"
+	"github.com/ChrisWiegman/kana/internal/lock"
+	"github.com/ChrisWiegman/kana/internal/settings"
+)
+
+// Lock stops other kana commands from changing the site until the returned lock is released, waiting for any command
+// already changing it unless wait is false. Nothing is locked for a site that doesn't exist yet unless it is being
+// started as only start creates it.
+func (s *Site) Lock(command string, wait bool, onWait func(lock.Holder)) (*lock.Lock, error) {
+	return lockSiteDirectory(s.settings.Get("siteDirectory"), command, wait, onWait)
+}
+
+// LockSite locks another site the command changes, such as the site `kana link` links the current directory to, in the
+// same way as Lock. Nothing more is locked if it is the current site as that is already locked by the command.
+func (s *Site) LockSite(name, command string, wait bool, onWait func(lock.Holder)) (*lock.Lock, error) {
+	name = helpers.SanitizeSiteName(name)
+
+	if name == s.settings.Get("name") {
+		return nil, nil
+	}
+
+	return lockSiteDirectory(filepath.Join(s.settings.Get("sitesDirectory"), name), command, wait, onWait)
+}
+
+func lockSiteDirectory(siteDirectory, command string, wait bool, onWait func(lock.Holder)) (*lock.Lock, error) {
+	// A site whose files are on a disk that isn't connected can still be destroyed
+	if command != "destroy" {
+		err := settings.EnsureSiteDirectory(siteDirectory)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err := os.Stat(siteDirectory)
+	if os.IsNotExist(err) {
+		if command != "start" {
+			return nil, nil
+		}
+
+		err = os.MkdirAll(siteDirectory, os.FileMode(defaultDirPermissions))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return lock.Acquire(siteDirectory, command, wait, onWait)
+}
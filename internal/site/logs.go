@@ -0,0 +1,346 @@
+package site
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ChrisWiegman/kana/internal/docker"
+
+	"github.com/docker/docker/api/types/mount"
+)
+
+const (
+	phpErrorLogFile      = "php-error.log"
+	phpLogContainerPath  = "/var/log/kana"
+	phpLogIniFile        = "kana-logs.ini"
+	phpLogFollowInterval = 500 * time.Millisecond
+)
+
+// LogContainers are the site containers that `kana logs` can show the output of.
+var LogContainers = []string{"database", "mailpit", "phpmyadmin", "redis", "wordpress"}
+
+// phpLogEntryStart matches the timestamp PHP starts each log entry with. Lines without it, such as a stack trace,
+// belong to the entry before them.
+var phpLogEntryStart = regexp.MustCompile(`^\[\d{2}-[A-Za-z]{3}-\d{4} `)
+
+// getPHPLogMounts returns the mounts that send PHP's errors to a log in the site directory, rather than Apache's
+// output where they're mixed in with every request. The log directory is mounted directly, and writable by anyone,
+// so Apache's user can write to it whatever the permissions of the site directory.
+func (s *Site) getPHPLogMounts() ([]mount.Mount, error) {
+	logDirectory := s.getLogDirectory()
+
+	err := os.MkdirAll(logDirectory, os.FileMode(defaultDirPermissions))
+	if err != nil {
+		return []mount.Mount{}, err
+	}
+
+	err = os.Chmod(logDirectory, 0o777) //nolint:gosec
+	if err != nil {
+		return []mount.Mount{}, err
+	}
+
+	iniFile := filepath.Join(s.settings.Get("siteDirectory"), "config", phpLogIniFile)
+
+	err = os.MkdirAll(filepath.Dir(iniFile), os.FileMode(defaultDirPermissions))
+	if err != nil {
+		return []mount.Mount{}, err
+	}
+
+	iniSettings := fmt.Sprintf("log_errors = On\nerror_log = %s/%s\n", phpLogContainerPath, phpErrorLogFile)
+
+	err = os.WriteFile(iniFile, []byte(iniSettings), 0o644) //nolint:gosec
+	if err != nil {
+		return []mount.Mount{}, err
+	}
+
+	return []mount.Mount{
+		{
+			Type:   mount.TypeBind,
+			Source: logDirectory,
+			Target: phpLogContainerPath,
+		},
+		{
+			Type:     mount.TypeBind,
+			Source:   iniFile,
+			Target:   filepath.ToSlash(filepath.Join("/usr/local/etc/php/conf.d", phpLogIniFile)),
+			ReadOnly: true,
+		},
+	}, nil
+}
+
+// GetPHPLogPath returns the path of the site's PHP error log.
+func (s *Site) GetPHPLogPath() string {
+	return filepath.Join(s.getLogDirectory(), phpErrorLogFile)
+}
+
+// ShowPHPLog writes the last entries of the site's PHP error log, optionally only those matching the pattern, to the
+// output. If follow is true it keeps writing new entries as they're logged.
+func (s *Site) ShowPHPLog(lines int, pattern string, follow bool, output io.Writer) error {
+	var matcher *regexp.Regexp
+
+	if pattern != "" {
+		var err error
+
+		matcher, err = regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("the pattern, %s, is not a valid regular expression: %s", pattern, err)
+		}
+	}
+
+	logFile, err := os.Open(s.GetPHPLogPath())
+	if os.IsNotExist(err) && !follow {
+		return fmt.Errorf(
+			"nothing has been logged yet. If the site was started before PHP logging was added restart it with 'kana stop' and 'kana start'")
+	}
+
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	entries := []string{}
+	var offset int64
+
+	if logFile != nil {
+		entries, offset, err = readPHPLogEntries(logFile, 0)
+		logFile.Close()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	entries = filterPHPLogEntries(entries, matcher)
+
+	if lines > 0 && len(entries) > lines {
+		entries = entries[len(entries)-lines:]
+	}
+
+	for _, entry := range entries {
+		fmt.Fprintln(output, entry)
+	}
+
+	for follow {
+		time.Sleep(phpLogFollowInterval)
+
+		entries, offset, err = s.readNewPHPLogEntries(offset)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range filterPHPLogEntries(entries, matcher) {
+			fmt.Fprintln(output, entry)
+		}
+	}
+
+	return nil
+}
+
+// readNewPHPLogEntries returns the entries logged after the offset, starting again from the beginning if the log
+// has been truncated or removed.
+func (s *Site) readNewPHPLogEntries(offset int64) ([]string, int64, error) {
+	logFile, err := os.Open(s.GetPHPLogPath())
+	if os.IsNotExist(err) {
+		return []string{}, 0, nil
+	}
+
+	if err != nil {
+		return []string{}, offset, err
+	}
+
+	defer logFile.Close()
+
+	info, err := logFile.Stat()
+	if err != nil {
+		return []string{}, offset, err
+	}
+
+	if info.Size() < offset {
+		offset = 0
+	}
+
+	return readPHPLogEntries(logFile, offset)
+}
+
+// readPHPLogEntries reads the complete log entries after the offset, returning them and the offset after the last one.
+func readPHPLogEntries(logFile *os.File, offset int64) ([]string, int64, error) {
+	_, err := logFile.Seek(offset, io.SeekStart)
+	if err != nil {
+		return []string{}, offset, err
+	}
+
+	entries := []string{}
+	reader := bufio.NewReader(logFile)
+
+	for {
+		line, err := reader.ReadString('\n')
+
+		// Leave a partly written line to be read once PHP has finished writing it
+		if err == io.EOF {
+			return entries, offset, nil
+		}
+
+		if err != nil {
+			return entries, offset, err
+		}
+
+		offset += int64(len(line))
+		line = strings.TrimRight(line, "\r\n")
+
+		if len(entries) > 0 && !phpLogEntryStart.MatchString(line) {
+			entries[len(entries)-1] += "\n" + line
+
+			continue
+		}
+
+		entries = append(entries, line)
+	}
+}
+
+func filterPHPLogEntries(entries []string, matcher *regexp.Regexp) []string {
+	if matcher == nil {
+		return entries
+	}
+
+	filtered := []string{}
+
+	for _, entry := range entries {
+		if matcher.MatchString(entry) {
+			filtered = append(filtered, entry)
+		}
+	}
+
+	return filtered
+}
+
+// StreamContainerLogs writes the output of one of the site's containers or, if containerName is empty, of all of its
+// running containers with each line prefixed by the container it came from.
+func (s *Site) StreamContainerLogs(containerName string, options docker.LogOptions, output io.Writer) error {
+	if containerName != "" {
+		if !slices.Contains(LogContainers, containerName) {
+			return fmt.Errorf("invalid container %s. Valid containers are %s", containerName, strings.Join(LogContainers, ", "))
+		}
+
+		return s.dockerClient.ContainerLogsStream(fmt.Sprintf("kana-%s-%s", s.settings.Get("name"), containerName), options, output)
+	}
+
+	runningContainers := []string{}
+
+	for _, logContainer := range LogContainers {
+		if s.dockerClient.ContainerIsRunning(fmt.Sprintf("kana-%s-%s", s.settings.Get("name"), logContainer)) {
+			runningContainers = append(runningContainers, logContainer)
+		}
+	}
+
+	var outputLock sync.Mutex
+
+	// Without following, each container's log is shown in turn rather than mixed together
+	if !options.Follow {
+		for _, logContainer := range runningContainers {
+			err := s.streamPrefixedLog(logContainer, options, output, &outputLock)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	var wait sync.WaitGroup
+
+	errors := make(chan error, len(runningContainers))
+
+	for _, logContainer := range runningContainers {
+		wait.Add(1)
+
+		go func() {
+			defer wait.Done()
+
+			errors <- s.streamPrefixedLog(logContainer, options, output, &outputLock)
+		}()
+	}
+
+	wait.Wait()
+	close(errors)
+
+	for err := range errors {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// streamPrefixedLog writes the log of one of the site's containers to output with each line prefixed by its name.
+func (s *Site) streamPrefixedLog(containerName string, options docker.LogOptions, output io.Writer, outputLock *sync.Mutex) error {
+	writer := &prefixWriter{
+		prefix: fmt.Sprintf("%-10s | ", containerName),
+		output: output,
+		lock:   outputLock,
+	}
+
+	err := s.dockerClient.ContainerLogsStream(fmt.Sprintf("kana-%s-%s", s.settings.Get("name"), containerName), options, writer)
+
+	writer.Flush()
+
+	return err
+}
+
+// prefixWriter writes whole lines to the output, each starting with the prefix, so the lines of containers being
+// followed at the same time aren't mixed together.
+type prefixWriter struct {
+	prefix  string
+	output  io.Writer
+	lock    *sync.Mutex
+	partial []byte
+}
+
+func (w *prefixWriter) Write(data []byte) (int, error) {
+	w.partial = append(w.partial, data...)
+
+	lastNewline := bytes.LastIndexByte(w.partial, '\n')
+	if lastNewline == -1 {
+		return len(data), nil
+	}
+
+	lines := strings.Split(strings.TrimRight(string(w.partial[:lastNewline]), "\r"), "\n")
+	w.partial = w.partial[lastNewline+1:]
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	for _, line := range lines {
+		_, err := fmt.Fprintf(w.output, "%s%s\n", w.prefix, strings.TrimRight(line, "\r"))
+		if err != nil {
+			return len(data), err
+		}
+	}
+
+	return len(data), nil
+}
+
+// Flush writes the last line of the log if it didn't end with a newline.
+func (w *prefixWriter) Flush() {
+	if len(w.partial) == 0 {
+		return
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	fmt.Fprintf(w.output, "%s%s\n", w.prefix, strings.TrimRight(string(w.partial), "\r"))
+	w.partial = nil
+}
+
+func (s *Site) getLogDirectory() string {
+	return filepath.Join(s.settings.Get("siteDirectory"), "logs")
+}
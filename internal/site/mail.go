@@ -0,0 +1,117 @@
+package site
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/pkg/mailpit"
+)
+
+const mailpitAPIPort = 8025
+
+// ListMail returns the emails caught by Mailpit, newest first, up to the given limit.
+func (s *Site) ListMail(limit int) ([]mailpit.Summary, error) {
+	client, err := s.getMailpitClient()
+	if err != nil {
+		return []mailpit.Summary{}, err
+	}
+
+	return client.List(limit)
+}
+
+// GetMail returns an email caught by Mailpit. The ID "latest" returns the newest email.
+func (s *Site) GetMail(id string) (mailpit.Message, error) {
+	client, err := s.getMailpitClient()
+	if err != nil {
+		return mailpit.Message{}, err
+	}
+
+	return client.Get(id)
+}
+
+// DeleteMail deletes the given emails from Mailpit or, if no IDs are given, all of them.
+func (s *Site) DeleteMail(ids []string) error {
+	client, err := s.getMailpitClient()
+	if err != nil {
+		return err
+	}
+
+	return client.Delete(ids)
+}
+
+// WaitForMail blocks until Mailpit has caught an email matching the filter, returning the newest one that does.
+func (s *Site) WaitForMail(filter mailpit.Filter, timeout time.Duration) (mailpit.Message, error) {
+	client, err := s.getMailpitClient()
+	if err != nil {
+		return mailpit.Message{}, err
+	}
+
+	return client.WaitFor(filter, timeout)
+}
+
+// SendTestMail sends an email through WordPress's wp_mail function so the site's email setup can be checked in Mailpit.
+func (s *Site) SendTestMail(to string, consoleOutput *console.Console) error {
+	address, err := mail.ParseAddress(to)
+	if err != nil {
+		return fmt.Errorf("%s is not a valid email address", to)
+	}
+
+	_, err = s.GetMailpitURL()
+	if err != nil {
+		return err
+	}
+
+	phpAddress := strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(address.Address)
+
+	code, output, err := s.WPCli([]string{
+		"eval",
+		fmt.Sprintf(
+			"if ( ! wp_mail( '%s', 'Kana test email', 'This email was sent by kana mail send-test.' ) ) { exit( 1 ); }",
+			phpAddress)},
+		false,
+		consoleOutput)
+	if err != nil {
+		return err
+	}
+
+	if code != 0 {
+		return fmt.Errorf("WordPress was unable to send the test email: %s", strings.TrimSpace(output))
+	}
+
+	return nil
+}
+
+// GetMailpitURL returns the local URL of the site's Mailpit API, which is published on a random port.
+func (s *Site) GetMailpitURL() (string, error) {
+	containers, err := s.dockerClient.ContainerList(s.settings.Get("name"))
+	if err != nil {
+		return "", err
+	}
+
+	for i := range containers {
+		if containers[i].Image != "axllent/mailpit" {
+			continue
+		}
+
+		for _, port := range containers[i].Ports {
+			if port.PrivatePort == mailpitAPIPort && port.PublicPort != 0 {
+				return fmt.Sprintf("http://127.0.0.1:%d", port.PublicPort), nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("mailpit is not running. Start the site with the --mailpit flag or run 'kana open --mailpit' to start it")
+}
+
+// getMailpitClient returns a client for the site's Mailpit API.
+func (s *Site) getMailpitClient() (*mailpit.Client, error) {
+	mailpitURL, err := s.GetMailpitURL()
+	if err != nil {
+		return nil, err
+	}
+
+	return mailpit.NewClient(mailpitURL), nil
+}
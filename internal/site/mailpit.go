@@ -1,6 +1,7 @@
 package site
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/ChrisWiegman/kana/internal/console"
@@ -66,8 +67,8 @@ func (s *Site) isMailpitRunning() bool {
 }
 
 // startMailpit Starts the Mailpit container.
-func (s *Site) startMailpit(consoleOutput *console.Console) error {
+func (s *Site) startMailpit(ctx context.Context, consoleOutput *console.Console) error {
 	mailpitContainer := s.getMailpitContainer()
 
-	return s.startContainer(&mailpitContainer, true, true, consoleOutput)
+	return s.startContainer(ctx, &mailpitContainer, true, true, consoleOutput)
 }
@@ -0,0 +1,88 @@
+package site
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/docker"
+	"github.com/ChrisWiegman/kana/internal/settings"
+
+	"github.com/docker/docker/api/types/mount"
+)
+
+const (
+	nodeDirectory      = "/app"
+	nodeCacheDirectory = "/npm-cache"
+)
+
+// Node runs npm or npx in the project's directory with the user's terminal attached and returns its exit code. The site
+// doesn't need to be running. npm's cache is kept in the app directory and shared by every site so packages already
+// downloaded for one project don't need to be downloaded again for the next.
+func (s *Site) Node(command string, args []string, consoleOutput *console.Console) (int64, error) {
+	cacheDirectory := filepath.Join(s.settings.Get("appDirectory"), "cache", "npm")
+	dirPermissions, _ := settings.GetDefaultFilePermissions()
+
+	err := os.MkdirAll(cacheDirectory, os.FileMode(dirPermissions))
+	if err != nil {
+		return 1, err
+	}
+
+	// Builds often watch for changes while other npm commands are run so each command gets its own container
+	name := fmt.Sprintf("kana-%s-node-%d", s.settings.Get("name"), os.Getpid())
+
+	container := docker.ContainerConfig{
+		Name:     name,
+		Image:    fmt.Sprintf("node:%s", s.settings.Get("nodeVersion")),
+		HostName: name,
+		Env: []string{
+			fmt.Sprintf("npm_config_cache=%s", nodeCacheDirectory),
+			"npm_config_update_notifier=false",
+			"HOME=/tmp",
+		},
+		Labels: map[string]string{
+			"kana.site": s.settings.Get("name"),
+		},
+		Volumes: []mount.Mount{
+			{
+				Type:   mount.TypeBind,
+				Source: s.settings.Get("workingDirectory"),
+				Target: nodeDirectory,
+			},
+			{
+				Type:   mount.TypeBind,
+				Source: cacheDirectory,
+				Target: nodeCacheDirectory,
+			},
+		},
+		// The node image doesn't set a working directory so change to the project's before running the command
+		Command: append([]string{"sh", "-c", fmt.Sprintf("cd %s && exec \"$0\" \"$@\"", nodeDirectory), command}, args...),
+		Init:    true,
+	}
+
+	container.Env = append(container.Env, s.getGitEnv([]string{nodeDirectory})...)
+	s.addProxy(&container)
+	s.addCACertificates(&container)
+
+	err = s.addSSHAgent(&container)
+	if err != nil {
+		return 1, err
+	}
+
+	err = s.dockerClient.EnsureImage(
+		context.Background(),
+		container.Image,
+		s.settings.Get("appDirectory"),
+		s.settings.GetInt("updateInterval"),
+		consoleOutput)
+	if err != nil {
+		return 1, err
+	}
+
+	// npm runs as the host user, as interactive containers do, so node_modules and build files can be edited on the host
+	code, _, err := s.dockerClient.ContainerRunAndClean(&container, true, nil)
+
+	return code, err
+}
@@ -0,0 +1,73 @@
+package site
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+)
+
+// Option is a WordPress option as listed by wp-cli. Options holding arrays or objects are listed in their serialized
+// form.
+type Option struct {
+	Name     string `json:"option_name"`
+	Value    string `json:"option_value"`
+	Autoload string `json:"autoload"`
+}
+
+// GetOption returns the value of an option as JSON so arrays and objects keep their structure.
+func (s *Site) GetOption(name string, consoleOutput *console.Console) (json.RawMessage, error) {
+	value := json.RawMessage{}
+
+	code, output, err := s.WPCli([]string{"option", "get", name, "--format=json"}, false, consoleOutput)
+	if err != nil {
+		return value, err
+	}
+
+	if code != 0 {
+		return value, fmt.Errorf("the option, %s, does not exist", name)
+	}
+
+	err = json.Unmarshal([]byte(output), &value)
+	if err != nil {
+		return value, fmt.Errorf("unable to read the %s option: %s", name, err)
+	}
+
+	return value, nil
+}
+
+// SetOption sets an option, adding it if it doesn't exist. Values that are JSON arrays or objects are stored as arrays
+// so they can be round-tripped with GetOption. Any other value is stored as a string.
+func (s *Site) SetOption(name, value string, consoleOutput *console.Console) error {
+	command := []string{"option", "update", name, value}
+
+	trimmedValue := strings.TrimSpace(value)
+
+	if (strings.HasPrefix(trimmedValue, "{") || strings.HasPrefix(trimmedValue, "[")) && json.Valid([]byte(trimmedValue)) {
+		command = append(command, "--format=json")
+	}
+
+	err := s.wpCliOrError(command, consoleOutput)
+	if err != nil {
+		return fmt.Errorf("unable to set the %s option: %s", name, err)
+	}
+
+	return nil
+}
+
+// ListOptions returns the site's options, leaving out transients. The search may include * wildcards, such as
+// woocommerce_*, to only list matching options.
+func (s *Site) ListOptions(search string, consoleOutput *console.Console) ([]Option, error) {
+	options := []Option{}
+
+	command := []string{"option", "list", "--no-transients", "--fields=option_name,option_value,autoload", "--format=json"}
+
+	if search != "" {
+		command = append(command, fmt.Sprintf("--search=%s", search))
+	}
+
+	err := s.wpCliJSON(command, &options, consoleOutput)
+
+	return options, err
+}
@@ -1,6 +1,7 @@
 package site
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/ChrisWiegman/kana/internal/console"
@@ -47,8 +48,8 @@ func (s *Site) getPhpMyAdminContainer() docker.ContainerConfig {
 }
 
 // startPHPMyAdmin Starts the PhpMyAdmin container.
-func (s *Site) startPHPMyAdmin(consoleOutput *console.Console) error {
+func (s *Site) startPHPMyAdmin(ctx context.Context, consoleOutput *console.Console) error {
 	phpMyAdminContainer := s.getPhpMyAdminContainer()
 
-	return s.startContainer(&phpMyAdminContainer, true, false, consoleOutput)
+	return s.startContainer(ctx, &phpMyAdminContainer, true, false, consoleOutput)
 }
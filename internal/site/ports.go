@@ -0,0 +1,80 @@
+package site
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/ChrisWiegman/kana/internal/docker"
+	"github.com/ChrisWiegman/kana/internal/helpers"
+)
+
+// traefikDashboardPort is only used for Traefik's dashboard so Traefik can start without it if the port is taken.
+const traefikDashboardPort = "8080"
+
+// getPortOwner describes what is using a port on the host, such as another container or a web server, for
+// explaining a port conflict.
+func (s *Site) getPortOwner(port string) string {
+	containerName := s.dockerClient.GetPortContainer(port)
+	if containerName != "" {
+		return fmt.Sprintf("the Docker container %s", containerName)
+	}
+
+	process := helpers.GetPortProcess(port)
+	if process != "" {
+		return process
+	}
+
+	return "another application"
+}
+
+// getTraefikPorts returns the ports Traefik publishes on the host, leaving out the dashboard's port if it's in use,
+// or an error explaining what is using the ports Traefik needs to route the sites.
+func (s *Site) getTraefikPorts() (ports []docker.ExposedPorts, warning string, err error) {
+	for _, port := range []string{"80", "443"} {
+		if !docker.IsPortAvailable(port) {
+			return ports, "", fmt.Errorf(
+				"port %s is already in use by %s so Traefik can't route your sites. Stop it and run 'kana start' again or use --ci to serve the site on its own port", //nolint:lll
+				port,
+				s.getPortOwner(port))
+		}
+
+		ports = append(ports, docker.ExposedPorts{Port: port, Protocol: "tcp"})
+	}
+
+	if !docker.IsPortAvailable(traefikDashboardPort) {
+		warning = fmt.Sprintf(
+			"Port %s is already in use by %s so Traefik's dashboard won't be available. Your sites aren't affected.",
+			traefikDashboardPort,
+			s.getPortOwner(traefikDashboardPort))
+
+		return ports, warning, nil
+	}
+
+	return append(ports, docker.ExposedPorts{Port: traefikDashboardPort, Protocol: "tcp"}), "", nil
+}
+
+// checkCIPort returns an error explaining what is using the port a site in CI mode is served on, suggesting a free
+// port to use instead.
+func (s *Site) checkCIPort() error {
+	port := s.settings.Get("ciPort")
+
+	if docker.IsPortAvailable(port) {
+		return nil
+	}
+
+	suggestion := ""
+
+	for candidate := 8081; candidate < 8181; candidate++ {
+		if docker.IsPortAvailable(strconv.Itoa(candidate)) {
+			suggestion = fmt.Sprintf(", such as with 'kana config ciPort %d'", candidate)
+
+			break
+		}
+	}
+
+	return fmt.Errorf(
+		"port %s is already in use by %s so the site can't be served on it. Stop it or set the ciPort setting to a free port%s",
+		port,
+		s.getPortOwner(port),
+		suggestion)
+}
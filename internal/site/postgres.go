@@ -0,0 +1,174 @@
+package site
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/helpers"
+)
+
+// PG4WP is the db.php drop-in that lets WordPress run on PostgreSQL. A tagged release is installed, and its download
+// checked against pg4wpChecksum, so every site gets the same reviewed code. Update both together, with the checksum from
+// `curl -sL <pg4wpURL> | shasum -a 256`.
+const (
+	pg4wpVersion  = "v3.4.1"
+	pg4wpURL      = "https://codeload.github.com/PostgreSQL-For-Wordpress/postgresql-for-wordpress/zip/refs/tags/" + pg4wpVersion
+	pg4wpChecksum = ""
+)
+
+// The official WordPress images don't include PHP's PostgreSQL extension so it is built in the containers that need it.
+const (
+	pgsqlCheckCommand        = "php -m | grep -q '^pgsql$'"
+	pgsqlWordPressInstall    = "apt-get update && apt-get install -y --no-install-recommends libpq-dev && docker-php-ext-install pgsql"
+	pgsqlCliInstall          = "apk add --no-cache postgresql-dev $PHPIZE_DEPS && docker-php-ext-install pgsql"
+	postgresDatabaseUser     = "wordpress"
+	postgresDatabaseName     = "wordpress"
+	postgresDatabasePassword = "wordpress"
+)
+
+// isUsingPostgres returns true if the running site was started with PostgreSQL or, if it isn't running yet, if the
+// database setting is postgres.
+func (s *Site) isUsingPostgres() (bool, error) {
+	if !s.IsSiteRunning() {
+		return s.settings.Get("database") == "postgres", nil
+	}
+
+	output, err := s.WordPress("echo $KANA_POSTGRES", false, false)
+	if err != nil {
+		return false, err
+	}
+
+	return strings.Contains(output.StdOut, "true"), nil
+}
+
+// maybeSetupPostgres installs PHP's PostgreSQL extension in the WordPress container and the PG4WP drop-in in
+// wp-content for PostgreSQL sites.
+func (s *Site) maybeSetupPostgres(consoleOutput *console.Console) error {
+	isUsingPostgres, err := s.isUsingPostgres()
+	if err != nil || !isUsingPostgres {
+		return err
+	}
+
+	output, err := s.WordPress(pgsqlCheckCommand, false, true)
+	if err != nil {
+		return err
+	}
+
+	if output.ExitCode != 0 {
+		consoleOutput.Println("Installing PHP's PostgreSQL extension in the WordPress container.")
+
+		output, err = s.WordPress(pgsqlWordPressInstall, true, true)
+		if err != nil {
+			return err
+		}
+
+		if output.ExitCode != 0 {
+			return fmt.Errorf("unable to install PHP's PostgreSQL extension: %s", output.StdErr)
+		}
+	}
+
+	wordPressDirectory, err := s.getWordPressDirectory()
+	if err != nil {
+		return err
+	}
+
+	contentDirectory := filepath.Join(wordPressDirectory, "wp-content")
+
+	_, err = os.Stat(filepath.Join(contentDirectory, "pg4wp"))
+	if err == nil {
+		return nil
+	}
+
+	return installPG4WP(contentDirectory)
+}
+
+// installPG4WP downloads PG4WP into wp-content and installs its db.php drop-in.
+func installPG4WP(contentDirectory string) error {
+	downloadDirectory, err := os.MkdirTemp(contentDirectory, "pg4wp-download")
+	if err != nil {
+		return err
+	}
+
+	defer os.RemoveAll(downloadDirectory)
+
+	file, err := helpers.DownloadFile(pg4wpURL, downloadDirectory)
+	if err != nil {
+		return err
+	}
+
+	checksum, err := helpers.PathChecksum(filepath.Join(downloadDirectory, file))
+	if err != nil {
+		return err
+	}
+
+	if checksum != pg4wpChecksum {
+		return fmt.Errorf("the download of PG4WP %s from %s doesn't match its checksum so it wasn't installed", pg4wpVersion, pg4wpURL)
+	}
+
+	err = helpers.UnZipFile(filepath.Join(downloadDirectory, file), downloadDirectory)
+	if err != nil {
+		return err
+	}
+
+	// The download's folder is named after the release it was taken from
+	pg4wpDirectories, err := filepath.Glob(filepath.Join(downloadDirectory, "*", "pg4wp"))
+	if err != nil || len(pg4wpDirectories) == 0 {
+		return fmt.Errorf("unable to find PG4WP in its download from %s", pg4wpURL)
+	}
+
+	err = os.Rename(pg4wpDirectories[0], filepath.Join(contentDirectory, "pg4wp"))
+	if err != nil {
+		return err
+	}
+
+	return helpers.CopyFile(
+		filepath.Join(contentDirectory, "pg4wp", "db.php"),
+		filepath.Join(contentDirectory, "db.php"))
+}
+
+// ensurePostgresCli installs PHP's PostgreSQL extension in the site's long-lived CLI container if it isn't there yet.
+func (s *Site) ensurePostgresCli(containerName string) error {
+	output, err := s.dockerClient.ContainerExec(containerName, true, []string{pgsqlCheckCommand})
+	if err != nil || output.ExitCode == 0 {
+		return err
+	}
+
+	output, err = s.dockerClient.ContainerExec(containerName, true, []string{pgsqlCliInstall})
+	if err != nil {
+		return err
+	}
+
+	if output.ExitCode != 0 {
+		return fmt.Errorf("unable to install PHP's PostgreSQL extension for wp-cli: %s", output.StdErr)
+	}
+
+	return nil
+}
+
+// getPostgresCommand returns the command, with the site's credentials, to run psql or, if dump is true, pg_dump
+// against the WordPress database.
+func getPostgresCommand(dump bool, args ...string) []string {
+	command := []string{
+		"psql",
+		fmt.Sprintf("--username=%s", postgresDatabaseUser),
+		"--quiet",
+		"--set=ON_ERROR_STOP=1",
+	}
+
+	if dump {
+		command = []string{
+			"pg_dump",
+			fmt.Sprintf("--username=%s", postgresDatabaseUser),
+			"--clean",
+			"--if-exists",
+			"--no-owner",
+		}
+	}
+
+	command = append(command, args...)
+
+	return append(command, postgresDatabaseName)
+}
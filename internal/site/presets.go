@@ -0,0 +1,103 @@
+package site
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/helpers"
+	"github.com/ChrisWiegman/kana/internal/settings"
+)
+
+// ApplyPreset installs the preset's plugins and theme, sets its options, adds its content and runs its commands.
+func (s *Site) ApplyPreset(preset *settings.Preset, consoleOutput *console.Console) error {
+	for _, plugin := range preset.Plugins {
+		err := s.installPresetPlugin(plugin, consoleOutput)
+		if err != nil {
+			return err
+		}
+	}
+
+	if preset.Theme != "" {
+		consoleOutput.Println(fmt.Sprintf("Installing theme:  %s", consoleOutput.Bold(consoleOutput.Blue(preset.Theme))))
+
+		err := s.wpCliOrError([]string{"theme", "install", preset.Theme, "--activate"}, consoleOutput)
+		if err != nil {
+			return fmt.Errorf("unable to install the theme %s: %s", preset.Theme, err)
+		}
+	}
+
+	for _, option := range slices.Sorted(maps.Keys(preset.Options)) {
+		value, err := json.Marshal(preset.Options[option])
+		if err != nil {
+			return err
+		}
+
+		consoleOutput.Println(fmt.Sprintf("Setting option:  %s", consoleOutput.Bold(consoleOutput.Blue(option))))
+
+		err = s.wpCliOrError([]string{"option", "update", option, string(value), "--format=json"}, consoleOutput)
+		if err != nil {
+			return fmt.Errorf("unable to set the option %s: %s", option, err)
+		}
+	}
+
+	for _, content := range preset.Content {
+		err := s.addContent(content, consoleOutput)
+		if err != nil {
+			return fmt.Errorf("unable to add the content %s: %s", content, err)
+		}
+	}
+
+	for _, command := range preset.Commands {
+		commandLine := fmt.Sprintf("wp %s", strings.Join(command, " "))
+
+		consoleOutput.Println(fmt.Sprintf("Running:  %s", consoleOutput.Bold(consoleOutput.Blue(commandLine))))
+
+		err := s.wpCliOrError(command, consoleOutput)
+		if err != nil {
+			return fmt.Errorf("the command %s failed: %s", commandLine, err)
+		}
+	}
+
+	return nil
+}
+
+// installPresetPlugin installs and activates a plugin from WordPress.org, a URL or a local zip file.
+func (s *Site) installPresetPlugin(plugin string, consoleOutput *console.Console) error {
+	source, network, err := settings.ParsePlugin(plugin)
+	if err != nil {
+		return err
+	}
+
+	consoleOutput.Println(fmt.Sprintf("Installing plugin:  %s", consoleOutput.Bold(consoleOutput.Blue(source))))
+
+	// Local zip files need to be copied to the site directory, which is mounted in the container
+	if _, err = os.Stat(source); err == nil {
+		err = helpers.CopyFile(source, filepath.Join(s.settings.Get("siteDirectory"), filepath.Base(source)))
+		if err != nil {
+			return err
+		}
+
+		defer os.Remove(filepath.Join(s.settings.Get("siteDirectory"), filepath.Base(source)))
+
+		source = fmt.Sprintf("/Site/%s", filepath.Base(source))
+	}
+
+	activateFlag := "--activate"
+
+	if network {
+		activateFlag = "--activate-network"
+	}
+
+	err = s.wpCliOrError([]string{"plugin", "install", source, activateFlag}, consoleOutput)
+	if err != nil {
+		return fmt.Errorf("unable to install the plugin %s: %s", plugin, err)
+	}
+
+	return nil
+}
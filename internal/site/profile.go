@@ -0,0 +1,78 @@
+package site
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+)
+
+const profileRequestTimeout = 60 * time.Second
+
+// ProfileReport is what the Kana plugin recorded about a request it was asked to profile.
+type ProfileReport struct {
+	URL        string         `json:"url"`
+	Status     int            `json:"status"`
+	Time       float64        `json:"time"` // In seconds
+	Memory     int64          `json:"memory"`
+	QueryCount int            `json:"query_count"`
+	QueryTime  float64        `json:"query_time"`
+	Hooks      []ProfileHook  `json:"hooks"`   // The slowest hooks first
+	Queries    []ProfileQuery `json:"queries"` // The slowest queries first
+}
+
+// ProfileHook is the time spent running a hook's callbacks, including any hooks they fired, and how often it ran.
+type ProfileHook struct {
+	Hook  string  `json:"hook"`
+	Calls int     `json:"calls"`
+	Time  float64 `json:"time"`
+}
+
+// ProfileQuery is a database query made during the request and the function that made it.
+type ProfileQuery struct {
+	Query  string  `json:"query"`
+	Time   float64 `json:"time"`
+	Caller string  `json:"caller"`
+}
+
+// ProfileRequest requests a page of the running site, given as a path or a URL on the site's domain, with the Kana
+// plugin's profiling turned on and returns the hook timings, queries and memory it recorded.
+func (s *Site) ProfileRequest(pageURL string, consoleOutput *console.Console) (ProfileReport, error) {
+	report := ProfileReport{}
+
+	requestURL, err := s.getSitePageURL(pageURL)
+	if err != nil {
+		return report, err
+	}
+
+	profileID := strconv.FormatInt(time.Now().UnixNano(), 36)
+
+	// Profile the request itself rather than wherever it redirects to
+	_, _, err = getPage(requestURL, http.Header{"X-Kana-Profile": {profileID}}, profileRequestTimeout)
+	if err != nil {
+		return report, err
+	}
+
+	transient := fmt.Sprintf("kana_profile_%s", profileID)
+
+	code, output, err := s.WPCli([]string{"transient", "get", transient, "--format=json"}, false, consoleOutput)
+	if err != nil {
+		return report, err
+	}
+
+	if code != 0 {
+		return report, fmt.Errorf(
+			"no profile was recorded for %s. If the site was started with an older version of Kana restart it with 'kana stop' and 'kana start'",
+			requestURL)
+	}
+
+	err = json.Unmarshal([]byte(output), &report)
+	if err != nil {
+		return report, err
+	}
+
+	return report, s.wpCliOrError([]string{"transient", "delete", transient}, consoleOutput)
+}
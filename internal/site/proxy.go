@@ -0,0 +1,93 @@
+package site
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/ChrisWiegman/kana/internal/docker"
+)
+
+// proxyHostName is the name containers reach a proxy server running on the user's computer by.
+const proxyHostName = "host.docker.internal"
+
+// addProxy passes the proxy settings on to a container so plugins, themes and packages can be downloaded from inside
+// it. A proxy running on the user's computer, such as at localhost:3128, is reached through the host's address as
+// localhost in a container is the container itself. The site's own domains and the containers on Kana's network, such as
+// the cache of WordPress.org's API, are never reached through the proxy.
+func (s *Site) addProxy(container *docker.ContainerConfig) {
+	proxy := s.settings.GetProxy()
+	if !proxy.IsSet() {
+		return
+	}
+
+	httpProxy, httpOnHost := getContainerProxyURL(proxy.HTTP)
+	httpsProxy, httpsOnHost := getContainerProxyURL(proxy.HTTPS)
+
+	noProxy := []string{
+		"localhost",
+		"127.0.0.1",
+		s.settings.GetDomain(),
+		s.getStaticDomain(),
+		fmt.Sprintf("kana-%s-database", s.settings.Get("name")),
+		fmt.Sprintf("kana-%s-wordpress", s.settings.Get("name")),
+		fmt.Sprintf("kana-%s-mailpit", s.settings.Get("name")),
+		fmt.Sprintf("kana-%s-phpmyadmin", s.settings.Get("name")),
+		s.getRedisContainerName(),
+		s.getUpdateServerContainerName(),
+		s.getStaticContainerName(),
+		s.getShareContainerName(),
+		wordPressAPIContainerName,
+		traefikContainerName,
+		"kanatraefik",
+	}
+
+	for _, host := range strings.Split(proxy.NoProxy, ",") {
+		if host = strings.TrimSpace(host); host != "" && !slices.Contains(noProxy, host) {
+			noProxy = append(noProxy, host)
+		}
+	}
+
+	// Tools differ in whether they read the upper or lowercase variables so both are set
+	for _, variable := range [][]string{
+		{"HTTP_PROXY", httpProxy},
+		{"HTTPS_PROXY", httpsProxy},
+		{"NO_PROXY", strings.Join(noProxy, ",")},
+	} {
+		if variable[1] == "" {
+			continue
+		}
+
+		container.Env = append(container.Env,
+			fmt.Sprintf("%s=%s", variable[0], variable[1]),
+			fmt.Sprintf("%s=%s", strings.ToLower(variable[0]), variable[1]))
+	}
+
+	if (httpOnHost || httpsOnHost) && !slices.Contains(container.ExtraHosts, proxyHostName+":host-gateway") {
+		container.ExtraHosts = append(container.ExtraHosts, proxyHostName+":host-gateway")
+	}
+}
+
+// getContainerProxyURL returns the URL a container reaches a proxy server at and whether the proxy is on the user's
+// computer.
+func getContainerProxyURL(proxyURL string) (string, bool) {
+	parsedURL, err := url.Parse(proxyURL)
+	if err != nil || parsedURL.Host == "" {
+		return proxyURL, false
+	}
+
+	if !slices.Contains([]string{"localhost", "127.0.0.1", "::1"}, parsedURL.Hostname()) {
+		return proxyURL, false
+	}
+
+	port := parsedURL.Port()
+	parsedURL.Host = proxyHostName
+
+	if port != "" {
+		parsedURL.Host = net.JoinHostPort(proxyHostName, port)
+	}
+
+	return parsedURL.String(), true
+}
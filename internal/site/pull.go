@@ -0,0 +1,172 @@
+package site
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+)
+
+// PullParts are the parts of a remote site that `kana pull` can copy.
+var PullParts = []string{"db", "uploads", "plugins", "themes"}
+
+// pullDatabaseFile is the file, in the site directory, the remote database is downloaded to before it is imported.
+const pullDatabaseFile = "pull.sql"
+
+// Pull copies the given parts of the site on the pullHost server, installed in pullPath, into the site. Files are
+// copied with rsync, without deleting local files missing from the server, and the database is exported with the
+// server's wp-cli, imported and changed to use the site's URL. The remote site's URL is returned.
+func (s *Site) Pull(parts []string, consoleOutput *console.Console) (string, error) {
+	for _, part := range parts {
+		if !slices.Contains(PullParts, part) {
+			return "", fmt.Errorf("%s is not a part of the site that can be pulled. Valid parts are %s", part, strings.Join(PullParts, ", "))
+		}
+	}
+
+	remote := s.getPullRemote()
+
+	if !remote.isSet() {
+		return "", fmt.Errorf(
+			"set the pullHost and pullPath settings in the site's .kana.json file, such as with 'kana config edit --local', before pulling the site")
+	}
+
+	err := checkRemoteTools("pull", slices.ContainsFunc(parts, func(part string) bool { return part != "db" }))
+	if err != nil {
+		return "", err
+	}
+
+	remoteURL, err := remote.getURL()
+	if err != nil {
+		return "", err
+	}
+
+	for _, part := range PullParts {
+		if !slices.Contains(parts, part) {
+			continue
+		}
+
+		if part == "db" {
+			err = s.pullDatabase(remote, remoteURL, consoleOutput)
+		} else {
+			err = s.pullFiles(remote, part, consoleOutput)
+		}
+
+		if err != nil {
+			return "", fmt.Errorf("unable to pull the site's %s: %s", part, err)
+		}
+	}
+
+	s.recordHistory("pulled", fmt.Sprintf("%s from %s", strings.Join(parts, ", "), remoteURL))
+
+	return remoteURL, nil
+}
+
+// pullDatabase exports the remote site's database, imports it into the site and replaces the remote site's URL with
+// the site's URL.
+func (s *Site) pullDatabase(remote remoteSite, remoteURL string, consoleOutput *console.Console) error {
+	err := s.checkRemoteDatabase("pull")
+	if err != nil {
+		return err
+	}
+
+	consoleOutput.Println(fmt.Sprintf("Downloading the database from %s.", remote.host))
+
+	dumpFile := filepath.Join(s.settings.Get("siteDirectory"), pullDatabaseFile)
+
+	file, err := os.Create(dumpFile)
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(dumpFile)
+	defer file.Close()
+
+	var errorOutput bytes.Buffer
+
+	exportCommand := remote.wpCli("db", "export", "-", "--single-transaction")
+	exportCommand.Stdout = file
+	exportCommand.Stderr = &errorOutput
+
+	err = exportCommand.Run()
+	if err != nil {
+		return fmt.Errorf("the database could not be exported: %s", strings.TrimSpace(errorOutput.String()))
+	}
+
+	err = file.Close()
+	if err != nil {
+		return err
+	}
+
+	remoteDomain, _ := url.Parse(remoteURL)
+
+	err = s.importDatabaseFile(dumpFile, false, "", consoleOutput)
+	if err != nil {
+		return err
+	}
+
+	// The full URL is replaced first so the protocol changes too if only one of the sites uses SSL
+	consoleOutput.Println("Replacing the remote site's URL.")
+
+	err = s.wpCliOrError([]string{"search-replace", remoteURL, s.settings.GetURL(), "--all-tables"}, consoleOutput)
+	if err != nil {
+		return fmt.Errorf("replace URL failed: %s", err)
+	}
+
+	err = s.replaceDomain(remoteDomain.Host, consoleOutput)
+	if err != nil {
+		return err
+	}
+
+	return s.MakeImportSafe(consoleOutput)
+}
+
+// pullFiles copies the remote site's uploads, plugins or themes into the site with rsync. The plugins and themes being
+// developed in the site are never overwritten.
+func (s *Site) pullFiles(remote remoteSite, part string, consoleOutput *console.Console) error {
+	consoleOutput.Println(fmt.Sprintf("Copying the %s from %s.", part, remote.host))
+
+	wordPressDirectory, err := s.getWordPressDirectory()
+	if err != nil {
+		return err
+	}
+
+	localDirectory := filepath.Join(wordPressDirectory, "wp-content", part)
+
+	err = os.MkdirAll(localDirectory, os.FileMode(defaultDirPermissions))
+	if err != nil {
+		return err
+	}
+
+	// Owners and permissions aren't copied as the server's users don't exist locally
+	args := []string{"--recursive", "--links", "--times", "--compress"}
+
+	if part != "uploads" {
+		var mountedProjects map[string]string
+
+		mountedProjects, err = s.getMountedProjects(strings.TrimSuffix(part, "s"))
+		if err != nil {
+			return err
+		}
+
+		for name := range mountedProjects {
+			args = append(args, fmt.Sprintf("--exclude=/%s/", name))
+		}
+	}
+
+	args = append(args, remote.getRsyncPath("wp-content/"+part), localDirectory+string(filepath.Separator))
+
+	rsyncCommand := Command("rsync", args...)
+	rsyncCommand.Stdin = os.Stdin
+
+	output, err := rsyncCommand.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s", strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
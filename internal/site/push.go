@@ -0,0 +1,298 @@
+package site
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+)
+
+// PushParts are the parts of the site that `kana push` can copy to a server. The project is the plugin or theme, or
+// the plugins and themes of a monorepo, being developed in the site.
+var PushParts = []string{"project", "plugins", "themes", "uploads", "db"}
+
+// pushDatabaseFile is the file, in the site directory, the database is exported to before it is uploaded.
+const pushDatabaseFile = "push.sql"
+
+// Push copies the given parts of the site to the site on the pushHost server, installed in pushPath. Files are copied
+// with rsync, skipping any matching the exclude patterns or the pushExclude setting, without deleting files on the
+// server missing from the site. The database replaces the server's and is changed to use the server's URL. With dryRun
+// the changes rsync would make are shown and nothing is copied. The remote site's URL is returned.
+func (s *Site) Push(parts, exclude []string, dryRun bool, consoleOutput *console.Console) (string, error) {
+	for _, part := range parts {
+		if !slices.Contains(PushParts, part) {
+			return "", fmt.Errorf("%s is not a part of the site that can be pushed. Valid parts are %s", part, strings.Join(PushParts, ", "))
+		}
+	}
+
+	remote := s.getPushRemote()
+
+	if !remote.isSet() {
+		return "", fmt.Errorf(
+			"set the pushHost and pushPath, or pullHost and pullPath, settings in the site's .kana.json file before pushing the site")
+	}
+
+	err := checkRemoteTools("push", slices.ContainsFunc(parts, func(part string) bool { return part != "db" }))
+	if err != nil {
+		return "", err
+	}
+
+	remoteURL, err := remote.getURL()
+	if err != nil {
+		return "", err
+	}
+
+	exclude = append(slices.Clone(exclude), s.settings.GetSlice("pushExclude")...)
+
+	for _, part := range PushParts {
+		if !slices.Contains(parts, part) {
+			continue
+		}
+
+		switch part {
+		case "db":
+			err = s.pushDatabase(remote, remoteURL, dryRun, consoleOutput)
+		case "project":
+			err = s.pushProjects(remote, exclude, dryRun, consoleOutput)
+		default:
+			err = s.pushFiles(remote, part, exclude, dryRun, consoleOutput)
+		}
+
+		if err != nil {
+			return "", fmt.Errorf("unable to push the site's %s: %s", part, err)
+		}
+	}
+
+	if !dryRun {
+		s.recordHistory("pushed", fmt.Sprintf("%s to %s", strings.Join(parts, ", "), remoteURL))
+	}
+
+	return remoteURL, nil
+}
+
+// pushProjects copies the plugins and themes being developed in the site to the server.
+func (s *Site) pushProjects(remote remoteSite, exclude []string, dryRun bool, consoleOutput *console.Console) error {
+	projects, err := s.getProjects()
+	if err != nil {
+		return err
+	}
+
+	if len(projects) == 0 {
+		return fmt.Errorf("the site isn't developing a plugin or theme. Use --what to choose the plugins, themes or uploads instead")
+	}
+
+	for _, project := range projects {
+		consoleOutput.Println(fmt.Sprintf("Copying the %s %s to %s.", project.Type, project.Name, remote.host))
+
+		err = rsyncToRemote(
+			project.Path,
+			remote.getRsyncPath(fmt.Sprintf("wp-content/%ss/%s", project.Type, project.Name)),
+			exclude,
+			dryRun,
+			consoleOutput)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// pushFiles copies the site's uploads, plugins or themes to the server. The plugins and themes being developed in the
+// site are left to the project part so they're only copied when asked for.
+func (s *Site) pushFiles(remote remoteSite, part string, exclude []string, dryRun bool, consoleOutput *console.Console) error {
+	consoleOutput.Println(fmt.Sprintf("Copying the %s to %s.", part, remote.host))
+
+	wordPressDirectory, err := s.getWordPressDirectory()
+	if err != nil {
+		return err
+	}
+
+	exclude = slices.Clone(exclude)
+
+	if part != "uploads" {
+		var mountedProjects map[string]string
+
+		mountedProjects, err = s.getMountedProjects(strings.TrimSuffix(part, "s"))
+		if err != nil {
+			return err
+		}
+
+		for name := range mountedProjects {
+			exclude = append(exclude, fmt.Sprintf("/%s/", name))
+		}
+	}
+
+	return rsyncToRemote(
+		filepath.Join(wordPressDirectory, "wp-content", part),
+		remote.getRsyncPath("wp-content/"+part),
+		exclude,
+		dryRun,
+		consoleOutput)
+}
+
+// rsyncToRemote copies the contents of a local folder to a folder on the server. With dryRun the changes are shown
+// instead of being made.
+func rsyncToRemote(localDirectory, remoteDirectory string, exclude []string, dryRun bool, consoleOutput *console.Console) error {
+	// Owners and permissions aren't copied as the site's user doesn't exist on the server
+	args := []string{"--recursive", "--links", "--times", "--compress"}
+
+	if dryRun {
+		args = append(args, "--dry-run", "--itemize-changes")
+	}
+
+	for _, pattern := range exclude {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			args = append(args, fmt.Sprintf("--exclude=%s", pattern))
+		}
+	}
+
+	args = append(args, strings.TrimSuffix(localDirectory, string(filepath.Separator))+string(filepath.Separator), remoteDirectory)
+
+	rsyncCommand := Command("rsync", args...)
+	rsyncCommand.Stdin = os.Stdin
+
+	output, err := rsyncCommand.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s", strings.TrimSpace(string(output)))
+	}
+
+	if dryRun {
+		changes := strings.TrimSpace(string(output))
+
+		if changes == "" {
+			changes = "Nothing would change."
+		}
+
+		consoleOutput.Println(changes)
+	}
+
+	return nil
+}
+
+// pushDatabase exports the site's database, without the changes made to make it safe for development, replaces the
+// remote site's database with it and replaces the site's URL with the remote site's URL.
+func (s *Site) pushDatabase(remote remoteSite, remoteURL string, dryRun bool, consoleOutput *console.Console) error {
+	err := s.checkRemoteDatabase("push")
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		consoleOutput.Println(
+			fmt.Sprintf("The database of %s would be backed up on the server and replaced with the site's database.", remoteURL))
+
+		var safeImportChanges []string
+
+		safeImportChanges, err = s.GetSafeImportChanges()
+		if err != nil {
+			return err
+		}
+
+		if len(safeImportChanges) > 0 {
+			consoleOutput.Println(
+				fmt.Sprintf("The safe import changes to %s would be undone in the pushed database.", strings.Join(safeImportChanges, ", ")))
+		}
+
+		return nil
+	}
+
+	// The server gets its own search visibility, payment settings and plugins back rather than those made safe for
+	// development when the database was pulled or imported. They're put back on the server so the site's own database
+	// is never changed.
+	undoCommands, err := s.getUndoSafeImportCommands(consoleOutput)
+	if err != nil {
+		return err
+	}
+
+	dumpFile := filepath.Join(s.settings.Get("siteDirectory"), pushDatabaseFile)
+
+	defer os.Remove(dumpFile)
+
+	err = s.streamExport(dumpFile, consoleOutput)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(dumpFile)
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	var errorOutput bytes.Buffer
+
+	// The backup is saved in the SSH user's home folder, rather than the site's, so it can't be downloaded from the site
+	backupFile := fmt.Sprintf("kana-push-backup-%s.sql", time.Now().Format("20060102-150405"))
+
+	consoleOutput.Println(fmt.Sprintf("Backing up the database on %s to ~/%s.", remote.host, backupFile))
+
+	exportCommand := remote.wpCli("db", "export", backupFile)
+	exportCommand.Stderr = &errorOutput
+
+	err = exportCommand.Run()
+	if err != nil {
+		return fmt.Errorf("the server's database could not be backed up so it wasn't replaced: %s", strings.TrimSpace(errorOutput.String()))
+	}
+
+	restoreHint := fmt.Sprintf("restore the server's database from ~/%s with `wp db import`", backupFile)
+
+	consoleOutput.Println(fmt.Sprintf("Uploading the database to %s.", remote.host))
+
+	errorOutput.Reset()
+
+	importCommand := remote.wpCli("db", "import", "-")
+	importCommand.Stdin = file
+	importCommand.Stderr = &errorOutput
+
+	err = importCommand.Run()
+	if err != nil {
+		return fmt.Errorf("the database could not be imported, %s: %s", restoreHint, strings.TrimSpace(errorOutput.String()))
+	}
+
+	remoteDomain, _ := url.Parse(remoteURL)
+
+	// The full URL is replaced first so the protocol changes too if only one of the sites uses SSL
+	consoleOutput.Println("Replacing the site's URL on the server.")
+
+	for _, replacement := range [][]string{
+		{s.settings.GetURL(), remoteURL},
+		{s.settings.GetDomain(), remoteDomain.Host},
+	} {
+		errorOutput.Reset()
+
+		replaceCommand := remote.wpCli("search-replace", replacement[0], replacement[1], "--all-tables")
+		replaceCommand.Stderr = &errorOutput
+
+		err = replaceCommand.Run()
+		if err != nil {
+			return fmt.Errorf("replace URL failed, %s: %s", restoreHint, strings.TrimSpace(errorOutput.String()))
+		}
+	}
+
+	if len(undoCommands) > 0 {
+		consoleOutput.Println("Undoing the safe import changes on the server.")
+	}
+
+	for _, command := range undoCommands {
+		errorOutput.Reset()
+
+		undoCommand := remote.wpCli(command...)
+		undoCommand.Stderr = &errorOutput
+
+		err = undoCommand.Run()
+		if err != nil {
+			return fmt.Errorf("unable to undo the safe import changes on the server with wp %s: %s",
+				strings.Join(command, " "), strings.TrimSpace(errorOutput.String()))
+		}
+	}
+
+	return nil
+}
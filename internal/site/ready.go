@@ -0,0 +1,67 @@
+package site
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+)
+
+// How long WaitUntilReady waits between checks of the site.
+const readyCheckInterval = time.Second
+
+// WaitUntilReady blocks until the site's homepage returns a 200 status and wp-cli confirms WordPress is installed.
+// If the site still isn't ready once the timeout has passed the error explains what it is waiting on.
+func (s *Site) WaitUntilReady(timeout time.Duration, consoleOutput *console.Console) error {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		reason := s.getNotReadyReason(consoleOutput)
+		if reason == "" {
+			return nil
+		}
+
+		if time.Now().Add(readyCheckInterval).After(deadline) {
+			return fmt.Errorf("the site was not ready after %s as %s", timeout, reason)
+		}
+
+		if consoleOutput.Debug {
+			consoleOutput.Println(fmt.Sprintf("The site isn't ready yet as %s.", reason))
+		}
+
+		time.Sleep(readyCheckInterval)
+	}
+}
+
+// getNotReadyReason returns why the site isn't ready or an empty string if it is.
+func (s *Site) getNotReadyReason(consoleOutput *console.Console) string {
+	if !s.IsSiteRunning() {
+		return "the site isn't running"
+	}
+
+	statusCode, err := getStatusCode(s.settings.GetURL())
+	if err != nil {
+		return fmt.Sprintf("the homepage can't be reached: %s", err)
+	}
+
+	if statusCode != http.StatusOK {
+		return fmt.Sprintf("the homepage returned a %d status", statusCode)
+	}
+
+	code, output, err := s.WPCli([]string{"core", "is-installed"}, false, consoleOutput)
+	if err != nil {
+		return fmt.Sprintf("wp-cli couldn't be run: %s", err)
+	}
+
+	if code != 0 {
+		if strings.TrimSpace(output) != "" {
+			return fmt.Sprintf("wp-cli reports WordPress isn't installed: %s", strings.TrimSpace(output))
+		}
+
+		return "wp-cli reports WordPress isn't installed"
+	}
+
+	return ""
+}
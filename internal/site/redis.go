@@ -0,0 +1,90 @@
+package site
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/docker"
+
+	"github.com/docker/docker/api/types/mount"
+)
+
+const (
+	redisImage = "redis:alpine"
+
+	// redisCachePlugin provides the object cache drop-in and its wp-cli commands.
+	redisCachePlugin = "redis-cache"
+)
+
+// redisDropInHeader identifies the object cache drop-in installed by the Redis Object Cache plugin.
+var redisDropInHeader = []byte("Plugin Name: Redis Object Cache Drop-In")
+
+func (s *Site) getRedisContainerName() string {
+	return fmt.Sprintf("kana-%s-redis", s.settings.Get("name"))
+}
+
+func (s *Site) getRedisContainer() docker.ContainerConfig {
+	return docker.ContainerConfig{
+		Name:        s.getRedisContainerName(),
+		Image:       redisImage,
+		NetworkName: "kana",
+		HostName:    s.getRedisContainerName(),
+		Env:         []string{},
+		Volumes:     []mount.Mount{},
+		Labels: map[string]string{
+			"kana.type": "redis",
+			"kana.site": s.settings.Get("name"),
+		},
+	}
+}
+
+// startRedis starts the Redis container used as the site's object cache.
+func (s *Site) startRedis(ctx context.Context, consoleOutput *console.Console) error {
+	redisContainer := s.getRedisContainer()
+
+	return s.startContainer(ctx, &redisContainer, false, false, consoleOutput)
+}
+
+// maybeEnableObjectCache installs the Redis object cache drop-in if the site uses Redis or, if it doesn't, removes the
+// drop-in so the site doesn't try to connect to a Redis container that isn't running.
+func (s *Site) maybeEnableObjectCache(consoleOutput *console.Console) error {
+	wordPressDirectory, err := s.getWordPressDirectory()
+	if err != nil {
+		return err
+	}
+
+	dropInFile := filepath.Join(wordPressDirectory, "wp-content", "object-cache.php")
+	hasDropIn := false
+
+	dropIn, err := os.ReadFile(dropInFile)
+	if err == nil {
+		hasDropIn = bytes.Contains(dropIn, redisDropInHeader)
+	}
+
+	if !s.settings.GetBool("redis") {
+		if !hasDropIn {
+			return nil
+		}
+
+		consoleOutput.Println("Removing the Redis object cache drop-in as the site no longer uses Redis.")
+
+		return os.Remove(dropInFile)
+	}
+
+	if hasDropIn {
+		return nil
+	}
+
+	consoleOutput.Println("Enabling the Redis object cache.")
+
+	err = s.wpCliOrError([]string{"redis", "enable"}, consoleOutput)
+	if err != nil {
+		return fmt.Errorf("unable to enable the Redis object cache: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package site
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// remoteArgumentPattern matches wp-cli arguments that can be passed to the server's shell without quoting.
+var remoteArgumentPattern = regexp.MustCompile(`^[A-Za-z0-9._/:=@%+,-]+$`)
+
+// remoteSite is a copy of the site on a server, reached over SSH, that the site is pulled from or pushed to.
+type remoteSite struct {
+	host string
+	path string
+}
+
+// getPullRemote returns the server, set by pullHost and pullPath, kana pull copies the site from.
+func (s *Site) getPullRemote() remoteSite {
+	return remoteSite{
+		host: s.settings.Get("pullHost"),
+		path: s.settings.Get("pullPath"),
+	}
+}
+
+// getPushRemote returns the server, set by pushHost and pushPath, kana push deploys the site to. The pull settings are
+// used for any that are empty so a site pulled from and pushed to the same server only needs them set once.
+func (s *Site) getPushRemote() remoteSite {
+	remote := s.getPullRemote()
+
+	if s.settings.Get("pushHost") != "" {
+		remote.host = s.settings.Get("pushHost")
+	}
+
+	if s.settings.Get("pushPath") != "" {
+		remote.path = s.settings.Get("pushPath")
+	}
+
+	return remote
+}
+
+// isSet returns true if both the server and the folder WordPress is installed in on it are known.
+func (r remoteSite) isSet() bool {
+	return r.host != "" && r.path != ""
+}
+
+// wpCli returns the command that runs wp-cli on the server, over SSH, in the remote site's folder. Plugins and themes
+// are skipped so a broken plugin on the server can't stop the site being pulled or pushed.
+func (r remoteSite) wpCli(args ...string) *exec.Cmd {
+	// The path is validated to be safe to use in the server's shell without quoting, which would stop ~ being expanded
+	wpCommand := []string{"wp", fmt.Sprintf("--path=%s", r.path), "--skip-plugins", "--skip-themes"}
+
+	for _, arg := range args {
+		if !remoteArgumentPattern.MatchString(arg) {
+			arg = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+		}
+
+		wpCommand = append(wpCommand, arg)
+	}
+
+	command := Command("ssh", r.host, strings.Join(wpCommand, " "))
+
+	// SSH asks for passwords and passphrases on the terminal
+	command.Stdin = os.Stdin
+
+	return command
+}
+
+// getURL returns the home URL of the site on the server.
+func (r remoteSite) getURL() (string, error) {
+	var errorOutput bytes.Buffer
+
+	homeCommand := r.wpCli("option", "get", "home")
+	homeCommand.Stderr = &errorOutput
+
+	output, err := homeCommand.Output()
+	if err != nil {
+		return "", fmt.Errorf("unable to reach the site at %s on %s: %s", r.path, r.host, strings.TrimSpace(errorOutput.String()))
+	}
+
+	remoteURL := strings.TrimSpace(string(output))
+
+	parsedURL, err := url.Parse(remoteURL)
+	if err != nil || parsedURL.Host == "" {
+		return "", fmt.Errorf("the site on %s has an invalid home URL, %s", r.host, remoteURL)
+	}
+
+	return strings.TrimSuffix(remoteURL, "/"), nil
+}
+
+// getRsyncPath returns the rsync location of a folder, relative to the WordPress folder, on the server.
+func (r remoteSite) getRsyncPath(relativePath string) string {
+	// rsync already treats paths on the server as relative to the user's home folder
+	remotePath := strings.TrimSuffix(strings.TrimPrefix(r.path, "~/"), "/")
+
+	return fmt.Sprintf("%s:%s/%s/", r.host, remotePath, strings.Trim(relativePath, "/"))
+}
+
+// checkRemoteTools returns an error if ssh, or rsync when files are being copied, isn't installed.
+func checkRemoteTools(action string, copyingFiles bool) error {
+	tools := []string{"ssh"}
+
+	if copyingFiles {
+		tools = append(tools, "rsync")
+	}
+
+	for _, tool := range tools {
+		_, err := exec.LookPath(tool)
+		if err != nil {
+			return fmt.Errorf("%s is needed to %s a site but could not be found. Please install it and try again", tool, action)
+		}
+	}
+
+	return nil
+}
+
+// checkRemoteDatabase returns an error if the site's database can't be copied to or from a server, as the server's
+// wp-cli only exports and imports MariaDB and MySQL databases.
+func (s *Site) checkRemoteDatabase(action string) error {
+	isUsingSQLite, err := s.isUsingSQLite()
+	if err != nil {
+		return err
+	}
+
+	isUsingPostgres, err := s.isUsingPostgres()
+	if err != nil {
+		return err
+	}
+
+	if isUsingSQLite || isUsingPostgres {
+		return fmt.Errorf("only sites using MariaDB or MySQL can %s a database", action)
+	}
+
+	return nil
+}
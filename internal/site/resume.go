@@ -0,0 +1,194 @@
+package site
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/lock"
+	"github.com/ChrisWiegman/kana/internal/settings"
+
+	"github.com/docker/docker/api/types"
+)
+
+// runningSitesFile lists the sites that have been started and not yet stopped with Kana.
+const runningSitesFile = "running.json"
+
+// getRunningSiteNames returns the names of the sites that have been started and not yet stopped with Kana.
+func (s *Site) getRunningSiteNames() []string {
+	names := []string{}
+
+	contents, err := os.ReadFile(filepath.Join(s.settings.Get("appDirectory"), runningSitesFile))
+	if err != nil {
+		return names
+	}
+
+	_ = json.Unmarshal(contents, &names)
+
+	return names
+}
+
+// trackRunningSite adds the site to, or if running is false removes it from, the sites that can be resumed after
+// Docker restarts. Failing to do so shouldn't stop the site starting or stopping so errors are ignored.
+func (s *Site) trackRunningSite(running bool) {
+	name := s.settings.Get("name")
+
+	_ = s.updateRunningSiteNames(func(names []string) []string {
+		names = slices.DeleteFunc(names, func(runningName string) bool {
+			return runningName == name
+		})
+
+		if running {
+			names = append(names, name)
+		}
+
+		return names
+	})
+}
+
+// updateRunningSiteNames changes the names of the sites that have been started and not yet stopped with Kana. Several
+// sites can be started or stopped at once, such as by `kana stop --all`, so other Kana commands can't change the list
+// until it is done and it is replaced in a single step so it is never left half written.
+func (s *Site) updateRunningSiteNames(change func(names []string) []string) error {
+	appDirectory := s.settings.Get("appDirectory")
+
+	runningSitesLock, err := lock.Acquire(appDirectory, "track running sites", true, nil)
+	if err != nil {
+		return err
+	}
+
+	defer runningSitesLock.Release() //nolint:errcheck
+
+	contents, err := json.Marshal(change(s.getRunningSiteNames()))
+	if err != nil {
+		return err
+	}
+
+	_, filePermissions := settings.GetDefaultFilePermissions()
+	runningFile := filepath.Join(appDirectory, runningSitesFile)
+
+	err = os.WriteFile(runningFile+".tmp", contents, os.FileMode(filePermissions))
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(runningFile+".tmp", runningFile)
+}
+
+// removeExitedContainers removes the site's containers, and those shared by every site, that Docker stopped when it
+// restarted so they can be created again.
+func (s *Site) removeExitedContainers() error {
+	for _, label := range []string{fmt.Sprintf("kana.site=%s", s.settings.Get("name")), "kana.global"} {
+		_, err := s.dockerClient.ContainerRemoveExited(label)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetResumableSites returns the sites that were started with Kana and have since stopped without being stopped by
+// Kana, such as when Docker restarts.
+func (s *Site) GetResumableSites() ([]SiteInfo, error) {
+	resumableSites := []SiteInfo{}
+
+	runningNames := s.getRunningSiteNames()
+	if len(runningNames) == 0 {
+		return resumableSites, nil
+	}
+
+	siteList, err := s.GetSiteList(false)
+	if err != nil {
+		return resumableSites, err
+	}
+
+	for i := range siteList {
+		if !slices.Contains(runningNames, siteList[i].Name) {
+			continue
+		}
+
+		containers, err := s.dockerClient.ContainerList(siteList[i].Name)
+		if err != nil {
+			return resumableSites, err
+		}
+
+		isRunning := slices.ContainsFunc(containers, func(container types.Container) bool {
+			return container.State == "running"
+		})
+
+		if !isRunning {
+			resumableSites = append(resumableSites, siteList[i])
+		}
+	}
+
+	return resumableSites, nil
+}
+
+// ResumeSites starts the given sites again, without opening them in the browser, and returns the names of those that
+// started.
+func (s *Site) ResumeSites(sites []SiteInfo, consoleOutput *console.Console) []string {
+	resumed := []string{}
+
+	// Each site adds itself back as it starts so the sites still waiting aren't resumed a second time by those starting
+	_ = s.updateRunningSiteNames(func(names []string) []string {
+		return slices.DeleteFunc(names, func(name string) bool {
+			return slices.ContainsFunc(sites, func(site SiteInfo) bool {
+				return site.Name == name
+			})
+		})
+	})
+
+	for i := range sites {
+		consoleOutput.Println(fmt.Sprintf("Starting %s.", sites[i].Name))
+
+		err := s.runKanaForSite(&sites[i], "start", "--open=false")
+		if err != nil {
+			consoleOutput.Warn(fmt.Sprintf("Unable to start %s: %s", sites[i].Name, err))
+
+			continue
+		}
+
+		resumed = append(resumed, sites[i].Name)
+	}
+
+	return resumed
+}
+
+// MaybeResumeSites starts the sites stopped by a Docker restart again, other than the excluded site, if the autoResume
+// setting is on or, otherwise, says how to.
+func (s *Site) MaybeResumeSites(exclude string, consoleOutput *console.Console) {
+	sites, err := s.GetResumableSites()
+	if err != nil {
+		return
+	}
+
+	sites = slices.DeleteFunc(sites, func(site SiteInfo) bool {
+		return site.Name == exclude
+	})
+
+	if len(sites) == 0 {
+		return
+	}
+
+	if s.settings.GetBool("autoResume") {
+		s.ResumeSites(sites, consoleOutput)
+
+		return
+	}
+
+	names := []string{}
+
+	for i := range sites {
+		names = append(names, sites[i].Name)
+	}
+
+	consoleOutput.Warn(fmt.Sprintf(
+		"%d site(s) stopped when Docker restarted: %s. Run 'kana resume --last' to start them again.",
+		len(sites),
+		strings.Join(names, ", ")))
+}
@@ -0,0 +1,62 @@
+package site
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ChrisWiegman/kana/internal/settings"
+)
+
+// writeRoutesConfig writes the site's routes setting to a Traefik dynamic config file, which Traefik picks up without
+// restarting. Labels can't be used as Traefik's Docker provider can only send requests to the container they're on.
+func (s *Site) writeRoutesConfig() error {
+	routes, err := settings.ParseRoutes(s.settings.GetSlice("routes"))
+	if err != nil {
+		return err
+	}
+
+	// Routes are served through Traefik, which isn't used in CI mode
+	if len(routes) == 0 || s.settings.GetBool("isCI") {
+		return s.removeRoutesConfig()
+	}
+
+	var config strings.Builder
+
+	for i, route := range routes {
+		name := fmt.Sprintf("kana-%s-route-%d", s.settings.Get("name"), i+1)
+		rule := fmt.Sprintf("Host(`%s`) && PathPrefix(`%s`)", s.settings.GetDomain(), route.Path)
+
+		fmt.Fprintf(&config, "[http.routers.%s-http]\n", name)
+		fmt.Fprintf(&config, "entryPoints = [\"web\"]\nrule = %q\nservice = %q\n\n", rule, name)
+		fmt.Fprintf(&config, "[http.routers.%s]\n", name)
+		fmt.Fprintf(&config, "entryPoints = [\"websecure\"]\nrule = %q\nservice = %q\n\n", rule, name)
+		fmt.Fprintf(&config, "[http.routers.%s.tls]\n\n", name)
+		fmt.Fprintf(&config, "[[http.services.%s.loadBalancer.servers]]\nurl = %q\n\n", name, route.URL)
+	}
+
+	dirPerms, filePerms := settings.GetDefaultFilePermissions()
+
+	err = os.MkdirAll(filepath.Dir(s.getRoutesConfigPath()), os.FileMode(dirPerms))
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(s.getRoutesConfigPath(), []byte(config.String()), os.FileMode(filePerms))
+}
+
+// removeRoutesConfig removes the site's routes from Traefik.
+func (s *Site) removeRoutesConfig() error {
+	err := os.Remove(s.getRoutesConfigPath())
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	return err
+}
+
+// getRoutesConfigPath returns the path of the Traefik dynamic config file for the site's routes.
+func (s *Site) getRoutesConfigPath() string {
+	return filepath.Join(s.settings.Get("appDirectory"), "config", "traefik", "dynamic", fmt.Sprintf("routes-%s.toml", s.settings.Get("name")))
+}
@@ -0,0 +1,337 @@
+package site
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/helpers"
+	"github.com/ChrisWiegman/kana/internal/settings"
+)
+
+// safeImportFile is the file, in the site directory, recording the changes MakeImportSafe made to the database so they
+// can be undone before the database is pushed back to a server.
+const safeImportFile = "safe-import.json"
+
+// safeImportChanges are the changes MakeImportSafe made to the site's database.
+type safeImportChanges struct {
+	Options []safeImportOption `json:"options"`
+	Plugins []safeImportPlugin `json:"plugins"`
+}
+
+// safeImportOption is an option, or a key of an option holding an array, that MakeImportSafe changed. The values are
+// JSON, as wp-cli reads and writes them with --format=json, so they are put back exactly.
+type safeImportOption struct {
+	Option   string `json:"option"`
+	Key      string `json:"key,omitempty"`
+	Original string `json:"original"`
+	Safe     string `json:"safe"`
+}
+
+// safeImportPlugin is a plugin that MakeImportSafe deactivated.
+type safeImportPlugin struct {
+	Name    string `json:"name"`
+	Network bool   `json:"network"`
+}
+
+// MakeImportSafe makes a database imported from another site, such as production, safe to develop with. It sets the
+// options in the safeImportOptions setting that the site has and deactivates the plugins in the safeImportPlugins
+// setting. WP_ENVIRONMENT_TYPE comes from the environment setting rather than the database so it only warns if that is
+// production. The changes are recorded so kana push can undo them.
+func (s *Site) MakeImportSafe(consoleOutput *console.Console) error {
+	changes := safeImportChanges{
+		Options: []safeImportOption{},
+		Plugins: []safeImportPlugin{},
+	}
+
+	// The imported database replaced any changes made to the last one
+	if !s.settings.GetBool("safeImport") {
+		return s.saveSafeImportChanges(changes)
+	}
+
+	consoleOutput.Println("Making the imported database safe for development.")
+
+	if s.settings.Get("environment") == "production" {
+		consoleOutput.Warn(
+			"The site's WP_ENVIRONMENT_TYPE is production so plugins may behave as they do on your live site. Run 'kana config environment local' and restart the site to change it.") //nolint:lll
+	}
+
+	overrides, err := settings.ParseOptionOverrides(s.settings.GetSlice("safeImportOptions"))
+	if err != nil {
+		return err
+	}
+
+	for _, override := range overrides {
+		change, changed, err := s.overrideOption(override, consoleOutput)
+		if err != nil {
+			return err
+		}
+
+		if changed {
+			changes.Options = append(changes.Options, change)
+
+			consoleOutput.Println(fmt.Sprintf("Set the %s option to %s.", change.getName(), override.Value))
+		}
+	}
+
+	changes.Plugins, err = s.deactivateUnsafePlugins(consoleOutput)
+	if err != nil {
+		return err
+	}
+
+	for _, plugin := range changes.Plugins {
+		consoleOutput.Println(fmt.Sprintf("Deactivated the %s plugin.", plugin.Name))
+	}
+
+	return s.saveSafeImportChanges(changes)
+}
+
+// GetSafeImportChanges returns descriptions of the changes MakeImportSafe made to the site's database, such as "the
+// blog_public option", for warning about them before the database is pushed.
+func (s *Site) GetSafeImportChanges() ([]string, error) {
+	descriptions := []string{}
+
+	changes, err := s.loadSafeImportChanges()
+	if err != nil {
+		return descriptions, err
+	}
+
+	for _, option := range changes.Options {
+		descriptions = append(descriptions, fmt.Sprintf("the %s option", option.getName()))
+	}
+
+	for _, plugin := range changes.Plugins {
+		descriptions = append(descriptions, fmt.Sprintf("the %s plugin", plugin.Name))
+	}
+
+	return descriptions, nil
+}
+
+// getUndoSafeImportCommands returns the wp-cli commands that put back the original values of the options
+// MakeImportSafe changed, and reactivate the plugins it deactivated, where the site still has them as MakeImportSafe
+// left them. Anything changed since, by hand or by a later import, is left alone. The site itself isn't changed.
+func (s *Site) getUndoSafeImportCommands(consoleOutput *console.Console) ([][]string, error) {
+	commands := [][]string{}
+
+	changes, err := s.loadSafeImportChanges()
+	if err != nil {
+		return commands, err
+	}
+
+	for _, option := range changes.Options {
+		current, found, err := s.getOptionJSON(option.Option, option.Key, consoleOutput)
+		if err != nil {
+			return commands, err
+		}
+
+		if !found || current != option.Safe {
+			continue
+		}
+
+		commands = append(commands, getSetOptionJSONCommand(option.Option, option.Key, option.Original))
+	}
+
+	if len(changes.Plugins) == 0 {
+		return commands, nil
+	}
+
+	plugins, err := s.GetExtensions("plugin", consoleOutput)
+	if err != nil {
+		return commands, err
+	}
+
+	for _, plugin := range plugins {
+		index := slices.IndexFunc(changes.Plugins, func(change safeImportPlugin) bool { return change.Name == plugin.Name })
+		if index == -1 || plugin.Status != "inactive" {
+			continue
+		}
+
+		activateCommand := []string{"plugin", "activate", plugin.Name}
+
+		if changes.Plugins[index].Network {
+			activateCommand = append(activateCommand, "--network")
+		}
+
+		commands = append(commands, activateCommand)
+	}
+
+	return commands, nil
+}
+
+// overrideOption sets an option, or a key of an option holding an array, if the site has it and returns its original
+// and new values. Options the site doesn't have, such as the settings of a payment gateway it doesn't use, are left
+// alone.
+func (s *Site) overrideOption(
+	override settings.OptionOverride,
+	consoleOutput *console.Console) (safeImportOption, bool, error) {
+	change := safeImportOption{
+		Option: override.Option,
+		Key:    override.Key,
+	}
+
+	original, found, err := s.getOptionJSON(override.Option, override.Key, consoleOutput)
+	if err != nil || !found {
+		return change, false, err
+	}
+
+	updateCommand := []string{"option", "update", override.Option, override.Value}
+
+	if override.Key != "" {
+		updateCommand = []string{"option", "patch", "update", override.Option, override.Key, override.Value}
+	}
+
+	err = s.wpCliOrError(updateCommand, consoleOutput)
+	if err != nil {
+		return change, false, fmt.Errorf("unable to set the %s option: %s", override.Option, err)
+	}
+
+	safe, _, err := s.getOptionJSON(override.Option, override.Key, consoleOutput)
+	if err != nil {
+		return change, false, err
+	}
+
+	change.Original = original
+	change.Safe = safe
+
+	return change, true, nil
+}
+
+// getOptionJSON returns an option, or a key of an option holding an array, as JSON and whether the site has it.
+func (s *Site) getOptionJSON(option, key string, consoleOutput *console.Console) (string, bool, error) {
+	command := []string{"option", "get", option, "--format=json"}
+
+	if key != "" {
+		command = []string{"option", "pluck", option, key, "--format=json"}
+	}
+
+	code, output, err := s.WPCli(command, false, consoleOutput)
+	if err != nil {
+		return "", false, err
+	}
+
+	if code != 0 {
+		return "", false, nil
+	}
+
+	return strings.TrimSpace(output), true, nil
+}
+
+// getSetOptionJSONCommand returns the wp-cli command that sets an option, or a key of an option holding an array, to a
+// JSON value.
+func getSetOptionJSONCommand(option, key, value string) []string {
+	if key != "" {
+		return []string{"option", "patch", "update", option, key, value, "--format=json"}
+	}
+
+	return []string{"option", "update", option, value, "--format=json"}
+}
+
+// deactivateUnsafePlugins deactivates the active plugins in the safeImportPlugins setting and returns them.
+func (s *Site) deactivateUnsafePlugins(consoleOutput *console.Console) ([]safeImportPlugin, error) {
+	unsafePlugins := []string{}
+
+	for _, entry := range s.settings.GetSlice("safeImportPlugins") {
+		slug, _, err := settings.ParsePlugin(entry)
+		if err == nil {
+			unsafePlugins = append(unsafePlugins, slug)
+		}
+	}
+
+	deactivated := []safeImportPlugin{}
+
+	if len(unsafePlugins) == 0 {
+		return deactivated, nil
+	}
+
+	plugins, err := s.GetExtensions("plugin", consoleOutput)
+	if err != nil {
+		return deactivated, err
+	}
+
+	for _, plugin := range plugins {
+		if !slices.Contains(unsafePlugins, plugin.Name) {
+			continue
+		}
+
+		if plugin.Status != "active" && plugin.Status != "active-network" {
+			continue
+		}
+
+		command := []string{"plugin", "deactivate", plugin.Name}
+
+		if plugin.Status == "active-network" {
+			command = append(command, "--network")
+		}
+
+		err = s.wpCliOrError(command, consoleOutput)
+		if err != nil {
+			return deactivated, fmt.Errorf("unable to deactivate %s: %s", plugin.Name, err)
+		}
+
+		deactivated = append(deactivated, safeImportPlugin{
+			Name:    plugin.Name,
+			Network: plugin.Status == "active-network",
+		})
+	}
+
+	return deactivated, nil
+}
+
+// saveSafeImportChanges records the changes MakeImportSafe made to the database, removing the record if there are none.
+func (s *Site) saveSafeImportChanges(changes safeImportChanges) error {
+	changesFile := filepath.Join(s.settings.Get("siteDirectory"), safeImportFile)
+
+	if len(changes.Options) == 0 && len(changes.Plugins) == 0 {
+		err := os.Remove(changesFile)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		return nil
+	}
+
+	changesJSON, err := json.MarshalIndent(changes, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	_, filePermissions := settings.GetDefaultFilePermissions()
+
+	return os.WriteFile(changesFile, changesJSON, os.FileMode(filePermissions))
+}
+
+// loadSafeImportChanges returns the changes MakeImportSafe last made to the database.
+func (s *Site) loadSafeImportChanges() (safeImportChanges, error) {
+	changes := safeImportChanges{}
+	changesFile := filepath.Join(s.settings.Get("siteDirectory"), safeImportFile)
+
+	hasChanges, err := helpers.PathExists(changesFile)
+	if err != nil || !hasChanges {
+		return changes, err
+	}
+
+	changesJSON, err := os.ReadFile(changesFile)
+	if err != nil {
+		return changes, err
+	}
+
+	err = json.Unmarshal(changesJSON, &changes)
+	if err != nil {
+		return changes, fmt.Errorf("the record of the site's safe import changes, %s, is not valid: %s", changesFile, err)
+	}
+
+	return changes, nil
+}
+
+// getName returns the option's name, with the key if it's a key of an option holding an array.
+func (o safeImportOption) getName() string {
+	if o.Key != "" {
+		return fmt.Sprintf("%s.%s", o.Option, o.Key)
+	}
+
+	return o.Option
+}
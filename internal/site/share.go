@@ -0,0 +1,149 @@
+package site
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/docker"
+
+	"github.com/docker/docker/api/types/mount"
+)
+
+const (
+	shareImage        = "cloudflare/cloudflared"
+	shareURLTimeout   = 30 * time.Second
+	shareWaitInterval = 500 * time.Millisecond
+)
+
+// shareURLPattern matches the public URL cloudflared logs once a quick tunnel is ready.
+var shareURLPattern = regexp.MustCompile(`https://[a-z0-9-]+\.trycloudflare\.com`)
+
+// StartSharing publishes the running site at a temporary public HTTPS URL through a Cloudflare quick tunnel and returns
+// the URL. The site's home and siteurl options are changed to the public URL so its links and redirects work for
+// visitors until StopSharing is called.
+func (s *Site) StartSharing(ctx context.Context, consoleOutput *console.Console) (string, error) {
+	if s.settings.Get("multisite") != "none" {
+		return "", fmt.Errorf("multisite sites can't be shared as their sites' domains can't be changed to the public URL")
+	}
+
+	shareContainer := s.getShareContainer()
+
+	err := s.startContainer(ctx, &shareContainer, false, false, consoleOutput)
+	if err != nil {
+		return "", err
+	}
+
+	publicURL, err := s.waitForShareURL(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	err = s.setSiteURL(publicURL, consoleOutput)
+	if err != nil {
+		return "", err
+	}
+
+	return publicURL, nil
+}
+
+// StopSharing removes the tunnel and changes the site's URL back to its local one.
+func (s *Site) StopSharing(consoleOutput *console.Console) error {
+	_, err := s.dockerClient.ContainerStop(s.getShareContainerName())
+	if err != nil {
+		return err
+	}
+
+	return s.setSiteURL(s.settings.GetURL(), consoleOutput)
+}
+
+// WaitWhileSharing blocks until the context is cancelled, such as by Ctrl-C, or the tunnel stops, such as when the
+// site is stopped.
+func (s *Site) WaitWhileSharing(ctx context.Context) {
+	ticker := time.NewTicker(shareWaitInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !s.dockerClient.ContainerIsRunning(s.getShareContainerName()) {
+				return
+			}
+		}
+	}
+}
+
+func (s *Site) getShareContainerName() string {
+	return fmt.Sprintf("kana-%s-share", s.settings.Get("name"))
+}
+
+// getShareContainer returns the cloudflared container that tunnels to the WordPress container directly, rather than
+// through Traefik, as Traefik only routes requests for the site's local domain.
+func (s *Site) getShareContainer() docker.ContainerConfig {
+	return docker.ContainerConfig{
+		Name:        s.getShareContainerName(),
+		Image:       shareImage,
+		NetworkName: "kana",
+		HostName:    s.getShareContainerName(),
+		Command: []string{
+			"tunnel",
+			"--no-autoupdate",
+			"--url",
+			fmt.Sprintf("http://kana-%s-wordpress", s.settings.Get("name"))},
+		Env:     []string{},
+		Volumes: []mount.Mount{},
+		Labels: map[string]string{
+			"kana.type": "share",
+			"kana.site": s.settings.Get("name"),
+		},
+	}
+}
+
+// waitForShareURL reads the tunnel's public URL from cloudflared's log, which can take a few seconds to appear.
+func (s *Site) waitForShareURL(ctx context.Context) (string, error) {
+	deadline := time.Now().Add(shareURLTimeout)
+
+	for time.Now().Before(deadline) {
+		output, err := s.dockerClient.ContainerLogs(s.getShareContainerName())
+		if err != nil {
+			return "", err
+		}
+
+		if publicURL := shareURLPattern.FindString(output); publicURL != "" {
+			return publicURL, nil
+		}
+
+		if !s.dockerClient.ContainerIsRunning(s.getShareContainerName()) {
+			return "", fmt.Errorf("the tunnel stopped before the site could be shared: %s", strings.TrimSpace(output))
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(shareWaitInterval):
+		}
+	}
+
+	return "", fmt.Errorf("the tunnel didn't start within %s. Check your internet connection and try again", shareURLTimeout)
+}
+
+// setSiteURL changes the home and siteurl options so WordPress builds its links, and redirects, for the given URL.
+func (s *Site) setSiteURL(siteURL string, consoleOutput *console.Console) error {
+	for _, option := range []string{"siteurl", "home"} {
+		code, output, err := s.WPCli([]string{"option", "update", option, siteURL}, false, consoleOutput)
+		if err != nil {
+			return err
+		}
+
+		if code != 0 {
+			return fmt.Errorf("the site's %s could not be changed to %s: %s", option, siteURL, output)
+		}
+	}
+
+	return nil
+}
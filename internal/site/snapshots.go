@@ -0,0 +1,170 @@
+package site
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/helpers"
+)
+
+// DatabaseSnapshot is a named copy of the site's database saved with `kana db snapshot save`.
+type DatabaseSnapshot struct {
+	Name    string
+	Path    string
+	Size    int64
+	Created time.Time
+}
+
+// snapshotNamePattern limits snapshot names to those that are safe to use as file names.
+var snapshotNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// getSnapshotDirectory returns the directory used to store the site's database snapshots, creating it if needed.
+func (s *Site) getSnapshotDirectory() (string, error) {
+	snapshotDirectory := filepath.Join(s.settings.Get("siteDirectory"), "snapshots")
+
+	err := os.MkdirAll(snapshotDirectory, os.FileMode(defaultDirPermissions))
+	if err != nil {
+		return "", err
+	}
+
+	return snapshotDirectory, nil
+}
+
+// GetDatabaseSnapshots returns the site's database snapshots, newest first.
+func (s *Site) GetDatabaseSnapshots() ([]DatabaseSnapshot, error) {
+	snapshots := []DatabaseSnapshot{}
+
+	snapshotDirectory, err := s.getSnapshotDirectory()
+	if err != nil {
+		return snapshots, err
+	}
+
+	files, err := os.ReadDir(snapshotDirectory)
+	if err != nil {
+		return snapshots, err
+	}
+
+	for _, file := range files {
+		extension := filepath.Ext(file.Name())
+
+		if file.IsDir() || (extension != ".sql" && extension != ".sqlite") {
+			continue
+		}
+
+		info, err := file.Info()
+		if err != nil {
+			return snapshots, err
+		}
+
+		snapshots = append(snapshots, DatabaseSnapshot{
+			Name:    strings.TrimSuffix(file.Name(), extension),
+			Path:    filepath.Join(snapshotDirectory, file.Name()),
+			Size:    info.Size(),
+			Created: info.ModTime(),
+		})
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Created.After(snapshots[j].Created)
+	})
+
+	return snapshots, nil
+}
+
+// SaveDatabaseSnapshot saves the site's current database as a snapshot with the given name, replacing an existing
+// snapshot with the same name only if overwrite is true.
+func (s *Site) SaveDatabaseSnapshot(name string, overwrite bool, consoleOutput *console.Console) (DatabaseSnapshot, error) {
+	if !snapshotNamePattern.MatchString(name) {
+		return DatabaseSnapshot{}, fmt.Errorf(
+			"invalid snapshot name %s. Names can only contain letters, numbers, dots, dashes and underscores", name)
+	}
+
+	snapshotDirectory, err := s.getSnapshotDirectory()
+	if err != nil {
+		return DatabaseSnapshot{}, err
+	}
+
+	existing, err := s.getDatabaseSnapshot(name)
+	if err == nil {
+		if !overwrite {
+			return DatabaseSnapshot{}, fmt.Errorf("the snapshot %s already exists. Use --force to replace it", name)
+		}
+
+		err = os.Remove(existing.Path)
+		if err != nil {
+			return DatabaseSnapshot{}, err
+		}
+	}
+
+	isUsingSQLite, err := s.isUsingSQLite()
+	if err != nil {
+		return DatabaseSnapshot{}, err
+	}
+
+	if isUsingSQLite {
+		err = helpers.CopyFile(s.GetSQLiteDatabaseFile(), filepath.Join(snapshotDirectory, name+".sqlite"))
+	} else {
+		err = s.dumpDatabase(filepath.Join("snapshots", name+".sql"), consoleOutput)
+	}
+
+	if err != nil {
+		return DatabaseSnapshot{}, fmt.Errorf("saving the snapshot failed: %s", err)
+	}
+
+	s.recordHistory("database snapshot saved", name)
+
+	return s.getDatabaseSnapshot(name)
+}
+
+// RestoreDatabaseSnapshot replaces the site's current database with the named snapshot.
+func (s *Site) RestoreDatabaseSnapshot(name string, consoleOutput *console.Console) error {
+	snapshot, err := s.getDatabaseSnapshot(name)
+	if err != nil {
+		return err
+	}
+
+	isUsingSQLite, err := s.isUsingSQLite()
+	if err != nil {
+		return err
+	}
+
+	if isUsingSQLite != (filepath.Ext(snapshot.Path) == ".sqlite") {
+		return fmt.Errorf("the snapshot %s was saved from a different type of database and cannot be restored to this site", name)
+	}
+
+	if isUsingSQLite {
+		err = helpers.CopyFile(snapshot.Path, s.GetSQLiteDatabaseFile())
+	} else {
+		err = s.restoreDatabaseDump(filepath.Join("snapshots", filepath.Base(snapshot.Path)), consoleOutput)
+	}
+
+	if err != nil {
+		return fmt.Errorf("restoring the snapshot failed: %s", err)
+	}
+
+	s.recordHistory("database snapshot restored", name)
+
+	return nil
+}
+
+// getDatabaseSnapshot returns the snapshot with the given name.
+func (s *Site) getDatabaseSnapshot(name string) (DatabaseSnapshot, error) {
+	snapshots, err := s.GetDatabaseSnapshots()
+	if err != nil {
+		return DatabaseSnapshot{}, err
+	}
+
+	for _, snapshot := range snapshots {
+		if snapshot.Name == name {
+			return snapshot, nil
+		}
+	}
+
+	return DatabaseSnapshot{}, fmt.Errorf("the snapshot %s could not be found. Use `kana db snapshot list` to see the saved snapshots", name)
+}
@@ -0,0 +1,118 @@
+package site
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+
+	"github.com/ChrisWiegman/kana/internal/docker"
+	"github.com/ChrisWiegman/kana/internal/helpers"
+	"github.com/ChrisWiegman/kana/internal/settings"
+
+	"github.com/docker/docker/api/types/mount"
+)
+
+const (
+	sshAgentSocket   = "/kana/ssh/agent.sock"
+	sshHomeDirectory = "/kana/ssh/home"
+	// dockerDesktopSSHSocket is where Docker Desktop, and apps compatible with it such as OrbStack, share the Mac's SSH
+	// agent with containers as the agent's own socket can't be mounted from macOS.
+	dockerDesktopSSHSocket = "/run/host-services/ssh-auth.sock"
+)
+
+// addSSHAgent gives a container the user's SSH agent, when the sshAgent setting is on, so Composer can install packages
+// from private Git repositories and wp-cli can use @aliases on remote servers without keys being copied into the
+// container. Nothing is added if no agent is running.
+func (s *Site) addSSHAgent(container *docker.ContainerConfig) error {
+	if !s.settings.GetBool("sshAgent") {
+		return nil
+	}
+
+	agentSocket := os.Getenv("SSH_AUTH_SOCK")
+
+	if runtime.GOOS == "darwin" {
+		agentSocket = dockerDesktopSSHSocket
+	}
+
+	if agentSocket == "" {
+		return nil
+	}
+
+	passwdFile, err := s.writeSSHPasswdFile()
+	if err != nil {
+		return err
+	}
+
+	container.Volumes = append(container.Volumes,
+		mount.Mount{
+			Type:   mount.TypeBind,
+			Source: agentSocket,
+			Target: sshAgentSocket,
+		},
+		// SSH won't run as a user the container doesn't know about, as the containers run as the host's user
+		mount.Mount{
+			Type:     mount.TypeBind,
+			Source:   passwdFile,
+			Target:   "/etc/passwd",
+			ReadOnly: true,
+		})
+
+	container.Env = append(container.Env, fmt.Sprintf("SSH_AUTH_SOCK=%s", sshAgentSocket))
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+
+	// The hosts the user already trusts are shared so connections don't stop to ask about them
+	knownHostsFile := filepath.Join(home, ".ssh", "known_hosts")
+
+	hasKnownHosts, err := helpers.PathExists(knownHostsFile)
+	if err != nil || !hasKnownHosts {
+		return err
+	}
+
+	container.Volumes = append(container.Volumes, mount.Mount{
+		Type:     mount.TypeBind,
+		Source:   knownHostsFile,
+		Target:   filepath.ToSlash(filepath.Join(sshHomeDirectory, ".ssh", "known_hosts")),
+		ReadOnly: true,
+	})
+
+	return nil
+}
+
+// writeSSHPasswdFile writes the passwd file mounted into containers using the SSH agent, which has the host's user
+// with a home folder holding the user's known hosts.
+func (s *Site) writeSSHPasswdFile() (string, error) {
+	currentUser, err := user.Current()
+	if err != nil {
+		return "", err
+	}
+
+	sshDirectory := filepath.Join(s.settings.Get("appDirectory"), "cache", "ssh")
+	dirPermissions, filePermissions := settings.GetDefaultFilePermissions()
+
+	err = os.MkdirAll(sshDirectory, os.FileMode(dirPermissions))
+	if err != nil {
+		return "", err
+	}
+
+	passwd := fmt.Sprintf("root:x:0:0:root:%s:/bin/sh\n", sshHomeDirectory)
+
+	if currentUser.Uid != "0" {
+		passwd = fmt.Sprintf(
+			"root:x:0:0:root:/root:/bin/sh\n%s:x:%s:%s:%s:%s:/bin/sh\n",
+			currentUser.Username,
+			currentUser.Uid,
+			currentUser.Gid,
+			currentUser.Username,
+			sshHomeDirectory)
+	}
+
+	passwdFile := filepath.Join(sshDirectory, "passwd")
+
+	return passwdFile, os.WriteFile(passwdFile, []byte(passwd), os.FileMode(filePermissions))
+}
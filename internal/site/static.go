@@ -0,0 +1,383 @@
+package site
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/docker"
+	"github.com/ChrisWiegman/kana/internal/helpers"
+	"github.com/ChrisWiegman/kana/internal/settings"
+
+	"github.com/docker/docker/api/types/mount"
+)
+
+const (
+	staticExportMarker  = ".kana-static"
+	staticServeImage    = "nginx:alpine"
+	staticFetchTimeout  = 30 * time.Second
+	staticMaxRedirects  = 10
+	staticServeHostName = "static"
+)
+
+// staticSeedPaths are crawled as well as the home page so pages only linked from the sitemap are exported too.
+var staticSeedPaths = []string{"/", "/robots.txt", "/wp-sitemap.xml", "/favicon.ico"}
+
+// staticExcludedPaths can't work without WordPress so are never exported.
+var staticExcludedPaths = []string{"/wp-admin", "/wp-login.php", "/wp-json", "/xmlrpc.php", "/wp-cron.php", "/wp-comments-post.php"}
+
+var staticLinkPattern = regexp.MustCompile(
+	`(?i)(?:href|src|srcset|data-src)\s*=\s*["']([^"']+)["']|url\(\s*["']?([^"')]+)["']?\s*\)|<loc>\s*([^<\s]+)\s*</loc>`)
+
+// StaticExport is the outcome of crawling a site into static files.
+type StaticExport struct {
+	Directory string
+	Files     int
+	Failed    []string // The paths that were linked to but couldn't be exported
+}
+
+// ExportStaticSite crawls the running site, starting from its home page and sitemap, and saves every page and file
+// it links to on the site's own domain in the output directory. Links to the site are rewritten to the base URL,
+// or made relative to the root of the domain if it is empty, so the export can be served from anywhere.
+func (s *Site) ExportStaticSite(outputDirectory, baseURL string, consoleOutput *console.Console) (StaticExport, error) {
+	export := StaticExport{Directory: outputDirectory, Failed: []string{}}
+
+	siteURL, err := url.Parse(s.settings.GetURL())
+	if err != nil {
+		return export, err
+	}
+
+	if baseURL != "" {
+		parsedBaseURL, err := url.Parse(baseURL)
+		if err != nil || (parsedBaseURL.Scheme != "http" && parsedBaseURL.Scheme != "https") || parsedBaseURL.Host == "" {
+			return export, fmt.Errorf("the base URL, %s, must be an http or https URL such as https://example.com", baseURL)
+		}
+	}
+
+	err = prepareStaticDirectory(outputDirectory)
+	if err != nil {
+		return export, err
+	}
+
+	client := &http.Client{
+		Timeout: staticFetchTimeout,
+		// Ignore SSL check as we're using our self-signed cert for development
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}, //nolint:gosec
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= staticMaxRedirects || req.URL.Host != siteURL.Host {
+				return http.ErrUseLastResponse
+			}
+
+			return nil
+		},
+	}
+
+	queue := []string{}
+	queued := map[string]bool{}
+	seeds := map[string]bool{}
+
+	enqueue := func(linkPath string) {
+		if !queued[linkPath] {
+			queued[linkPath] = true
+			queue = append(queue, linkPath)
+		}
+	}
+
+	for _, seedPath := range staticSeedPaths {
+		seeds[seedPath] = true
+		enqueue(seedPath)
+	}
+
+	consoleOutput.Println(fmt.Sprintf("Exporting %s to %s.", siteURL.String(), consoleOutput.Bold(outputDirectory)))
+
+	for len(queue) > 0 {
+		pagePath := queue[0]
+		queue = queue[1:]
+
+		pageURL := *siteURL
+		pageURL.Path = pagePath
+
+		links, saved, err := s.exportStaticFile(client, &pageURL, siteURL, outputDirectory, baseURL)
+		if err != nil {
+			return export, err
+		}
+
+		if !saved {
+			// The seed paths are only exported if the site has them
+			if !seeds[pagePath] {
+				export.Failed = append(export.Failed, pagePath)
+			}
+
+			continue
+		}
+
+		export.Files++
+
+		for _, link := range links {
+			enqueue(link)
+		}
+	}
+
+	return export, nil
+}
+
+// exportStaticFile saves a single page or file from the site, returning the paths on the site it links to.
+// Pages that don't return a 200 status aren't saved.
+func (s *Site) exportStaticFile(client *http.Client, pageURL, siteURL *url.URL, outputDirectory, baseURL string) ([]string, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), staticFetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL.String(), http.NoBody)
+	if err != nil {
+		return []string{}, false, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return []string{}, false, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return []string{}, false, nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	destination := filepath.Join(outputDirectory, filepath.FromSlash(getStaticFilePath(resp.Request.URL.Path, mediaType)))
+
+	dirPerms, filePerms := settings.GetDefaultFilePermissions()
+
+	err = os.MkdirAll(filepath.Dir(destination), os.FileMode(dirPerms))
+	if err != nil {
+		return []string{}, false, err
+	}
+
+	if !isStaticTextType(mediaType) {
+		file, err := os.OpenFile(destination, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(filePerms))
+		if err != nil {
+			return []string{}, false, err
+		}
+
+		defer file.Close()
+
+		_, err = io.Copy(file, resp.Body)
+
+		return []string{}, err == nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return []string{}, false, err
+	}
+
+	links := getStaticLinks(string(body), resp.Request.URL, siteURL)
+	content := rewriteSiteURLs(string(body), siteURL.Host, baseURL)
+
+	err = os.WriteFile(destination, []byte(content), os.FileMode(filePerms))
+
+	return links, err == nil, err
+}
+
+// StartStaticServer serves a static export from an Nginx container at the site's static domain, returning its URL.
+func (s *Site) StartStaticServer(directory string, consoleOutput *console.Console) (string, error) {
+	isExport, err := helpers.PathExists(filepath.Join(directory, staticExportMarker))
+	if err != nil {
+		return "", err
+	}
+
+	if !isExport {
+		return "", fmt.Errorf("%s is not a static export. Run 'kana static export' first", directory)
+	}
+
+	// Replace any server already running so it serves the given directory
+	_, err = s.dockerClient.ContainerStop(s.getStaticContainerName())
+	if err != nil {
+		return "", err
+	}
+
+	staticContainer := s.getStaticContainer(directory)
+
+	err = s.startContainer(context.Background(), &staticContainer, false, false, consoleOutput)
+	if err != nil {
+		return "", err
+	}
+
+	return s.getStaticURL(), nil
+}
+
+// StopStaticServer stops the container serving the site's static export.
+func (s *Site) StopStaticServer() error {
+	_, err := s.dockerClient.ContainerStop(s.getStaticContainerName())
+
+	return err
+}
+
+func (s *Site) getStaticContainer(directory string) docker.ContainerConfig {
+	name := s.settings.Get("name")
+	hostRule := fmt.Sprintf("Host(`%s`)", s.getStaticDomain())
+
+	return docker.ContainerConfig{
+		Name:        s.getStaticContainerName(),
+		Image:       staticServeImage,
+		NetworkName: "kana",
+		HostName:    s.getStaticContainerName(),
+		Labels: map[string]string{
+			"traefik.enable": "true",
+			"kana.type":      "static",
+			fmt.Sprintf("traefik.http.routers.wordpress-%s-%s-http.entrypoints", name, staticServeHostName): "web",
+			fmt.Sprintf("traefik.http.routers.wordpress-%s-%s-http.rule", name, staticServeHostName):        hostRule,
+			fmt.Sprintf("traefik.http.routers.wordpress-%s-%s.entrypoints", name, staticServeHostName):      "websecure",
+			fmt.Sprintf("traefik.http.routers.wordpress-%s-%s.rule", name, staticServeHostName):             hostRule,
+			fmt.Sprintf("traefik.http.routers.wordpress-%s-%s.tls", name, staticServeHostName):              "true",
+			"kana.site": name,
+		},
+		Volumes: []mount.Mount{
+			{
+				Type:     mount.TypeBind,
+				Source:   directory,
+				Target:   "/usr/share/nginx/html",
+				ReadOnly: true,
+			},
+		},
+	}
+}
+
+func (s *Site) getStaticContainerName() string {
+	return fmt.Sprintf("kana-%s-static", s.settings.Get("name"))
+}
+
+// getStaticDomain returns the domain a static export is served on, which is covered by the site's certificate.
+func (s *Site) getStaticDomain() string {
+	return fmt.Sprintf("%s-%s", staticServeHostName, s.settings.GetDomain())
+}
+
+func (s *Site) getStaticURL() string {
+	return fmt.Sprintf("%s://%s", s.settings.GetProtocol(), s.getStaticDomain())
+}
+
+// prepareStaticDirectory empties the output directory of a previous export so removed pages don't linger. Directories
+// with other files in them are never emptied in case the wrong directory was given.
+func prepareStaticDirectory(outputDirectory string) error {
+	isEmpty, err := helpers.IsEmpty(outputDirectory)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if err == nil && !isEmpty {
+		isExport, err := helpers.PathExists(filepath.Join(outputDirectory, staticExportMarker))
+		if err != nil {
+			return err
+		}
+
+		if !isExport {
+			return fmt.Errorf("%s already has files that aren't from a static export in it. Choose an empty directory", outputDirectory)
+		}
+
+		err = os.RemoveAll(outputDirectory)
+		if err != nil {
+			return err
+		}
+	}
+
+	dirPerms, filePerms := settings.GetDefaultFilePermissions()
+
+	err = os.MkdirAll(outputDirectory, os.FileMode(dirPerms))
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(outputDirectory, staticExportMarker), []byte{}, os.FileMode(filePerms))
+}
+
+// getStaticLinks returns the paths on the site that the content links to, without query strings or fragments.
+func getStaticLinks(content string, pageURL, siteURL *url.URL) []string {
+	links := []string{}
+
+	for _, match := range staticLinkPattern.FindAllStringSubmatch(content, -1) {
+		rawLinks := []string{match[1] + match[2] + match[3]}
+
+		// srcset holds a list of images, each followed by its width or density
+		if strings.Contains(rawLinks[0], ",") && strings.HasPrefix(strings.ToLower(strings.TrimSpace(match[0])), "srcset") {
+			rawLinks = strings.Split(rawLinks[0], ",")
+		}
+
+		for _, rawLink := range rawLinks {
+			fields := strings.Fields(strings.ReplaceAll(rawLink, "&amp;", "&"))
+			if len(fields) == 0 {
+				continue
+			}
+
+			link, err := pageURL.Parse(fields[0])
+			if err != nil || link.Host != siteURL.Host || (link.Scheme != "http" && link.Scheme != "https") {
+				continue
+			}
+
+			linkPath := path.Clean("/" + link.Path)
+
+			if strings.HasSuffix(link.Path, "/") && linkPath != "/" {
+				linkPath += "/"
+			}
+
+			if !isStaticExcludedPath(linkPath) {
+				links = append(links, linkPath)
+			}
+		}
+	}
+
+	return links
+}
+
+// getStaticFilePath returns the path, within the export, a page or file is saved to. Pages are saved as index.html
+// files so their pretty permalinks work on any static host.
+func getStaticFilePath(urlPath, mediaType string) string {
+	filePath := path.Clean("/" + urlPath)
+
+	if strings.HasSuffix(urlPath, "/") || filePath == "/" || (mediaType == "text/html" && path.Ext(filePath) == "") {
+		return path.Join(filePath, "index.html")
+	}
+
+	return filePath
+}
+
+// rewriteSiteURLs replaces the site's URLs in the content, including the escaped URLs in inline JSON, with the base URL.
+func rewriteSiteURLs(content, host, baseURL string) string {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	escapedBaseURL := strings.ReplaceAll(baseURL, "/", `\/`)
+
+	return strings.NewReplacer(
+		"https://"+host, baseURL,
+		"http://"+host, baseURL,
+		`https:\/\/`+host, escapedBaseURL,
+		`http:\/\/`+host, escapedBaseURL,
+		"//"+host, baseURL,
+	).Replace(content)
+}
+
+func isStaticTextType(mediaType string) bool {
+	return strings.HasPrefix(mediaType, "text/") ||
+		strings.HasSuffix(mediaType, "xml") ||
+		strings.HasSuffix(mediaType, "json") ||
+		strings.HasSuffix(mediaType, "javascript")
+}
+
+func isStaticExcludedPath(linkPath string) bool {
+	for _, excluded := range staticExcludedPaths {
+		if linkPath == excluded || strings.HasPrefix(linkPath, excluded+"/") {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,61 @@
+package site
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ServiceStatus is whether one of the site's services is running, stopped or not used by the site at all.
+type ServiceStatus struct {
+	Service   string `json:"service"`
+	Container string `json:"container"`
+	Status    string `json:"status"` // "running", "stopped" or "not used"
+}
+
+// siteServices are the services a site can run, in the order they're shown.
+var siteServices = []string{"wordpress", "database", "phpmyadmin", "mailpit", "redis", "updates", "cli", "static"}
+
+// GetStatus returns the status of each of the site's services. SQLite sites don't use the database or phpMyAdmin.
+func (s *Site) GetStatus() ([]ServiceStatus, error) {
+	statuses := []ServiceStatus{}
+
+	containers, err := s.dockerClient.ContainerList(s.settings.Get("name"))
+	if err != nil {
+		return statuses, err
+	}
+
+	running := []string{}
+
+	for i := range containers {
+		if containers[i].State != "running" {
+			continue
+		}
+
+		for _, name := range containers[i].Names {
+			running = append(running, strings.Trim(name, "/"))
+		}
+	}
+
+	wordPressContainers := s.getWordPressContainers()
+
+	for _, service := range siteServices {
+		container := fmt.Sprintf("kana-%s-%s", s.settings.Get("name"), service)
+		status := "stopped"
+
+		switch {
+		case slices.Contains(running, container):
+			status = "running"
+		case !slices.Contains(wordPressContainers, container):
+			status = "not used"
+		}
+
+		statuses = append(statuses, ServiceStatus{
+			Service:   service,
+			Container: container,
+			Status:    status,
+		})
+	}
+
+	return statuses, nil
+}
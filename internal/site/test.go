@@ -0,0 +1,229 @@
+package site
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/helpers"
+)
+
+// TestResult is the outcome of running the testCommand setting against one version of WordPress.
+type TestResult struct {
+	WordPressVersion string
+	Passed           bool
+	Duration         time.Duration
+	CoverageReport   string // The folder the coverage reports were written to, if coverage was collected
+	Error            error  // Set when the test site for the version couldn't be created
+}
+
+var wordPressVersionPattern = regexp.MustCompile(`^(\d+\.\d+(\.\d+)?|latest|nightly)$`)
+
+// CoverageDrivers are the PHP extensions kana test can collect code coverage with.
+var CoverageDrivers = []string{"pcov", "xdebug"}
+
+// coverageDirectory is where coverage reports are written in the project, in a folder for each version of WordPress.
+const coverageDirectory = "coverage"
+
+// TestWordPressVersions runs the testCommand setting against each version of WordPress in turn. Each version gets its own
+// throwaway site, sharing the project's mount and Docker images, which is removed once its tests have run.
+// If a coverage driver is given it is installed in each site and PHPUnit's coverage reports are written to the project.
+func (s *Site) TestWordPressVersions(versions []string, coverageDriver string, consoleOutput *console.Console) ([]TestResult, error) {
+	results := []TestResult{}
+
+	if s.settings.Get("testCommand") == "" {
+		return results, fmt.Errorf(
+			"no test command has been set. Set the testCommand setting to the command that runs your tests, " +
+				"such as `kana config testCommand vendor/bin/phpunit`")
+	}
+
+	// A site's own WordPress files can't be swapped for another version without changing the site
+	if s.settings.Get("type") != "plugin" && s.settings.Get("type") != "theme" {
+		return results, fmt.Errorf("the test command only works on plugins and themes")
+	}
+
+	for _, version := range versions {
+		if !wordPressVersionPattern.MatchString(version) {
+			return results, fmt.Errorf("%s is not a valid WordPress version. Use a version number, such as 6.4, latest or nightly", version)
+		}
+	}
+
+	if coverageDriver != "" && !slices.Contains(CoverageDrivers, coverageDriver) {
+		return results, fmt.Errorf("%s is not a valid coverage driver. Use %s", coverageDriver, strings.Join(CoverageDrivers, " or "))
+	}
+
+	for _, version := range versions {
+		consoleOutput.Println(fmt.Sprintf("Testing against WordPress %s.", consoleOutput.Bold(version)))
+
+		start := time.Now()
+		passed, err := s.testWordPressVersion(version, coverageDriver, consoleOutput)
+
+		result := TestResult{
+			WordPressVersion: version,
+			Passed:           passed,
+			Duration:         time.Since(start),
+			Error:            err,
+		}
+
+		if coverageDriver != "" && err == nil {
+			result.CoverageReport = filepath.Join(s.settings.Get("workingDirectory"), getCoverageReportPath(version))
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// testWordPressVersion creates a test site running the given version of WordPress, runs the test command in it
+// and removes the site again, returning whether the tests passed.
+func (s *Site) testWordPressVersion(version, coverageDriver string, consoleOutput *console.Console) (bool, error) {
+	testSite, err := s.newTestSite(version)
+	if err != nil {
+		return false, err
+	}
+
+	defer func() {
+		removeErr := testSite.removeTestSite()
+		if removeErr != nil {
+			consoleOutput.Warn(fmt.Sprintf("The test site %s could not be removed: %s", testSite.settings.Get("name"), removeErr))
+		}
+	}()
+
+	err = testSite.startSite(context.Background(), consoleOutput)
+	if err != nil {
+		return false, err
+	}
+
+	updateCommand := []string{"core", "update", "--force"}
+
+	if version != "latest" {
+		updateCommand = append(updateCommand, fmt.Sprintf("--version=%s", version))
+	}
+
+	consoleOutput.Println(fmt.Sprintf("Installing WordPress %s.", version))
+
+	err = testSite.wpCliStreamOrError(updateCommand, consoleOutput)
+	if err != nil {
+		return false, err
+	}
+
+	err = testSite.wpCliOrError([]string{"core", "update-db"}, consoleOutput)
+	if err != nil {
+		return false, err
+	}
+
+	projects, err := testSite.getProjects()
+	if err != nil {
+		return false, err
+	}
+
+	testEnv, err := testSite.installTestSuite(consoleOutput)
+	if err != nil {
+		return false, err
+	}
+
+	projectDirectory := fmt.Sprintf("/var/www/html/wp-content/%ss/%s", projects[0].Type, projects[0].Name)
+	testCommand := s.settings.Get("testCommand")
+
+	if coverageDriver == "" {
+		code, err := testSite.runTestCommand(fmt.Sprintf("cd %s && %s", projectDirectory, testCommand), testEnv, consoleOutput)
+		if err != nil {
+			return false, err
+		}
+
+		return code == 0, nil
+	}
+
+	// Coverage drivers are installed in the WordPress container, rather than a CLI container, so the tests run there
+	testCommand, err = testSite.enableCoverage(testCommand, version, coverageDriver, consoleOutput)
+	if err != nil {
+		return false, err
+	}
+
+	if len(testEnv) > 0 {
+		testCommand = fmt.Sprintf("export %s && %s", strings.Join(testEnv, " "), testCommand)
+	}
+
+	code, err := testSite.Exec(
+		"wordpress",
+		[]string{"sh", "-c", fmt.Sprintf("cd %s && %s", projectDirectory, testCommand)},
+		false)
+	if err != nil {
+		return false, err
+	}
+
+	return code == 0, nil
+}
+
+// newTestSite returns a copy of the site, under its own name and with its own WordPress installation, for testing
+// the project against the given version of WordPress.
+func (s *Site) newTestSite(version string) (*Site, error) {
+	name := helpers.SanitizeSiteName(fmt.Sprintf("%s-test-wp%s", s.settings.Get("name"), strings.ReplaceAll(version, ".", "-")))
+
+	testSite, err := s.newNamedSite(name)
+	if err != nil {
+		return nil, err
+	}
+
+	testSite.projectName = s.settings.Get("name")
+
+	return testSite, nil
+}
+
+// removeTestSite stops a test site and deletes its files.
+func (s *Site) removeTestSite() error {
+	err := s.StopSite()
+	if err != nil {
+		return err
+	}
+
+	return os.RemoveAll(s.settings.Get("siteDirectory"))
+}
+
+// enableCoverage installs the coverage driver in the test site's WordPress container and returns the test command
+// with the PHPUnit options needed to write clover and HTML coverage reports to the project.
+func (s *Site) enableCoverage(testCommand, version, coverageDriver string, consoleOutput *console.Console) (string, error) {
+	consoleOutput.Println(fmt.Sprintf("Installing %s to collect code coverage.", coverageDriver))
+
+	reportPath := getCoverageReportPath(version)
+	coverageOptions := fmt.Sprintf("--coverage-clover %[1]s/clover.xml --coverage-html %[1]s/html", reportPath)
+
+	if coverageDriver == "xdebug" {
+		err := s.StartXdebug(consoleOutput)
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("XDEBUG_MODE=coverage %s %s", testCommand, coverageOptions), nil
+	}
+
+	commands := []string{
+		"pecl list | grep pcov || pecl install pcov",
+		"docker-php-ext-enable pcov",
+	}
+
+	for _, command := range commands {
+		output, err := s.WordPress(command, false, true)
+		if err != nil {
+			return "", err
+		}
+
+		if output.ExitCode != 0 {
+			return "", fmt.Errorf("unable to install pcov: %s", strings.TrimSpace(output.StdErr))
+		}
+	}
+
+	return fmt.Sprintf("%s %s", testCommand, coverageOptions), nil
+}
+
+// getCoverageReportPath returns the folder, relative to the project, that coverage reports for the version of WordPress are written to.
+func getCoverageReportPath(version string) string {
+	return fmt.Sprintf("%s/wordpress-%s", coverageDirectory, version)
+}
@@ -0,0 +1,188 @@
+package site
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/helpers"
+	"github.com/ChrisWiegman/kana/internal/settings"
+)
+
+const (
+	testSuiteDirectory = "wordpress-tests-lib" // In the site's folder, which is mounted at /Site in its containers
+	testDatabaseName   = "wordpress_test"
+	testSuiteURL       = "https://codeload.github.com/wp-phpunit/wp-phpunit/zip/refs"
+)
+
+// installTestSuite installs the WordPress test suite for the site's version of WordPress, along with a
+// wp-tests-config.php file pointing it at a database of its own as the test suite empties its database on each run.
+// The environment variables PHPUnit bootstrap files, such as those from `wp scaffold plugin-tests`, use to find the test
+// suite are returned. Sites using SQLite or PostgreSQL can't run the test suite so nothing is installed for them.
+func (s *Site) installTestSuite(consoleOutput *console.Console) ([]string, error) {
+	database := s.settings.Get("database")
+
+	if database != "mariadb" && database != "mysql" {
+		consoleOutput.Warn(fmt.Sprintf("The WordPress test suite needs MariaDB or MySQL so it hasn't been installed in this %s site.", database))
+
+		return []string{}, nil
+	}
+
+	code, version, err := s.WPCli([]string{"core", "version"}, false, consoleOutput)
+	if err != nil {
+		return nil, err
+	}
+
+	if code != 0 {
+		return nil, fmt.Errorf("unable to read the site's version of WordPress: %s", strings.TrimSpace(version))
+	}
+
+	version = strings.TrimSpace(version)
+
+	consoleOutput.Println(fmt.Sprintf("Installing the WordPress test suite for WordPress %s.", version))
+
+	err = s.downloadTestSuite(version)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.createTestDatabase()
+	if err != nil {
+		return nil, err
+	}
+
+	wordPressPath, err := s.getWordPressPath()
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := settings.GetWPTestsConfig(settings.WPTestsConfig{
+		WordPressPath: wordPressPath,
+		DatabaseHost:  fmt.Sprintf("kana-%s-database", s.settings.Get("name")),
+		DatabaseName:  testDatabaseName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, filePermissions := settings.GetDefaultFilePermissions()
+
+	// The test suite's bootstrap looks for wp-tests-config.php in its own root folder
+	err = os.WriteFile(
+		filepath.Join(s.settings.Get("siteDirectory"), testSuiteDirectory, "wp-tests-config.php"),
+		[]byte(config),
+		os.FileMode(filePermissions))
+	if err != nil {
+		return nil, err
+	}
+
+	testsDirectory := path.Join("/Site", testSuiteDirectory)
+
+	return []string{
+		fmt.Sprintf("WP_TESTS_DIR=%s", testsDirectory),
+		fmt.Sprintf("WP_PHPUNIT__TESTS_CONFIG=%s/wp-tests-config.php", testsDirectory),
+	}, nil
+}
+
+// downloadTestSuite downloads the test suite's includes and data, as packaged by wp-phpunit, for the version of
+// WordPress to the site's folder.
+func (s *Site) downloadTestSuite(wordPressVersion string) error {
+	siteDirectory := s.settings.Get("siteDirectory")
+	downloadDirectory := filepath.Join(siteDirectory, testSuiteDirectory+".download")
+
+	defer os.RemoveAll(downloadDirectory)
+
+	err := os.MkdirAll(downloadDirectory, os.FileMode(defaultDirPermissions))
+	if err != nil {
+		return err
+	}
+
+	archive, err := helpers.DownloadFile(getTestSuiteURL(wordPressVersion), downloadDirectory)
+	if err != nil {
+		return err
+	}
+
+	err = helpers.UnZipFile(filepath.Join(downloadDirectory, archive), downloadDirectory)
+	if err != nil {
+		return fmt.Errorf("the WordPress test suite for WordPress %s couldn't be downloaded: %w", wordPressVersion, err)
+	}
+
+	err = os.RemoveAll(filepath.Join(siteDirectory, testSuiteDirectory))
+	if err != nil {
+		return err
+	}
+
+	// The archive holds a single folder named after the version
+	entries, err := os.ReadDir(downloadDirectory)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			return os.Rename(filepath.Join(downloadDirectory, entry.Name()), filepath.Join(siteDirectory, testSuiteDirectory))
+		}
+	}
+
+	return fmt.Errorf("the WordPress test suite for WordPress %s couldn't be found in its download", wordPressVersion)
+}
+
+// createTestDatabase creates the test suite's database on the site's database server, giving the site's database user
+// access to it.
+func (s *Site) createTestDatabase() error {
+	client := "mariadb"
+
+	if s.settings.Get("database") == "mysql" {
+		client = "mysql"
+	}
+
+	query := fmt.Sprintf("CREATE DATABASE IF NOT EXISTS %[1]s; GRANT ALL PRIVILEGES ON %[1]s.* TO 'wordpress'@'%%';", testDatabaseName)
+
+	output, err := s.dockerClient.ContainerExec(
+		fmt.Sprintf("kana-%s-database", s.settings.Get("name")),
+		false,
+		[]string{fmt.Sprintf("%s --user=root --password=password --execute=\"%s\"", client, query)})
+	if err != nil {
+		return err
+	}
+
+	if output.ExitCode != 0 {
+		return fmt.Errorf("unable to create the test database: %s", getDatabaseErrors(output.StdErr))
+	}
+
+	return nil
+}
+
+// runTestCommand runs the test command in a CLI container, with the site's files and database, showing its output as it
+// runs and returning its exit code.
+func (s *Site) runTestCommand(command string, env []string, consoleOutput *console.Console) (int64, error) {
+	container, err := s.getCliContainer(consoleOutput)
+	if err != nil {
+		return 1, err
+	}
+
+	container.Env = append(container.Env, env...)
+	container.Command = []string{"sh", "-c", command}
+
+	code, _, err := s.dockerClient.ContainerRunAndClean(&container, false, os.Stdout)
+
+	return code, err
+}
+
+// getTestSuiteURL returns the download of wp-phpunit's test suite for the version of WordPress. Development versions,
+// such as nightly builds, use the latest test suite.
+func getTestSuiteURL(wordPressVersion string) string {
+	if strings.Contains(wordPressVersion, "-") {
+		return fmt.Sprintf("%s/heads/master", testSuiteURL)
+	}
+
+	// WordPress leaves the patch version off its first release of each version but wp-phpunit doesn't
+	if strings.Count(wordPressVersion, ".") == 1 {
+		wordPressVersion += ".0"
+	}
+
+	return fmt.Sprintf("%s/tags/%s", testSuiteURL, wordPressVersion)
+}
@@ -1,7 +1,10 @@
 package site
 
 import (
+	"context"
+	"fmt"
 	"path/filepath"
+	"strings"
 
 	"github.com/ChrisWiegman/kana/internal/console"
 	"github.com/ChrisWiegman/kana/internal/docker"
@@ -13,9 +16,12 @@ import (
 const (
 	traefikContainerName = "kana-traefik"
 	traefikVersion       = "3.1"
+
+	traefikDynamicConfigDirectory = "/etc/traefik/dynamic"
 )
 
-// maybeStopTraefik Checks to see if other sites are running and shuts down the traefik instance if none are.
+// maybeStopTraefik Checks to see if other sites are running and shuts down the traefik instance, and the other
+// containers shared by sites, if none are.
 func (s *Site) maybeStopTraefik() error {
 	containers, err := s.dockerClient.ContainerList("")
 	if err != nil {
@@ -23,25 +29,43 @@ func (s *Site) maybeStopTraefik() error {
 	}
 
 	if len(containers) == 0 {
+		err = s.stopWordPressAPI()
+		if err != nil {
+			return err
+		}
+
 		return s.stopTraefik()
 	}
 
 	return nil
 }
 
+// StopUnusedSharedContainers stops Traefik and the other containers shared by every site if no sites are left running.
+// Sites stopped at the same time, such as by `kana stop --all`, can each see the others still running and leave them.
+func (s *Site) StopUnusedSharedContainers() error {
+	return s.maybeStopTraefik()
+}
+
 // startTraefik Starts the Traefik container.
-func (s *Site) startTraefik(consoleOutput *console.Console) error {
-	err := settings.EnsureSSLCerts(s.settings.Get("appDirectory"), s.settings.GetBool("SSL"), consoleOutput)
+func (s *Site) startTraefik(ctx context.Context, consoleOutput *console.Console) error {
+	// The certificates are always needed to verify the site but, in CI mode, never need to be trusted
+	err := settings.EnsureSSLCerts(s.settings.Get("appDirectory"), s.settings.GetBool("SSL") && !s.settings.GetBool("isCI"), consoleOutput)
 	if err != nil {
 		return err
 	}
 
+	// Sites in CI mode are published on their own port rather than routed through Traefik
+	if s.settings.GetBool("isCI") {
+		return nil
+	}
+
 	_, _, err = s.dockerClient.EnsureNetwork("kana")
 	if err != nil {
 		return err
 	}
 
 	err = s.dockerClient.EnsureImage(
+		ctx,
 		"traefik:"+traefikVersion,
 		s.settings.Get("appDirectory"),
 		s.settings.GetInt("updateInterval"),
@@ -50,10 +74,26 @@ func (s *Site) startTraefik(consoleOutput *console.Console) error {
 		return err
 	}
 
-	traefikPorts := []docker.ExposedPorts{
-		{Port: "80", Protocol: "tcp"},
-		{Port: "443", Protocol: "tcp"},
-		{Port: "8080", Protocol: "tcp"},
+	// Traefik from older versions of Kana reads a single dynamic config file so it needs replacing to pick up site routes
+	if s.isTraefikOutdated() {
+		_, err = s.dockerClient.ContainerStop(traefikContainerName)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Traefik is shared by every site so, if it's already running, its ports are already published
+	if len(s.dockerClient.ContainerGetMounts(traefikContainerName)) > 0 {
+		return nil
+	}
+
+	traefikPorts, warning, err := s.getTraefikPorts()
+	if err != nil {
+		return err
+	}
+
+	if warning != "" {
+		consoleOutput.Warn(warning)
 	}
 
 	traefikConfig := docker.ContainerConfig{
@@ -65,6 +105,8 @@ func (s *Site) startTraefik(consoleOutput *console.Console) error {
 		Labels: map[string]string{
 			"kana.global": "true",
 		},
+		// Lets the routes setting send requests to apps running on the host on Linux as well as Docker Desktop
+		ExtraHosts: []string{"host.docker.internal:host-gateway"},
 		Volumes: []mount.Mount{
 			{
 				Type:   mount.TypeBind,
@@ -73,8 +115,8 @@ func (s *Site) startTraefik(consoleOutput *console.Console) error {
 			},
 			{
 				Type:   mount.TypeBind,
-				Source: filepath.Join(s.settings.Get("appDirectory"), "config", "traefik", "dynamic.toml"),
-				Target: "/etc/traefik/dynamic.toml",
+				Source: filepath.Join(s.settings.Get("appDirectory"), "config", "traefik", "dynamic"),
+				Target: traefikDynamicConfigDirectory,
 			},
 			{
 				Type:   mount.TypeBind,
@@ -89,11 +131,24 @@ func (s *Site) startTraefik(consoleOutput *console.Console) error {
 		},
 	}
 
-	_, err = s.dockerClient.ContainerRun(&traefikConfig, false, false)
+	_, err = s.dockerClient.ContainerRun(ctx, &traefikConfig, false, false)
 
 	return err
 }
 
+// isTraefikOutdated returns true if Traefik is running without the dynamic config directory mounted.
+func (s *Site) isTraefikOutdated() bool {
+	mounts := s.dockerClient.ContainerGetMounts(traefikContainerName)
+
+	for _, traefikMount := range mounts {
+		if traefikMount.Destination == traefikDynamicConfigDirectory {
+			return false
+		}
+	}
+
+	return len(mounts) > 0
+}
+
 // stopTraefik Stops the Traefik container.
 func (s *Site) stopTraefik() error {
 	_, err := s.dockerClient.ContainerStop(traefikContainerName)
@@ -106,3 +161,85 @@ func (s *Site) stopTraefik() error {
 
 	return err
 }
+
+// getMiddlewareLabels returns the Traefik labels that define the site's headers and middlewares settings and
+// apply them to the given routers. Middlewares are named after the site so they can't clash with another site's.
+func (s *Site) getMiddlewareLabels(routers []string) (map[string]string, error) {
+	labels := map[string]string{}
+	middlewareNames := []string{}
+
+	headers, err := settings.ParseHeaders(s.settings.GetSlice("headers"))
+	if err != nil {
+		return labels, err
+	}
+
+	headerOptions := map[string]string{}
+
+	for _, header := range headers {
+		headerOptions["customresponseheaders."+header.Name] = header.Value
+	}
+
+	for option, value := range s.getCORSOptions() {
+		headerOptions[option] = value
+	}
+
+	if len(headerOptions) > 0 {
+		name := fmt.Sprintf("kana-%s-headers", s.settings.Get("name"))
+		middlewareNames = append(middlewareNames, name)
+
+		for option, value := range headerOptions {
+			labels[fmt.Sprintf("traefik.http.middlewares.%s.headers.%s", name, option)] = value
+		}
+	}
+
+	middlewares, err := settings.ParseMiddlewares(s.settings.GetSlice("middlewares"))
+	if err != nil {
+		return labels, err
+	}
+
+	for _, middleware := range middlewares {
+		name := fmt.Sprintf("kana-%s-middleware-%s", s.settings.Get("name"), middleware.Name)
+		middlewareNames = append(middlewareNames, name)
+
+		for option, value := range middleware.Options {
+			labels[fmt.Sprintf("traefik.http.middlewares.%s.%s", name, option)] = value
+		}
+	}
+
+	if len(middlewareNames) == 0 {
+		return labels, nil
+	}
+
+	for _, router := range routers {
+		labels[fmt.Sprintf("traefik.http.routers.%s.middlewares", router)] = strings.Join(middlewareNames, ",")
+	}
+
+	return labels, nil
+}
+
+// getCORSOptions returns the options for Traefik's headers middleware that answer cross-origin requests, including
+// preflight requests, from the origins in the corsOrigins setting. It returns no options if no origins are set.
+func (s *Site) getCORSOptions() map[string]string {
+	origins := []string{}
+
+	for _, origin := range s.settings.GetSlice("corsOrigins") {
+		origin = strings.TrimSuffix(strings.TrimSpace(origin), "/")
+
+		if origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+
+	if len(origins) == 0 {
+		return map[string]string{}
+	}
+
+	return map[string]string{
+		"accesscontrolalloworiginlist":  strings.Join(origins, ","),
+		"accesscontrolallowmethods":     "GET,POST,PUT,PATCH,DELETE,OPTIONS",
+		"accesscontrolallowheaders":     strings.Join(s.settings.GetSlice("corsHeaders"), ","),
+		"accesscontrolallowcredentials": fmt.Sprintf("%t", s.settings.GetBool("corsCredentials")),
+		"accesscontrolmaxage":           "600",
+		"addvaryheader":                 "true",
+	}
+}
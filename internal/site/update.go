@@ -0,0 +1,190 @@
+package site
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+)
+
+// UpdateReport is the outcome of updating WordPress, its plugins, themes and translations.
+type UpdateReport struct {
+	Snapshot string // The export archive taken before updating, which can be restored with kana import
+	Items    []UpdateItem
+	Failed   []string // The update steps that failed, along with wp-cli's output
+}
+
+// UpdateItem is the version of WordPress, a plugin or a theme before and after updating.
+type UpdateItem struct {
+	Type   string
+	Name   string
+	Before string
+	After  string
+}
+
+// snapshotParts are the parts of the site saved before updating or rolling back. Uploads aren't changed by either.
+var snapshotParts = []string{"db", "plugins", "themes"}
+
+// Updated returns true if the item's version changed.
+func (u UpdateItem) Updated() bool {
+	return u.Before != u.After
+}
+
+// UpdateSite updates WordPress core, plugins, themes and translations, as a production maintenance window would, and
+// reports the versions before and after. Unless skipped, a snapshot of the database, plugins and themes is taken first.
+// Plugins and themes being developed in the site are never updated.
+func (s *Site) UpdateSite(takeSnapshot bool, version string, consoleOutput *console.Console) (UpdateReport, error) {
+	report := UpdateReport{Items: []UpdateItem{}, Failed: []string{}}
+
+	before, err := s.getInstalledVersions(consoleOutput)
+	if err != nil {
+		return report, err
+	}
+
+	if takeSnapshot {
+		consoleOutput.Println("Taking a snapshot of the database, plugins and themes.")
+
+		report.Snapshot, err = s.createSnapshot("update", version, consoleOutput)
+		if err != nil {
+			return report, err
+		}
+	}
+
+	updateSteps, err := s.getUpdateSteps()
+	if err != nil {
+		return report, err
+	}
+
+	for _, step := range updateSteps {
+		consoleOutput.Println(fmt.Sprintf("Running wp %s.", strings.Join(step, " ")))
+
+		code, output, err := s.wpCliStream(step, consoleOutput)
+		if err != nil {
+			return report, err
+		}
+
+		// Keep going so one failed plugin doesn't stop everything else from being updated, as in production
+		if code != 0 {
+			// The step's output has already been shown unless using JSON output
+			failure := fmt.Sprintf("wp %s exited with status %d", strings.Join(step, " "), code)
+
+			if consoleOutput.JSON {
+				failure = fmt.Sprintf("wp %s: %s", strings.Join(step, " "), strings.TrimSpace(output))
+			}
+
+			report.Failed = append(report.Failed, failure)
+		}
+	}
+
+	after, err := s.getInstalledVersions(consoleOutput)
+	if err != nil {
+		return report, err
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(after)) {
+		item := after[key]
+
+		if beforeItem, ok := before[key]; ok {
+			item.Before = beforeItem.After
+		}
+
+		report.Items = append(report.Items, item)
+
+		if item.Updated() && item.Before != "" {
+			s.recordHistory(fmt.Sprintf("%s updated", item.Type), fmt.Sprintf("%s %s to %s", item.Name, item.Before, item.After))
+		}
+	}
+
+	// Include anything that was removed by the update, such as a bundled theme
+	for _, key := range slices.Sorted(maps.Keys(before)) {
+		if _, ok := after[key]; !ok {
+			report.Items = append(report.Items, UpdateItem{Type: before[key].Type, Name: before[key].Name, Before: before[key].After})
+		}
+	}
+
+	return report, nil
+}
+
+// getUpdateSteps returns the wp-cli commands that update the site, in the order a maintenance window would run them.
+func (s *Site) getUpdateSteps() ([][]string, error) {
+	steps := [][]string{
+		{"core", "update"},
+		{"core", "update-db"},
+	}
+
+	for _, extensionType := range extensionTypes {
+		step := []string{extensionType, "update", "--all"}
+
+		mountedProjects, err := s.getMountedProjects(extensionType)
+		if err != nil {
+			return steps, err
+		}
+
+		if len(mountedProjects) > 0 {
+			step = append(step, fmt.Sprintf("--exclude=%s", strings.Join(slices.Sorted(maps.Keys(mountedProjects)), ",")))
+		}
+
+		steps = append(steps, step)
+	}
+
+	return append(steps,
+		[]string{"language", "core", "update"},
+		[]string{"language", "plugin", "update", "--all"},
+		[]string{"language", "theme", "update", "--all"}), nil
+}
+
+// getInstalledVersions returns the installed versions of WordPress, its plugins and its themes, keyed by type and name.
+// The versions are returned as the After field of each item.
+func (s *Site) getInstalledVersions(consoleOutput *console.Console) (map[string]UpdateItem, error) {
+	versions := map[string]UpdateItem{}
+
+	coreVersion, err := s.getWordPressVersion(consoleOutput)
+	if err != nil {
+		return versions, err
+	}
+
+	versions["core"] = UpdateItem{Type: "core", Name: "WordPress", After: coreVersion}
+
+	for _, extensionType := range extensionTypes {
+		extensions, err := s.GetExtensions(extensionType, consoleOutput)
+		if err != nil {
+			return versions, err
+		}
+
+		for _, extension := range extensions {
+			versions[fmt.Sprintf("%s:%s", extensionType, extension.Name)] = UpdateItem{
+				Type:  extensionType,
+				Name:  extension.Name,
+				After: extension.Version,
+			}
+		}
+	}
+
+	return versions, nil
+}
+
+// createSnapshot exports the database, plugins and themes to the site's backup directory before the given change. The
+// snapshot is named so it isn't mistaken for, or pruned with, the site's regular database backups.
+func (s *Site) createSnapshot(change, version string, consoleOutput *console.Console) (string, error) {
+	backupDirectory, err := s.getBackupDirectory()
+	if err != nil {
+		return "", err
+	}
+
+	snapshotFile := filepath.Join(
+		backupDirectory,
+		fmt.Sprintf("kana-%s-pre-%s-%s.zip", s.settings.Get("name"), change, time.Now().Format(backupTimeFormat)))
+
+	snapshotFile, err = s.ExportSite(snapshotParts, []string{snapshotFile}, version, consoleOutput)
+	if err != nil {
+		return snapshotFile, err
+	}
+
+	s.recordHistory("snapshot taken", filepath.Base(snapshotFile))
+
+	return snapshotFile, nil
+}
@@ -0,0 +1,257 @@
+package site
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/docker"
+	"github.com/ChrisWiegman/kana/internal/helpers"
+	"github.com/ChrisWiegman/kana/internal/settings"
+
+	"github.com/docker/docker/api/types/mount"
+)
+
+// updateServerIndex lists the newest release of each plugin published to the mock update server.
+const updateServerIndex = "index.json"
+
+// versionHeader matches the Version header of a plugin's main file.
+var versionHeader = regexp.MustCompile(`(?m)^[ \t/*#@]*Version:[ \t]*(\S+)`)
+
+// packageSkip are the files and folders of a plugin that aren't included in the packages it's published as.
+var packageSkip = []string{".git", "node_modules", ".kana.json", ".kana.local.json"}
+
+// UpdateRelease is a version of a plugin published to the site's mock update server.
+type UpdateRelease struct {
+	Slug    string `json:"slug"`
+	Version string `json:"version"`
+	Package string `json:"package"`
+}
+
+func (s *Site) getUpdateServerContainerName() string {
+	return fmt.Sprintf("kana-%s-updates", s.settings.Get("name"))
+}
+
+// getUpdateServerURL returns the URL of the mock update server on Kana's network, where WordPress and wp-cli reach it.
+func (s *Site) getUpdateServerURL() string {
+	return fmt.Sprintf("http://%s", s.getUpdateServerContainerName())
+}
+
+// getUpdatesDirectory returns the directory of the packages served by the mock update server, creating it if needed.
+func (s *Site) getUpdatesDirectory() (string, error) {
+	updatesDirectory := filepath.Join(s.settings.Get("siteDirectory"), "updates")
+
+	err := os.MkdirAll(updatesDirectory, os.FileMode(defaultDirPermissions))
+	if err != nil {
+		return "", err
+	}
+
+	return updatesDirectory, nil
+}
+
+func (s *Site) getUpdateServerContainer(directory string) docker.ContainerConfig {
+	return docker.ContainerConfig{
+		Name:        s.getUpdateServerContainerName(),
+		Image:       staticServeImage,
+		NetworkName: "kana",
+		HostName:    s.getUpdateServerContainerName(),
+		Labels: map[string]string{
+			"kana.type": "updates",
+			"kana.site": s.settings.Get("name"),
+		},
+		Volumes: []mount.Mount{
+			{
+				Type:     mount.TypeBind,
+				Source:   directory,
+				Target:   "/usr/share/nginx/html",
+				ReadOnly: true,
+			},
+		},
+	}
+}
+
+func (s *Site) isUpdateServerRunning() bool {
+	containers, err := s.dockerClient.ContainerList(s.settings.Get("name"))
+	if err != nil {
+		return false
+	}
+
+	for i := range containers {
+		if containers[i].Labels["kana.type"] == "updates" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// startUpdateServer starts the container serving the plugin releases published to the site's mock update server.
+func (s *Site) startUpdateServer(ctx context.Context, consoleOutput *console.Console) error {
+	updatesDirectory, err := s.getUpdatesDirectory()
+	if err != nil {
+		return err
+	}
+
+	updateServerContainer := s.getUpdateServerContainer(updatesDirectory)
+
+	return s.startContainer(ctx, &updateServerContainer, false, false, consoleOutput)
+}
+
+// PublishUpdate packages the plugin in the directory as the given version, or the next patch version if none is given,
+// and publishes it to the site's mock update server so WordPress offers it as an update. If install is true the
+// release is also installed on the site, such as to install the version to update from.
+func (s *Site) PublishUpdate(directory, version string, install bool, consoleOutput *console.Console) (UpdateRelease, error) {
+	release := UpdateRelease{
+		Slug: filepath.Base(directory),
+	}
+
+	if !s.isUpdateServerRunning() {
+		return release, fmt.Errorf(
+			"the site doesn't have a mock update server. Restart it with 'kana start --updateServer' to add one")
+	}
+
+	mainFile, err := settings.GetMainPluginFile(directory)
+	if err != nil {
+		return release, err
+	}
+
+	mountedProjects, err := s.getMountedProjects("plugin")
+	if err != nil {
+		return release, err
+	}
+
+	if _, ok := mountedProjects[release.Slug]; ok {
+		return release, fmt.Errorf(
+			"%s is mounted into the site from your computer so updating it would overwrite your code. Publish it to a named site instead, such as with 'kana start --name=%s-updates --updateServer' and 'kana updates publish --name=%s-updates'", //nolint:lll
+			release.Slug,
+			release.Slug,
+			release.Slug)
+	}
+
+	contents, err := os.ReadFile(filepath.Join(directory, mainFile))
+	if err != nil {
+		return release, err
+	}
+
+	header := versionHeader.FindSubmatchIndex(contents)
+	if header == nil {
+		return release, fmt.Errorf("%s has no Version header to publish a release of", mainFile)
+	}
+
+	release.Version = version
+
+	if release.Version == "" {
+		release.Version, err = bumpVersion(string(contents[header[2]:header[3]]))
+		if err != nil {
+			return release, err
+		}
+	}
+
+	// Set the new version in the package so the updated plugin doesn't offer the same update again
+	packagedContents := append([]byte{}, contents[:header[2]]...)
+	packagedContents = append(packagedContents, release.Version...)
+	packagedContents = append(packagedContents, contents[header[3]:]...)
+
+	updatesDirectory, err := s.getUpdatesDirectory()
+	if err != nil {
+		return release, err
+	}
+
+	packageName := fmt.Sprintf("%s-%s.zip", release.Slug, release.Version)
+	release.Package = fmt.Sprintf("%s/%s", s.getUpdateServerURL(), packageName)
+
+	err = writePluginPackage(filepath.Join(updatesDirectory, packageName), directory, release.Slug, mainFile, packagedContents)
+	if err != nil {
+		return release, err
+	}
+
+	err = s.addUpdateRelease(updatesDirectory, release)
+	if err != nil {
+		return release, err
+	}
+
+	if install {
+		consoleOutput.Println(fmt.Sprintf("Installing %s %s.", release.Slug, release.Version))
+
+		err = s.wpCliOrError([]string{"plugin", "install", release.Package, "--force", "--activate"}, consoleOutput)
+		if err != nil {
+			return release, fmt.Errorf("unable to install %s: %s", packageName, err)
+		}
+	}
+
+	// Make WordPress check for updates again rather than waiting for its next scheduled check
+	_, _, err = s.WPCli([]string{"transient", "delete", "update_plugins", "--network"}, false, consoleOutput)
+
+	return release, err
+}
+
+// writePluginPackage zips the plugin in the directory into a folder named after its slug, replacing its main file with
+// the given contents.
+func writePluginPackage(packageFile, directory, slug, mainFile string, mainFileContents []byte) error {
+	file, err := os.Create(packageFile)
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	archive := helpers.NewArchive(file)
+
+	err = archive.AddDirectory(directory, slug, append([]string{mainFile}, packageSkip...))
+	if err != nil {
+		return err
+	}
+
+	err = archive.AddBytes(filepath.Join(slug, mainFile), mainFileContents)
+	if err != nil {
+		return err
+	}
+
+	return archive.Close()
+}
+
+// addUpdateRelease adds a release to the mock update server's index, replacing any earlier release of the plugin.
+func (s *Site) addUpdateRelease(updatesDirectory string, release UpdateRelease) error {
+	indexFile := filepath.Join(updatesDirectory, updateServerIndex)
+	releases := map[string]UpdateRelease{}
+
+	contents, err := os.ReadFile(indexFile)
+	if err == nil {
+		err = json.Unmarshal(contents, &releases)
+	}
+
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("unable to read the mock update server's %s: %s", updateServerIndex, err)
+	}
+
+	releases[release.Slug] = release
+
+	contents, err = json.MarshalIndent(releases, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, filePermissions := settings.GetDefaultFilePermissions()
+
+	return os.WriteFile(indexFile, contents, os.FileMode(filePermissions))
+}
+
+// bumpVersion returns the version with its last number increased by one, such as 1.2.4 for 1.2.3.
+func bumpVersion(version string) (string, error) {
+	parts := strings.Split(version, ".")
+
+	last, err := strconv.Atoi(parts[len(parts)-1])
+	if err != nil {
+		return "", fmt.Errorf("unable to work out the version after %s. Give the version to publish instead", version)
+	}
+
+	parts[len(parts)-1] = strconv.Itoa(last + 1)
+
+	return strings.Join(parts, "."), nil
+}
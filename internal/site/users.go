@@ -0,0 +1,163 @@
+package site
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/settings"
+)
+
+// UserCredentials are the login details of a WordPress user created by Kana.
+type UserCredentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Email    string `json:"email"`
+	Role     string `json:"role"`
+}
+
+// The WordPress core roles, other than administrator, that a test user is created for.
+var coreRoles = []string{
+	"editor",
+	"author",
+	"contributor",
+	"subscriber",
+}
+
+// GetCredentials returns the login details of the admin user and any seeded test users that exist on the site.
+func (s *Site) GetCredentials(consoleOutput *console.Console) ([]UserCredentials, error) {
+	credentials := []UserCredentials{
+		{
+			Username: s.settings.Get("adminUser"),
+			Password: s.settings.Get("adminPassword"),
+			Email:    s.settings.Get("adminEmail"),
+			Role:     "administrator",
+		},
+	}
+
+	seedUsers, err := s.getSeedUsers()
+	if err != nil {
+		return credentials, err
+	}
+
+	existingUsers, err := s.getUsernames(consoleOutput)
+	if err != nil {
+		return credentials, err
+	}
+
+	for _, user := range seedUsers {
+		if slices.Contains(existingUsers, user.Username) {
+			credentials = append(credentials, user)
+		}
+	}
+
+	return credentials, nil
+}
+
+// SeedUsers creates a test user for each core role, and any extra users, that don't already exist on the site.
+// It returns the users that were created.
+func (s *Site) SeedUsers(consoleOutput *console.Console) ([]UserCredentials, error) {
+	created := []UserCredentials{}
+
+	seedUsers, err := s.getSeedUsers()
+	if err != nil {
+		return created, err
+	}
+
+	existingUsers, err := s.getUsernames(consoleOutput)
+	if err != nil {
+		return created, err
+	}
+
+	for _, user := range seedUsers {
+		if slices.Contains(existingUsers, user.Username) {
+			continue
+		}
+
+		createCommand := []string{
+			"user",
+			"create",
+			user.Username,
+			user.Email,
+			fmt.Sprintf("--role=%s", user.Role),
+			fmt.Sprintf("--user_pass=%s", user.Password),
+		}
+
+		code, output, err := s.WPCli(createCommand, false, consoleOutput)
+		if err != nil {
+			return created, err
+		}
+
+		if code != 0 {
+			return created, fmt.Errorf("unable to create the user %s: %s", user.Username, strings.TrimSpace(output))
+		}
+
+		created = append(created, user)
+	}
+
+	return created, nil
+}
+
+// getSeedUsers returns the test users Kana creates. They share the admin password and use the admin email's domain.
+func (s *Site) getSeedUsers() ([]UserCredentials, error) {
+	seedUsers := []UserCredentials{}
+
+	_, emailDomain, _ := strings.Cut(s.settings.Get("adminEmail"), "@")
+
+	extraUsers, err := settings.ParseExtraUsers(s.settings.GetSlice("extraUsers"))
+	if err != nil {
+		return seedUsers, err
+	}
+
+	users := []settings.ExtraUser{}
+
+	for _, role := range coreRoles {
+		users = append(users, settings.ExtraUser{Username: role, Role: role})
+	}
+
+	for _, user := range append(users, extraUsers...) {
+		seedUsers = append(seedUsers, UserCredentials{
+			Username: user.Username,
+			Password: s.settings.Get("adminPassword"),
+			Email:    fmt.Sprintf("%s@%s", user.Username, emailDomain),
+			Role:     user.Role,
+		})
+	}
+
+	return seedUsers, nil
+}
+
+// getUsernames returns the usernames of all users on the site.
+func (s *Site) getUsernames(consoleOutput *console.Console) ([]string, error) {
+	code, output, err := s.WPCli([]string{"user", "list", "--field=user_login"}, false, consoleOutput)
+	if err != nil {
+		return []string{}, err
+	}
+
+	if code != 0 {
+		return []string{}, fmt.Errorf("unable to list the site's users: %s", strings.TrimSpace(output))
+	}
+
+	return strings.Fields(output), nil
+}
+
+// maybeSeedUsers creates the test users when the seedUsers setting is enabled.
+func (s *Site) maybeSeedUsers(consoleOutput *console.Console) error {
+	if !s.settings.GetBool("seedUsers") {
+		return nil
+	}
+
+	defer consoleOutput.StartPhase("User seeding")()
+
+	created, err := s.SeedUsers(consoleOutput)
+	if err != nil {
+		return err
+	}
+
+	for _, user := range created {
+		consoleOutput.Println(fmt.Sprintf("Created %s user: %s", user.Role, consoleOutput.Bold(consoleOutput.Blue(user.Username))))
+	}
+
+	return nil
+}
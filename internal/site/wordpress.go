@@ -1,11 +1,12 @@
 package site
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
-	"runtime"
+	"slices"
 	"strings"
 
 	"github.com/ChrisWiegman/kana/internal/console"
@@ -15,13 +16,6 @@ import (
 	"github.com/docker/docker/api/types/mount"
 )
 
-type PluginInfo struct {
-	Name    string `json:"name"`
-	Status  string `json:"status"`
-	Update  string `json:"update"`
-	Version string `json:"version"`
-}
-
 var defaultDirPermissions = 0750
 
 func (s *Site) getWordPressDirectory() (wordPressDirectory string, err error) {
@@ -50,34 +44,22 @@ func (s *Site) getWordPressDirectory() (wordPressDirectory string, err error) {
 
 // getInstalledWordPressPlugins Returns list of installed plugins and whether default plugins are still present.
 func (s *Site) getInstalledWordPressPlugins(consoleOutput *console.Console) (pluginList []string, hasDefaultPlugins bool, err error) {
-	commands := []string{
-		"plugin",
-		"list",
-		"--format=json",
-	}
-
 	hasDefaultPlugins = false
 
-	_, commandOutput, err := s.WPCli(commands, false, consoleOutput)
+	rawPlugins, err := s.GetExtensions("plugin", consoleOutput)
 	if err != nil {
 		return []string{}, true, err
 	}
 
-	rawPlugins := []PluginInfo{}
 	plugins := []string{}
 
-	err = json.Unmarshal([]byte(commandOutput), &rawPlugins)
-	if err != nil {
-		return []string{}, true, err
-	}
-
 	for _, plugin := range rawPlugins {
 		if plugin.Status != "dropin" &&
 			plugin.Status != "must-use" &&
 			plugin.Name != s.settings.Get("name") &&
 			plugin.Name != "hello" &&
 			plugin.Name != "akismet" {
-			plugins = append(plugins, plugin.Name)
+			plugins = append(plugins, settings.FormatPlugin(plugin.Name, plugin.Status == "active-network"))
 		}
 
 		if plugin.Name == "hello" ||
@@ -103,46 +85,114 @@ func (s *Site) getWordPressMounts(appDir string) ([]mount.Mount, error) {
 		},
 	}
 
-	wpContentDir := "/var/www/html/wp-content"
+	phpLogMounts, err := s.getPHPLogMounts()
+	if err != nil {
+		return appVolumes, err
+	}
+
+	appVolumes = append(appVolumes, phpLogMounts...)
+
+	caCertificateMounts, err := s.getCACertificateMounts()
+	if err != nil {
+		return appVolumes, err
+	}
+
+	appVolumes = append(appVolumes, caCertificateMounts...)
+
+	projects, err := s.getProjects()
+	if err != nil {
+		return appVolumes, err
+	}
 
-	if s.settings.Get("type") == "plugin" {
-		err := os.MkdirAll(
-			filepath.Join(
-				appDir,
-				"wp-content",
-				"plugins",
-				s.settings.Get("name")),
-			os.FileMode(defaultDirPermissions))
+	for _, project := range projects {
+		projectDirectory := filepath.Join("wp-content", project.Type+"s", project.Name)
+
+		err = os.MkdirAll(filepath.Join(appDir, projectDirectory), os.FileMode(defaultDirPermissions))
 		if err != nil {
 			return appVolumes, err
 		}
 
-		appVolumes = append(appVolumes, mount.Mount{ // Map's the user's working directory as a plugin
+		appVolumes = append(appVolumes, mount.Mount{ // Map's the user's plugin or theme into wp-content
 			Type:   mount.TypeBind,
-			Source: s.settings.Get("workingDirectory"),
-			Target: filepath.Join(wpContentDir, "plugins", s.settings.Get("name")),
+			Source: project.Path,
+			Target: filepath.Join("/var/www/html", projectDirectory),
 		})
 	}
 
-	if s.settings.Get("type") == "theme" {
-		err := os.MkdirAll(
-			filepath.Join(appDir,
-				"wp-content",
-				"themes",
-				s.settings.Get("name")),
-			os.FileMode(defaultDirPermissions))
-		if err != nil {
-			return appVolumes, err
+	readOnlyPaths, err := settings.ParseReadOnlyPaths(s.settings.GetSlice("readOnlyPaths"))
+	if err != nil {
+		return appVolumes, err
+	}
+
+	return addReadOnlyMounts(appVolumes, readOnlyPaths), nil
+}
+
+// addReadOnlyMounts makes the readOnlyPaths setting's folders read-only. Folders that are already mounted, such as a
+// plugin being developed, have their mount made read-only and others, such as a premium plugin copied into the site, are
+// mounted again, read-only, over the mount they are in. Folders that don't exist yet are skipped.
+func addReadOnlyMounts(mounts []mount.Mount, readOnlyPaths []string) []mount.Mount {
+	for _, readOnlyPath := range readOnlyPaths {
+		target := path.Join("/var/www/html", readOnlyPath)
+		parent := -1
+
+		// The deepest mount holding the folder is the one its files are in
+		for i := range mounts {
+			if target != mounts[i].Target && !strings.HasPrefix(target, mounts[i].Target+"/") {
+				continue
+			}
+
+			if parent == -1 || len(mounts[i].Target) > len(mounts[parent].Target) {
+				parent = i
+			}
 		}
 
-		appVolumes = append(appVolumes, mount.Mount{ // Map's the user's working directory as a theme
-			Type:   mount.TypeBind,
-			Source: s.settings.Get("workingDirectory"),
-			Target: filepath.Join(wpContentDir, "themes", s.settings.Get("name")),
+		if parent == -1 {
+			continue
+		}
+
+		if mounts[parent].Target == target {
+			mounts[parent].ReadOnly = true
+
+			continue
+		}
+
+		source := filepath.Join(mounts[parent].Source, filepath.FromSlash(strings.TrimPrefix(target, mounts[parent].Target+"/")))
+
+		if _, err := os.Stat(source); err != nil {
+			continue
+		}
+
+		mounts = append(mounts, mount.Mount{
+			Type:     mount.TypeBind,
+			Source:   source,
+			Target:   target,
+			ReadOnly: true,
 		})
 	}
 
-	return appVolumes, nil
+	return mounts
+}
+
+// getProjects returns the plugins and themes being developed in the site, which are mounted into wp-content.
+func (s *Site) getProjects() ([]settings.Project, error) {
+	switch s.settings.Get("type") {
+	case "plugin", "theme":
+		name := s.settings.Get("name")
+
+		if s.projectName != "" {
+			name = s.projectName
+		}
+
+		return []settings.Project{{
+			Name: name,
+			Path: s.settings.Get("workingDirectory"),
+			Type: s.settings.Get("type"),
+		}}, nil
+	case "monorepo":
+		return s.settings.GetProjects()
+	}
+
+	return []settings.Project{}, nil
 }
 
 func (s *Site) getWordPressContainer(appVolumes []mount.Mount, appContainers []docker.ContainerConfig) []docker.ContainerConfig {
@@ -168,6 +218,15 @@ func (s *Site) getWordPressContainer(appVolumes []mount.Mount, appContainers []d
 			"WORDPRESS_ADMIN_USER=admin")
 	}
 
+	isUsingPostgres, err := s.isUsingPostgres()
+	if err != nil {
+		return appContainers
+	}
+
+	if isUsingPostgres {
+		envVars = append(envVars, "KANA_POSTGRES=true")
+	}
+
 	wordPressContainer := docker.ContainerConfig{
 		Name:        fmt.Sprintf("kana-%s-wordpress", s.settings.Get("name")),
 		Image:       fmt.Sprintf("wordpress:php%s", s.settings.Get("php")),
@@ -187,8 +246,28 @@ func (s *Site) getWordPressContainer(appVolumes []mount.Mount, appContainers []d
 		Volumes: appVolumes,
 	}
 
+	middlewareLabels, err := s.getMiddlewareLabels([]string{
+		fmt.Sprintf("wordpress-%s-http", s.settings.Get("name")),
+		fmt.Sprintf("wordpress-%s", s.settings.Get("name")),
+	})
+	if err != nil {
+		return appContainers
+	}
+
+	for label, value := range middlewareLabels {
+		wordPressContainer.Labels[label] = value
+	}
+
+	if s.settings.GetBool("isCI") {
+		wordPressContainer.Ports = []docker.ExposedPorts{
+			{Port: "80", Protocol: "tcp", HostPort: s.settings.Get("ciPort")},
+		}
+	}
+
 	if s.settings.GetBool("AutomaticLogin") {
-		wordPressContainer.Env = append(wordPressContainer.Env, "KANA_ADMIN_LOGIN=true")
+		wordPressContainer.Env = append(wordPressContainer.Env,
+			"KANA_ADMIN_LOGIN=true",
+			fmt.Sprintf("KANA_LOGIN_USER=%s", s.settings.Get("loginUser")))
 	}
 
 	if s.settings.GetBool("WPDebug") {
@@ -201,34 +280,67 @@ func (s *Site) getWordPressContainer(appVolumes []mount.Mount, appContainers []d
 		extraConfig += "define( 'SCRIPT_DEBUG', true );"
 	}
 
+	if s.settings.GetBool("redis") {
+		extraConfig += fmt.Sprintf("define( 'WP_REDIS_HOST', '%s' );", s.getRedisContainerName())
+	}
+
 	wordPressContainer.Env = append(wordPressContainer.Env, extraConfig)
 
+	safeDirectories := getGitSafeDirectories(s.settings.Get("workingDirectory"), appVolumes)
+	wordPressContainer.Env = append(wordPressContainer.Env, s.getGitEnv(safeDirectories)...)
+
+	s.addProxy(&wordPressContainer)
+
 	appContainers = append(appContainers, wordPressContainer)
 
 	return appContainers
 }
 
 // getWordPressContainers returns an array of strings containing the container names for the site.
+// SQLite sites don't have the database or phpMyAdmin containers.
 func (s *Site) getWordPressContainers() []string {
-	return []string{
-		fmt.Sprintf("kana-%s-database", s.settings.Get("name")),
-		fmt.Sprintf("kana-%s-wordpress", s.settings.Get("name")),
-		fmt.Sprintf("kana-%s-phpmyadmin", s.settings.Get("name")),
-		fmt.Sprintf("kana-%s-mailpit", s.settings.Get("name")),
+	containers := []string{}
+
+	isUsingSQLite, err := s.isUsingSQLite()
+	if err != nil || !isUsingSQLite {
+		containers = append(containers, fmt.Sprintf("kana-%s-database", s.settings.Get("name")))
+	}
+
+	containers = append(containers, fmt.Sprintf("kana-%s-wordpress", s.settings.Get("name")))
+
+	if err != nil || !isUsingSQLite {
+		containers = append(containers, fmt.Sprintf("kana-%s-phpmyadmin", s.settings.Get("name")))
 	}
+
+	return append(containers,
+		fmt.Sprintf("kana-%s-mailpit", s.settings.Get("name")),
+		s.getRedisContainerName(),
+		s.getUpdateServerContainerName(),
+		fmt.Sprintf("kana-%s-cli", s.settings.Get("name")),
+		fmt.Sprintf("kana-%s-static", s.settings.Get("name")),
+		s.getShareContainerName())
 }
 
 func (s *Site) activateProject(consoleOutput *console.Console) error {
-	if s.settings.GetBool("Activate") && s.settings.Get("type") != "site" {
+	if !s.settings.GetBool("Activate") {
+		return nil
+	}
+
+	projects, err := s.getProjects()
+	if err != nil {
+		return err
+	}
+
+	for _, project := range projects {
 		consoleOutput.Println(
 			fmt.Sprintf("Activating %s:  %s",
-				s.settings.Get("type"),
-				consoleOutput.Bold(consoleOutput.Blue(s.settings.Get("name")))))
+				project.Type,
+				consoleOutput.Bold(consoleOutput.Blue(project.Name))))
 
 		setupCommand := []string{
-			s.settings.Get("type"),
+			project.Type,
 			"activate",
-			s.settings.Get("name"),
+			project.Name,
 		}
 
 		code, _, err := s.WPCli(setupCommand, false, consoleOutput)
@@ -240,8 +352,8 @@ func (s *Site) activateProject(consoleOutput *console.Console) error {
 			consoleOutput.Warn(
 				fmt.Sprintf(
 					"Unable to activate %s: %s.",
-					s.settings.Get("type"),
-					consoleOutput.Bold(consoleOutput.Blue(s.settings.Get("name")))))
+					project.Type,
+					consoleOutput.Bold(consoleOutput.Blue(project.Name))))
 		}
 	}
 
@@ -253,6 +365,8 @@ func (s *Site) activateTheme(consoleOutput *console.Console) error {
 		return nil
 	}
 
+	defer consoleOutput.StartPhase("Theme install")()
+
 	consoleOutput.Println(fmt.Sprintf("Installing default theme:  %s", consoleOutput.Bold(consoleOutput.Blue(s.settings.Get("theme")))))
 
 	setupCommand := []string{
@@ -274,40 +388,221 @@ func (s *Site) activateTheme(consoleOutput *console.Console) error {
 	return nil
 }
 
-// installDefaultPlugins Installs a list of WordPress plugins.
+// installDefaultPlugins installs and activates the plugins in the plugins setting. If the syncPlugins setting is strict
+// any other plugins are deactivated and deleted so the setting is the site's full list of plugins.
 func (s *Site) installDefaultPlugins(consoleOutput *console.Console) error {
-	installedPlugins, _, err := s.getInstalledWordPressPlugins(consoleOutput)
+	defer consoleOutput.StartPhase("Plugin installs")()
+
+	installedPlugins, err := s.GetExtensions("plugin", consoleOutput)
 	if err != nil {
 		return err
 	}
 
-	for _, plugin := range s.settings.GetSlice("plugins") {
-		setupCommand := []string{
-			"plugin",
-			"install",
-			"--activate",
-			plugin,
+	listedPlugins := []string{}
+	newPlugins := []string{}
+	plugins := s.settings.GetSlice("plugins")
+
+	// The Redis object cache drop-in comes from its plugin
+	if s.settings.GetBool("redis") {
+		plugins = append(plugins, settings.FormatPlugin(redisCachePlugin, s.settings.Get("multisite") != "none"))
+	}
+
+	for _, plugin := range plugins {
+		slug, network, err := settings.ParsePlugin(plugin)
+		if err != nil {
+			return err
+		}
+
+		listedPlugins = append(listedPlugins, slug)
+
+		if network && s.settings.Get("multisite") == "none" {
+			consoleOutput.Warn(fmt.Sprintf(
+				"%s can only be network activated on a multisite installation. Activating it normally instead.",
+				consoleOutput.Bold(consoleOutput.Blue(slug))))
+
+			network = false
 		}
 
-		// Don't  try to reinstall the plugin if it is already installed
-		for _, installedPlugin := range installedPlugins {
-			if installedPlugin == plugin {
-				setupCommand = []string{}
+		activateFlag := "--activate"
+
+		if network {
+			activateFlag = "--activate-network"
+		}
+
+		command := []string{"plugin", "install", activateFlag, slug}
+		message := "Installing plugin:  %s"
+
+		index := slices.IndexFunc(installedPlugins, func(installedPlugin ExtensionInfo) bool {
+			return installedPlugin.Name == slug
+		})
+
+		if index != -1 {
+			status := installedPlugins[index].Status
+
+			// Don't try to reactivate a plugin that is already active
+			if status == "active-network" || (status == "active" && !network) {
+				continue
+			}
+
+			command = []string{"plugin", "activate", slug}
+			message = "Activating plugin:  %s"
+
+			if network {
+				command = append(command, "--network")
 			}
 		}
 
-		if len(setupCommand) > 0 {
-			consoleOutput.Println(fmt.Sprintf("Installing plugin:  %s", consoleOutput.Bold(consoleOutput.Blue(plugin))))
+		consoleOutput.Println(fmt.Sprintf(message, consoleOutput.Bold(consoleOutput.Blue(slug))))
 
-			code, _, err := s.WPCli(setupCommand, false, consoleOutput)
-			if err != nil {
-				return err
+		code, _, err := s.WPCli(command, false, consoleOutput)
+		if err != nil {
+			return err
+		}
+
+		if code != 0 {
+			consoleOutput.Warn(fmt.Sprintf("Unable to %s plugin: %s.", command[1], consoleOutput.Bold(consoleOutput.Blue(slug))))
+
+			continue
+		}
+
+		if index == -1 {
+			newPlugins = append(newPlugins, slug)
+		} else {
+			s.recordHistory("plugin activated", slug)
+		}
+	}
+
+	if len(newPlugins) > 0 {
+		s.recordExtensionVersions("plugin", "installed", newPlugins, consoleOutput)
+	}
+
+	if s.settings.Get("syncPlugins") == "strict" {
+		return s.removeUnlistedPlugins(installedPlugins, listedPlugins, consoleOutput)
+	}
+
+	return nil
+}
+
+// removeUnlistedPlugins deactivates and deletes the installed plugins that aren't in the plugins setting. The plugins
+// and themes being developed, the plugins they depend on and WordPress's default plugins, which are handled by the
+// removeDefaultPlugins setting, are always kept.
+func (s *Site) removeUnlistedPlugins(installedPlugins []ExtensionInfo, listedPlugins []string, consoleOutput *console.Console) error {
+	keptPlugins := slices.Concat(listedPlugins, []string{"hello", "akismet"})
+
+	mountedProjects, err := s.getMountedProjects("plugin")
+	if err != nil {
+		return err
+	}
+
+	for project := range mountedProjects {
+		keptPlugins = append(keptPlugins, project)
+	}
+
+	dependencies, err := s.settings.GetPluginDependencies()
+	if err != nil {
+		return err
+	}
+
+	keptPlugins = append(keptPlugins, dependencies...)
+
+	for _, plugin := range installedPlugins {
+		if plugin.Status == "must-use" || plugin.Status == "dropin" || slices.Contains(keptPlugins, plugin.Name) {
+			continue
+		}
+
+		consoleOutput.Println(fmt.Sprintf(
+			"Removing plugin:  %s as it isn't in the plugins setting",
+			consoleOutput.Bold(consoleOutput.Blue(plugin.Name))))
+
+		if plugin.Status == "active" || plugin.Status == "active-network" {
+			command := []string{"plugin", "deactivate", plugin.Name}
+
+			if plugin.Status == "active-network" {
+				command = append(command, "--network")
 			}
 
-			if code != 0 {
-				consoleOutput.Warn(fmt.Sprintf("Unable to install plugin: %s.", consoleOutput.Bold(consoleOutput.Blue(plugin))))
+			err = s.wpCliOrError(command, consoleOutput)
+			if err != nil {
+				return fmt.Errorf("unable to deactivate %s: %s", plugin.Name, err)
 			}
 		}
+
+		err = s.wpCliOrError([]string{"plugin", "delete", plugin.Name}, consoleOutput)
+		if err != nil {
+			return fmt.Errorf("unable to delete %s: %s", plugin.Name, err)
+		}
+
+		s.recordHistory("plugin removed", plugin.Name)
+	}
+
+	return nil
+}
+
+// installPluginDependencies installs and activates the plugins required by the plugins and themes being developed.
+func (s *Site) installPluginDependencies(consoleOutput *console.Console) error {
+	if !s.settings.GetBool("installDependencies") {
+		return nil
+	}
+
+	dependencies, err := s.settings.GetPluginDependencies()
+	if err != nil || len(dependencies) == 0 {
+		return err
+	}
+
+	defer consoleOutput.StartPhase("Plugin dependencies")()
+
+	installedPlugins, _, err := s.getInstalledWordPressPlugins(consoleOutput)
+	if err != nil {
+		return err
+	}
+
+	for _, dependency := range dependencies {
+		command := []string{"plugin", "install", "--activate", dependency}
+
+		if slices.ContainsFunc(installedPlugins, func(installedPlugin string) bool {
+			installedSlug, _, _ := settings.ParsePlugin(installedPlugin)
+			return installedSlug == dependency
+		}) {
+			command = []string{"plugin", "activate", dependency}
+		} else {
+			consoleOutput.Println(fmt.Sprintf("Installing dependency:  %s", consoleOutput.Bold(consoleOutput.Blue(dependency))))
+		}
+
+		code, _, err := s.WPCli(command, false, consoleOutput)
+		if err != nil {
+			return err
+		}
+
+		if code != 0 {
+			consoleOutput.Warn(fmt.Sprintf("Unable to install dependency: %s.", consoleOutput.Bold(consoleOutput.Blue(dependency))))
+		}
+	}
+
+	return nil
+}
+
+// ensureSuperAdmin makes sure the admin user is a super admin of a multisite network.
+func (s *Site) ensureSuperAdmin(consoleOutput *console.Console) error {
+	if s.settings.Get("multisite") == "none" {
+		return nil
+	}
+
+	code, output, err := s.WPCli([]string{"super-admin", "list"}, false, consoleOutput)
+	if err != nil {
+		return err
+	}
+
+	if code == 0 && slices.Contains(strings.Fields(output), s.settings.Get("adminUser")) {
+		return nil
+	}
+
+	code, output, err = s.WPCli([]string{"super-admin", "add", s.settings.Get("adminUser")}, false, consoleOutput)
+	if err != nil {
+		return err
+	}
+
+	if code != 0 {
+		return fmt.Errorf("unable to make %s a super admin: %s", s.settings.Get("adminUser"), strings.TrimSpace(output))
 	}
 
 	return nil
@@ -320,11 +615,24 @@ func (s *Site) installKanaPlugin() error {
 		return err
 	}
 
-	return settings.EnsureKanaPlugin(wordPressDirectory, s.settings.Get("version"), s.settings.Get("name"))
+	pluginVars := settings.PluginVersion{
+		Version:      s.settings.Get("version"),
+		SiteName:     s.settings.Get("name"),
+		CatchMail:    s.settings.GetBool("catchMail"),
+		WordPressAPI: s.getWordPressAPIURL(),
+	}
+
+	if s.settings.GetBool("updateServer") {
+		pluginVars.UpdateServer = s.getUpdateServerURL()
+	}
+
+	return settings.EnsureKanaPlugin(wordPressDirectory, pluginVars)
 }
 
 // installWordPress Installs and configures WordPress core.
 func (s *Site) installWordPress(consoleOutput *console.Console) error {
+	defer consoleOutput.StartPhase("WordPress install")()
+
 	checkCommand := []string{
 		"option",
 		"get",
@@ -369,6 +677,9 @@ func (s *Site) installWordPress(consoleOutput *console.Console) error {
 		if err != nil || code != 0 {
 			return fmt.Errorf("installation of WordPress failed: %s", output)
 		}
+
+		s.setPermalinks(consoleOutput)
+		s.importStarterContent(consoleOutput)
 	} else if strings.TrimSpace(checkURL) != s.settings.GetURL() {
 		consoleOutput.Println("The SSL config has changed. Updating the site URL accordingly.")
 
@@ -397,7 +708,7 @@ func (s *Site) installWordPress(consoleOutput *console.Console) error {
 }
 
 // startWordPress Starts the WordPress containers.
-func (s *Site) startWordPress(consoleOutput *console.Console) error {
+func (s *Site) startWordPress(ctx context.Context, consoleOutput *console.Console) error {
 	_, _, err := s.dockerClient.EnsureNetwork("kana")
 	if err != nil {
 		return err
@@ -408,44 +719,61 @@ func (s *Site) startWordPress(consoleOutput *console.Console) error {
 		return err
 	}
 
-	// Replace wp-config.php with the container's file
-	_, err = os.Stat(filepath.Join(appDir, "wp-config.php"))
-	if err == nil {
-		os.Remove(filepath.Join(appDir, "wp-config.php"))
+	appVolumes, err := s.getWordPressMounts(appDir)
+	if err != nil {
+		return err
 	}
 
-	appVolumes, err := s.getWordPressMounts(appDir)
+	// Check before starting the containers as, once WordPress is running, this is read from its container
+	isUsingSQLite, err := s.isUsingSQLite()
 	if err != nil {
 		return err
 	}
 
+	if s.settings.GetBool("isCI") && !s.IsSiteRunning() {
+		err = s.checkCIPort()
+		if err != nil {
+			return err
+		}
+	}
+
 	var appContainers []docker.ContainerConfig
 
-	appContainers = s.getDatabaseContainer(databaseDir, appContainers)
+	appContainers = s.getDatabaseContainer(databaseDir, appContainers, consoleOutput)
 	appContainers = s.getWordPressContainer(appVolumes, appContainers)
 
+	// Replace wp-config.php with the container's file, unless the WordPress container is running and being kept
 	for i := range appContainers {
-		err := s.startContainer(&appContainers[i], true, true, consoleOutput)
+		if appContainers[i].Name != fmt.Sprintf("kana-%s-wordpress", s.settings.Get("name")) {
+			continue
+		}
+
+		if !s.dockerClient.ContainerIsRunning(appContainers[i].Name) || s.dockerClient.ContainerIsOutdated(&appContainers[i]) {
+			_, err = os.Stat(filepath.Join(appDir, "wp-config.php"))
+			if err == nil {
+				os.Remove(filepath.Join(appDir, "wp-config.php"))
+			}
+		}
+	}
+
+	for i := range appContainers {
+		err := s.startContainer(ctx, &appContainers[i], true, true, consoleOutput)
 		if err != nil {
 			return err
 		}
 	}
 
-	return s.verifyDatabase(consoleOutput) // verify the database is ready for connections. On slow filesystems this can take a few seconds.
-}
+	err = s.trustCACertificates()
+	if err != nil {
+		return err
+	}
 
-// resetWPFilePermissions Ensures the www-data user owns the WordPress directory.
-func (s *Site) resetWPFilePermissions() error {
-	if runtime.GOOS == "linux" {
+	// SQLite sites don't have a database server to wait for
+	if isUsingSQLite {
 		return nil
 	}
 
-	_, err := s.dockerClient.ContainerExec(
-		fmt.Sprintf("kana-%s-wordpress", s.settings.Get("name")),
-		true,
-		[]string{"chown -R www-data:www-data /var/www/html"})
-
-	return err
+	return s.verifyDatabase(consoleOutput) // verify the database is ready for connections. On slow filesystems this can take a few seconds.
 }
 
 // stopWordPress Stops the site in docker, destroying the containers when they close.
@@ -0,0 +1,82 @@
+package site
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/docker"
+
+	"github.com/docker/docker/api/types/mount"
+)
+
+// wordPressAPIContainerName is the cache of WordPress.org's API shared by every site with the wordpressAPI setting.
+const wordPressAPIContainerName = "kana-wordpress-api"
+
+// getWordPressAPIURL returns the URL the site's WordPress.org API requests are sent to instead of WordPress.org, or an
+// empty string if they go straight to WordPress.org. nginx can't send its requests through a proxy server so the cache
+// isn't used while there is one and the site's requests go through the proxy instead.
+func (s *Site) getWordPressAPIURL() string {
+	mode := s.settings.Get("wordpressAPI")
+
+	if mode == "live" || s.settings.GetProxy().IsSet() {
+		return ""
+	}
+
+	return fmt.Sprintf("http://%s/%s", wordPressAPIContainerName, mode)
+}
+
+// startWordPressAPI starts the shared cache of WordPress.org's API if the site uses it and it isn't already running.
+func (s *Site) startWordPressAPI(ctx context.Context, consoleOutput *console.Console) error {
+	if s.getWordPressAPIURL() == "" {
+		if s.settings.Get("wordpressAPI") != "live" {
+			consoleOutput.Warn(fmt.Sprintf(
+				"The wordpressAPI setting, %s, is ignored while a proxy server is used as the cache can't reach WordPress.org through it. "+
+					"The site's WordPress.org requests will go through the proxy instead.",
+				s.settings.Get("wordpressAPI")))
+		}
+
+		return nil
+	}
+
+	cacheDirectory := filepath.Join(s.settings.Get("appDirectory"), "cache", "wordpress-api")
+
+	err := os.MkdirAll(cacheDirectory, os.FileMode(defaultDirPermissions))
+	if err != nil {
+		return err
+	}
+
+	wordPressAPIContainer := docker.ContainerConfig{
+		Name:        wordPressAPIContainerName,
+		Image:       staticServeImage,
+		NetworkName: "kana",
+		HostName:    wordPressAPIContainerName,
+		Labels: map[string]string{
+			"kana.global": "true",
+		},
+		Volumes: []mount.Mount{
+			{
+				Type:     mount.TypeBind,
+				Source:   filepath.Join(s.settings.Get("appDirectory"), "config", "wordpress-api", "default.conf"),
+				Target:   "/etc/nginx/conf.d/default.conf",
+				ReadOnly: true,
+			},
+			{
+				Type:   mount.TypeBind,
+				Source: cacheDirectory,
+				Target: "/var/cache/nginx/wordpress",
+			},
+		},
+	}
+
+	return s.startContainer(ctx, &wordPressAPIContainer, false, false, consoleOutput)
+}
+
+// stopWordPressAPI stops the shared cache of WordPress.org's API.
+func (s *Site) stopWordPressAPI() error {
+	_, err := s.dockerClient.ContainerStop(wordPressAPIContainerName)
+
+	return err
+}
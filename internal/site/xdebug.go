@@ -1,11 +1,29 @@
 package site
 
 import (
+	"context"
+	"fmt"
+	"net/http"
 	"strings"
+	"time"
 
 	"github.com/ChrisWiegman/kana/internal/console"
 )
 
+// xdebugTriggerTimeout is long enough to step through a request paused at a breakpoint.
+const xdebugTriggerTimeout = 10 * time.Minute
+
+// XdebugStatus is how Xdebug is configured in the site's PHP container and whether a debugging client, such as
+// VSCode or PhpStorm, is listening for it.
+type XdebugStatus struct {
+	Enabled          bool   `json:"enabled"`
+	Mode             string `json:"mode,omitempty"`
+	StartWithRequest string `json:"startWithRequest,omitempty"`
+	ClientHost       string `json:"clientHost,omitempty"`
+	ClientPort       string `json:"clientPort,omitempty"`
+	ClientListening  bool   `json:"clientListening"`
+}
+
 // IsXdebugRunning returns true if Xdebug is already running or false if not.
 func (s *Site) IsXdebugRunning(consoleOutput *console.Console) bool {
 	output, err := s.WordPress("pecl list | grep xdebug", false, false)
@@ -59,5 +77,63 @@ func (s *Site) StopXdebug(consoleOutput *console.Console) error {
 		return err
 	}
 
-	return s.startWordPress(consoleOutput)
+	return s.startWordPress(context.Background(), consoleOutput)
+}
+
+// GetXdebugStatus returns Xdebug's settings in the site's PHP container and checks, from inside the container, whether
+// a debugging client is listening on the host and port Xdebug connects to.
+func (s *Site) GetXdebugStatus(consoleOutput *console.Console) (XdebugStatus, error) {
+	status := XdebugStatus{
+		Enabled: s.IsXdebugRunning(consoleOutput),
+	}
+
+	if !status.Enabled {
+		return status, nil
+	}
+
+	output, err := s.WordPress(
+		`php -r 'foreach (array("xdebug.mode", "xdebug.start_with_request", "xdebug.client_host", "xdebug.client_port") as $setting) { echo ini_get($setting), PHP_EOL; }'`, //nolint:lll
+		false,
+		false)
+	if err != nil {
+		return status, err
+	}
+
+	settings := strings.Split(strings.TrimSpace(output.StdOut), "\n")
+	if len(settings) != 4 {
+		return status, fmt.Errorf("unable to read Xdebug's settings: %s", strings.TrimSpace(output.StdErr))
+	}
+
+	status.Mode = strings.TrimSpace(settings[0])
+	status.StartWithRequest = strings.TrimSpace(settings[1])
+	status.ClientHost = strings.TrimSpace(settings[2])
+	status.ClientPort = strings.TrimSpace(settings[3])
+
+	output, err = s.WordPress(
+		fmt.Sprintf(
+			`php -r '$socket = @fsockopen("%s", %s, $errno, $errstr, 1); echo $socket ? "listening" : "";'`,
+			status.ClientHost,
+			status.ClientPort),
+		false,
+		false)
+	if err != nil {
+		return status, err
+	}
+
+	status.ClientListening = strings.TrimSpace(output.StdOut) == "listening"
+
+	return status, nil
+}
+
+// TriggerXdebug requests a page of the site with the XDEBUG_TRIGGER cookie set so Xdebug starts debugging the request,
+// returning the page's status code once the request, and any debugging session, is done.
+func (s *Site) TriggerXdebug(pageURL string) (string, int, error) {
+	requestURL, err := s.getSitePageURL(pageURL)
+	if err != nil {
+		return "", 0, err
+	}
+
+	code, _, err := getPage(requestURL, http.Header{"Cookie": {"XDEBUG_TRIGGER=kana"}}, xdebugTriggerTimeout)
+
+	return requestURL, code, err
 }
@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+const keychainService = "kana-backup-remote"
+
+var execCommand = exec.Command
+
+// GetSecretKey returns the secret key for the given access key from the environment or the system keychain.
+func GetSecretKey(accessKey string) (string, error) {
+	secretKey := os.Getenv("KANA_BACKUP_REMOTE_SECRET")
+	if secretKey != "" {
+		return secretKey, nil
+	}
+
+	var lookupCommand *exec.Cmd
+
+	switch runtime.GOOS {
+	case "darwin":
+		lookupCommand = execCommand(
+			"security",
+			"find-generic-password",
+			"-s",
+			keychainService,
+			"-a",
+			accessKey,
+			"-w")
+	case "linux":
+		lookupCommand = execCommand(
+			"secret-tool",
+			"lookup",
+			"service",
+			keychainService,
+			"account",
+			accessKey)
+	default:
+		return "", fmt.Errorf("unable to read the remote backup secret. Please set the KANA_BACKUP_REMOTE_SECRET environment variable")
+	}
+
+	var out bytes.Buffer
+	lookupCommand.Stdout = &out
+
+	err := lookupCommand.Run()
+	if err != nil {
+		return "", fmt.Errorf(
+			"unable to find the secret for %s in your keychain under the service %s. You can also set the KANA_BACKUP_REMOTE_SECRET environment variable", //nolint:lll
+			accessKey,
+			keychainService)
+	}
+
+	return strings.TrimSpace(out.String()), nil
+}
@@ -0,0 +1,183 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// S3Target represents an S3-compatible bucket that archives can be pushed to.
+type S3Target struct {
+	Endpoint  string
+	Bucket    string
+	Region    string
+	AccessKey string
+	SecretKey string
+}
+
+const (
+	amzDateFormat   = "20060102T150405Z"
+	shortDateFormat = "20060102"
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+)
+
+var httpClient = http.DefaultClient
+
+// Upload sends the given file to the bucket using the given object key.
+func (t *S3Target) Upload(filePath, key string) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	objectURL, err := t.objectURL(key)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPut, objectURL.String(), file)
+	if err != nil {
+		return err
+	}
+
+	req.ContentLength = fileInfo.Size()
+
+	t.sign(req, time.Now().UTC())
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		body, _ := io.ReadAll(resp.Body)
+
+		return fmt.Errorf("unable to upload %s to %s: %s\n%s", filepath.Base(filePath), t.Bucket, resp.Status, string(body))
+	}
+
+	return nil
+}
+
+// Validate ensures the target has everything needed to connect to the remote.
+func (t *S3Target) Validate() error {
+	if t.Endpoint == "" || t.Bucket == "" {
+		return fmt.Errorf("a remote backup target has not been configured. Please set the backupRemoteEndpoint and backupRemoteBucket settings")
+	}
+
+	if t.AccessKey == "" || t.SecretKey == "" {
+		return fmt.Errorf("credentials for the remote backup target could not be found")
+	}
+
+	return nil
+}
+
+// objectURL returns the path-style URL for the given object key.
+func (t *S3Target) objectURL(key string) (*url.URL, error) {
+	endpoint := t.Endpoint
+
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+
+	objectURL, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	objectURL.Path = "/" + t.Bucket + "/" + strings.TrimPrefix(key, "/")
+	objectURL.RawPath = uriEncode(objectURL.Path)
+
+	return objectURL, nil
+}
+
+// sign adds an AWS Signature Version 4 authorization header to the request.
+func (t *S3Target) sign(req *http.Request, now time.Time) {
+	amzDate := now.Format(amzDateFormat)
+	shortDate := now.Format(shortDateFormat)
+
+	req.Header.Set("x-amz-content-sha256", unsignedPayload)
+	req.Header.Set("x-amz-date", amzDate)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		uriEncode(req.URL.Path),
+		req.URL.RawQuery,
+		"host:" + req.URL.Host,
+		"x-amz-content-sha256:" + unsignedPayload,
+		"x-amz-date:" + amzDate,
+		"",
+		signedHeaders,
+		unsignedPayload,
+	}, "\n")
+
+	scope := fmt.Sprintf("%s/%s/s3/aws4_request", shortDate, t.Region)
+
+	canonicalHash := sha256.Sum256([]byte(canonicalRequest))
+
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hex.EncodeToString(canonicalHash[:]),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+t.SecretKey), shortDate)
+	signingKey = hmacSHA256(signingKey, t.Region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set(
+		"Authorization",
+		fmt.Sprintf(
+			"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+			t.AccessKey,
+			scope,
+			signedHeaders,
+			signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+
+	return mac.Sum(nil)
+}
+
+// uriEncode encodes a path as required by S3, leaving only unreserved characters and slashes as-is.
+func uriEncode(path string) string {
+	var encoded strings.Builder
+
+	for _, b := range []byte(path) {
+		if (b >= 'A' && b <= 'Z') || (b >= 'a' && b <= 'z') || (b >= '0' && b <= '9') ||
+			b == '-' || b == '_' || b == '.' || b == '~' || b == '/' {
+			encoded.WriteByte(b)
+			continue
+		}
+
+		fmt.Fprintf(&encoded, "%%%02X", b)
+	}
+
+	return encoded.String()
+}
@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpload(t *testing.T) {
+	var receivedPath, receivedBody, receivedAuth string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		receivedPath = r.URL.Path
+		receivedBody = string(body)
+		receivedAuth = r.Header.Get("Authorization")
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	testFile := filepath.Join(t.TempDir(), "kana-test.sql")
+
+	err := os.WriteFile(testFile, []byte("SELECT 1;"), 0600)
+	assert.NoError(t, err)
+
+	target := S3Target{
+		Endpoint:  server.URL,
+		Bucket:    "backups",
+		Region:    "us-east-1",
+		AccessKey: "access",
+		SecretKey: "secret",
+	}
+
+	err = target.Upload(testFile, "test/kana-test.sql")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "/backups/test/kana-test.sql", receivedPath)
+	assert.Equal(t, "SELECT 1;", receivedBody)
+	assert.True(t, strings.HasPrefix(receivedAuth, "AWS4-HMAC-SHA256 Credential=access/"), receivedAuth)
+}
+
+func TestUploadError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	testFile := filepath.Join(t.TempDir(), "kana-test.sql")
+
+	err := os.WriteFile(testFile, []byte("SELECT 1;"), 0600)
+	assert.NoError(t, err)
+
+	target := S3Target{
+		Endpoint: server.URL,
+		Bucket:   "backups",
+		Region:   "us-east-1",
+	}
+
+	err = target.Upload(testFile, "kana-test.sql")
+	assert.Error(t, err)
+}
+
+func TestURIEncode(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{
+			name:     "Unreserved characters are not encoded",
+			path:     "/bucket/site/kana-site-20240101-101010.sql",
+			expected: "/bucket/site/kana-site-20240101-101010.sql",
+		},
+		{
+			name:     "Reserved characters are encoded",
+			path:     "/bucket/my file+1.sql",
+			expected: "/bucket/my%20file%2B1.sql",
+		},
+	}
+
+	for _, test := range testCases {
+		assert.Equal(t, test.expected, uriEncode(test.path), test.name)
+	}
+}
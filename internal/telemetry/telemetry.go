@@ -0,0 +1,135 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+)
+
+// Event is a single anonymous usage record. It must never contain information about the user's sites.
+type Event struct {
+	Command  string `json:"command"`
+	OS       string `json:"os"`
+	Arch     string `json:"arch"`
+	Version  string `json:"version"`
+	Duration int64  `json:"durationMs"`
+}
+
+const (
+	eventFileName = "telemetry.json"
+	maxEvents     = 100
+	sendBatchSize = 10
+	sendTimeout   = 5
+)
+
+var httpClient = http.DefaultClient
+
+// NewEvent returns an event for the given command using the current platform.
+func NewEvent(command, version string, duration time.Duration) Event {
+	return Event{
+		Command:  command,
+		OS:       runtime.GOOS,
+		Arch:     runtime.GOARCH,
+		Version:  version,
+		Duration: duration.Milliseconds(),
+	}
+}
+
+// GetPending returns the events that have been recorded but not yet sent.
+func GetPending(appDirectory string) ([]Event, error) {
+	events := []Event{}
+
+	contents, err := os.ReadFile(filepath.Join(appDirectory, eventFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return events, nil
+		}
+
+		return events, err
+	}
+
+	err = json.Unmarshal(contents, &events)
+
+	return events, err
+}
+
+// Clear removes all pending events.
+func Clear(appDirectory string) error {
+	err := os.Remove(filepath.Join(appDirectory, eventFileName))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// Record adds an event to the pending events, dropping the oldest events if there are too many.
+func Record(appDirectory string, event Event) error {
+	events, err := GetPending(appDirectory)
+	if err != nil {
+		events = []Event{}
+	}
+
+	events = append(events, event)
+
+	if len(events) > maxEvents {
+		events = events[len(events)-maxEvents:]
+	}
+
+	return writeEvents(appDirectory, events)
+}
+
+// MaybeSend sends the pending events to the endpoint once enough have been recorded, clearing them if successful.
+func MaybeSend(appDirectory, endpoint string) error {
+	if endpoint == "" {
+		return nil
+	}
+
+	events, err := GetPending(appDirectory)
+	if err != nil || len(events) < sendBatchSize {
+		return err
+	}
+
+	body, err := json.Marshal(events)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(sendTimeout)*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unable to send usage metrics: %s", resp.Status)
+	}
+
+	return Clear(appDirectory)
+}
+
+func writeEvents(appDirectory string, events []Event) error {
+	contents, err := json.Marshal(events)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(appDirectory, eventFileName), contents, 0600)
+}
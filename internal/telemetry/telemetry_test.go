@@ -0,0 +1,74 @@
+package telemetry
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecord(t *testing.T) {
+	appDirectory := t.TempDir()
+
+	events, err := GetPending(appDirectory)
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+
+	for i := 0; i < maxEvents+5; i++ {
+		err = Record(appDirectory, NewEvent("kana start", "1.0.0", time.Second))
+		assert.NoError(t, err)
+	}
+
+	events, err = GetPending(appDirectory)
+	assert.NoError(t, err)
+	assert.Len(t, events, maxEvents)
+	assert.Equal(t, "kana start", events[0].Command)
+	assert.Equal(t, int64(1000), events[0].Duration)
+
+	err = Clear(appDirectory)
+	assert.NoError(t, err)
+
+	events, err = GetPending(appDirectory)
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+}
+
+func TestMaybeSend(t *testing.T) {
+	var received []Event
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		_ = json.Unmarshal(body, &received)
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	appDirectory := t.TempDir()
+
+	for i := 0; i < sendBatchSize-1; i++ {
+		err := Record(appDirectory, NewEvent("kana list", "1.0.0", time.Millisecond))
+		assert.NoError(t, err)
+	}
+
+	// Nothing is sent until a full batch has been recorded
+	err := MaybeSend(appDirectory, server.URL)
+	assert.NoError(t, err)
+	assert.Empty(t, received)
+
+	err = Record(appDirectory, NewEvent("kana list", "1.0.0", time.Millisecond))
+	assert.NoError(t, err)
+
+	err = MaybeSend(appDirectory, server.URL)
+	assert.NoError(t, err)
+	assert.Len(t, received, sendBatchSize)
+
+	events, err := GetPending(appDirectory)
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+}
@@ -0,0 +1,204 @@
+// Package mailpit is a small client for the Mailpit API used by Kana sites to catch email. It can be used from Go
+// integration tests to check that a site sent an email, using the API URL shown by `kana mail url`.
+package mailpit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	requestTimeout  = 10 * time.Second
+	waitInterval    = time.Second
+	waitSearchLimit = 50
+)
+
+// Client talks to the Mailpit API at BaseURL, such as http://127.0.0.1:49153.
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// Address is the sender or a recipient of an email.
+type Address struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+}
+
+// Summary is an email in the list of emails caught by Mailpit.
+type Summary struct {
+	ID      string    `json:"id"`
+	From    Address   `json:"from"`
+	To      []Address `json:"to"`
+	Subject string    `json:"subject"`
+	Created time.Time `json:"created"`
+	Snippet string    `json:"snippet"`
+}
+
+// Message is a single email caught by Mailpit, including its body.
+type Message struct {
+	ID      string    `json:"id"`
+	From    Address   `json:"from"`
+	To      []Address `json:"to"`
+	Cc      []Address `json:"cc"`
+	Subject string    `json:"subject"`
+	Date    time.Time `json:"date"`
+	Text    string    `json:"text"`
+	HTML    string    `json:"html"`
+}
+
+// Filter picks out emails by their recipient, sender and subject. Empty fields match every email.
+type Filter struct {
+	To, From, Subject string
+}
+
+// NewClient returns a client for the Mailpit API at the given URL.
+func NewClient(baseURL string) *Client {
+	return &Client{
+		BaseURL:    strings.TrimSuffix(baseURL, "/"),
+		HTTPClient: http.DefaultClient,
+	}
+}
+
+// String returns the address in the usual "Name <address>" form.
+func (a Address) String() string {
+	if a.Name == "" {
+		return a.Address
+	}
+
+	return fmt.Sprintf("%s <%s>", a.Name, a.Address)
+}
+
+// Matches returns true if the email is to and from the filter's addresses, ignoring case,
+// and its subject contains the filter's subject.
+func (f Filter) Matches(message Summary) bool {
+	if f.From != "" && !strings.EqualFold(message.From.Address, f.From) {
+		return false
+	}
+
+	if f.Subject != "" && !strings.Contains(strings.ToLower(message.Subject), strings.ToLower(f.Subject)) {
+		return false
+	}
+
+	if f.To == "" {
+		return true
+	}
+
+	for _, recipient := range message.To {
+		if strings.EqualFold(recipient.Address, f.To) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// List returns the emails caught by Mailpit, newest first, up to the given limit.
+func (c *Client) List(limit int) ([]Summary, error) {
+	response := struct {
+		Messages []Summary `json:"messages"`
+	}{}
+
+	err := c.request(http.MethodGet, fmt.Sprintf("/api/v1/messages?limit=%d", limit), nil, &response)
+
+	return response.Messages, err
+}
+
+// Get returns an email caught by Mailpit. The ID "latest" returns the newest email.
+func (c *Client) Get(id string) (Message, error) {
+	message := Message{}
+
+	err := c.request(http.MethodGet, fmt.Sprintf("/api/v1/message/%s", url.PathEscape(id)), nil, &message)
+
+	return message, err
+}
+
+// Delete deletes the given emails or, if no IDs are given, all of them.
+func (c *Client) Delete(ids []string) error {
+	request := struct {
+		IDs []string `json:"IDs,omitempty"`
+	}{
+		IDs: ids,
+	}
+
+	return c.request(http.MethodDelete, "/api/v1/messages", request, nil)
+}
+
+// WaitFor blocks until Mailpit has caught an email matching the filter, returning the newest one that does.
+// Emails caught before the wait started also match so delete them first to only wait for new ones.
+func (c *Client) WaitFor(filter Filter, timeout time.Duration) (Message, error) {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		messages, err := c.List(waitSearchLimit)
+		if err != nil {
+			return Message{}, err
+		}
+
+		for _, message := range messages {
+			if filter.Matches(message) {
+				return c.Get(message.ID)
+			}
+		}
+
+		if time.Now().Add(waitInterval).After(deadline) {
+			return Message{}, fmt.Errorf("no matching email arrived within %s", timeout)
+		}
+
+		time.Sleep(waitInterval)
+	}
+}
+
+// request sends a request to the API, decoding the JSON response into response if it isn't nil.
+func (c *Client) request(method, path string, request, response interface{}) error {
+	var body io.Reader = http.NoBody
+
+	if request != nil {
+		requestJSON, err := json.Marshal(request)
+		if err != nil {
+			return err
+		}
+
+		body = bytes.NewReader(requestJSON)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, body)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to reach Mailpit: %s", err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("the email could not be found")
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		message, _ := io.ReadAll(resp.Body)
+
+		return fmt.Errorf("mailpit returned an error: %s %s", resp.Status, strings.TrimSpace(string(message)))
+	}
+
+	if response == nil {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(response)
+}
@@ -0,0 +1,89 @@
+package mailpit
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilter_Matches(t *testing.T) {
+	message := Summary{
+		From:    Address{Address: "wordpress@sites.kana.sh"},
+		To:      []Address{{Name: "Admin", Address: "admin@sites.kana.sh"}, {Address: "user@example.com"}},
+		Subject: "[Kana] Password Reset",
+	}
+
+	tests := []struct {
+		name     string
+		filter   Filter
+		expected bool
+	}{
+		{"Empty filter", Filter{}, true},
+		{"Matching recipient", Filter{To: "User@Example.com"}, true},
+		{"Other recipient", Filter{To: "someone@example.com"}, false},
+		{"Matching sender", Filter{From: "wordpress@sites.kana.sh"}, true},
+		{"Other sender", Filter{From: "admin@sites.kana.sh"}, false},
+		{"Subject contains", Filter{Subject: "password reset"}, true},
+		{"Subject doesn't contain", Filter{Subject: "Welcome"}, false},
+		{"All fields match", Filter{To: "admin@sites.kana.sh", From: "wordpress@sites.kana.sh", Subject: "Reset"}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.filter.Matches(message))
+		})
+	}
+}
+
+func TestAddress_String(t *testing.T) {
+	assert.Equal(t, "admin@sites.kana.sh", Address{Address: "admin@sites.kana.sh"}.String())
+	assert.Equal(t, "Admin <admin@sites.kana.sh>", Address{Name: "Admin", Address: "admin@sites.kana.sh"}.String())
+}
+
+func TestClient_WaitFor(t *testing.T) {
+	requests := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/messages":
+			requests++
+
+			messages := []map[string]interface{}{}
+
+			// The email arrives on the second check
+			if requests > 1 {
+				messages = append(messages, map[string]interface{}{
+					"ID":      "abc123",
+					"To":      []map[string]string{{"Address": "user@example.com"}},
+					"Subject": "Password Reset",
+				})
+			}
+
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"messages": messages})
+		case "/api/v1/message/abc123":
+			_, _ = io.WriteString(w, `{"ID":"abc123","Subject":"Password Reset","Text":"Reset your password"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+
+	message, err := client.WaitFor(Filter{To: "user@example.com"}, 5*time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, "abc123", message.ID)
+	assert.Equal(t, "Reset your password", message.Text)
+	assert.Equal(t, 2, requests)
+
+	_, err = client.WaitFor(Filter{To: "someone@example.com"}, time.Second)
+	assert.EqualError(t, err, "no matching email arrived within 1s")
+
+	_, err = client.Get("missing")
+	assert.EqualError(t, err, "the email could not be found")
+}